@@ -8,6 +8,8 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/nack098/nakumanager/internal/auth"
+	"github.com/nack098/nakumanager/internal/mail"
 	_ "modernc.org/sqlite"
 )
 
@@ -28,7 +30,8 @@ func runMigrations() {
 func main() {
 	app := fiber.New()
 
-	conn, err := sql.Open("sqlite", "./app.db")
+	// เก็บเวลาในรูปแบบที่ฟังก์ชันวันที่ของ SQLite อ่านได้ เพื่อให้กรองและเรียงตามวันที่ใน SQL ได้
+	conn, err := sql.Open("sqlite", "./app.db?_time_format=sqlite")
 	if err != nil {
 		log.Fatal("cannot connect to db:", err)
 	}
@@ -41,7 +44,17 @@ func main() {
 
 	runMigrations()
 
-	SetUpRouters(app, conn)
+	keys, err := auth.LoadKeySet()
+	if err != nil {
+		log.Fatal("failed to load signing keys:", err)
+	}
+
+	mailer, err := mail.NewSenderFromEnv()
+	if err != nil {
+		log.Fatal("failed to set up mail sender:", err)
+	}
+
+	SetUpRouters(app, conn, keys, mailer)
 
 	log.Fatal(app.Listen(":8080"))
 }
@@ -1,16 +1,23 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log"
+	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/nack098/nakumanager/internal/auth"
 	"github.com/nack098/nakumanager/internal/db"
 	"github.com/nack098/nakumanager/internal/gateway"
+	"github.com/nack098/nakumanager/internal/jobs"
+	"github.com/nack098/nakumanager/internal/mail"
 	"github.com/nack098/nakumanager/internal/repositories"
 	"github.com/nack098/nakumanager/internal/routes"
+	"github.com/nack098/nakumanager/internal/viewcache"
 	"github.com/nack098/nakumanager/internal/ws"
 )
 
@@ -24,22 +31,72 @@ func LoggerMiddleware(c *fiber.Ctx) error {
 	return c.Next()
 }
 
-func SetUpRouters(app *fiber.App, conn *sql.DB) {
+func SetUpRouters(app *fiber.App, conn *sql.DB, keys *auth.KeySet, mailer mail.Sender) {
 	queries := db.New(conn)
 	userRepo := repositories.NewUserRepository(queries)
 	workspaceRepo := repositories.NewWorkspaceRepository(queries)
 	teamRepo := repositories.NewTeamRepository(queries)
 	projectRepo := repositories.NewProjectRepository(queries)
-	issueRepo := repositories.NewIssueRepository(queries)
+	issueRepo := repositories.NewIssueRepository(conn)
 	viewRepo := repositories.NewViewRepository(conn)
+	commentRepo := repositories.NewCommentRepository(conn)
+	sessionRepo := repositories.NewSessionRepository(queries)
+	roleRepo := repositories.NewRoleRepository(queries)
+	invitationRepo := repositories.NewInvitationRepository(conn)
+	searchRepo := repositories.NewSearchRepository(conn)
+	labelRepo := repositories.NewLabelRepository(conn)
+	relationRepo := repositories.NewRelationRepository(conn)
+	cycleRepo := repositories.NewCycleRepository(conn)
+	templateRepo := repositories.NewIssueTemplateRepository(conn)
+	recurringRepo := repositories.NewRecurringIssueRepository(conn)
+	notificationRepo := repositories.NewNotificationRepository(conn)
 	// wsHandler := ws.NewWSHandler(workspaceRepo, teamRepo, projectRepo, issueRepo, userRepo, viewRepo)
 
-	authHandler := auth.NewAuthHandler(userRepo)
-	workspaceHandler := routes.NewWorkspaceHandler(workspaceRepo, userRepo)
-	teamHandler := routes.NewTeamHandler(teamRepo, workspaceRepo)
-	projectHandler := routes.NewProjectHandler(conn, projectRepo, teamRepo)
-	issueHandler := routes.NewIssueHandler(conn, issueRepo, teamRepo, projectRepo)
-	viewHandler := routes.NewViewHandler(conn, viewRepo)
+	authHandler := auth.NewAuthHandler(userRepo, sessionRepo, keys)
+	workspaceHandler := routes.NewWorkspaceHandler(workspaceRepo, userRepo, roleRepo)
+	teamHandler := routes.NewTeamHandler(conn, teamRepo, workspaceRepo, roleRepo)
+	projectHandler := routes.NewProjectHandler(conn, projectRepo, teamRepo, labelRepo, roleRepo)
+	issueHandler := routes.NewIssueHandler(conn, issueRepo, teamRepo, projectRepo, commentRepo, labelRepo, relationRepo, cycleRepo, templateRepo, roleRepo)
+	viewHandler := routes.NewViewHandler(conn, viewRepo, roleRepo)
+	if ttl := os.Getenv("VIEW_CACHE_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			log.Fatal("invalid VIEW_CACHE_TTL:", err)
+		}
+		viewHandler.Cache = viewcache.New(d)
+	}
+	searchHandler := routes.NewSearchHandler(searchRepo)
+	labelHandler := routes.NewLabelHandler(labelRepo, teamRepo, roleRepo)
+	cycleHandler := routes.NewCycleHandler(conn, cycleRepo, teamRepo, issueRepo, roleRepo)
+	templateHandler := routes.NewIssueTemplateHandler(conn, templateRepo, teamRepo, projectRepo, labelRepo, roleRepo)
+	recurringHandler := routes.NewRecurringIssueHandler(recurringRepo, teamRepo, projectRepo, templateRepo, roleRepo)
+	notificationHandler := routes.NewNotificationHandler(notificationRepo)
+	invitationHandler := routes.NewInvitationHandler(invitationRepo, workspaceRepo, userRepo, roleRepo, keys, mailer)
+	if baseURL := os.Getenv("APP_BASE_URL"); baseURL != "" {
+		invitationHandler.BaseURL = baseURL
+	}
+
+	// issue ที่ตั้งให้สร้างตามรอบสร้างผ่าน CreateIssue เหมือนที่สร้างจาก API
+	recurringJob := jobs.NewRecurringIssues(recurringRepo, issueHandler)
+	if interval := os.Getenv("RECURRING_ISSUE_INTERVAL"); interval != "" {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			log.Fatal("invalid RECURRING_ISSUE_INTERVAL:", err)
+		}
+		recurringJob.Interval = d
+	}
+	go recurringJob.Run(context.Background())
+
+	// เตือนเจ้าของและผู้รับผิดชอบ issue ที่ใกล้ครบกำหนดหรือเลยกำหนดแล้ว
+	reminderJob := jobs.NewDueReminders(notificationRepo)
+	if window := os.Getenv("DUE_REMINDER_WINDOW"); window != "" {
+		d, err := time.ParseDuration(window)
+		if err != nil {
+			log.Fatal("invalid DUE_REMINDER_WINDOW:", err)
+		}
+		reminderJob.Window = d
+	}
+	go reminderJob.Run(context.Background())
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "http://localhost:8080",
@@ -56,13 +113,21 @@ func SetUpRouters(app *fiber.App, conn *sql.DB) {
 	private := api.Group("/")
 	private.Use(authHandler.AuthRequired)
 
+	gateway.SetUpSessionRoutes(private, authHandler)
 	gateway.SetUpWorkspaceRoutes(private, workspaceHandler)
 	gateway.SetUpTeamRoutes(private, teamHandler)
 	gateway.SetUpProjectsRoutes(private, projectHandler)
 	gateway.SetUpIssueRoutes(private, issueHandler)
 	gateway.SetUpViewRoutes(private, viewHandler)
+	gateway.SetUpInvitationRoutes(private, invitationHandler)
+	gateway.SetUpSearchRoutes(private, searchHandler)
+	gateway.SetUpLabelRoutes(private, labelHandler)
+	gateway.SetUpCycleRoutes(private, cycleHandler)
+	gateway.SetUpIssueTemplateRoutes(private, templateHandler)
+	gateway.SetUpRecurringIssueRoutes(private, recurringHandler)
+	gateway.SetUpNotificationRoutes(private, notificationHandler)
 
-	wsHandler := &ws.WebSocketHandler{}
+	wsHandler := ws.NewWebSocketHandler(issueRepo, roleRepo)
 	app.Use("/ws", authHandler.WebSocketAuthRequired())
 	app.Get("/ws", wsHandler.Handle)
 
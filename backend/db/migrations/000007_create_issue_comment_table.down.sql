@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS issue_comment_mentions;
+DROP TABLE IF EXISTS issue_comment_edits;
+DROP TABLE IF EXISTS issue_comments;
\ No newline at end of file
@@ -0,0 +1,28 @@
+CREATE TABLE issue_comments (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id)
+);
+
+CREATE TABLE issue_comment_edits (
+    id TEXT PRIMARY KEY,
+    comment_id TEXT NOT NULL,
+    previous_body TEXT NOT NULL,
+    edited_by TEXT NOT NULL,
+    edited_at DATETIME NOT NULL,
+    FOREIGN KEY (comment_id) REFERENCES issue_comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (edited_by) REFERENCES users(id)
+);
+
+CREATE TABLE issue_comment_mentions (
+    comment_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (comment_id, user_id),
+    FOREIGN KEY (comment_id) REFERENCES issue_comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_issue_events_issue_id;
+DROP TABLE IF EXISTS issue_events;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE issue_events (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_issue_events_issue_id ON issue_events (issue_id, created_at);
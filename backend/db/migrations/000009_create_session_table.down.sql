@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_sessions_previous_token_hash;
+DROP INDEX IF EXISTS idx_sessions_user_id;
+DROP TABLE IF EXISTS sessions;
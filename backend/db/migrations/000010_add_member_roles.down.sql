@@ -0,0 +1,2 @@
+ALTER TABLE team_members DROP COLUMN role;
+ALTER TABLE workspace_members DROP COLUMN role;
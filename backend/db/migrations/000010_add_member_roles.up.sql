@@ -0,0 +1,27 @@
+ALTER TABLE workspace_members ADD COLUMN role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member', 'guest'));
+ALTER TABLE team_members ADD COLUMN role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('lead', 'member'));
+
+-- เจ้าของ workspace และหัวหน้าทีมเดิมต้องมี role ตามตำแหน่ง
+INSERT OR IGNORE INTO workspace_members (workspace_id, user_id)
+SELECT id, owner_id FROM workspaces;
+
+UPDATE workspace_members SET role = 'owner'
+WHERE EXISTS (
+    SELECT 1 FROM workspaces w
+    WHERE w.id = workspace_members.workspace_id AND w.owner_id = workspace_members.user_id
+);
+
+INSERT OR IGNORE INTO team_members (team_id, user_id)
+SELECT id, leader_id FROM teams WHERE leader_id IS NOT NULL;
+
+UPDATE team_members SET role = 'lead'
+WHERE EXISTS (
+    SELECT 1 FROM teams t
+    WHERE t.id = team_members.team_id AND t.leader_id = team_members.user_id
+);
+
+-- สมาชิกทีมทุกคนต้องเป็นสมาชิกของ workspace ของทีมด้วย คนที่ยังไม่มีได้ role member
+INSERT OR IGNORE INTO workspace_members (workspace_id, user_id, role)
+SELECT t.workspace_id, tm.user_id, 'member'
+FROM team_members tm
+JOIN teams t ON t.id = tm.team_id;
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS projects_fts_delete;
+DROP TRIGGER IF EXISTS projects_fts_update;
+DROP TRIGGER IF EXISTS projects_fts_insert;
+DROP TRIGGER IF EXISTS issues_fts_delete;
+DROP TRIGGER IF EXISTS issues_fts_update;
+DROP TRIGGER IF EXISTS issues_fts_insert;
+DROP TABLE IF EXISTS projects_fts;
+DROP TABLE IF EXISTS issues_fts;
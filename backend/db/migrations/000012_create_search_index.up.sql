@@ -0,0 +1,47 @@
+-- ตาราง FTS เก็บข้อความของตัวเองและผูกกับ id เดิม เพราะ rowid ของตารางที่ใช้ TEXT PRIMARY KEY อาจเปลี่ยนได้หลัง VACUUM
+CREATE VIRTUAL TABLE issues_fts USING fts5(
+    issue_id UNINDEXED,
+    title,
+    content,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE VIRTUAL TABLE projects_fts USING fts5(
+    project_id UNINDEXED,
+    name,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO issues_fts (issue_id, title, content)
+SELECT id, title, COALESCE(content, '') FROM issues;
+
+INSERT INTO projects_fts (project_id, name)
+SELECT id, name FROM projects;
+
+CREATE TRIGGER issues_fts_insert AFTER INSERT ON issues BEGIN
+    INSERT INTO issues_fts (issue_id, title, content)
+    VALUES (new.id, new.title, COALESCE(new.content, ''));
+END;
+
+CREATE TRIGGER issues_fts_update AFTER UPDATE OF id, title, content ON issues BEGIN
+    DELETE FROM issues_fts WHERE issue_id = old.id;
+    INSERT INTO issues_fts (issue_id, title, content)
+    VALUES (new.id, new.title, COALESCE(new.content, ''));
+END;
+
+CREATE TRIGGER issues_fts_delete AFTER DELETE ON issues BEGIN
+    DELETE FROM issues_fts WHERE issue_id = old.id;
+END;
+
+CREATE TRIGGER projects_fts_insert AFTER INSERT ON projects BEGIN
+    INSERT INTO projects_fts (project_id, name) VALUES (new.id, new.name);
+END;
+
+CREATE TRIGGER projects_fts_update AFTER UPDATE OF id, name ON projects BEGIN
+    DELETE FROM projects_fts WHERE project_id = old.id;
+    INSERT INTO projects_fts (project_id, name) VALUES (new.id, new.name);
+END;
+
+CREATE TRIGGER projects_fts_delete AFTER DELETE ON projects BEGIN
+    DELETE FROM projects_fts WHERE project_id = old.id;
+END;
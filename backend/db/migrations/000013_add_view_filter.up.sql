@@ -0,0 +1,2 @@
+-- filter เก็บเป็น JSON ตามโครงสร้าง models.ViewFilter, NULL คือไม่กรอง
+ALTER TABLE views ADD COLUMN filter TEXT;
@@ -0,0 +1,14 @@
+DROP TRIGGER IF EXISTS team_issue_versions_assignee_delete;
+DROP TRIGGER IF EXISTS team_issue_versions_assignee_insert;
+DROP TRIGGER IF EXISTS team_issue_versions_issue_delete;
+DROP TRIGGER IF EXISTS team_issue_versions_issue_update;
+DROP TRIGGER IF EXISTS team_issue_versions_issue_insert;
+DROP TABLE IF EXISTS team_issue_versions;
+
+CREATE TABLE view_issues (
+    view_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    PRIMARY KEY (view_id, issue_id),
+    FOREIGN KEY (view_id) REFERENCES views(id) ON DELETE CASCADE,
+    FOREIGN KEY (issue_id) REFERENCES issues(id)
+);
@@ -0,0 +1,36 @@
+-- view คำนวณจากเงื่อนไขทุกครั้งที่อ่าน จึงไม่ต้องเก็บรายการ issue ของแต่ละ view อีก
+DROP TABLE IF EXISTS view_issues;
+
+-- version เพิ่มขึ้นทุกครั้งที่ issue ของทีมถูกเขียน ใช้ตรวจว่าผลของ view ที่ cache ไว้ยังใช้ได้หรือไม่
+CREATE TABLE team_issue_versions (
+    team_id TEXT PRIMARY KEY,
+    version INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER team_issue_versions_issue_insert AFTER INSERT ON issues BEGIN
+    INSERT INTO team_issue_versions (team_id, version) VALUES (new.team_id, 1)
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_issue_update AFTER UPDATE ON issues BEGIN
+    INSERT INTO team_issue_versions (team_id, version) VALUES (old.team_id, 1)
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+    INSERT INTO team_issue_versions (team_id, version) SELECT new.team_id, 1 WHERE new.team_id != old.team_id
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_issue_delete AFTER DELETE ON issues BEGIN
+    INSERT INTO team_issue_versions (team_id, version) VALUES (old.team_id, 1)
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_assignee_insert AFTER INSERT ON issue_assignees BEGIN
+    INSERT INTO team_issue_versions (team_id, version) SELECT team_id, 1 FROM issues WHERE id = new.issue_id
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_assignee_delete AFTER DELETE ON issue_assignees BEGIN
+    INSERT INTO team_issue_versions (team_id, version) SELECT team_id, 1 FROM issues WHERE id = old.issue_id
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
@@ -0,0 +1,4 @@
+ALTER TABLE view_group_bys DROP COLUMN show_empty;
+ALTER TABLE view_group_bys DROP COLUMN descending;
+ALTER TABLE view_group_bys DROP COLUMN group_order;
+ALTER TABLE view_group_bys DROP COLUMN position;
@@ -0,0 +1,5 @@
+-- position คือลำดับชั้นของการจัดกลุ่ม เช่น status แล้วจึง priority
+ALTER TABLE view_group_bys ADD COLUMN position INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE view_group_bys ADD COLUMN group_order TEXT NOT NULL DEFAULT 'natural' CHECK (group_order IN ('natural', 'value', 'count'));
+ALTER TABLE view_group_bys ADD COLUMN descending BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE view_group_bys ADD COLUMN show_empty BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS team_issue_versions_view_rank_update;
+DROP TRIGGER IF EXISTS team_issue_versions_view_rank_insert;
+DROP TABLE IF EXISTS view_issue_ranks;
+DROP INDEX IF EXISTS idx_issues_board;
+ALTER TABLE issues DROP COLUMN rank;
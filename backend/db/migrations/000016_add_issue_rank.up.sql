@@ -0,0 +1,32 @@
+-- rank คือตำแหน่งของ issue ในคอลัมน์ของ board ทีม เรียงแบบข้อความ (lexorank)
+ALTER TABLE issues ADD COLUMN rank TEXT NOT NULL DEFAULT '';
+
+-- issue เดิมเรียงตามลำดับที่ view แสดงอยู่ก่อนหน้า คือ start_date ใหม่สุดก่อน
+UPDATE issues SET rank = (
+    SELECT printf('%06di', r.n) FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY team_id, status ORDER BY start_date DESC, id) AS n
+        FROM issues
+    ) r WHERE r.id = issues.id
+);
+
+CREATE INDEX idx_issues_board ON issues (team_id, status, rank);
+
+-- ตำแหน่งบน board ของ view ที่ถูกลากเปลี่ยน ส่วน issue ที่ไม่มีแถวที่นี่ใช้ rank ของทีม
+CREATE TABLE view_issue_ranks (
+    view_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    rank TEXT NOT NULL,
+    PRIMARY KEY (view_id, issue_id),
+    FOREIGN KEY (view_id) REFERENCES views(id) ON DELETE CASCADE,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER team_issue_versions_view_rank_insert AFTER INSERT ON view_issue_ranks BEGIN
+    INSERT INTO team_issue_versions (team_id, version) SELECT team_id, 1 FROM issues WHERE id = new.issue_id
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_view_rank_update AFTER UPDATE ON view_issue_ranks BEGIN
+    INSERT INTO team_issue_versions (team_id, version) SELECT team_id, 1 FROM issues WHERE id = new.issue_id
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
@@ -0,0 +1,74 @@
+DROP TRIGGER IF EXISTS team_statuses_delete;
+DROP TRIGGER IF EXISTS issues_status_update;
+DROP TRIGGER IF EXISTS issues_status_insert;
+
+-- สถานะที่ทีมสร้างเองกลับไปเป็นสถานะเดิมตาม category
+UPDATE issues SET status = COALESCE((
+    SELECT CASE ts.category WHEN 'unstarted' THEN 'todo' WHEN 'started' THEN 'doing' ELSE 'done' END
+    FROM team_statuses ts
+    WHERE ts.team_id = issues.team_id AND ts.key = issues.status
+), 'todo')
+WHERE status NOT IN ('todo', 'doing', 'done');
+
+CREATE TABLE issues_old (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    content TEXT,
+    priority TEXT CHECK(priority IN ('low', 'medium', 'high')),
+    status TEXT CHECK(status IN ('todo', 'doing', 'done')) NOT NULL,
+    project_id TEXT,
+    team_id TEXT NOT NULL,
+    start_date DATETIME,
+    end_date DATETIME,
+    label TEXT,
+    owner_id TEXT NOT NULL,
+    rank TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (team_id) REFERENCES teams(id),
+    FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+
+INSERT INTO issues_old (id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id, rank)
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id, rank FROM issues;
+
+DROP TABLE issues;
+PRAGMA legacy_alter_table = ON;
+ALTER TABLE issues_old RENAME TO issues;
+PRAGMA legacy_alter_table = OFF;
+
+CREATE INDEX idx_issues_board ON issues (team_id, status, rank);
+
+CREATE TRIGGER issues_fts_insert AFTER INSERT ON issues BEGIN
+    INSERT INTO issues_fts (issue_id, title, content)
+    VALUES (new.id, new.title, COALESCE(new.content, ''));
+END;
+
+CREATE TRIGGER issues_fts_update AFTER UPDATE OF id, title, content ON issues BEGIN
+    DELETE FROM issues_fts WHERE issue_id = old.id;
+    INSERT INTO issues_fts (issue_id, title, content)
+    VALUES (new.id, new.title, COALESCE(new.content, ''));
+END;
+
+CREATE TRIGGER issues_fts_delete AFTER DELETE ON issues BEGIN
+    DELETE FROM issues_fts WHERE issue_id = old.id;
+END;
+
+CREATE TRIGGER team_issue_versions_issue_insert AFTER INSERT ON issues BEGIN
+    INSERT INTO team_issue_versions (team_id, version) VALUES (new.team_id, 1)
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_issue_update AFTER UPDATE ON issues BEGIN
+    INSERT INTO team_issue_versions (team_id, version) VALUES (old.team_id, 1)
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+    INSERT INTO team_issue_versions (team_id, version) SELECT new.team_id, 1 WHERE new.team_id != old.team_id
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_issue_delete AFTER DELETE ON issues BEGIN
+    INSERT INTO team_issue_versions (team_id, version) VALUES (old.team_id, 1)
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+DROP TRIGGER IF EXISTS team_statuses_team_insert;
+DROP TABLE IF EXISTS team_statuses;
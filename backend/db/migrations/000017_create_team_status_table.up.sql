@@ -0,0 +1,108 @@
+-- สถานะของ issue กำหนดได้เองในแต่ละทีม issues.status เก็บ key ของสถานะในทีมของ issue
+CREATE TABLE team_statuses (
+    team_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL CHECK (category IN ('unstarted', 'started', 'completed', 'cancelled')),
+    position INTEGER NOT NULL,
+    color TEXT NOT NULL,
+    PRIMARY KEY (team_id, key),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+-- ทีมเดิมได้สถานะ todo, doing, done ชุดเดิม issue เดิมจึงใช้ status เดิมได้ทันที
+INSERT INTO team_statuses (team_id, key, name, category, position, color)
+SELECT t.id, s.key, s.name, s.category, s.position, s.color
+FROM teams t, (
+    SELECT 'todo' AS key, 'Todo' AS name, 'unstarted' AS category, 0 AS position, '#bec2c8' AS color
+    UNION ALL SELECT 'doing', 'Doing', 'started', 1, '#f2c94c'
+    UNION ALL SELECT 'done', 'Done', 'completed', 2, '#5e6ad2'
+) s;
+
+CREATE TRIGGER team_statuses_team_insert AFTER INSERT ON teams BEGIN
+    INSERT INTO team_statuses (team_id, key, name, category, position, color) VALUES
+        (new.id, 'todo', 'Todo', 'unstarted', 0, '#bec2c8'),
+        (new.id, 'doing', 'Doing', 'started', 1, '#f2c94c'),
+        (new.id, 'done', 'Done', 'completed', 2, '#5e6ad2');
+END;
+
+-- SQLite ลบ CHECK ไม่ได้ ต้องสร้างตาราง issues ใหม่แล้วสร้าง index และ trigger ที่หายไปกับตารางเดิมกลับมา
+CREATE TABLE issues_new (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    content TEXT,
+    priority TEXT CHECK(priority IN ('low', 'medium', 'high')),
+    status TEXT NOT NULL,
+    project_id TEXT,
+    team_id TEXT NOT NULL,
+    start_date DATETIME,
+    end_date DATETIME,
+    label TEXT,
+    owner_id TEXT NOT NULL,
+    rank TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (project_id) REFERENCES projects(id),
+    FOREIGN KEY (team_id) REFERENCES teams(id),
+    FOREIGN KEY (owner_id) REFERENCES users(id)
+);
+
+INSERT INTO issues_new (id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id, rank)
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id, rank FROM issues;
+
+-- trigger ของตารางอื่นยังอ้างถึง issues อยู่ ต้องเปลี่ยนชื่อแบบเดิมที่ไม่ตรวจ trigger เหล่านั้น
+DROP TABLE issues;
+PRAGMA legacy_alter_table = ON;
+ALTER TABLE issues_new RENAME TO issues;
+PRAGMA legacy_alter_table = OFF;
+
+CREATE INDEX idx_issues_board ON issues (team_id, status, rank);
+
+CREATE TRIGGER issues_fts_insert AFTER INSERT ON issues BEGIN
+    INSERT INTO issues_fts (issue_id, title, content)
+    VALUES (new.id, new.title, COALESCE(new.content, ''));
+END;
+
+CREATE TRIGGER issues_fts_update AFTER UPDATE OF id, title, content ON issues BEGIN
+    DELETE FROM issues_fts WHERE issue_id = old.id;
+    INSERT INTO issues_fts (issue_id, title, content)
+    VALUES (new.id, new.title, COALESCE(new.content, ''));
+END;
+
+CREATE TRIGGER issues_fts_delete AFTER DELETE ON issues BEGIN
+    DELETE FROM issues_fts WHERE issue_id = old.id;
+END;
+
+CREATE TRIGGER team_issue_versions_issue_insert AFTER INSERT ON issues BEGIN
+    INSERT INTO team_issue_versions (team_id, version) VALUES (new.team_id, 1)
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_issue_update AFTER UPDATE ON issues BEGIN
+    INSERT INTO team_issue_versions (team_id, version) VALUES (old.team_id, 1)
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+    INSERT INTO team_issue_versions (team_id, version) SELECT new.team_id, 1 WHERE new.team_id != old.team_id
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_issue_delete AFTER DELETE ON issues BEGIN
+    INSERT INTO team_issue_versions (team_id, version) VALUES (old.team_id, 1)
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+-- แทน CHECK เดิม: status ต้องเป็นสถานะที่มีอยู่ในทีมของ issue
+CREATE TRIGGER issues_status_insert BEFORE INSERT ON issues
+WHEN NOT EXISTS (SELECT 1 FROM team_statuses WHERE team_id = new.team_id AND key = new.status)
+BEGIN
+    SELECT RAISE(ABORT, 'status does not exist in the team of the issue');
+END;
+
+CREATE TRIGGER issues_status_update BEFORE UPDATE OF status, team_id ON issues
+WHEN NOT EXISTS (SELECT 1 FROM team_statuses WHERE team_id = new.team_id AND key = new.status)
+BEGIN
+    SELECT RAISE(ABORT, 'status does not exist in the team of the issue');
+END;
+
+CREATE TRIGGER team_statuses_delete BEFORE DELETE ON team_statuses
+WHEN EXISTS (SELECT 1 FROM issues WHERE team_id = old.team_id AND status = old.key)
+BEGIN
+    SELECT RAISE(ABORT, 'status is still used by issues');
+END;
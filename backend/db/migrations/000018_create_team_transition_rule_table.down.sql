@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_team_transition_rules_team;
+DROP TABLE IF EXISTS team_transition_rules;
@@ -0,0 +1,17 @@
+-- กฎการเปลี่ยนสถานะของ issue ในทีม แต่ละแถวคือเงื่อนไขหนึ่งข้อของการย้ายไปยังสถานะหรือ category ปลายทาง
+-- from_status ว่างหมายถึงย้ายมาจากสถานะใดก็ได้
+CREATE TABLE team_transition_rules (
+    id TEXT PRIMARY KEY,
+    team_id TEXT NOT NULL,
+    from_status TEXT,
+    to_status TEXT,
+    to_category TEXT CHECK (to_category IN ('unstarted', 'started', 'completed', 'cancelled')),
+    requirement TEXT NOT NULL CHECK (requirement IN ('assignee', 'end_date', 'actor_is_assignee', 'forbidden')),
+    created_at DATETIME NOT NULL,
+    CHECK ((to_status IS NULL) != (to_category IS NULL)),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (team_id, from_status) REFERENCES team_statuses(team_id, key) ON DELETE CASCADE,
+    FOREIGN KEY (team_id, to_status) REFERENCES team_statuses(team_id, key) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_team_transition_rules_team ON team_transition_rules (team_id, created_at);
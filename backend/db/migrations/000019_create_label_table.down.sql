@@ -0,0 +1,16 @@
+ALTER TABLE issues ADD COLUMN label TEXT;
+ALTER TABLE projects ADD COLUMN label TEXT NULL;
+
+-- กลับเป็นข้อความได้แค่ label เดียว จึงเก็บชื่อแรกตามตัวอักษร
+UPDATE issues SET label = (
+    SELECT MIN(l.name) FROM issue_labels il JOIN labels l ON l.id = il.label_id WHERE il.issue_id = issues.id
+);
+UPDATE projects SET label = (
+    SELECT MIN(l.name) FROM project_labels pl JOIN labels l ON l.id = pl.label_id WHERE pl.project_id = projects.id
+);
+
+DROP TRIGGER IF EXISTS team_issue_versions_label_delete;
+DROP TRIGGER IF EXISTS team_issue_versions_label_insert;
+DROP TABLE IF EXISTS project_labels;
+DROP TABLE IF EXISTS issue_labels;
+DROP TABLE IF EXISTS labels;
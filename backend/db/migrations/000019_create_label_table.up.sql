@@ -0,0 +1,70 @@
+-- label ของ workspace ใช้ได้ทุกทีม ส่วน label ที่มี team_id ใช้ได้เฉพาะทีมนั้น
+CREATE TABLE labels (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL,
+    team_id TEXT,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL DEFAULT '#bec2c8',
+    description TEXT,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+-- ชื่อซ้ำกันไม่ได้ในขอบเขตเดียวกัน โดยไม่สนตัวพิมพ์เล็กใหญ่
+CREATE UNIQUE INDEX idx_labels_scope_name ON labels (workspace_id, COALESCE(team_id, ''), name COLLATE NOCASE);
+
+CREATE TABLE issue_labels (
+    issue_id TEXT NOT NULL,
+    label_id TEXT NOT NULL,
+    PRIMARY KEY (issue_id, label_id),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_issue_labels_label ON issue_labels (label_id);
+
+CREATE TABLE project_labels (
+    project_id TEXT NOT NULL,
+    label_id TEXT NOT NULL,
+    PRIMARY KEY (project_id, label_id),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_project_labels_label ON project_labels (label_id);
+
+CREATE TRIGGER team_issue_versions_label_insert AFTER INSERT ON issue_labels BEGIN
+    INSERT INTO team_issue_versions (team_id, version) SELECT team_id, 1 FROM issues WHERE id = new.issue_id
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+CREATE TRIGGER team_issue_versions_label_delete AFTER DELETE ON issue_labels BEGIN
+    INSERT INTO team_issue_versions (team_id, version) SELECT team_id, 1 FROM issues WHERE id = old.issue_id
+    ON CONFLICT (team_id) DO UPDATE SET version = version + 1;
+END;
+
+-- ย้าย label แบบข้อความเดิมมาเป็น label ของ workspace ชื่อที่ต่างกันแค่ตัวพิมพ์หรือช่องว่างรวมเป็นอันเดียว
+INSERT INTO labels (id, workspace_id, name, created_at)
+SELECT lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-'
+        || substr('89ab', 1 + abs(random()) % 4, 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))),
+    workspace_id, MIN(name), CURRENT_TIMESTAMP
+FROM (
+    SELECT t.workspace_id, TRIM(i.label) AS name FROM issues i JOIN teams t ON t.id = i.team_id
+    UNION
+    SELECT workspace_id, TRIM(label) FROM projects
+)
+WHERE name IS NOT NULL AND name != ''
+GROUP BY workspace_id, lower(name);
+
+INSERT INTO issue_labels (issue_id, label_id)
+SELECT i.id, l.id FROM issues i
+JOIN teams t ON t.id = i.team_id
+JOIN labels l ON l.workspace_id = t.workspace_id AND lower(l.name) = lower(TRIM(i.label));
+
+INSERT INTO project_labels (project_id, label_id)
+SELECT p.id, l.id FROM projects p
+JOIN labels l ON l.workspace_id = p.workspace_id AND lower(l.name) = lower(TRIM(p.label));
+
+ALTER TABLE issues DROP COLUMN label;
+ALTER TABLE projects DROP COLUMN label;
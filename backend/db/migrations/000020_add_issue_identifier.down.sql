@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_issues_identifier;
+DROP INDEX IF EXISTS idx_issues_team_number;
+ALTER TABLE issues DROP COLUMN identifier;
+ALTER TABLE issues DROP COLUMN number;
+
+DROP TABLE IF EXISTS team_issue_sequences;
+
+DROP INDEX IF EXISTS idx_teams_workspace_key;
+ALTER TABLE teams DROP COLUMN key;
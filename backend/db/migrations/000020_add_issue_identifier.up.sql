@@ -0,0 +1,47 @@
+-- key ของทีมใช้นำหน้าเลข issue เช่น ENG-142 และซ้ำกันไม่ได้ใน workspace เดียวกัน
+ALTER TABLE teams ADD COLUMN key TEXT NOT NULL DEFAULT '';
+
+-- ทีมเดิมได้ key จากสามตัวแรกของชื่อ ถ้าใช้ไม่ได้ใช้ TEAM แทน ตัวที่ซ้ำกันต่อท้ายด้วยลำดับ
+UPDATE teams SET key = (
+    SELECT CASE WHEN n = 1 THEN candidate ELSE candidate || n END
+    FROM (
+        SELECT id, candidate, ROW_NUMBER() OVER (PARTITION BY workspace_id, candidate ORDER BY rowid) AS n
+        FROM (
+            SELECT id, workspace_id, rowid,
+                   CASE WHEN prefix GLOB '[A-Z]*' AND prefix NOT GLOB '*[^A-Z0-9]*' THEN prefix ELSE 'TEAM' END AS candidate
+            FROM (
+                SELECT id, workspace_id, rowid,
+                       UPPER(SUBSTR(REPLACE(REPLACE(REPLACE(name, ' ', ''), '-', ''), '_', ''), 1, 3)) AS prefix
+                FROM teams
+            )
+        )
+    ) k
+    WHERE k.id = teams.id
+);
+
+CREATE UNIQUE INDEX idx_teams_workspace_key ON teams (workspace_id, key);
+
+-- เลขล่าสุดที่ออกให้ issue ของแต่ละทีม เพิ่มทีละหนึ่งใน statement เดียว
+CREATE TABLE team_issue_sequences (
+    team_id TEXT PRIMARY KEY,
+    last_number INTEGER NOT NULL,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+ALTER TABLE issues ADD COLUMN number INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE issues ADD COLUMN identifier TEXT NOT NULL DEFAULT '';
+
+-- issue เดิมได้เลขตามลำดับวันที่เริ่มภายในทีม
+UPDATE issues SET number = (
+    SELECT n
+    FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY team_id ORDER BY start_date, rowid) AS n FROM issues) x
+    WHERE x.id = issues.id
+);
+
+UPDATE issues SET identifier = (SELECT key FROM teams WHERE teams.id = issues.team_id) || '-' || number;
+
+INSERT INTO team_issue_sequences (team_id, last_number)
+SELECT team_id, MAX(number) FROM issues GROUP BY team_id;
+
+CREATE UNIQUE INDEX idx_issues_team_number ON issues (team_id, number);
+CREATE INDEX idx_issues_identifier ON issues (identifier);
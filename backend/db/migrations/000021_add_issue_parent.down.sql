@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_issues_parent;
+ALTER TABLE issues DROP COLUMN parent_id;
@@ -0,0 +1,4 @@
+-- sub-issue ชี้ไปที่ issue แม่ ลบแม่แล้ว sub-issue ยังอยู่แต่ไม่มีแม่
+ALTER TABLE issues ADD COLUMN parent_id TEXT REFERENCES issues(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_issues_parent ON issues (parent_id);
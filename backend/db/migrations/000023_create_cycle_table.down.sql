@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_issues_cycle;
+ALTER TABLE issues DROP COLUMN cycle_added_at;
+ALTER TABLE issues DROP COLUMN cycle_id;
+DROP TABLE IF EXISTS cycles;
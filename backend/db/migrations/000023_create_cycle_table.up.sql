@@ -0,0 +1,25 @@
+-- รอบการทำงานของทีม เลขรอบนับแยกแต่ละทีม
+-- ตัวเลข *_count เก็บสรุปตอนปิดรอบ ระหว่างรอบยังเปิดอยู่ให้คำนวณสดจาก issues
+CREATE TABLE cycles (
+    id TEXT PRIMARY KEY,
+    team_id TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    name TEXT,
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    closed_at DATETIME,
+    scope_count INTEGER NOT NULL DEFAULT 0,
+    completed_count INTEGER NOT NULL DEFAULT 0,
+    added_count INTEGER NOT NULL DEFAULT 0,
+    rolled_over_count INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    CHECK (end_date > start_date),
+    UNIQUE (team_id, number),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+-- cycle_added_at ใช้แยก issue ที่ถูกเพิ่มเข้ามาหลังรอบเริ่มไปแล้ว
+ALTER TABLE issues ADD COLUMN cycle_id TEXT REFERENCES cycles(id) ON DELETE SET NULL;
+ALTER TABLE issues ADD COLUMN cycle_added_at DATETIME;
+
+CREATE INDEX idx_issues_cycle ON issues (cycle_id);
@@ -0,0 +1,17 @@
+-- milestone ของโปรเจกต์ เรียงตาม position
+CREATE TABLE project_milestones (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    target_date DATETIME,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_project_milestones_project ON project_milestones (project_id, position);
+
+-- ลบ milestone แล้ว issue ยังอยู่ในโปรเจกต์แต่ไม่มี milestone
+ALTER TABLE issues ADD COLUMN milestone_id TEXT REFERENCES project_milestones(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_issues_milestone ON issues (milestone_id);
@@ -0,0 +1,14 @@
+-- รายงานความคืบหน้าของโปรเจกต์ health ล่าสุดแสดงในรายการโปรเจกต์
+CREATE TABLE project_updates (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    health TEXT NOT NULL CHECK (health IN ('on_track', 'at_risk', 'off_track')),
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_project_updates_project ON project_updates (project_id, created_at);
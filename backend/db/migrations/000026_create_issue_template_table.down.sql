@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS issue_template_assignees;
+DROP TABLE IF EXISTS issue_template_labels;
+DROP TABLE IF EXISTS issue_templates;
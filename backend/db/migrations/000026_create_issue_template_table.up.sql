@@ -0,0 +1,35 @@
+-- template ของ issue แยกตามทีม title เป็นรูปแบบที่แทน {title} และ {date} ตอนสร้าง issue
+-- label และ assignee ที่ถูกลบไปแล้วหลุดออกจาก template เอง
+CREATE TABLE issue_templates (
+    id TEXT PRIMARY KEY,
+    team_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    content TEXT,
+    priority TEXT CHECK (priority IN ('low', 'medium', 'high')),
+    project_id TEXT,
+    created_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_issue_templates_team ON issue_templates (team_id, name);
+
+CREATE TABLE issue_template_labels (
+    template_id TEXT NOT NULL,
+    label_id TEXT NOT NULL,
+    PRIMARY KEY (template_id, label_id),
+    FOREIGN KEY (template_id) REFERENCES issue_templates(id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE
+);
+
+CREATE TABLE issue_template_assignees (
+    template_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    PRIMARY KEY (template_id, user_id),
+    FOREIGN KEY (template_id) REFERENCES issue_templates(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,38 @@
+-- issue ที่สร้างซ้ำตามรอบ rule เป็น cron 5 ช่องหรือ RRULE คิดเวลาเป็น UTC
+-- next_run_at เป็น NULL เมื่อไม่มีรอบเหลือแล้ว
+CREATE TABLE recurring_issues (
+    id TEXT PRIMARY KEY,
+    team_id TEXT NOT NULL,
+    template_id TEXT,
+    title TEXT NOT NULL DEFAULT '',
+    content TEXT,
+    priority TEXT CHECK (priority IN ('low', 'medium', 'high')),
+    project_id TEXT,
+    rule TEXT NOT NULL,
+    starts_at DATETIME NOT NULL,
+    next_run_at DATETIME,
+    last_run_at DATETIME,
+    paused BOOLEAN NOT NULL DEFAULT 0,
+    created_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (template_id) REFERENCES issue_templates(id) ON DELETE SET NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE INDEX idx_recurring_issues_team ON recurring_issues (team_id);
+CREATE INDEX idx_recurring_issues_next_run ON recurring_issues (next_run_at);
+
+-- รอบที่สร้างไปแล้ว server จอง (recurring_id, scheduled_for) ก่อนสร้าง issue จึงไม่สร้างซ้ำเมื่อเริ่มใหม่
+CREATE TABLE recurring_issue_runs (
+    recurring_id TEXT NOT NULL,
+    scheduled_for DATETIME NOT NULL,
+    issue_id TEXT,
+    error TEXT,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (recurring_id, scheduled_for),
+    FOREIGN KEY (recurring_id) REFERENCES recurring_issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE SET NULL
+);
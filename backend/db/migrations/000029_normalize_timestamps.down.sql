@@ -0,0 +1,3 @@
+-- ย้อนกลับไม่ได้โดยตั้งใจ: ค่าเดิมในรูปแบบ time.String() ถูกแปลงเป็น UTC ไปแล้ว ทั้ง offset เดิมและส่วน m=+
+-- ของ Go หายไปจึงสร้างคืนไม่ได้ รูปแบบใหม่อ่านได้ทั้ง Go และ SQLite down จึงไม่แก้ข้อมูลและคงค่าที่แปลงแล้วไว้
+SELECT 1;
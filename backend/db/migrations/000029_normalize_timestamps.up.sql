@@ -0,0 +1,77 @@
+-- เวลาที่บันทึกก่อนเปิด _time_format=sqlite อยู่ในรูปแบบ time.String() ของ Go เช่น
+-- '2026-01-05 07:00:00.5 +0700 +07 m=+0.1' ซึ่ง SQLite อ่านไม่ได้ ตัดส่วนวันเวลากับ offset ออกมาเป็น
+-- '2026-01-05 07:00:00.5 +07:00' ให้ SQLite แปลง แล้วเขียนทุกค่าใหม่เป็น UTC รูปแบบเดียวกับที่ driver เขียน
+-- ค่าที่อ่านไม่ได้ทั้งสองแบบคงไว้ตามเดิม
+
+UPDATE projects SET
+    start_date = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', start_date), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(start_date, 1, instr(substr(start_date, 12), ' ') + 14) || ':' || substr(start_date, instr(substr(start_date, 12), ' ') + 15, 2)), start_date),
+    end_date = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', end_date), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(end_date, 1, instr(substr(end_date, 12), ' ') + 14) || ':' || substr(end_date, instr(substr(end_date, 12), ' ') + 15, 2)), end_date);
+
+UPDATE issues SET
+    start_date = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', start_date), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(start_date, 1, instr(substr(start_date, 12), ' ') + 14) || ':' || substr(start_date, instr(substr(start_date, 12), ' ') + 15, 2)), start_date),
+    end_date = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', end_date), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(end_date, 1, instr(substr(end_date, 12), ' ') + 14) || ':' || substr(end_date, instr(substr(end_date, 12), ' ') + 15, 2)), end_date),
+    cycle_added_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', cycle_added_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(cycle_added_at, 1, instr(substr(cycle_added_at, 12), ' ') + 14) || ':' || substr(cycle_added_at, instr(substr(cycle_added_at, 12), ' ') + 15, 2)), cycle_added_at);
+
+UPDATE issue_comments SET
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at),
+    updated_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', updated_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(updated_at, 1, instr(substr(updated_at, 12), ' ') + 14) || ':' || substr(updated_at, instr(substr(updated_at, 12), ' ') + 15, 2)), updated_at);
+
+UPDATE issue_comment_edits SET
+    edited_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', edited_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(edited_at, 1, instr(substr(edited_at, 12), ' ') + 14) || ':' || substr(edited_at, instr(substr(edited_at, 12), ' ') + 15, 2)), edited_at);
+
+UPDATE issue_events SET
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at);
+
+UPDATE sessions SET
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at),
+    last_used_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', last_used_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(last_used_at, 1, instr(substr(last_used_at, 12), ' ') + 14) || ':' || substr(last_used_at, instr(substr(last_used_at, 12), ' ') + 15, 2)), last_used_at),
+    expires_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', expires_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(expires_at, 1, instr(substr(expires_at, 12), ' ') + 14) || ':' || substr(expires_at, instr(substr(expires_at, 12), ' ') + 15, 2)), expires_at),
+    revoked_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', revoked_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(revoked_at, 1, instr(substr(revoked_at, 12), ' ') + 14) || ':' || substr(revoked_at, instr(substr(revoked_at, 12), ' ') + 15, 2)), revoked_at);
+
+UPDATE workspace_invitations SET
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at),
+    expires_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', expires_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(expires_at, 1, instr(substr(expires_at, 12), ' ') + 14) || ':' || substr(expires_at, instr(substr(expires_at, 12), ' ') + 15, 2)), expires_at),
+    responded_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', responded_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(responded_at, 1, instr(substr(responded_at, 12), ' ') + 14) || ':' || substr(responded_at, instr(substr(responded_at, 12), ' ') + 15, 2)), responded_at);
+
+UPDATE team_transition_rules SET
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at);
+
+UPDATE labels SET
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at);
+
+UPDATE issue_relations SET
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at);
+
+UPDATE cycles SET
+    start_date = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', start_date), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(start_date, 1, instr(substr(start_date, 12), ' ') + 14) || ':' || substr(start_date, instr(substr(start_date, 12), ' ') + 15, 2)), start_date),
+    end_date = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', end_date), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(end_date, 1, instr(substr(end_date, 12), ' ') + 14) || ':' || substr(end_date, instr(substr(end_date, 12), ' ') + 15, 2)), end_date),
+    closed_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', closed_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(closed_at, 1, instr(substr(closed_at, 12), ' ') + 14) || ':' || substr(closed_at, instr(substr(closed_at, 12), ' ') + 15, 2)), closed_at),
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at);
+
+UPDATE project_milestones SET
+    target_date = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', target_date), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(target_date, 1, instr(substr(target_date, 12), ' ') + 14) || ':' || substr(target_date, instr(substr(target_date, 12), ' ') + 15, 2)), target_date),
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at);
+
+UPDATE project_updates SET
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at),
+    updated_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', updated_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(updated_at, 1, instr(substr(updated_at, 12), ' ') + 14) || ':' || substr(updated_at, instr(substr(updated_at, 12), ' ') + 15, 2)), updated_at);
+
+UPDATE issue_templates SET
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at),
+    updated_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', updated_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(updated_at, 1, instr(substr(updated_at, 12), ' ') + 14) || ':' || substr(updated_at, instr(substr(updated_at, 12), ' ') + 15, 2)), updated_at);
+
+UPDATE recurring_issues SET
+    starts_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', starts_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(starts_at, 1, instr(substr(starts_at, 12), ' ') + 14) || ':' || substr(starts_at, instr(substr(starts_at, 12), ' ') + 15, 2)), starts_at),
+    next_run_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', next_run_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(next_run_at, 1, instr(substr(next_run_at, 12), ' ') + 14) || ':' || substr(next_run_at, instr(substr(next_run_at, 12), ' ') + 15, 2)), next_run_at),
+    last_run_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', last_run_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(last_run_at, 1, instr(substr(last_run_at, 12), ' ') + 14) || ':' || substr(last_run_at, instr(substr(last_run_at, 12), ' ') + 15, 2)), last_run_at),
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at),
+    updated_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', updated_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(updated_at, 1, instr(substr(updated_at, 12), ' ') + 14) || ':' || substr(updated_at, instr(substr(updated_at, 12), ' ') + 15, 2)), updated_at);
+
+UPDATE recurring_issue_runs SET
+    scheduled_for = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', scheduled_for), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(scheduled_for, 1, instr(substr(scheduled_for, 12), ' ') + 14) || ':' || substr(scheduled_for, instr(substr(scheduled_for, 12), ' ') + 15, 2)), scheduled_for),
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at);
+
+UPDATE notifications SET
+    due_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', due_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(due_at, 1, instr(substr(due_at, 12), ' ') + 14) || ':' || substr(due_at, instr(substr(due_at, 12), ' ') + 15, 2)), due_at),
+    created_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', created_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(created_at, 1, instr(substr(created_at, 12), ' ') + 14) || ':' || substr(created_at, instr(substr(created_at, 12), ' ') + 15, 2)), created_at),
+    read_at = COALESCE(strftime('%Y-%m-%d %H:%M:%f+00:00', read_at), strftime('%Y-%m-%d %H:%M:%f+00:00', substr(read_at, 1, instr(substr(read_at, 12), ' ') + 14) || ':' || substr(read_at, instr(substr(read_at, 12), ' ') + 15, 2)), read_at);
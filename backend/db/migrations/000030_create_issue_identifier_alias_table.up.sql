@@ -0,0 +1,9 @@
+-- identifier เดิมของ issue ก่อนเปลี่ยน key ของทีม ลิงก์อย่าง ENG-142 ที่แชร์ไปแล้วจะได้ยังเปิดได้
+CREATE TABLE issue_identifier_aliases (
+    issue_id TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    PRIMARY KEY (issue_id, identifier),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_issue_identifier_aliases_identifier ON issue_identifier_aliases (identifier);
@@ -0,0 +1,72 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestNormalizeTimestamps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.db")
+	m, err := migrate.New("file://.", "sqlite://"+path)
+	require.NoError(t, err)
+	defer m.Close()
+	require.NoError(t, m.Migrate(28))
+
+	// ค่าเก่าที่บันทึกเป็นข้อความของ time.String() รวม offset ที่ไม่ใช่ UTC และส่วน m=+ ของ time.Now()
+	legacy, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	bangkok := time.FixedZone("+07", 7*3600)
+	end := time.Date(2026, 1, 5, 2, 30, 0, 500_000_000, bangkok)
+	start := time.Now()
+	require.Contains(t, start.String(), " m=+")
+	for _, stmt := range []string{
+		`INSERT INTO users (id, username, password_hash, email, roles) VALUES ('u1', 'alice', 'x', 'a@x.com', 'user')`,
+		`INSERT INTO workspaces (id, name, owner_id) VALUES ('w1', 'Workspace', 'u1')`,
+		`INSERT INTO teams (id, name, workspace_id) VALUES ('t1', 'Team', 'w1')`,
+		`INSERT INTO labels (id, workspace_id, name, color, created_at) VALUES ('l1', 'w1', 'bug', '#000000', CURRENT_TIMESTAMP)`,
+	} {
+		_, err := legacy.Exec(stmt)
+		require.NoError(t, err, stmt)
+	}
+	_, err = legacy.Exec(`INSERT INTO issues (id, title, status, team_id, owner_id, start_date, end_date, cycle_added_at) VALUES ('i1', 'Issue', 'todo', 't1', 'u1', ?, ?, ?)`,
+		start.String(), end.String(), end.Format(time.RFC3339Nano))
+	require.NoError(t, err)
+	require.NoError(t, legacy.Close())
+
+	require.NoError(t, m.Migrate(29))
+
+	conn, err := sql.Open("sqlite", path+"?_time_format=sqlite")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var rawStart, rawEnd, rawAdded string
+	require.NoError(t, conn.QueryRow(`SELECT start_date || '', end_date || '', cycle_added_at || '' FROM issues`).Scan(&rawStart, &rawEnd, &rawAdded))
+	assert.Equal(t, "2026-01-04 19:30:00.500+00:00", rawEnd)
+	assert.Equal(t, "2026-01-04 19:30:00.500+00:00", rawAdded)
+	assert.True(t, strings.HasSuffix(rawStart, "+00:00"), rawStart)
+	assert.NotContains(t, rawStart, "m=")
+
+	var gotStart, gotEnd time.Time
+	require.NoError(t, conn.QueryRow(`SELECT start_date, end_date FROM issues`).Scan(&gotStart, &gotEnd))
+	assert.True(t, gotEnd.Equal(end), gotEnd)
+	assert.Less(t, gotStart.Sub(start).Abs(), time.Millisecond)
+
+	var created time.Time
+	require.NoError(t, conn.QueryRow(`SELECT created_at FROM labels`).Scan(&created))
+	assert.False(t, created.IsZero())
+
+	// down ไม่แก้ข้อมูล ค่าที่แปลงแล้วคงอยู่
+	require.NoError(t, m.Migrate(28))
+	require.NoError(t, conn.QueryRow(`SELECT end_date || '' FROM issues`).Scan(&rawEnd))
+	assert.Equal(t, "2026-01-04 19:30:00.500+00:00", rawEnd)
+}
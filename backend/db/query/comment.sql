@@ -0,0 +1,46 @@
+-- name: CreateComment :exec
+INSERT INTO issue_comments (id, issue_id, author_id, body, created_at)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetCommentByID :one
+SELECT *
+FROM issue_comments
+WHERE id = ?;
+
+-- name: ListCommentsByIssueID :many
+SELECT *
+FROM issue_comments
+WHERE issue_id = ?
+ORDER BY created_at ASC;
+
+-- name: UpdateCommentBody :exec
+UPDATE issue_comments
+SET body = ?, updated_at = ?
+WHERE id = ?;
+
+-- name: DeleteComment :exec
+DELETE FROM issue_comments WHERE id = ?;
+
+-- name: CreateCommentEdit :exec
+INSERT INTO issue_comment_edits (id, comment_id, previous_body, edited_by, edited_at)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: ListCommentEditsByCommentID :many
+SELECT *
+FROM issue_comment_edits
+WHERE comment_id = ?
+ORDER BY edited_at DESC;
+
+-- name: AddMentionToComment :exec
+INSERT OR IGNORE INTO issue_comment_mentions (comment_id, user_id)
+VALUES (?, ?);
+
+-- name: ClearMentionsFromComment :exec
+DELETE FROM issue_comment_mentions
+WHERE comment_id = ?;
+
+-- name: ListMentionsByCommentID :many
+SELECT u.id, u.username
+FROM users u
+JOIN issue_comment_mentions m ON u.id = m.user_id
+WHERE m.comment_id = ?;
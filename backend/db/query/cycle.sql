@@ -0,0 +1,56 @@
+-- name: CreateCycle :exec
+INSERT INTO cycles (id, team_id, number, name, start_date, end_date, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetCycleByID :one
+SELECT * FROM cycles
+WHERE id = ?;
+
+-- name: ListTeamCycles :many
+SELECT * FROM cycles
+WHERE team_id = ?
+ORDER BY number;
+
+-- name: GetNextCycleNumber :one
+SELECT CAST(COALESCE(MAX(number), 0) + 1 AS INTEGER) AS next_number
+FROM cycles
+WHERE team_id = ?;
+
+-- name: GetNextOpenCycle :one
+SELECT * FROM cycles
+WHERE team_id = ? AND number > ? AND closed_at IS NULL
+ORDER BY number
+LIMIT 1;
+
+-- name: UpdateCycle :exec
+UPDATE cycles SET name = ?, start_date = ?, end_date = ?
+WHERE id = ?;
+
+-- name: DeleteCycle :exec
+DELETE FROM cycles WHERE id = ?;
+
+-- name: CloseCycle :execrows
+UPDATE cycles
+SET closed_at = ?, scope_count = ?, completed_count = ?, added_count = ?, rolled_over_count = ?
+WHERE id = ? AND closed_at IS NULL;
+
+-- name: GetCycleStats :one
+SELECT
+    CAST(COUNT(i.id) AS INTEGER) AS scope,
+    CAST(COALESCE(SUM(s.category = 'completed'), 0) AS INTEGER) AS completed,
+    CAST(COALESCE(SUM(julianday(i.cycle_added_at) > julianday(c.start_date)), 0) AS INTEGER) AS added
+FROM issues i
+JOIN cycles c ON c.id = i.cycle_id
+LEFT JOIN team_statuses s ON s.team_id = i.team_id AND s.key = i.status
+WHERE i.cycle_id = ? AND COALESCE(s.category, '') != 'cancelled';
+
+-- name: ListUnfinishedCycleIssueIDs :many
+SELECT i.id
+FROM issues i
+LEFT JOIN team_statuses s ON s.team_id = i.team_id AND s.key = i.status
+WHERE i.cycle_id = ? AND COALESCE(s.category, '') NOT IN ('completed', 'cancelled')
+ORDER BY i.number;
+
+-- name: SetIssueCycle :exec
+UPDATE issues SET cycle_id = ?, cycle_added_at = ?
+WHERE id = ?;
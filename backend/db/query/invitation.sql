@@ -0,0 +1,34 @@
+-- name: CreateInvitation :exec
+INSERT INTO workspace_invitations (id, workspace_id, email, role, token_hash, invited_by, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetInvitationByID :one
+SELECT * FROM workspace_invitations
+WHERE id = ?;
+
+-- name: ListInvitationsByWorkspaceID :many
+SELECT * FROM workspace_invitations
+WHERE workspace_id = ?
+ORDER BY created_at DESC;
+
+-- name: ListPendingInvitationsByEmail :many
+SELECT wi.id, wi.workspace_id, w.name AS workspace_name, wi.email, wi.role, wi.invited_by, wi.created_at, wi.expires_at
+FROM workspace_invitations wi
+JOIN workspaces w ON w.id = wi.workspace_id
+WHERE wi.email = ? AND wi.status = 'pending'
+ORDER BY wi.created_at DESC;
+
+-- name: RevokePendingInvitationsByEmail :exec
+UPDATE workspace_invitations
+SET status = 'revoked', responded_at = ?
+WHERE workspace_id = ? AND email = ? AND status = 'pending';
+
+-- name: RevokeInvitation :execrows
+UPDATE workspace_invitations
+SET status = 'revoked', responded_at = ?
+WHERE id = ? AND workspace_id = ? AND status = 'pending';
+
+-- name: RespondToInvitation :execrows
+UPDATE workspace_invitations
+SET status = ?, responded_at = ?
+WHERE id = ? AND token_hash = ? AND status = 'pending';
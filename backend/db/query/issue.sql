@@ -1,9 +1,9 @@
 -- name: CreateIssue :exec
 INSERT INTO issues (
     id, title, content, priority, status, project_id, team_id,
-    start_date, end_date, label, owner_id
+    start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id
 )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetIssueByID :one
 SELECT *
@@ -45,3 +45,100 @@ SELECT DISTINCT i.*
 FROM issues i
 LEFT JOIN issue_assignees ia ON i.id = ia.issue_id
 WHERE i.owner_id = ? OR ia.user_id = ?;
+
+-- name: CreateIssueEvent :exec
+INSERT INTO issue_events (id, issue_id, actor_id, field, old_value, new_value, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListIssueEvents :many
+SELECT *
+FROM issue_events
+WHERE issue_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountIssueEvents :one
+SELECT COUNT(*) AS count
+FROM issue_events
+WHERE issue_id = ?;
+
+-- name: GetIssueBoardRank :one
+SELECT i.id, i.team_id, i.status, COALESCE(vr.rank, i.rank) AS rank
+FROM issues i
+LEFT JOIN view_issue_ranks vr ON vr.issue_id = i.id AND vr.view_id = ?
+WHERE i.id = ?;
+
+-- name: GetLastIssueBoardRank :one
+SELECT CAST(COALESCE(MAX(COALESCE(vr.rank, i.rank)), '') AS TEXT) AS rank
+FROM issues i
+LEFT JOIN view_issue_ranks vr ON vr.issue_id = i.id AND vr.view_id = ?
+WHERE i.team_id = ? AND i.status = ? AND i.id != ?;
+
+-- name: UpdateIssueBoardPosition :exec
+UPDATE issues SET status = ?, rank = ? WHERE id = ?;
+
+-- name: UpdateIssueStatus :exec
+UPDATE issues SET status = ? WHERE id = ?;
+
+-- name: SetViewIssueRank :exec
+INSERT INTO view_issue_ranks (view_id, issue_id, rank)
+VALUES (?, ?, ?)
+ON CONFLICT (view_id, issue_id) DO UPDATE SET rank = excluded.rank;
+
+-- name: NextIssueNumber :one
+INSERT INTO team_issue_sequences (team_id, last_number)
+VALUES (?, 1)
+ON CONFLICT (team_id) DO UPDATE SET last_number = last_number + 1
+RETURNING last_number;
+
+-- name: GetTeamKey :one
+SELECT key
+FROM teams
+WHERE id = ?;
+
+-- name: ListIssueIDsByIdentifier :many
+SELECT i.id
+FROM issues i
+JOIN teams t ON t.id = i.team_id
+JOIN workspace_members wm ON wm.workspace_id = t.workspace_id
+WHERE i.identifier = ? AND wm.user_id = ?;
+
+-- name: ListIssueIDsByIdentifierAlias :many
+SELECT a.issue_id
+FROM issue_identifier_aliases a
+JOIN issues i ON i.id = a.issue_id
+JOIN teams t ON t.id = i.team_id
+JOIN workspace_members wm ON wm.workspace_id = t.workspace_id
+WHERE a.identifier = ? AND wm.user_id = ?;
+
+-- name: ListChildIssues :many
+SELECT *
+FROM issues
+WHERE parent_id = ?
+ORDER BY rank, id;
+
+-- name: ListIssueDescendants :many
+WITH RECURSIVE descendants(id) AS (
+    SELECT id FROM issues WHERE parent_id = ?
+    UNION
+    SELECT c.id FROM issues c JOIN descendants d ON c.parent_id = d.id
+)
+SELECT i.*
+FROM issues i
+JOIN descendants d ON d.id = i.id;
+
+-- name: ListIssueAncestorIDs :many
+WITH RECURSIVE ancestors(id) AS (
+    SELECT parent_id FROM issues WHERE id = ? AND parent_id IS NOT NULL
+    UNION
+    SELECT p.parent_id FROM issues p JOIN ancestors a ON p.id = a.id WHERE p.parent_id IS NOT NULL
+)
+SELECT CAST(id AS TEXT) AS id
+FROM ancestors;
+
+-- name: GetSubIssueProgress :one
+SELECT COUNT(*) AS total,
+       CAST(COALESCE(SUM(ts.category = 'completed'), 0) AS INTEGER) AS completed
+FROM issues i
+LEFT JOIN team_statuses ts ON ts.team_id = i.team_id AND ts.key = i.status
+WHERE i.parent_id = ? AND COALESCE(ts.category, '') != 'cancelled';
@@ -0,0 +1,44 @@
+-- name: CreateIssueTemplate :exec
+INSERT INTO issue_templates (id, team_id, name, title, content, priority, project_id, created_by, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetIssueTemplateByID :one
+SELECT * FROM issue_templates
+WHERE id = ?;
+
+-- name: ListTeamIssueTemplates :many
+SELECT * FROM issue_templates
+WHERE team_id = ?
+ORDER BY name;
+
+-- name: UpdateIssueTemplate :exec
+UPDATE issue_templates
+SET name = ?, title = ?, content = ?, priority = ?, project_id = ?, updated_at = ?
+WHERE id = ?;
+
+-- name: DeleteIssueTemplate :exec
+DELETE FROM issue_templates WHERE id = ?;
+
+-- name: AddIssueTemplateLabel :exec
+INSERT OR IGNORE INTO issue_template_labels (template_id, label_id)
+VALUES (?, ?);
+
+-- name: ClearIssueTemplateLabels :exec
+DELETE FROM issue_template_labels WHERE template_id = ?;
+
+-- name: ListIssueTemplateLabelIDs :many
+SELECT label_id FROM issue_template_labels
+WHERE template_id = ?
+ORDER BY label_id;
+
+-- name: AddIssueTemplateAssignee :exec
+INSERT OR IGNORE INTO issue_template_assignees (template_id, user_id)
+VALUES (?, ?);
+
+-- name: ClearIssueTemplateAssignees :exec
+DELETE FROM issue_template_assignees WHERE template_id = ?;
+
+-- name: ListIssueTemplateAssigneeIDs :many
+SELECT user_id FROM issue_template_assignees
+WHERE template_id = ?
+ORDER BY user_id;
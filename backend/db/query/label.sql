@@ -0,0 +1,63 @@
+-- name: CreateLabel :exec
+INSERT INTO labels (id, workspace_id, team_id, name, color, description, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLabelByID :one
+SELECT * FROM labels
+WHERE id = ?;
+
+-- name: ListWorkspaceLabels :many
+SELECT * FROM labels
+WHERE workspace_id = ? AND team_id IS NULL
+ORDER BY name COLLATE NOCASE, id;
+
+-- name: ListTeamLabels :many
+SELECT l.* FROM labels l
+JOIN teams t ON t.workspace_id = l.workspace_id
+WHERE t.id = sqlc.arg(team_id) AND (l.team_id IS NULL OR l.team_id = t.id)
+ORDER BY l.name COLLATE NOCASE, l.id;
+
+-- name: UpdateLabel :exec
+UPDATE labels SET name = ?, color = ?, description = ?
+WHERE id = ?;
+
+-- name: DeleteLabel :exec
+DELETE FROM labels WHERE id = ?;
+
+-- name: ListLabelsByIssueID :many
+SELECT l.* FROM labels l
+JOIN issue_labels il ON il.label_id = l.id
+WHERE il.issue_id = ?
+ORDER BY l.name COLLATE NOCASE, l.id;
+
+-- name: ListLabelsByProjectID :many
+SELECT l.* FROM labels l
+JOIN project_labels pl ON pl.label_id = l.id
+WHERE pl.project_id = ?
+ORDER BY l.name COLLATE NOCASE, l.id;
+
+-- name: AddLabelToIssue :exec
+INSERT OR IGNORE INTO issue_labels (issue_id, label_id)
+VALUES (?, ?);
+
+-- name: RemoveLabelFromIssue :exec
+DELETE FROM issue_labels
+WHERE issue_id = ? AND label_id = ?;
+
+-- name: AddLabelToProject :exec
+INSERT OR IGNORE INTO project_labels (project_id, label_id)
+VALUES (?, ?);
+
+-- name: RemoveLabelFromProject :exec
+DELETE FROM project_labels
+WHERE project_id = ? AND label_id = ?;
+
+-- name: MoveIssueLabels :exec
+INSERT OR IGNORE INTO issue_labels (issue_id, label_id)
+SELECT issue_id, CAST(sqlc.arg(target_id) AS TEXT) FROM issue_labels
+WHERE label_id = sqlc.arg(source_id);
+
+-- name: MoveProjectLabels :exec
+INSERT OR IGNORE INTO project_labels (project_id, label_id)
+SELECT project_id, CAST(sqlc.arg(target_id) AS TEXT) FROM project_labels
+WHERE label_id = sqlc.arg(source_id);
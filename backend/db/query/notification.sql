@@ -0,0 +1,25 @@
+-- name: CreateNotification :execrows
+INSERT OR IGNORE INTO notifications (id, user_id, issue_id, type, due_at, created_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListUserNotifications :many
+SELECT n.id, n.issue_id, n.type, n.due_at, n.created_at, n.read_at, i.identifier, i.title, i.team_id
+FROM notifications n
+JOIN issues i ON i.id = n.issue_id
+WHERE n.user_id = ?
+ORDER BY n.created_at DESC, n.id
+LIMIT ? OFFSET ?;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = ? AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, sqlc.arg(read_at))
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id);
+
+-- name: MarkAllNotificationsRead :exec
+UPDATE notifications
+SET read_at = ?
+WHERE user_id = ? AND read_at IS NULL;
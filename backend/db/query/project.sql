@@ -1,11 +1,11 @@
 -- name: CreateProject :exec
 INSERT INTO projects (
-  id, name, status, priority, workspace_id, team_id, leader_id, start_date, end_date, label, created_by
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+  id, name, status, priority, workspace_id, team_id, leader_id, start_date, end_date, created_by
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 
 -- name: GetProjectByID :one
-SELECT id, name, status, priority, workspace_id, team_id, leader_id, start_date, end_date, label, created_by
+SELECT id, name, status, priority, workspace_id, team_id, leader_id, start_date, end_date, created_by
 FROM projects
 WHERE id = ?;
 
@@ -14,7 +14,7 @@ WHERE id = ?;
 DELETE FROM projects WHERE id = ?;
 
 -- name: ListProjectsByWorkspace :many
-SELECT id, name, status, priority, workspace_id, leader_id, start_date, end_date, label
+SELECT id, name, status, priority, workspace_id, leader_id, start_date, end_date
 FROM projects
 WHERE workspace_id = ?
 ORDER BY start_date DESC;
@@ -28,9 +28,15 @@ WHERE pm.project_id = ?;
 
 
 -- name: GetProjectsByUserID :many
-SELECT DISTINCT p.*
+SELECT DISTINCT p.*, lu.health AS latest_health, lu.created_at AS latest_update_at
 FROM projects p
 LEFT JOIN project_members pm ON p.id = pm.project_id
+LEFT JOIN project_updates lu ON lu.id = (
+  SELECT pu.id FROM project_updates pu
+  WHERE pu.project_id = p.id
+  ORDER BY pu.created_at DESC, pu.id DESC
+  LIMIT 1
+)
 WHERE pm.user_id = ? OR p.created_by = ?;
 
 -- name: IsProjectExists :one
@@ -38,6 +44,11 @@ SELECT COUNT(*) AS count
 FROM projects
 WHERE id = ?;
 
+-- name: IsProjectMember :one
+SELECT COUNT(*) AS count
+FROM project_members
+WHERE project_id = ? AND user_id = ?;
+
 -- name: GetOwnerByProjectID :one
 SELECT created_by
 FROM projects
@@ -54,4 +65,64 @@ VALUES (?, ?);
 
 -- name: RemoveMemberFromProject :exec
 DELETE FROM project_members
-WHERE project_id = ? AND user_id = ?;
\ No newline at end of file
+WHERE project_id = ? AND user_id = ?;
+
+-- name: CreateProjectMilestone :exec
+INSERT INTO project_milestones (id, project_id, name, target_date, position, created_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetProjectMilestone :one
+SELECT * FROM project_milestones
+WHERE id = ?;
+
+-- name: ListProjectMilestones :many
+SELECT * FROM project_milestones
+WHERE project_id = ?
+ORDER BY position, created_at;
+
+-- name: UpdateProjectMilestone :exec
+UPDATE project_milestones SET name = ?, target_date = ?
+WHERE id = ?;
+
+-- name: SetProjectMilestonePosition :exec
+UPDATE project_milestones SET position = ?
+WHERE id = ?;
+
+-- name: DeleteProjectMilestone :exec
+DELETE FROM project_milestones WHERE id = ?;
+
+-- name: ListMilestoneProgress :many
+SELECT i.milestone_id,
+       COUNT(*) AS total,
+       CAST(COALESCE(SUM(ts.category = 'completed'), 0) AS INTEGER) AS completed
+FROM issues i
+LEFT JOIN team_statuses ts ON ts.team_id = i.team_id AND ts.key = i.status
+WHERE i.project_id = ? AND i.milestone_id IS NOT NULL AND COALESCE(ts.category, '') != 'cancelled'
+GROUP BY i.milestone_id;
+
+-- name: GetProjectProgress :one
+SELECT COUNT(*) AS total,
+       CAST(COALESCE(SUM(ts.category = 'completed'), 0) AS INTEGER) AS completed
+FROM issues i
+LEFT JOIN team_statuses ts ON ts.team_id = i.team_id AND ts.key = i.status
+WHERE i.project_id = ? AND COALESCE(ts.category, '') != 'cancelled';
+
+-- name: CreateProjectUpdate :exec
+INSERT INTO project_updates (id, project_id, author_id, health, body, created_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetProjectUpdate :one
+SELECT * FROM project_updates
+WHERE id = ?;
+
+-- name: ListProjectUpdates :many
+SELECT * FROM project_updates
+WHERE project_id = ?
+ORDER BY created_at DESC, id DESC;
+
+-- name: EditProjectUpdate :exec
+UPDATE project_updates SET health = ?, body = ?, updated_at = ?
+WHERE id = ?;
+
+-- name: DeleteProjectUpdate :exec
+DELETE FROM project_updates WHERE id = ?;
@@ -0,0 +1,45 @@
+-- name: CreateRecurringIssue :exec
+INSERT INTO recurring_issues (id, team_id, template_id, title, content, priority, project_id, rule, starts_at, next_run_at, paused, created_by, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetRecurringIssueByID :one
+SELECT * FROM recurring_issues
+WHERE id = ?;
+
+-- name: ListTeamRecurringIssues :many
+SELECT * FROM recurring_issues
+WHERE team_id = ?
+ORDER BY created_at;
+
+-- name: UpdateRecurringIssue :exec
+UPDATE recurring_issues
+SET template_id = ?, title = ?, content = ?, priority = ?, project_id = ?, rule = ?, starts_at = ?, next_run_at = ?, paused = ?, updated_at = ?
+WHERE id = ?;
+
+-- name: DeleteRecurringIssue :exec
+DELETE FROM recurring_issues WHERE id = ?;
+
+-- name: ListDueRecurringIssues :many
+SELECT * FROM recurring_issues
+WHERE paused = 0 AND next_run_at IS NOT NULL AND next_run_at <= sqlc.arg(now)
+ORDER BY next_run_at;
+
+-- name: AdvanceRecurringIssue :exec
+UPDATE recurring_issues
+SET next_run_at = ?, last_run_at = ?
+WHERE id = ?;
+
+-- name: ClaimRecurringIssueRun :execrows
+INSERT OR IGNORE INTO recurring_issue_runs (recurring_id, scheduled_for, created_at)
+VALUES (?, ?, ?);
+
+-- name: FinishRecurringIssueRun :exec
+UPDATE recurring_issue_runs
+SET issue_id = ?, error = ?
+WHERE recurring_id = ? AND scheduled_for = ?;
+
+-- name: ListRecurringIssueRuns :many
+SELECT * FROM recurring_issue_runs
+WHERE recurring_id = ?
+ORDER BY scheduled_for DESC
+LIMIT ?;
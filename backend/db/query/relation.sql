@@ -0,0 +1,32 @@
+-- name: CreateIssueRelation :exec
+INSERT INTO issue_relations (id, issue_id, related_issue_id, type, created_by, created_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetIssueRelation :one
+SELECT * FROM issue_relations
+WHERE id = ?;
+
+-- name: DeleteIssueRelation :exec
+DELETE FROM issue_relations WHERE id = ?;
+
+-- name: ListIssueRelations :many
+SELECT r.id, r.type, i.id AS related_issue_id, i.identifier, i.title, i.status, r.created_by, r.created_at
+FROM (
+    SELECT id, related_issue_id AS other_id, type, created_by, created_at
+    FROM issue_relations
+    WHERE issue_id = ?
+    UNION ALL
+    SELECT id, issue_id, CAST(CASE type WHEN 'blocks' THEN 'blocked_by' WHEN 'duplicates' THEN 'duplicated_by' ELSE type END AS TEXT), created_by, created_at
+    FROM issue_relations
+    WHERE related_issue_id = ?
+) r
+JOIN issues i ON i.id = r.other_id
+ORDER BY r.created_at, r.id;
+
+-- name: ListOpenBlockedIssues :many
+SELECT i.id, i.identifier, i.title, i.status
+FROM issue_relations r
+JOIN issues i ON i.id = r.related_issue_id
+JOIN team_statuses s ON s.team_id = i.team_id AND s.key = i.status
+WHERE r.issue_id = ? AND r.type = 'blocks' AND s.category NOT IN ('completed', 'cancelled')
+ORDER BY r.created_at, r.id;
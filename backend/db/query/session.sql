@@ -0,0 +1,33 @@
+-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, refresh_token_hash, user_agent, ip_address, created_at, last_used_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetSessionByID :one
+SELECT * FROM sessions
+WHERE id = ?;
+
+-- name: GetSessionByRefreshTokenHash :one
+SELECT * FROM sessions
+WHERE refresh_token_hash = ?;
+
+-- name: GetSessionByPreviousTokenHash :one
+SELECT * FROM sessions
+WHERE previous_token_hash = ?;
+
+-- name: RotateSessionToken :execrows
+UPDATE sessions
+SET previous_token_hash = refresh_token_hash,
+    refresh_token_hash = ?,
+    last_used_at = ?,
+    expires_at = ?
+WHERE id = ? AND refresh_token_hash = ? AND revoked_at IS NULL;
+
+-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = ?
+WHERE id = ? AND revoked_at IS NULL;
+
+-- name: RevokeSessionsByUserID :exec
+UPDATE sessions
+SET revoked_at = ?
+WHERE user_id = ? AND revoked_at IS NULL;
@@ -1,5 +1,5 @@
 -- name: GetTeamsByUserID :many
-SELECT t.id, t.name, t.workspace_id, t.leader_id
+SELECT t.id, t.name, t.workspace_id, t.leader_id, t.key
 FROM teams t
 JOIN team_members tm ON t.id = tm.team_id
 WHERE tm.user_id = ?;
@@ -8,7 +8,7 @@ WHERE tm.user_id = ?;
 DELETE FROM teams WHERE id = ?;
 
 -- name: ListTeams :many
-SELECT id, name, workspace_id, leader_id
+SELECT id, name, workspace_id, leader_id, key
 FROM teams
 ORDER BY name;
 
@@ -60,11 +60,11 @@ SET name = ?
 WHERE id = ?;
 
 -- name: CreateTeam :exec
-INSERT INTO teams (id, name, workspace_id)
-VALUES (?, ?, ?);
+INSERT INTO teams (id, name, workspace_id, key)
+VALUES (?, ?, ?, ?);
 
 -- name: GetTeamByID :one
-SELECT id, name, workspace_id, leader_id
+SELECT id, name, workspace_id, leader_id, key
 FROM teams
 WHERE id = ?;
 
@@ -73,3 +73,97 @@ UPDATE teams
 SET leader_id = ?
 WHERE id = ?;
 
+-- name: GetTeamMemberRoles :one
+SELECT t.workspace_id,
+       COALESCE(wm.role, '') AS workspace_role,
+       COALESCE(tm.role, '') AS team_role
+FROM teams t
+LEFT JOIN workspace_members wm ON wm.workspace_id = t.workspace_id AND wm.user_id = ?
+LEFT JOIN team_members tm ON tm.team_id = t.id AND tm.user_id = ?
+WHERE t.id = ?;
+
+-- name: SetTeamLead :exec
+UPDATE team_members
+SET role = CASE WHEN user_id = ? THEN 'lead' ELSE 'member' END
+WHERE team_id = ? AND (role = 'lead' OR user_id = ?);
+
+-- name: ListTeamStatuses :many
+SELECT team_id, key, name, category, position, color
+FROM team_statuses
+WHERE team_id = ?
+ORDER BY position, key;
+
+-- name: GetTeamStatus :one
+SELECT team_id, key, name, category, position, color
+FROM team_statuses
+WHERE team_id = ? AND key = ?;
+
+-- name: GetDefaultTeamStatus :one
+SELECT key
+FROM team_statuses
+WHERE team_id = ?
+ORDER BY category != 'unstarted', position, key
+LIMIT 1;
+
+-- name: CreateTeamStatus :exec
+INSERT INTO team_statuses (team_id, key, name, category, position, color)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: UpdateTeamStatus :exec
+UPDATE team_statuses
+SET name = ?, category = ?, color = ?
+WHERE team_id = ? AND key = ?;
+
+-- name: SetTeamStatusPosition :exec
+UPDATE team_statuses
+SET position = ?
+WHERE team_id = ? AND key = ?;
+
+-- name: DeleteTeamStatus :exec
+DELETE FROM team_statuses
+WHERE team_id = ? AND key = ?;
+
+-- name: CountIssuesByTeamStatus :one
+SELECT COUNT(*) AS count
+FROM issues
+WHERE team_id = ? AND status = ?;
+
+-- name: MoveIssuesToTeamStatus :exec
+UPDATE issues
+SET status = ?
+WHERE team_id = ? AND status = ?;
+
+-- name: ListTransitionRules :many
+SELECT id, team_id, from_status, to_status, to_category, requirement, created_at
+FROM team_transition_rules
+WHERE team_id = ?
+ORDER BY created_at, id;
+
+-- name: CreateTransitionRule :exec
+INSERT INTO team_transition_rules (id, team_id, from_status, to_status, to_category, requirement, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: DeleteTransitionRule :execrows
+DELETE FROM team_transition_rules
+WHERE id = ? AND team_id = ?;
+
+-- name: ListTeamKeys :many
+SELECT key
+FROM teams
+WHERE workspace_id = ?;
+
+-- name: SetTeamKey :exec
+UPDATE teams
+SET key = ?
+WHERE id = ?;
+
+-- name: AddTeamIssueIdentifierAliases :exec
+INSERT OR IGNORE INTO issue_identifier_aliases (issue_id, identifier)
+SELECT id, identifier
+FROM issues
+WHERE team_id = ?;
+
+-- name: RenumberTeamIssues :exec
+UPDATE issues
+SET identifier = (SELECT t.key FROM teams t WHERE t.id = issues.team_id) || '-' || number
+WHERE team_id = ?;
@@ -33,3 +33,8 @@ WHERE email = ?;
 SELECT id, username, email, password_hash, roles
 FROM users
 WHERE email = ?;
+
+-- name: GetUserIDByUsername :one
+SELECT id
+FROM users
+WHERE username = ?;
@@ -1,6 +1,6 @@
 -- name: CreateView :exec
-INSERT INTO views (id, name, created_by, team_id)
-VALUES (?, ?, ?, ?);
+INSERT INTO views (id, name, created_by, team_id, filter)
+VALUES (?, ?, ?, ?, ?);
 
 -- name: GetViewByID :many
 SELECT *
@@ -12,14 +12,14 @@ WHERE id = ?;
 DELETE FROM views WHERE id = ?;
 
 -- name: ListViewsByUser :many
-SELECT id, name, created_by, team_id
+SELECT id, name, created_by, team_id, filter
 FROM views
 WHERE created_by = ?
 ORDER BY name;
 
 -- name: AddGroupByToView :exec
-INSERT INTO view_group_bys (view_id, group_by)
-VALUES (?, ?);
+INSERT INTO view_group_bys (view_id, group_by, position, group_order, descending, show_empty)
+VALUES (?, ?, ?, ?, ?, ?);
 
 -- name: RemoveGroupByFromView :exec
 DELETE FROM view_group_bys
@@ -30,19 +30,11 @@ SELECT group_by
 FROM view_group_bys
 WHERE view_id = ?;
 
--- name: AddIssueToView :exec
-INSERT OR IGNORE INTO view_issues (view_id, issue_id)
-VALUES (?, ?);
-
--- name: RemoveIssueFromView :exec
-DELETE FROM view_issues
-WHERE view_id = ?;
-
--- name: ListIssuesByViewID :many
-SELECT i.*
-FROM issues i
-JOIN view_issues vi ON i.id = vi.issue_id
-WHERE vi.view_id = ?;
+-- name: ListViewGroupBys :many
+SELECT *
+FROM view_group_bys
+WHERE view_id = ?
+ORDER BY position, rowid;
 
 -- name: ListViewByTeamID :many
 SELECT *
@@ -68,8 +60,9 @@ SELECT * FROM issues
 WHERE team_id = ? AND project_id = ?;
 
 -- name: GetIssuesByLabel :many
-SELECT * FROM issues
-WHERE team_id = ? AND Label = ?;
+SELECT i.* FROM issues i
+JOIN issue_labels il ON il.issue_id = i.id
+WHERE i.team_id = ? AND il.label_id = ?;
 
 -- name: GetIssuesByTeamID :many
 SELECT * FROM issues
@@ -87,6 +80,10 @@ WHERE id = ?;
 UPDATE view_group_bys SET group_by = ? 
 WHERE view_id = ?;
 
+-- name: UpdateViewFilter :exec
+UPDATE views SET filter = ?
+WHERE id = ?;
+
 -- name: UpdateViewTeamID :exec
 UPDATE views SET team_id = ? 
 WHERE id = ?;
@@ -95,3 +92,8 @@ WHERE id = ?;
 SELECT team_id
 FROM views
 WHERE id = ?;
+
+-- name: GetTeamIssueVersion :one
+SELECT version
+FROM team_issue_versions
+WHERE team_id = ?;
@@ -20,8 +20,8 @@ JOIN workspace_members wm ON u.id = wm.user_id
 WHERE wm.workspace_id = ?;
 
 -- name: AddMemberToWorkspace :exec
-INSERT OR IGNORE INTO workspace_members (workspace_id, user_id)
-VALUES (?, ?);
+INSERT OR IGNORE INTO workspace_members (workspace_id, user_id, role)
+VALUES (?, ?, ?);
 
 -- name: RemoveMemberFromWorkspace :exec
 DELETE FROM workspace_members
@@ -38,3 +38,13 @@ FROM workspaces w
 LEFT JOIN workspace_members wm ON w.id = wm.workspace_id
 WHERE w.owner_id = ? OR wm.user_id = ?
 ORDER BY w.id;
+
+-- name: GetWorkspaceMemberRole :one
+SELECT role
+FROM workspace_members
+WHERE workspace_id = ? AND user_id = ?;
+
+-- name: SetWorkspaceMemberRole :exec
+UPDATE workspace_members
+SET role = ?
+WHERE workspace_id = ? AND user_id = ?;
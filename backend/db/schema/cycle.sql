@@ -0,0 +1,17 @@
+CREATE TABLE cycles (
+    id TEXT PRIMARY KEY,
+    team_id TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    name TEXT,
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    closed_at DATETIME,
+    scope_count INTEGER NOT NULL DEFAULT 0,
+    completed_count INTEGER NOT NULL DEFAULT 0,
+    added_count INTEGER NOT NULL DEFAULT 0,
+    rolled_over_count INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    CHECK (end_date > start_date),
+    UNIQUE (team_id, number),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
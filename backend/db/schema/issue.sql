@@ -3,16 +3,41 @@ CREATE TABLE issues (
     title TEXT NOT NULL,
     content TEXT,
     priority TEXT CHECK(priority IN ('low', 'medium', 'high')),
-    status TEXT CHECK(status IN ('todo', 'doing', 'done')) NOT NULL,
+    status TEXT NOT NULL,
     project_id TEXT,
     team_id TEXT NOT NULL,
     start_date DATETIME,
     end_date DATETIME,
-    label TEXT,
     owner_id TEXT NOT NULL,
+    rank TEXT NOT NULL DEFAULT '',
+    number INTEGER NOT NULL DEFAULT 0,
+    identifier TEXT NOT NULL DEFAULT '',
+    parent_id TEXT,
+    cycle_id TEXT,
+    cycle_added_at DATETIME,
+    milestone_id TEXT,
     FOREIGN KEY (project_id) REFERENCES projects(id),
     FOREIGN KEY (team_id) REFERENCES teams(id),
-    FOREIGN KEY (owner_id) REFERENCES users(id)
+    FOREIGN KEY (owner_id) REFERENCES users(id),
+    FOREIGN KEY (parent_id) REFERENCES issues(id) ON DELETE SET NULL,
+    FOREIGN KEY (cycle_id) REFERENCES cycles(id) ON DELETE SET NULL,
+    FOREIGN KEY (milestone_id) REFERENCES project_milestones(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_issues_board ON issues (team_id, status, rank);
+CREATE UNIQUE INDEX idx_issues_team_number ON issues (team_id, number);
+CREATE INDEX idx_issues_identifier ON issues (identifier);
+CREATE INDEX idx_issues_parent ON issues (parent_id);
+CREATE INDEX idx_issues_cycle ON issues (cycle_id);
+CREATE INDEX idx_issues_milestone ON issues (milestone_id);
+
+CREATE TABLE view_issue_ranks (
+    view_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    rank TEXT NOT NULL,
+    PRIMARY KEY (view_id, issue_id),
+    FOREIGN KEY (view_id) REFERENCES views(id) ON DELETE CASCADE,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
 );
 
 CREATE TABLE issue_assignees (
@@ -22,3 +47,26 @@ CREATE TABLE issue_assignees (
     FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
     FOREIGN KEY (user_id) REFERENCES users(id)
 );
+
+CREATE TABLE issue_events (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    field TEXT NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_issue_events_issue_id ON issue_events (issue_id, created_at);
+
+CREATE TABLE issue_identifier_aliases (
+    issue_id TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    PRIMARY KEY (issue_id, identifier),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_issue_identifier_aliases_identifier ON issue_identifier_aliases (identifier);
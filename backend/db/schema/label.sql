@@ -0,0 +1,33 @@
+CREATE TABLE labels (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL,
+    team_id TEXT,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL DEFAULT '#bec2c8',
+    description TEXT,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_labels_scope_name ON labels (workspace_id, COALESCE(team_id, ''), name COLLATE NOCASE);
+
+CREATE TABLE issue_labels (
+    issue_id TEXT NOT NULL,
+    label_id TEXT NOT NULL,
+    PRIMARY KEY (issue_id, label_id),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_issue_labels_label ON issue_labels (label_id);
+
+CREATE TABLE project_labels (
+    project_id TEXT NOT NULL,
+    label_id TEXT NOT NULL,
+    PRIMARY KEY (project_id, label_id),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (label_id) REFERENCES labels(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_project_labels_label ON project_labels (label_id);
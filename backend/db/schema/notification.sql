@@ -0,0 +1,14 @@
+CREATE TABLE notifications (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('due_soon', 'overdue')),
+    due_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    read_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_notifications_reminder ON notifications (user_id, issue_id, type, due_at);
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at);
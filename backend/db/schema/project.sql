@@ -8,7 +8,6 @@ CREATE TABLE projects (
     leader_id TEXT NULL,
     start_date DATETIME NULL,
     end_date DATETIME NULL,
-    label TEXT NULL,
     created_by TEXT NOT NULL,
 
     FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
@@ -24,3 +23,29 @@ CREATE TABLE project_members (
     FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
     FOREIGN KEY (user_id) REFERENCES users(id)
 );
+
+CREATE TABLE project_milestones (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    target_date DATETIME,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_project_milestones_project ON project_milestones (project_id, position);
+
+CREATE TABLE project_updates (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    health TEXT NOT NULL CHECK (health IN ('on_track', 'at_risk', 'off_track')),
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id)
+);
+
+CREATE INDEX idx_project_updates_project ON project_updates (project_id, created_at);
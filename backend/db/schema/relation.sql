@@ -0,0 +1,16 @@
+CREATE TABLE issue_relations (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    related_issue_id TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('blocks', 'duplicates', 'relates_to')),
+    created_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    CHECK (issue_id <> related_issue_id),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (related_issue_id) REFERENCES issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE UNIQUE INDEX idx_issue_relations_pair ON issue_relations (MIN(issue_id, related_issue_id), MAX(issue_id, related_issue_id), type);
+CREATE INDEX idx_issue_relations_issue ON issue_relations (issue_id);
+CREATE INDEX idx_issue_relations_related ON issue_relations (related_issue_id);
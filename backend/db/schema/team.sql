@@ -3,14 +3,51 @@ CREATE TABLE teams (
     name TEXT NOT NULL,
     workspace_id TEXT NOT NULL,
     leader_id TEXT NULL,
+    key TEXT NOT NULL DEFAULT '',
     FOREIGN KEY (workspace_id) REFERENCES workspaces(id),
     FOREIGN KEY (leader_id) REFERENCES users(id)
 );
 
+CREATE UNIQUE INDEX idx_teams_workspace_key ON teams (workspace_id, key);
+
+CREATE TABLE team_issue_sequences (
+    team_id TEXT PRIMARY KEY,
+    last_number INTEGER NOT NULL,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
 CREATE TABLE team_members (
     team_id TEXT NOT NULL,
     user_id TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('lead', 'member')),
     PRIMARY KEY (team_id, user_id),
     FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
     FOREIGN KEY (user_id) REFERENCES users(id)
 );
+
+CREATE TABLE team_statuses (
+    team_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT NOT NULL CHECK (category IN ('unstarted', 'started', 'completed', 'cancelled')),
+    position INTEGER NOT NULL,
+    color TEXT NOT NULL,
+    PRIMARY KEY (team_id, key),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE TABLE team_transition_rules (
+    id TEXT PRIMARY KEY,
+    team_id TEXT NOT NULL,
+    from_status TEXT,
+    to_status TEXT,
+    to_category TEXT CHECK (to_category IN ('unstarted', 'started', 'completed', 'cancelled')),
+    requirement TEXT NOT NULL CHECK (requirement IN ('assignee', 'end_date', 'actor_is_assignee', 'forbidden')),
+    created_at DATETIME NOT NULL,
+    CHECK ((to_status IS NULL) != (to_category IS NULL)),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (team_id, from_status) REFERENCES team_statuses(team_id, key) ON DELETE CASCADE,
+    FOREIGN KEY (team_id, to_status) REFERENCES team_statuses(team_id, key) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_team_transition_rules_team ON team_transition_rules (team_id, created_at);
@@ -3,6 +3,7 @@ CREATE TABLE views (
     name TEXT NOT NULL,
     created_by TEXT NOT NULL,
     team_id TEXT NOT NULL,
+    filter TEXT,
     FOREIGN KEY (team_id) REFERENCES teams(id),
     FOREIGN KEY (created_by) REFERENCES users(id)
 );
@@ -10,14 +11,16 @@ CREATE TABLE views (
 CREATE TABLE view_group_bys (
     view_id TEXT NOT NULL,
     group_by TEXT NOT NULL CHECK (group_by IN ('status', 'assignee', 'priority', 'project_id', 'label', 'team_id', 'end_date')),
+    position INTEGER NOT NULL DEFAULT 0,
+    group_order TEXT NOT NULL DEFAULT 'natural' CHECK (group_order IN ('natural', 'value', 'count')),
+    descending BOOLEAN NOT NULL DEFAULT 0,
+    show_empty BOOLEAN NOT NULL DEFAULT 0,
     PRIMARY KEY (view_id, group_by),
     FOREIGN KEY (view_id) REFERENCES views(id) ON DELETE CASCADE
 );
 
-CREATE TABLE view_issues (
-    view_id TEXT NOT NULL,
-    issue_id TEXT NOT NULL,
-    PRIMARY KEY (view_id, issue_id),
-    FOREIGN KEY (view_id) REFERENCES views(id) ON DELETE CASCADE,
-    FOREIGN KEY (issue_id) REFERENCES issues(id)
+CREATE TABLE team_issue_versions (
+    team_id TEXT PRIMARY KEY,
+    version INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
 );
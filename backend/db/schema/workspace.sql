@@ -8,6 +8,7 @@ CREATE TABLE workspaces (
 CREATE TABLE workspace_members (
     workspace_id TEXT NOT NULL,
     user_id TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member', 'guest')),
     PRIMARY KEY (workspace_id, user_id),
     FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
@@ -17,13 +17,17 @@ import (
 
 type AuthHandler struct {
 	UserRepo        repositories.UserRepository
-	CreateTokenFunc func(user models.User) (string, error)
+	SessionRepo     repositories.SessionRepository
+	Keys            *KeySet
+	CreateTokenFunc func(user models.User, sessionID string) (string, error)
 	VerifyTokenFunc func(tokenStr string) (*jwt.Token, error)
 }
 
-func NewAuthHandler(userRepo repositories.UserRepository) *AuthHandler {
+func NewAuthHandler(userRepo repositories.UserRepository, sessionRepo repositories.SessionRepository, keys *KeySet) *AuthHandler {
 	h := &AuthHandler{
-		UserRepo: userRepo,
+		UserRepo:    userRepo,
+		SessionRepo: sessionRepo,
+		Keys:        keys,
 	}
 	h.CreateTokenFunc = h.CreateToken
 	h.VerifyTokenFunc = h.verifyTokenInternal
@@ -31,9 +35,11 @@ func NewAuthHandler(userRepo repositories.UserRepository) *AuthHandler {
 }
 
 func (h *AuthHandler) verifyTokenInternal(tokenStr string) (*jwt.Token, error) {
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		return secretKey, nil
-	})
+	if h.Keys == nil {
+		return nil, fiber.ErrUnauthorized
+	}
+
+	token, err := jwt.Parse(tokenStr, h.Keys.Keyfunc)
 
 	if err != nil || !token.Valid {
 		return nil, fiber.ErrUnauthorized
@@ -57,17 +63,21 @@ var (
 	RateLimitMax    = 5
 	RateLimitWindow = time.Minute * 5
 	LastAttempt     = make(map[string]time.Time)
-	secretKey       = []byte("secret-key")
 	ResetDelay      = time.Minute
+	AccessTokenTTL  = time.Minute * 15
+	RefreshTokenTTL = time.Hour * 24 * 30
 )
 
-func (h *AuthHandler) CreateToken(user models.User) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+func (h *AuthHandler) CreateToken(user models.User, sessionID string) (string, error) {
+	return h.Keys.Sign(jwt.MapClaims{
 		"user_id": user.ID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+		"sid":     sessionID,
+		"exp":     time.Now().Add(AccessTokenTTL).Unix(),
 	})
+}
 
-	return token.SignedString(secretKey)
+func (h *AuthHandler) JWKS(c *fiber.Ctx) error {
+	return c.JSON(h.Keys.JWKS())
 }
 func (h *AuthHandler) AuthRequired(c *fiber.Ctx) error {
 	tokenStr := c.Cookies("token")
@@ -90,7 +100,13 @@ func (h *AuthHandler) AuthRequired(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user ID in token"})
 	}
 
+	sessionID, err := h.checkSession(c.Context(), claims, userID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	c.Locals("userID", userID)
+	c.Locals("sessionID", sessionID)
 	return c.Next()
 }
 
@@ -116,7 +132,13 @@ func (h *AuthHandler) WebSocketAuthRequired() fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user ID in token"})
 		}
 
+		sessionID, err := h.checkSession(c.Context(), claims, userID)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+
 		c.Locals("userID", userID)
+		c.Locals("sessionID", sessionID)
 		return c.Next()
 	}
 }
@@ -179,20 +201,18 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	delete(LastAttempt, ip)
 	LoginLock.Unlock()
 
-	tokenString, err := h.CreateTokenFunc(models.User{ID: user.ID})
+	sessionID := uuid.New().String()
+	tokenString, err := h.CreateTokenFunc(models.User{ID: user.ID}, sessionID)
 	if err != nil {
 		return c.Status(500).SendString("Error while creating token")
 	}
 
-	c.Cookie(&fiber.Cookie{
-		Name:     "token",
-		Value:    tokenString,
-		HTTPOnly: true,
-		Secure:   true,
-		SameSite: "Strict",
-		Path:     "/",
-		Expires:  time.Now().Add(24 * time.Hour),
-	})
+	refreshToken, err := h.startSession(c, sessionID, user.ID)
+	if err != nil {
+		return c.Status(500).SendString("Error while creating session")
+	}
+
+	setAuthCookies(c, tokenString, refreshToken)
 
 	return c.JSON(fiber.Map{
 		"message": "Login successful",
@@ -2,6 +2,7 @@ package auth_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
@@ -74,6 +75,49 @@ func (m *MockUserRepo) GetUserByEmailWithPassword(ctx context.Context, email str
 	return args.Get(0).(db.GetUserByEmailWithPasswordRow), args.Error(1)
 }
 
+type MockSessionRepo struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepo) CreateSession(ctx context.Context, data db.CreateSessionParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepo) GetSessionByID(ctx context.Context, id string) (db.Session, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Session), args.Error(1)
+}
+
+func (m *MockSessionRepo) GetSessionByRefreshTokenHash(ctx context.Context, hash string) (db.Session, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(db.Session), args.Error(1)
+}
+
+func (m *MockSessionRepo) GetSessionByPreviousTokenHash(ctx context.Context, hash string) (db.Session, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(db.Session), args.Error(1)
+}
+
+func (m *MockSessionRepo) RotateSessionToken(ctx context.Context, data db.RotateSessionTokenParams) (int64, error) {
+	args := m.Called(ctx, data)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSessionRepo) RevokeSession(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepo) RevokeSessionsByUserID(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func activeSession(id, userID string) db.Session {
+	return db.Session{ID: id, UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+}
+
 func setupApp(handler *auth.AuthHandler) *fiber.App {
 	app := fiber.New()
 	app.Post("/login", handler.Login)
@@ -83,7 +127,7 @@ func setupApp(handler *auth.AuthHandler) *fiber.App {
 
 func TestRegister_Success(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 
 	email := "test@example.com"
@@ -116,7 +160,7 @@ func TestRegister_Success(t *testing.T) {
 
 func TestRegister_InvalidEmail(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 
 	reqBody := `{
@@ -138,7 +182,7 @@ func TestRegister_InvalidEmail(t *testing.T) {
 
 func TestRegister_BodyParserError(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 
 	badJSON := `{"username": "tester", "email": "test@example.com", "password": "abc"`
@@ -157,7 +201,7 @@ func TestRegister_BodyParserError(t *testing.T) {
 
 func TestRegister_WeakPassword(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 
 	email := "test@example.com"
@@ -183,7 +227,7 @@ func TestRegister_WeakPassword(t *testing.T) {
 
 func TestRegister_UserAlreadyExists(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 
 	email := "test@example.com"
@@ -209,7 +253,7 @@ func TestRegister_UserAlreadyExists(t *testing.T) {
 
 func TestRegister_CreateUserFail(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 
 	email := "test@example.com"
@@ -247,7 +291,7 @@ func resetLoginRateLimit() {
 
 func TestLogin_BodyParserError(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 	resetLoginRateLimit()
 
@@ -268,7 +312,7 @@ func TestLogin_BodyParserError(t *testing.T) {
 
 func TestLogin_RateLimitExceeded(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 	resetLoginRateLimit()
 
@@ -309,7 +353,7 @@ func TestLogin_RateLimitExceeded(t *testing.T) {
 
 func TestLogin_UserNotFound(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 	resetLoginRateLimit()
 
@@ -334,7 +378,7 @@ func TestLogin_UserNotFound(t *testing.T) {
 }
 func TestLogin_InvalidPassword(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 
 	email := "user@example.com"
@@ -369,7 +413,8 @@ func TestLogin_InvalidPassword(t *testing.T) {
 
 func TestLogin_ValidPassword(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	sessionRepo := new(MockSessionRepo)
+	handler := auth.NewAuthHandler(mockRepo, sessionRepo, testKeys)
 	app := setupApp(handler)
 
 	email := "user@example.com"
@@ -378,6 +423,10 @@ func TestLogin_ValidPassword(t *testing.T) {
 	hashPass, err := argon2id.CreateHash(password, argon2id.DefaultParams)
 	require.NoError(t, err)
 
+	sessionRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(p db.CreateSessionParams) bool {
+		return p.UserID == "user-id-123" && p.RefreshTokenHash != ""
+	})).Return(nil)
+
 	mockRepo.On("GetUserByEmailWithPassword", mock.Anything, email).
 		Return(db.GetUserByEmailWithPasswordRow{
 			ID:           "user-id-123",
@@ -387,7 +436,7 @@ func TestLogin_ValidPassword(t *testing.T) {
 			Roles:        "user",
 		}, nil)
 
-	handler.CreateTokenFunc = func(user models.User) (string, error) {
+	handler.CreateTokenFunc = func(user models.User, sessionID string) (string, error) {
 		return "valid.jwt.token", nil
 	}
 
@@ -402,12 +451,14 @@ func TestLogin_ValidPassword(t *testing.T) {
 	body, _ := io.ReadAll(resp.Body)
 	assert.Contains(t, string(body), "Login successful")
 
+	sessionRepo.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestLogin_Success_WithRealArgon2id(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	sessionRepo := new(MockSessionRepo)
+	handler := auth.NewAuthHandler(mockRepo, sessionRepo, testKeys)
 	app := setupApp(handler)
 	resetLoginRateLimit()
 
@@ -415,6 +466,10 @@ func TestLogin_Success_WithRealArgon2id(t *testing.T) {
 	hashPass, err := argon2id.CreateHash(password, argon2id.DefaultParams)
 	assert.NoError(t, err)
 
+	sessionRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(p db.CreateSessionParams) bool {
+		return p.UserID == "user-id-123" && p.RefreshTokenHash != ""
+	})).Return(nil)
+
 	mockRepo.On("GetUserByEmailWithPassword", mock.Anything, "test@example.com").
 		Return(db.GetUserByEmailWithPasswordRow{
 			ID:           "user-id-123",
@@ -435,12 +490,13 @@ func TestLogin_Success_WithRealArgon2id(t *testing.T) {
 	body, _ := io.ReadAll(resp.Body)
 	assert.Contains(t, string(body), "Login successful")
 
+	sessionRepo.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestLogin_CreateTokenFail(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	resetLoginRateLimit()
 
 	app := setupApp(handler)
@@ -460,7 +516,7 @@ func TestLogin_CreateTokenFail(t *testing.T) {
 			Roles:        "user",
 		}, nil)
 
-	handler.CreateTokenFunc = func(user models.User) (string, error) {
+	handler.CreateTokenFunc = func(user models.User, sessionID string) (string, error) {
 		return "", errors.New("token creation failed")
 	}
 
@@ -480,7 +536,7 @@ func TestLogin_CreateTokenFail(t *testing.T) {
 
 func TestLogin_InvalidEmailFormat(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	handler := auth.NewAuthHandler(mockRepo, new(MockSessionRepo), testKeys)
 	app := setupApp(handler)
 	resetLoginRateLimit()
 
@@ -502,7 +558,8 @@ func TestLogin_InvalidEmailFormat(t *testing.T) {
 
 func TestLogin_Success_UsingRealCompare(t *testing.T) {
 	mockRepo := new(MockUserRepo)
-	handler := auth.NewAuthHandler(mockRepo)
+	sessionRepo := new(MockSessionRepo)
+	handler := auth.NewAuthHandler(mockRepo, sessionRepo, testKeys)
 	app := setupApp(handler)
 	resetLoginRateLimit()
 
@@ -512,6 +569,10 @@ func TestLogin_Success_UsingRealCompare(t *testing.T) {
 	hashPass, err := argon2id.CreateHash(password, argon2id.DefaultParams)
 	require.NoError(t, err)
 
+	sessionRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(p db.CreateSessionParams) bool {
+		return p.UserID == "user-id-123" && p.RefreshTokenHash != ""
+	})).Return(nil)
+
 	mockRepo.On("GetUserByEmailWithPassword", mock.Anything, email).
 		Return(db.GetUserByEmailWithPasswordRow{
 			ID:           "user-id-123",
@@ -533,6 +594,7 @@ func TestLogin_Success_UsingRealCompare(t *testing.T) {
 	bodyBytes, _ := io.ReadAll(resp.Body)
 	assert.Contains(t, string(bodyBytes), "Login successful")
 
+	sessionRepo.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -627,11 +689,15 @@ func TestAuthRequired_MissingOrEmptyUserID(t *testing.T) {
 }
 
 func TestAuthRequired_Success(t *testing.T) {
+	sessionRepo := new(MockSessionRepo)
+	sessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(activeSession("session-1", "12345"), nil)
 	handler := &auth.AuthHandler{
+		SessionRepo: sessionRepo,
 		VerifyTokenFunc: func(tokenStr string) (*jwt.Token, error) {
 			return &jwt.Token{
 				Claims: jwt.MapClaims{
 					"user_id": "12345",
+					"sid":     "session-1",
 				},
 				Valid: true,
 			}, nil
@@ -649,17 +715,93 @@ func TestAuthRequired_Success(t *testing.T) {
 	assert.Contains(t, string(body), "12345")
 }
 
-var secretKey = []byte("secret-key")
+func TestAuthRequired_SessionChecks(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     jwt.MapClaims
+		session    db.Session
+		sessionErr error
+		wantBody   string
+	}{
+		{
+			name:     "missing session ID",
+			claims:   jwt.MapClaims{"user_id": "12345"},
+			wantBody: "Invalid session in token",
+		},
+		{
+			name:       "session not found",
+			claims:     jwt.MapClaims{"user_id": "12345", "sid": "session-1"},
+			session:    db.Session{},
+			sessionErr: sql.ErrNoRows,
+			wantBody:   "Invalid session in token",
+		},
+		{
+			name:     "session of another user",
+			claims:   jwt.MapClaims{"user_id": "12345", "sid": "session-1"},
+			session:  activeSession("session-1", "other"),
+			wantBody: "Invalid session in token",
+		},
+		{
+			name:   "revoked session",
+			claims: jwt.MapClaims{"user_id": "12345", "sid": "session-1"},
+			session: db.Session{
+				ID:        "session-1",
+				UserID:    "12345",
+				ExpiresAt: time.Now().Add(time.Hour),
+				RevokedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			},
+			wantBody: "Session has been revoked",
+		},
+		{
+			name:     "expired session",
+			claims:   jwt.MapClaims{"user_id": "12345", "sid": "session-1"},
+			session:  db.Session{ID: "session-1", UserID: "12345", ExpiresAt: time.Now().Add(-time.Minute)},
+			wantBody: "Session has been revoked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := new(MockSessionRepo)
+			sessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(tt.session, tt.sessionErr)
+			handler := &auth.AuthHandler{
+				SessionRepo: sessionRepo,
+				VerifyTokenFunc: func(tokenStr string) (*jwt.Token, error) {
+					return &jwt.Token{Claims: tt.claims, Valid: true}, nil
+				},
+			}
+			app := setupAuthRequiredTestApp(handler)
+
+			req := httptest.NewRequest("GET", "/protected", nil)
+			req.AddCookie(&http.Cookie{Name: "token", Value: "valid.token"})
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+			body, _ := io.ReadAll(resp.Body)
+			assert.Contains(t, string(body), tt.wantBody)
+		})
+	}
+}
+
+var testKeys = func() *auth.KeySet {
+	ks, err := auth.NewKeySetFromConfig(auth.KeySetConfig{
+		Keys: []auth.KeyConfig{{ID: "test", Algorithm: "HS256", Secret: "test-secret-that-is-at-least-32-bytes"}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return ks
+}()
 
 func TestVerifyToken_ValidToken(t *testing.T) {
-	handler := &auth.AuthHandler{}
+	handler := &auth.AuthHandler{Keys: testKeys}
 
 	claims := jwt.MapClaims{
 		"user_id": "12345",
 		"exp":     time.Now().Add(time.Hour).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(secretKey)
+	tokenString, err := testKeys.Sign(claims)
 	require.NoError(t, err)
 
 	parsedToken, err := handler.VerifyToken(tokenString)
@@ -673,7 +815,7 @@ func TestVerifyToken_ValidToken(t *testing.T) {
 }
 
 func TestVerifyToken_InvalidToken(t *testing.T) {
-	handler := &auth.AuthHandler{}
+	handler := &auth.AuthHandler{Keys: testKeys}
 
 	invalidTokenString := "this.is.an.invalid.token"
 
@@ -684,14 +826,13 @@ func TestVerifyToken_InvalidToken(t *testing.T) {
 }
 
 func TestVerifyToken_ExpiredToken(t *testing.T) {
-	handler := &auth.AuthHandler{}
+	handler := &auth.AuthHandler{Keys: testKeys}
 
 	claims := jwt.MapClaims{
 		"user_id": "12345",
 		"exp":     time.Now().Add(-time.Hour).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(secretKey)
+	tokenString, err := testKeys.Sign(claims)
 	require.NoError(t, err)
 
 	parsedToken, err := handler.VerifyToken(tokenString)
@@ -715,11 +856,15 @@ func setupWebsocketTestApp(handler *auth.AuthHandler) *fiber.App {
 func TestWebSocketAuthRequired_Success(t *testing.T) {
 	app := fiber.New()
 
+	sessionRepo := new(MockSessionRepo)
+	sessionRepo.On("GetSessionByID", mock.Anything, "session-1").Return(activeSession("session-1", "12345"), nil)
 	authHandler := &auth.AuthHandler{
+		SessionRepo: sessionRepo,
 		VerifyTokenFunc: func(tokenStr string) (*jwt.Token, error) {
 			return &jwt.Token{
 				Claims: jwt.MapClaims{
 					"user_id": "12345",
+					"sid":     "session-1",
 				},
 				Valid: true,
 			}, nil
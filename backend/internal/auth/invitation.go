@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const invitationAudience = "workspace_invitation"
+
+var errInvalidInvitation = errors.New("invalid invitation token")
+
+type invitationClaims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// SignInvitation issues a token for the invitation. The token only proves who
+// issued it; whether it is still usable is decided by the stored invitation.
+func (ks *KeySet) SignInvitation(invitationID, email string, expiresAt time.Time) (string, error) {
+	return ks.Sign(invitationClaims{
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        invitationID,
+			Audience:  jwt.ClaimStrings{invitationAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+}
+
+// ParseInvitation verifies the token and returns the invitation ID it was issued for.
+func (ks *KeySet) ParseInvitation(tokenStr string) (string, error) {
+	var claims invitationClaims
+	token, err := jwt.ParseWithClaims(tokenStr, &claims, ks.Keyfunc,
+		jwt.WithAudience(invitationAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !token.Valid || claims.ID == "" {
+		return "", errInvalidInvitation
+	}
+	return claims.ID, nil
+}
@@ -0,0 +1,37 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitationToken(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		token, err := testKeys.SignInvitation("inv-1", "a@x.com", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		id, err := testKeys.ParseInvitation(token)
+		require.NoError(t, err)
+		assert.Equal(t, "inv-1", id)
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		token, err := testKeys.SignInvitation("inv-1", "a@x.com", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		_, err = testKeys.ParseInvitation(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("access token is not an invitation", func(t *testing.T) {
+		token, err := testKeys.Sign(userClaims())
+		require.NoError(t, err)
+
+		_, err = testKeys.ParseInvitation(token)
+		assert.Error(t, err)
+	})
+
+}
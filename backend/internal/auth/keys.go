@@ -0,0 +1,360 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyConfig describes one signing key. HS256 keys take a secret, EdDSA and
+// RS256 keys take a PEM encoded private key, or only a public key when the key
+// is kept around to verify tokens issued before a rotation.
+type KeyConfig struct {
+	ID             string `json:"kid"`
+	Algorithm      string `json:"alg"`
+	Secret         string `json:"secret,omitempty"`
+	SecretEnv      string `json:"secret_env,omitempty"`
+	SecretFile     string `json:"secret_file,omitempty"`
+	PrivateKeyFile string `json:"private_key_file,omitempty"`
+	PublicKeyFile  string `json:"public_key_file,omitempty"`
+}
+
+type KeySetConfig struct {
+	Active string      `json:"active"`
+	Keys   []KeyConfig `json:"keys"`
+}
+
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	SignKey    interface{}
+	VerifyKey  interface{}
+	VerifyOnly bool
+}
+
+// KeySet holds every key that may verify a token. Tokens are always signed with
+// the active key and carry its ID in the kid header.
+type KeySet struct {
+	mu     sync.RWMutex
+	active string
+	keys   map[string]*SigningKey
+}
+
+func NewKeySet() *KeySet {
+	return &KeySet{keys: map[string]*SigningKey{}}
+}
+
+func (ks *KeySet) Add(key *SigningKey) error {
+	if key.ID == "" {
+		return errors.New("signing key must have a kid")
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if _, exists := ks.keys[key.ID]; exists {
+		return fmt.Errorf("duplicate signing key %q", key.ID)
+	}
+	ks.keys[key.ID] = key
+	return nil
+}
+
+func (ks *KeySet) SetActive(kid string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	key, ok := ks.keys[kid]
+	if !ok {
+		return fmt.Errorf("unknown signing key %q", kid)
+	}
+	if key.VerifyOnly {
+		return fmt.Errorf("signing key %q has no private key", kid)
+	}
+	ks.active = kid
+	return nil
+}
+
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[ks.active]
+	ks.mu.RUnlock()
+	if !ok {
+		return "", errors.New("no active signing key")
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.SignKey)
+}
+
+// Keyfunc picks the verification key by kid and rejects tokens whose alg does
+// not match the algorithm of that key.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid")
+	}
+
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	ks.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.VerifyKey, nil
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public part of every asymmetric key. HS256 secrets are never published.
+func (ks *KeySet) JWKS() JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		switch pub := key.VerifyKey.(type) {
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     key.ID,
+				Algorithm: key.Method.Alg(),
+				Use:       "sig",
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(pub),
+			})
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Algorithm: key.Method.Alg(),
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		}
+	}
+	return set
+}
+
+func readSecret(cfg KeyConfig) ([]byte, error) {
+	switch {
+	case cfg.Secret != "":
+		return []byte(cfg.Secret), nil
+	case cfg.SecretEnv != "":
+		secret := os.Getenv(cfg.SecretEnv)
+		if secret == "" {
+			return nil, fmt.Errorf("env %s is empty", cfg.SecretEnv)
+		}
+		return []byte(secret), nil
+	case cfg.SecretFile != "":
+		b, err := os.ReadFile(cfg.SecretFile)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strings.TrimSpace(string(b))), nil
+	}
+	return nil, errors.New("no secret configured")
+}
+
+func readPEM(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", path)
+	}
+	return block.Bytes, nil
+}
+
+func parsePrivateKey(path string) (crypto.Signer, error) {
+	der, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported private key", path)
+		}
+		return signer, nil
+	}
+	return x509.ParsePKCS1PrivateKey(der)
+}
+
+func parsePublicKey(path string) (crypto.PublicKey, error) {
+	der, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return key, nil
+	}
+	return x509.ParsePKCS1PublicKey(der)
+}
+
+func NewSigningKey(cfg KeyConfig) (*SigningKey, error) {
+	key := &SigningKey{ID: cfg.ID}
+
+	switch cfg.Algorithm {
+	case "HS256", "":
+		secret, err := readSecret(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", cfg.ID, err)
+		}
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("key %q: HS256 secret must be at least 32 bytes", cfg.ID)
+		}
+		key.Method = jwt.SigningMethodHS256
+		key.SignKey = secret
+		key.VerifyKey = secret
+		return key, nil
+	case "EdDSA":
+		key.Method = jwt.SigningMethodEdDSA
+	case "RS256":
+		key.Method = jwt.SigningMethodRS256
+	default:
+		return nil, fmt.Errorf("key %q: unsupported algorithm %q", cfg.ID, cfg.Algorithm)
+	}
+
+	var public crypto.PublicKey
+	switch {
+	case cfg.PrivateKeyFile != "":
+		signer, err := parsePrivateKey(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", cfg.ID, err)
+		}
+		key.SignKey = signer
+		public = signer.Public()
+	case cfg.PublicKeyFile != "":
+		pub, err := parsePublicKey(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", cfg.ID, err)
+		}
+		key.VerifyOnly = true
+		public = pub
+	default:
+		return nil, fmt.Errorf("key %q: no key file configured", cfg.ID)
+	}
+
+	switch pub := public.(type) {
+	case ed25519.PublicKey:
+		if key.Method != jwt.SigningMethodEdDSA {
+			return nil, fmt.Errorf("key %q: Ed25519 key used with %s", cfg.ID, cfg.Algorithm)
+		}
+		key.VerifyKey = pub
+	case *rsa.PublicKey:
+		if key.Method != jwt.SigningMethodRS256 {
+			return nil, fmt.Errorf("key %q: RSA key used with %s", cfg.ID, cfg.Algorithm)
+		}
+		key.VerifyKey = pub
+	default:
+		return nil, fmt.Errorf("key %q: unsupported key type %T", cfg.ID, public)
+	}
+
+	return key, nil
+}
+
+func NewKeySetFromConfig(cfg KeySetConfig) (*KeySet, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, errors.New("no signing keys configured")
+	}
+
+	ks := NewKeySet()
+	for _, kc := range cfg.Keys {
+		key, err := NewSigningKey(kc)
+		if err != nil {
+			return nil, err
+		}
+		if err := ks.Add(key); err != nil {
+			return nil, err
+		}
+	}
+
+	active := cfg.Active
+	if active == "" {
+		active = cfg.Keys[0].ID
+	}
+	if err := ks.SetActive(active); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// LoadKeySet reads the signing keys from the environment:
+//
+//	JWT_KEYS_FILE    JSON file in the KeySetConfig format, for rotation and asymmetric keys
+//	JWT_SECRET       a single HS256 secret
+//	JWT_SECRET_FILE  a file holding a single HS256 secret
+//
+// Without any of them a random key is generated, so access tokens do not
+// survive a restart and clients have to go through /api/refresh.
+func LoadKeySet() (*KeySet, error) {
+	if path := os.Getenv("JWT_KEYS_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var cfg KeySetConfig
+		if err := json.Unmarshal(b, &cfg); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return NewKeySetFromConfig(cfg)
+	}
+
+	if os.Getenv("JWT_SECRET") != "" || os.Getenv("JWT_SECRET_FILE") != "" {
+		return NewKeySetFromConfig(KeySetConfig{Keys: []KeyConfig{{
+			ID:         "default",
+			Algorithm:  "HS256",
+			SecretEnv:  envIfSet("JWT_SECRET"),
+			SecretFile: os.Getenv("JWT_SECRET_FILE"),
+		}}})
+	}
+
+	log.Println("WARNING: no JWT signing key configured, using a random key")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	ks := NewKeySet()
+	if err := ks.Add(&SigningKey{ID: "ephemeral", Method: jwt.SigningMethodHS256, SignKey: secret, VerifyKey: secret}); err != nil {
+		return nil, err
+	}
+	return ks, ks.SetActive("ephemeral")
+}
+
+func envIfSet(name string) string {
+	if os.Getenv(name) == "" {
+		return ""
+	}
+	return name
+}
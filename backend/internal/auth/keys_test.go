@@ -0,0 +1,209 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nack098/nakumanager/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+func userClaims() jwt.MapClaims {
+	return jwt.MapClaims{"user_id": "12345", "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+func TestKeySet_RotationKeepsOldTokensValid(t *testing.T) {
+	ks, err := auth.NewKeySetFromConfig(auth.KeySetConfig{
+		Active: "old",
+		Keys: []auth.KeyConfig{
+			{ID: "old", Algorithm: "HS256", Secret: "old-secret-that-is-at-least-32-bytes"},
+			{ID: "new", Algorithm: "HS256", Secret: "new-secret-that-is-at-least-32-bytes"},
+		},
+	})
+	require.NoError(t, err)
+	handler := &auth.AuthHandler{Keys: ks}
+
+	oldToken, err := ks.Sign(userClaims())
+	require.NoError(t, err)
+
+	require.NoError(t, ks.SetActive("new"))
+	newToken, err := ks.Sign(userClaims())
+	require.NoError(t, err)
+
+	for _, tokenStr := range []string{oldToken, newToken} {
+		token, err := handler.VerifyToken(tokenStr)
+		require.NoError(t, err)
+		assert.True(t, token.Valid)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", parsed.Header["kid"])
+}
+
+func TestKeySet_RejectsUnknownOrMissingKid(t *testing.T) {
+	handler := &auth.AuthHandler{Keys: testKeys}
+	secret := []byte("test-secret-that-is-at-least-32-bytes")
+
+	noKid, err := jwt.NewWithClaims(jwt.SigningMethodHS256, userClaims()).SignedString(secret)
+	require.NoError(t, err)
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodHS256, userClaims())
+	unknown.Header["kid"] = "retired"
+	unknownKid, err := unknown.SignedString(secret)
+	require.NoError(t, err)
+
+	for _, tokenStr := range []string{noKid, unknownKid} {
+		_, err := handler.VerifyToken(tokenStr)
+		assert.Equal(t, fiber.ErrUnauthorized, err)
+	}
+}
+
+func TestKeySet_EdDSAAndJWKS(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaPub, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	ks, err := auth.NewKeySetFromConfig(auth.KeySetConfig{
+		Active: "ed",
+		Keys: []auth.KeyConfig{
+			{ID: "ed", Algorithm: "EdDSA", PrivateKeyFile: writePEM(t, "ed.pem", "PRIVATE KEY", der)},
+			{ID: "rsa-old", Algorithm: "RS256", PublicKeyFile: writePEM(t, "rsa.pub", "PUBLIC KEY", rsaPub)},
+			{ID: "hs", Algorithm: "HS256", Secret: "hs-secret-that-is-at-least-32-bytes!"},
+		},
+	})
+	require.NoError(t, err)
+	handler := &auth.AuthHandler{Keys: ks}
+
+	tokenStr, err := ks.Sign(userClaims())
+	require.NoError(t, err)
+	token, err := handler.VerifyToken(tokenStr)
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", token.Method.Alg())
+
+	// key ที่มีแต่ public key ใช้ verify ได้อย่างเดียว
+	assert.Error(t, ks.SetActive("rsa-old"))
+
+	rsaToken := jwt.NewWithClaims(jwt.SigningMethodRS256, userClaims())
+	rsaToken.Header["kid"] = "rsa-old"
+	rsaTokenStr, err := rsaToken.SignedString(rsaKey)
+	require.NoError(t, err)
+	_, err = handler.VerifyToken(rsaTokenStr)
+	assert.NoError(t, err)
+
+	// alg ใน header ต้องตรงกับ key ที่ kid ชี้ไป
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, userClaims())
+	confused.Header["kid"] = "ed"
+	confusedStr, err := confused.SignedString([]byte(priv.Public().(ed25519.PublicKey)))
+	require.NoError(t, err)
+	_, err = handler.VerifyToken(confusedStr)
+	assert.Equal(t, fiber.ErrUnauthorized, err)
+
+	jwks := ks.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	kinds := map[string]string{}
+	for _, k := range jwks.Keys {
+		kinds[k.KeyID] = k.KeyType
+	}
+	assert.Equal(t, map[string]string{"ed": "OKP", "rsa-old": "RSA"}, kinds)
+
+	app := fiber.New()
+	app.Get("/.well-known/jwks.json", handler.JWKS)
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
+
+func TestNewKeySetFromConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  auth.KeySetConfig
+	}{
+		{name: "no keys", cfg: auth.KeySetConfig{}},
+		{name: "short secret", cfg: auth.KeySetConfig{Keys: []auth.KeyConfig{{ID: "a", Algorithm: "HS256", Secret: "short"}}}},
+		{name: "unsupported alg", cfg: auth.KeySetConfig{Keys: []auth.KeyConfig{{ID: "a", Algorithm: "none", Secret: "x"}}}},
+		{name: "missing key file", cfg: auth.KeySetConfig{Keys: []auth.KeyConfig{{ID: "a", Algorithm: "EdDSA"}}}},
+		{name: "unknown active", cfg: auth.KeySetConfig{Active: "b", Keys: []auth.KeyConfig{{ID: "a", Secret: "secret-that-is-at-least-32-bytes!!"}}}},
+		{name: "duplicate kid", cfg: auth.KeySetConfig{Keys: []auth.KeyConfig{
+			{ID: "a", Secret: "secret-that-is-at-least-32-bytes!!"},
+			{ID: "a", Secret: "secret-that-is-at-least-32-bytes!!"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.NewKeySetFromConfig(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadKeySet(t *testing.T) {
+	t.Run("from JWT_SECRET", func(t *testing.T) {
+		t.Setenv("JWT_KEYS_FILE", "")
+		t.Setenv("JWT_SECRET_FILE", "")
+		t.Setenv("JWT_SECRET", "env-secret-that-is-at-least-32-bytes")
+
+		ks, err := auth.LoadKeySet()
+		require.NoError(t, err)
+		tokenStr, err := ks.Sign(userClaims())
+		require.NoError(t, err)
+		_, err = (&auth.AuthHandler{Keys: ks}).VerifyToken(tokenStr)
+		assert.NoError(t, err)
+	})
+
+	t.Run("from JWT_KEYS_FILE", func(t *testing.T) {
+		secretFile := filepath.Join(t.TempDir(), "secret")
+		require.NoError(t, os.WriteFile(secretFile, []byte("file-secret-that-is-at-least-32-bytes\n"), 0o600))
+		keysFile := filepath.Join(t.TempDir(), "keys.json")
+		require.NoError(t, os.WriteFile(keysFile, []byte(`{"active":"k2","keys":[
+			{"kid":"k1","alg":"HS256","secret_file":"`+secretFile+`"},
+			{"kid":"k2","alg":"HS256","secret_env":"ROTATED_SECRET"}]}`), 0o600))
+		t.Setenv("JWT_KEYS_FILE", keysFile)
+		t.Setenv("ROTATED_SECRET", "rotated-secret-that-is-at-least-32-bytes")
+
+		ks, err := auth.LoadKeySet()
+		require.NoError(t, err)
+		tokenStr, err := ks.Sign(userClaims())
+		require.NoError(t, err)
+		parsed, _, err := jwt.NewParser().ParseUnverified(tokenStr, jwt.MapClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, "k2", parsed.Header["kid"])
+	})
+
+	t.Run("random key when unconfigured", func(t *testing.T) {
+		t.Setenv("JWT_KEYS_FILE", "")
+		t.Setenv("JWT_SECRET", "")
+		t.Setenv("JWT_SECRET_FILE", "")
+
+		ks, err := auth.LoadKeySet()
+		require.NoError(t, err)
+		_, err = ks.Sign(userClaims())
+		assert.NoError(t, err)
+	})
+}
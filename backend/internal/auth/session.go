@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+)
+
+var (
+	errInvalidSession = errors.New("Invalid session in token")
+	errSessionRevoked = errors.New("Session has been revoked or expired")
+)
+
+func generateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// refresh token ถูกเก็บเป็น hash เท่านั้น
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func setAuthCookies(c *fiber.Ctx, accessToken, refreshToken string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     "token",
+		Value:    accessToken,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Strict",
+		Path:     "/",
+		Expires:  time.Now().Add(AccessTokenTTL),
+	})
+	c.Cookie(&fiber.Cookie{
+		Name:     "refresh_token",
+		Value:    refreshToken,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Strict",
+		Path:     "/api/refresh",
+		Expires:  time.Now().Add(RefreshTokenTTL),
+	})
+}
+
+func clearAuthCookies(c *fiber.Ctx) {
+	expired := time.Unix(0, 0)
+	c.Cookie(&fiber.Cookie{Name: "token", Path: "/", HTTPOnly: true, Secure: true, SameSite: "Strict", Expires: expired})
+	c.Cookie(&fiber.Cookie{Name: "refresh_token", Path: "/api/refresh", HTTPOnly: true, Secure: true, SameSite: "Strict", Expires: expired})
+}
+
+// startSession stores a new session for the user and returns its refresh token.
+func (h *AuthHandler) startSession(c *fiber.Ctx, sessionID, userID string) (string, error) {
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	err = h.SessionRepo.CreateSession(c.Context(), db.CreateSessionParams{
+		ID:               sessionID,
+		UserID:           userID,
+		RefreshTokenHash: hashRefreshToken(refreshToken),
+		UserAgent:        sql.NullString{String: c.Get(fiber.HeaderUserAgent), Valid: c.Get(fiber.HeaderUserAgent) != ""},
+		IpAddress:        sql.NullString{String: c.IP(), Valid: c.IP() != ""},
+		CreatedAt:        now,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(RefreshTokenTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// checkSession makes sure the session referenced by the access token still
+// belongs to the user and has not been revoked.
+func (h *AuthHandler) checkSession(ctx context.Context, claims jwt.MapClaims, userID string) (string, error) {
+	sessionID, ok := claims["sid"].(string)
+	if !ok || sessionID == "" || h.SessionRepo == nil {
+		return "", errInvalidSession
+	}
+
+	session, err := h.SessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil || session.UserID != userID {
+		return "", errInvalidSession
+	}
+
+	if session.RevokedAt.Valid || time.Now().After(session.ExpiresAt) {
+		return "", errSessionRevoked
+	}
+
+	return sessionID, nil
+}
+
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	refreshToken := c.Cookies("refresh_token")
+	if refreshToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Missing refresh token"})
+	}
+
+	ctx := c.Context()
+	hash := hashRefreshToken(refreshToken)
+
+	session, err := h.SessionRepo.GetSessionByRefreshTokenHash(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load session"})
+		}
+
+		// token ที่ถูก rotate ไปแล้วถูกนำกลับมาใช้ซ้ำ ถือว่าถูกขโมย ให้ revoke ทั้ง session
+		if reused, err := h.SessionRepo.GetSessionByPreviousTokenHash(ctx, hash); err == nil {
+			log.Printf("Refresh token reuse detected for session %s", reused.ID)
+			if err := h.SessionRepo.RevokeSession(ctx, reused.ID); err != nil {
+				log.Printf("Failed to revoke session %s: %v", reused.ID, err)
+			}
+		}
+
+		clearAuthCookies(c)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid refresh token"})
+	}
+
+	now := time.Now().UTC()
+	if session.RevokedAt.Valid || now.After(session.ExpiresAt) {
+		clearAuthCookies(c)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": errSessionRevoked.Error()})
+	}
+
+	newRefreshToken, err := generateRefreshToken()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create refresh token"})
+	}
+
+	rotated, err := h.SessionRepo.RotateSessionToken(ctx, db.RotateSessionTokenParams{
+		RefreshTokenHash:   hashRefreshToken(newRefreshToken),
+		LastUsedAt:         now,
+		ExpiresAt:          now.Add(RefreshTokenTTL),
+		ID:                 session.ID,
+		RefreshTokenHash_2: hash,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to rotate refresh token"})
+	}
+	if rotated == 0 {
+		// มี request อื่น rotate token นี้ไปก่อนแล้ว
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid refresh token"})
+	}
+
+	accessToken, err := h.CreateTokenFunc(models.User{ID: session.UserID}, session.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Error while creating token"})
+	}
+
+	setAuthCookies(c, accessToken, newRefreshToken)
+
+	return c.JSON(fiber.Map{"message": "Token refreshed"})
+}
+
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	sessionID, _ := c.Locals("sessionID").(string)
+	if sessionID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": errInvalidSession.Error()})
+	}
+
+	if err := h.SessionRepo.RevokeSession(c.Context(), sessionID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to revoke session"})
+	}
+
+	clearAuthCookies(c)
+	return c.JSON(fiber.Map{"message": "Logged out"})
+}
+
+func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	if err := h.SessionRepo.RevokeSessionsByUserID(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to revoke sessions"})
+	}
+
+	clearAuthCookies(c)
+	return c.JSON(fiber.Map{"message": "Logged out from all devices"})
+}
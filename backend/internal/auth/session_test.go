@@ -0,0 +1,203 @@
+package auth_test
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/auth"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func cookieNamed(resp *http.Response, name string) *http.Cookie {
+	for _, c := range resp.Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestRefresh(t *testing.T) {
+	oldHash := sha256Hex("old-refresh-token")
+
+	tests := []struct {
+		name       string
+		cookie     string
+		setupMocks func(m *MockSessionRepo)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "missing cookie",
+			setupMocks: func(m *MockSessionRepo) {},
+			wantStatus: fiber.StatusUnauthorized,
+			wantBody:   "Missing refresh token",
+		},
+		{
+			name:   "success rotates token",
+			cookie: "old-refresh-token",
+			setupMocks: func(m *MockSessionRepo) {
+				m.On("GetSessionByRefreshTokenHash", mock.Anything, oldHash).
+					Return(activeSession("session-1", "user-1"), nil)
+				m.On("RotateSessionToken", mock.Anything, mock.MatchedBy(func(p db.RotateSessionTokenParams) bool {
+					return p.ID == "session-1" && p.RefreshTokenHash_2 == oldHash && p.RefreshTokenHash != oldHash
+				})).Return(int64(1), nil)
+			},
+			wantStatus: fiber.StatusOK,
+			wantBody:   "Token refreshed",
+		},
+		{
+			name:   "reused token revokes session",
+			cookie: "old-refresh-token",
+			setupMocks: func(m *MockSessionRepo) {
+				m.On("GetSessionByRefreshTokenHash", mock.Anything, oldHash).Return(db.Session{}, sql.ErrNoRows)
+				m.On("GetSessionByPreviousTokenHash", mock.Anything, oldHash).
+					Return(activeSession("session-1", "user-1"), nil)
+				m.On("RevokeSession", mock.Anything, "session-1").Return(nil)
+			},
+			wantStatus: fiber.StatusUnauthorized,
+			wantBody:   "Invalid refresh token",
+		},
+		{
+			name:   "revoked session",
+			cookie: "old-refresh-token",
+			setupMocks: func(m *MockSessionRepo) {
+				m.On("GetSessionByRefreshTokenHash", mock.Anything, oldHash).Return(db.Session{
+					ID:        "session-1",
+					UserID:    "user-1",
+					ExpiresAt: time.Now().Add(time.Hour),
+					RevokedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				}, nil)
+			},
+			wantStatus: fiber.StatusUnauthorized,
+			wantBody:   "Session has been revoked",
+		},
+		{
+			name:   "concurrent rotation",
+			cookie: "old-refresh-token",
+			setupMocks: func(m *MockSessionRepo) {
+				m.On("GetSessionByRefreshTokenHash", mock.Anything, oldHash).
+					Return(activeSession("session-1", "user-1"), nil)
+				m.On("RotateSessionToken", mock.Anything, mock.Anything).Return(int64(0), nil)
+			},
+			wantStatus: fiber.StatusUnauthorized,
+			wantBody:   "Invalid refresh token",
+		},
+		{
+			name:   "lookup fails",
+			cookie: "old-refresh-token",
+			setupMocks: func(m *MockSessionRepo) {
+				m.On("GetSessionByRefreshTokenHash", mock.Anything, oldHash).Return(db.Session{}, errors.New("db error"))
+			},
+			wantStatus: fiber.StatusInternalServerError,
+			wantBody:   "Failed to load session",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := new(MockSessionRepo)
+			tt.setupMocks(sessionRepo)
+			handler := auth.NewAuthHandler(new(MockUserRepo), sessionRepo, testKeys)
+			handler.CreateTokenFunc = func(user models.User, sessionID string) (string, error) {
+				return "access." + user.ID + "." + sessionID, nil
+			}
+
+			app := fiber.New()
+			app.Post("/api/refresh", handler.Refresh)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/refresh", nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "refresh_token", Value: tt.cookie})
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			body, _ := io.ReadAll(resp.Body)
+			assert.Contains(t, string(body), tt.wantBody)
+
+			if tt.wantStatus == fiber.StatusOK {
+				access := cookieNamed(resp, "token")
+				require.NotNil(t, access)
+				assert.Equal(t, "access.user-1.session-1", access.Value)
+				refresh := cookieNamed(resp, "refresh_token")
+				require.NotNil(t, refresh)
+				assert.NotEqual(t, "old-refresh-token", refresh.Value)
+			}
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func withSession(userID, sessionID string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("userID", userID)
+		c.Locals("sessionID", sessionID)
+		return c.Next()
+	}
+}
+
+func TestLogout(t *testing.T) {
+	sessionRepo := new(MockSessionRepo)
+	sessionRepo.On("RevokeSession", mock.Anything, "session-1").Return(nil)
+	handler := auth.NewAuthHandler(new(MockUserRepo), sessionRepo, testKeys)
+
+	app := fiber.New()
+	app.Use(withSession("user-1", "session-1"))
+	app.Post("/logout", handler.Logout)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/logout", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	access := cookieNamed(resp, "token")
+	require.NotNil(t, access)
+	assert.Empty(t, access.Value)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestLogoutAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		revokeErr  error
+		wantStatus int
+	}{
+		{name: "success", wantStatus: fiber.StatusOK},
+		{name: "revoke fails", revokeErr: errors.New("db error"), wantStatus: fiber.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := new(MockSessionRepo)
+			sessionRepo.On("RevokeSessionsByUserID", mock.Anything, "user-1").Return(tt.revokeErr)
+			handler := auth.NewAuthHandler(new(MockUserRepo), sessionRepo, testKeys)
+
+			app := fiber.New()
+			app.Use(withSession("user-1", "session-1"))
+			app.Post("/logout/all", handler.LogoutAll)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/logout/all", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
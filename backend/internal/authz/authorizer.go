@@ -0,0 +1,63 @@
+package authz
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+// Store looks up the roles a user holds. It is implemented by repositories.RoleRepository.
+type Store interface {
+	GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (string, error)
+	GetTeamRoles(ctx context.Context, teamID, userID string) (db.GetTeamMemberRolesRow, error)
+}
+
+type Authorizer struct {
+	Store Store
+}
+
+func NewAuthorizer(store Store) *Authorizer {
+	return &Authorizer{Store: store}
+}
+
+// Resolve loads the roles of the user for the resource. When the resource
+// belongs to a team the workspace is taken from the team.
+func (a *Authorizer) Resolve(ctx context.Context, userID string, r Resource) (Subject, error) {
+	s := Subject{UserID: userID}
+
+	if r.TeamID != "" {
+		roles, err := a.Store.GetTeamRoles(ctx, r.TeamID, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return s, nil
+			}
+			return s, err
+		}
+		s.WorkspaceRole = Role(roles.WorkspaceRole)
+		s.TeamRole = Role(roles.TeamRole)
+		return s, nil
+	}
+
+	if r.WorkspaceID != "" {
+		role, err := a.Store.GetWorkspaceRole(ctx, r.WorkspaceID, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return s, nil
+			}
+			return s, err
+		}
+		s.WorkspaceRole = Role(role)
+	}
+
+	return s, nil
+}
+
+func (a *Authorizer) Can(ctx context.Context, userID string, action Action, r Resource) (bool, error) {
+	s, err := a.Resolve(ctx, userID, r)
+	if err != nil {
+		return false, err
+	}
+	return Can(s, action, r), nil
+}
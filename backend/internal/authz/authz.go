@@ -0,0 +1,230 @@
+package authz
+
+import "github.com/nack098/nakumanager/internal/db"
+
+type Role string
+
+// Workspace roles
+const (
+	RoleOwner  Role = "owner"
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+	RoleGuest  Role = "guest"
+)
+
+// Team roles
+const (
+	TeamRoleLead   Role = "lead"
+	TeamRoleMember Role = "member"
+)
+
+func IsWorkspaceRole(r string) bool {
+	switch Role(r) {
+	case RoleOwner, RoleAdmin, RoleMember, RoleGuest:
+		return true
+	}
+	return false
+}
+
+type Action string
+
+const (
+	WorkspaceView          Action = "workspace:view"
+	WorkspaceUpdate        Action = "workspace:update"
+	WorkspaceDelete        Action = "workspace:delete"
+	WorkspaceManageMembers Action = "workspace:manage_members"
+	WorkspaceManageAdmins  Action = "workspace:manage_admins"
+
+	TeamCreate Action = "team:create"
+	TeamView   Action = "team:view"
+	TeamUpdate Action = "team:update"
+	TeamDelete Action = "team:delete"
+
+	ProjectCreate Action = "project:create"
+	ProjectView   Action = "project:view"
+	ProjectUpdate Action = "project:update"
+	ProjectDelete Action = "project:delete"
+
+	ProjectUpdateEdit   Action = "project_update:edit"
+	ProjectUpdateDelete Action = "project_update:delete"
+
+	IssueCreate  Action = "issue:create"
+	IssueView    Action = "issue:view"
+	IssueUpdate  Action = "issue:update"
+	IssueDelete  Action = "issue:delete"
+	IssueComment Action = "issue:comment"
+
+	CommentUpdate Action = "comment:update"
+	CommentDelete Action = "comment:delete"
+
+	ViewCreate Action = "view:create"
+	ViewView   Action = "view:view"
+	ViewUpdate Action = "view:update"
+	ViewDelete Action = "view:delete"
+
+	LabelCreate Action = "label:create"
+	LabelUpdate Action = "label:update"
+	LabelDelete Action = "label:delete"
+
+	CycleCreate Action = "cycle:create"
+	CycleUpdate Action = "cycle:update"
+	CycleDelete Action = "cycle:delete"
+
+	TemplateCreate Action = "template:create"
+	TemplateUpdate Action = "template:update"
+	TemplateDelete Action = "template:delete"
+
+	RecurringIssueCreate Action = "recurring_issue:create"
+	RecurringIssueUpdate Action = "recurring_issue:update"
+	RecurringIssueDelete Action = "recurring_issue:delete"
+)
+
+// Subject is the user with the roles they hold in the workspace and team of the
+// resource. An empty role means the user is not a member.
+type Subject struct {
+	UserID        string
+	WorkspaceRole Role
+	TeamRole      Role
+}
+
+// Resource is what an action is performed on. OwnerID is the creator or owner
+// of the resource and LeaderID its leader, when the resource has one.
+type Resource struct {
+	WorkspaceID string
+	TeamID      string
+	OwnerID     string
+	LeaderID    string
+}
+
+func ForWorkspace(workspaceID string) Resource {
+	return Resource{WorkspaceID: workspaceID}
+}
+
+func ForTeam(teamID string) Resource {
+	return Resource{TeamID: teamID}
+}
+
+func ForProject(p db.Project) Resource {
+	var leaderID string
+	switch v := p.LeaderID.(type) {
+	case string:
+		leaderID = v
+	case []byte:
+		leaderID = string(v)
+	}
+	return Resource{WorkspaceID: p.WorkspaceID, TeamID: p.TeamID, OwnerID: p.CreatedBy, LeaderID: leaderID}
+}
+
+// ForProjectUpdate is a status update posted on the project; its owner is the
+// author.
+func ForProjectUpdate(p db.Project, u db.ProjectUpdate) Resource {
+	return Resource{WorkspaceID: p.WorkspaceID, TeamID: p.TeamID, OwnerID: u.AuthorID}
+}
+
+func ForIssue(i db.Issue) Resource {
+	return Resource{TeamID: i.TeamID, OwnerID: i.OwnerID}
+}
+
+func ForComment(i db.Issue, c db.IssueComment) Resource {
+	return Resource{TeamID: i.TeamID, OwnerID: c.AuthorID}
+}
+
+func ForView(v db.View) Resource {
+	return Resource{TeamID: v.TeamID, OwnerID: v.CreatedBy}
+}
+
+func ForCycle(c db.Cycle) Resource {
+	return Resource{TeamID: c.TeamID}
+}
+
+func ForIssueTemplate(t db.IssueTemplate) Resource {
+	return Resource{TeamID: t.TeamID, OwnerID: t.CreatedBy}
+}
+
+func ForRecurringIssue(r db.RecurringIssue) Resource {
+	return Resource{TeamID: r.TeamID, OwnerID: r.CreatedBy}
+}
+
+// ForLabel is the workspace of a workspace label, or the team of a team label.
+func ForLabel(l db.Label) Resource {
+	return Resource{WorkspaceID: l.WorkspaceID, TeamID: l.TeamID.String}
+}
+
+// Can reports whether the subject may perform the action on the resource.
+func Can(s Subject, action Action, r Resource) bool {
+	if s.UserID == "" {
+		return false
+	}
+
+	inWorkspace := s.WorkspaceRole != ""
+	wsAdmin := s.WorkspaceRole == RoleOwner || s.WorkspaceRole == RoleAdmin
+	// guest อ่านได้อย่างเดียว
+	writer := inWorkspace && s.WorkspaceRole != RoleGuest
+	teamMember := inWorkspace && s.TeamRole != ""
+	teamLead := writer && s.TeamRole == TeamRoleLead
+	isOwner := r.OwnerID != "" && r.OwnerID == s.UserID
+	isLeader := r.LeaderID != "" && r.LeaderID == s.UserID
+
+	switch action {
+	case WorkspaceView:
+		return inWorkspace
+	case WorkspaceUpdate, WorkspaceManageMembers, TeamCreate:
+		return wsAdmin
+	case WorkspaceDelete, WorkspaceManageAdmins:
+		return s.WorkspaceRole == RoleOwner
+
+	case TeamView, ProjectView, ViewView:
+		return wsAdmin || teamMember
+	case TeamUpdate, TeamDelete:
+		return wsAdmin || teamLead
+
+	case ProjectCreate, IssueCreate, ViewCreate:
+		return wsAdmin || (writer && teamMember)
+	case ProjectUpdate, ProjectDelete:
+		return wsAdmin || teamLead || (writer && (isOwner || isLeader))
+	// รายงานความคืบหน้าแก้ได้เฉพาะผู้เขียน ส่วนผู้ดูแลทีมลบทิ้งได้
+	case ProjectUpdateEdit:
+		return writer && isOwner
+	case ProjectUpdateDelete:
+		return wsAdmin || teamLead || (writer && isOwner)
+
+	case IssueView, IssueComment:
+		return wsAdmin || teamMember || (inWorkspace && isOwner)
+	case IssueUpdate, IssueDelete:
+		return wsAdmin || (writer && (teamMember || isOwner))
+
+	case CommentUpdate:
+		return inWorkspace && isOwner
+	case CommentDelete:
+		return wsAdmin || teamLead || (inWorkspace && isOwner)
+
+	case ViewUpdate, ViewDelete:
+		return wsAdmin || teamLead || (writer && teamMember && isOwner)
+
+	// ทุกคนที่เขียนได้ในขอบเขตของ label สร้างและแก้ label ได้ แต่ลบหรือรวมได้เฉพาะ admin หรือหัวหน้าของทีมเจ้าของ label
+	case LabelCreate, LabelUpdate:
+		return wsAdmin || (writer && (r.TeamID == "" || teamMember))
+	case LabelDelete:
+		return wsAdmin || (r.TeamID != "" && teamLead)
+
+	// สมาชิกทีมวางแผนรอบและปิดรอบได้ แต่ลบรอบทิ้งได้เฉพาะหัวหน้าทีม
+	case CycleCreate, CycleUpdate:
+		return wsAdmin || (writer && teamMember)
+	case CycleDelete:
+		return wsAdmin || teamLead
+
+	// template ใช้ร่วมกันทั้งทีม สมาชิกแก้ได้ แต่ลบได้เฉพาะผู้สร้างหรือหัวหน้าทีม
+	case TemplateCreate, TemplateUpdate:
+		return wsAdmin || (writer && teamMember)
+	case TemplateDelete:
+		return wsAdmin || teamLead || (writer && teamMember && isOwner)
+
+	// issue ที่สร้างตามรอบสร้างในนามของผู้ตั้ง จึงแก้ได้เฉพาะผู้ตั้งหรือหัวหน้าทีม
+	case RecurringIssueCreate:
+		return wsAdmin || (writer && teamMember)
+	case RecurringIssueUpdate, RecurringIssueDelete:
+		return wsAdmin || teamLead || (writer && teamMember && isOwner)
+	}
+
+	return false
+}
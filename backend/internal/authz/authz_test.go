@@ -0,0 +1,142 @@
+package authz_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type fakeStore struct {
+	workspaceRole string
+	teamRoles     db.GetTeamMemberRolesRow
+	err           error
+}
+
+func (f *fakeStore) GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (string, error) {
+	return f.workspaceRole, f.err
+}
+
+func (f *fakeStore) GetTeamRoles(ctx context.Context, teamID, userID string) (db.GetTeamMemberRolesRow, error) {
+	return f.teamRoles, f.err
+}
+
+func TestCan(t *testing.T) {
+	subject := func(wsRole, teamRole authz.Role) authz.Subject {
+		return authz.Subject{UserID: "user-1", WorkspaceRole: wsRole, TeamRole: teamRole}
+	}
+	team := authz.ForTeam("team-1")
+	workspace := authz.ForWorkspace("ws-1")
+	ownIssue := authz.Resource{TeamID: "team-1", OwnerID: "user-1"}
+	otherIssue := authz.Resource{TeamID: "team-1", OwnerID: "user-2"}
+	ledProject := authz.Resource{TeamID: "team-1", OwnerID: "user-2", LeaderID: "user-1"}
+
+	tests := []struct {
+		name     string
+		subject  authz.Subject
+		action   authz.Action
+		resource authz.Resource
+		want     bool
+	}{
+		{"anonymous is denied", authz.Subject{WorkspaceRole: authz.RoleOwner}, authz.WorkspaceView, team, false},
+		{"owner deletes workspace", subject(authz.RoleOwner, ""), authz.WorkspaceDelete, team, true},
+		{"admin cannot delete workspace", subject(authz.RoleAdmin, ""), authz.WorkspaceDelete, team, false},
+		{"admin manages members", subject(authz.RoleAdmin, ""), authz.WorkspaceManageMembers, team, true},
+		{"admin cannot manage admins", subject(authz.RoleAdmin, ""), authz.WorkspaceManageAdmins, team, false},
+		{"member cannot create team", subject(authz.RoleMember, ""), authz.TeamCreate, team, false},
+		{"guest views workspace", subject(authz.RoleGuest, ""), authz.WorkspaceView, team, true},
+		{"outsider cannot view workspace", subject("", ""), authz.WorkspaceView, team, false},
+
+		{"lead updates team", subject(authz.RoleMember, authz.TeamRoleLead), authz.TeamUpdate, team, true},
+		{"team member cannot update team", subject(authz.RoleMember, authz.TeamRoleMember), authz.TeamUpdate, team, false},
+		{"guest lead cannot update team", subject(authz.RoleGuest, authz.TeamRoleLead), authz.TeamUpdate, team, false},
+
+		{"team member creates issue", subject(authz.RoleMember, authz.TeamRoleMember), authz.IssueCreate, team, true},
+		{"guest cannot create issue", subject(authz.RoleGuest, authz.TeamRoleMember), authz.IssueCreate, team, false},
+		{"non team member cannot create issue", subject(authz.RoleMember, ""), authz.IssueCreate, team, false},
+		{"admin creates issue in any team", subject(authz.RoleAdmin, ""), authz.IssueCreate, team, true},
+
+		{"guest views team issue", subject(authz.RoleGuest, authz.TeamRoleMember), authz.IssueView, otherIssue, true},
+		{"guest cannot update issue", subject(authz.RoleGuest, authz.TeamRoleMember), authz.IssueUpdate, otherIssue, false},
+		{"owner outside team views issue", subject(authz.RoleMember, ""), authz.IssueView, ownIssue, true},
+		{"owner outside team updates issue", subject(authz.RoleMember, ""), authz.IssueUpdate, ownIssue, true},
+		{"outsider cannot view issue", subject(authz.RoleMember, ""), authz.IssueView, otherIssue, false},
+
+		{"author edits comment", subject(authz.RoleGuest, ""), authz.CommentUpdate, ownIssue, true},
+		{"admin cannot edit others comment", subject(authz.RoleAdmin, ""), authz.CommentUpdate, otherIssue, false},
+		{"lead deletes others comment", subject(authz.RoleMember, authz.TeamRoleLead), authz.CommentDelete, otherIssue, true},
+		{"member cannot delete others comment", subject(authz.RoleMember, authz.TeamRoleMember), authz.CommentDelete, otherIssue, false},
+
+		{"leader updates project", subject(authz.RoleMember, authz.TeamRoleMember), authz.ProjectUpdate, ledProject, true},
+		{"member cannot update others project", subject(authz.RoleMember, authz.TeamRoleMember), authz.ProjectUpdate, otherIssue, false},
+		{"author edits project update", subject(authz.RoleMember, authz.TeamRoleMember), authz.ProjectUpdateEdit, ownIssue, true},
+		{"lead cannot edit others project update", subject(authz.RoleMember, authz.TeamRoleLead), authz.ProjectUpdateEdit, otherIssue, false},
+		{"lead deletes others project update", subject(authz.RoleMember, authz.TeamRoleLead), authz.ProjectUpdateDelete, otherIssue, true},
+		{"member cannot delete others project update", subject(authz.RoleMember, authz.TeamRoleMember), authz.ProjectUpdateDelete, otherIssue, false},
+
+		{"creator deletes own view", subject(authz.RoleMember, authz.TeamRoleMember), authz.ViewDelete, ownIssue, true},
+		{"member cannot delete others view", subject(authz.RoleMember, authz.TeamRoleMember), authz.ViewDelete, otherIssue, false},
+		{"member creates workspace label", subject(authz.RoleMember, ""), authz.LabelCreate, workspace, true},
+		{"guest cannot create workspace label", subject(authz.RoleGuest, ""), authz.LabelCreate, workspace, false},
+		{"non team member cannot create team label", subject(authz.RoleMember, ""), authz.LabelCreate, team, false},
+		{"member cannot delete workspace label", subject(authz.RoleMember, authz.TeamRoleLead), authz.LabelDelete, workspace, false},
+		{"lead deletes team label", subject(authz.RoleMember, authz.TeamRoleLead), authz.LabelDelete, team, true},
+		{"team member cannot delete team label", subject(authz.RoleMember, authz.TeamRoleMember), authz.LabelDelete, team, false},
+		{"team member creates cycle", subject(authz.RoleMember, authz.TeamRoleMember), authz.CycleCreate, team, true},
+		{"guest cannot close cycle", subject(authz.RoleGuest, authz.TeamRoleMember), authz.CycleUpdate, team, false},
+		{"team member cannot delete cycle", subject(authz.RoleMember, authz.TeamRoleMember), authz.CycleDelete, team, false},
+		{"lead deletes cycle", subject(authz.RoleMember, authz.TeamRoleLead), authz.CycleDelete, team, true},
+		{"team member updates template", subject(authz.RoleMember, authz.TeamRoleMember), authz.TemplateUpdate, otherIssue, true},
+		{"team member cannot delete others' template", subject(authz.RoleMember, authz.TeamRoleMember), authz.TemplateDelete, otherIssue, false},
+		{"team member deletes own template", subject(authz.RoleMember, authz.TeamRoleMember), authz.TemplateDelete, ownIssue, true},
+		{"team member cannot update others' recurring issue", subject(authz.RoleMember, authz.TeamRoleMember), authz.RecurringIssueUpdate, otherIssue, false},
+		{"lead pauses any recurring issue", subject(authz.RoleMember, authz.TeamRoleLead), authz.RecurringIssueUpdate, otherIssue, true},
+
+		{"unknown action is denied", subject(authz.RoleOwner, authz.TeamRoleLead), authz.Action("unknown"), team, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, authz.Can(tt.subject, tt.action, tt.resource))
+		})
+	}
+}
+
+func TestAuthorizerResolve(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("team resource uses team roles", func(t *testing.T) {
+		a := authz.NewAuthorizer(&fakeStore{teamRoles: db.GetTeamMemberRolesRow{WorkspaceID: "ws-1", WorkspaceRole: "admin", TeamRole: "lead"}})
+		s, err := a.Resolve(ctx, "user-1", authz.ForTeam("team-1"))
+		require.NoError(t, err)
+		assert.Equal(t, authz.RoleAdmin, s.WorkspaceRole)
+		assert.Equal(t, authz.TeamRoleLead, s.TeamRole)
+	})
+
+	t.Run("workspace resource uses workspace role", func(t *testing.T) {
+		a := authz.NewAuthorizer(&fakeStore{workspaceRole: "guest"})
+		s, err := a.Resolve(ctx, "user-1", authz.ForWorkspace("ws-1"))
+		require.NoError(t, err)
+		assert.Equal(t, authz.RoleGuest, s.WorkspaceRole)
+		assert.Empty(t, s.TeamRole)
+	})
+
+	t.Run("no rows means no roles", func(t *testing.T) {
+		a := authz.NewAuthorizer(&fakeStore{err: sql.ErrNoRows})
+		ok, err := a.Can(ctx, "user-1", authz.WorkspaceView, authz.ForWorkspace("ws-1"))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("store error is returned", func(t *testing.T) {
+		a := authz.NewAuthorizer(&fakeStore{err: errors.New("db down")})
+		_, err := a.Can(ctx, "user-1", authz.IssueView, authz.ForTeam("team-1"))
+		assert.Error(t, err)
+	})
+}
@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const addAssigneeToIssue = `-- name: AddAssigneeToIssue :exec
@@ -25,27 +26,46 @@ func (q *Queries) AddAssigneeToIssue(ctx context.Context, arg AddAssigneeToIssue
 	return err
 }
 
+const countIssueEvents = `-- name: CountIssueEvents :one
+SELECT COUNT(*) AS count
+FROM issue_events
+WHERE issue_id = ?
+`
+
+func (q *Queries) CountIssueEvents(ctx context.Context, issueID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countIssueEvents, issueID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createIssue = `-- name: CreateIssue :exec
 INSERT INTO issues (
 
     id, title, content, priority, status, project_id, team_id,
-    start_date, end_date, label, owner_id
+    start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id
 )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateIssueParams struct {
-	ID        string         `json:"id"`
-	Title     string         `json:"title"`
-	Content   sql.NullString `json:"content"`
-	Priority  sql.NullString `json:"priority"`
-	Status    string         `json:"status"`
-	ProjectID sql.NullString `json:"project_id"`
-	TeamID    string         `json:"team_id"`
-	StartDate sql.NullTime   `json:"start_date"`
-	EndDate   sql.NullTime   `json:"end_date"`
-	Label     sql.NullString `json:"label"`
-	OwnerID   string         `json:"owner_id"`
+	ID           string         `json:"id"`
+	Title        string         `json:"title"`
+	Content      sql.NullString `json:"content"`
+	Priority     sql.NullString `json:"priority"`
+	Status       string         `json:"status"`
+	ProjectID    sql.NullString `json:"project_id"`
+	TeamID       string         `json:"team_id"`
+	StartDate    sql.NullTime   `json:"start_date"`
+	EndDate      sql.NullTime   `json:"end_date"`
+	OwnerID      string         `json:"owner_id"`
+	Rank         string         `json:"rank"`
+	Number       int64          `json:"number"`
+	Identifier   string         `json:"identifier"`
+	ParentID     sql.NullString `json:"parent_id"`
+	CycleID      sql.NullString `json:"cycle_id"`
+	CycleAddedAt sql.NullTime   `json:"cycle_added_at"`
+	MilestoneID  sql.NullString `json:"milestone_id"`
 }
 
 func (q *Queries) CreateIssue(ctx context.Context, arg CreateIssueParams) error {
@@ -59,8 +79,42 @@ func (q *Queries) CreateIssue(ctx context.Context, arg CreateIssueParams) error
 		arg.TeamID,
 		arg.StartDate,
 		arg.EndDate,
-		arg.Label,
 		arg.OwnerID,
+		arg.Rank,
+		arg.Number,
+		arg.Identifier,
+		arg.ParentID,
+		arg.CycleID,
+		arg.CycleAddedAt,
+		arg.MilestoneID,
+	)
+	return err
+}
+
+const createIssueEvent = `-- name: CreateIssueEvent :exec
+INSERT INTO issue_events (id, issue_id, actor_id, field, old_value, new_value, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateIssueEventParams struct {
+	ID        string         `json:"id"`
+	IssueID   string         `json:"issue_id"`
+	ActorID   string         `json:"actor_id"`
+	Field     string         `json:"field"`
+	OldValue  sql.NullString `json:"old_value"`
+	NewValue  sql.NullString `json:"new_value"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+func (q *Queries) CreateIssueEvent(ctx context.Context, arg CreateIssueEventParams) error {
+	_, err := q.db.ExecContext(ctx, createIssueEvent,
+		arg.ID,
+		arg.IssueID,
+		arg.ActorID,
+		arg.Field,
+		arg.OldValue,
+		arg.NewValue,
+		arg.CreatedAt,
 	)
 	return err
 }
@@ -74,9 +128,40 @@ func (q *Queries) DeleteIssue(ctx context.Context, id string) error {
 	return err
 }
 
+const getIssueBoardRank = `-- name: GetIssueBoardRank :one
+SELECT i.id, i.team_id, i.status, COALESCE(vr.rank, i.rank) AS rank
+FROM issues i
+LEFT JOIN view_issue_ranks vr ON vr.issue_id = i.id AND vr.view_id = ?
+WHERE i.id = ?
+`
+
+type GetIssueBoardRankParams struct {
+	ViewID string `json:"view_id"`
+	ID     string `json:"id"`
+}
+
+type GetIssueBoardRankRow struct {
+	ID     string `json:"id"`
+	TeamID string `json:"team_id"`
+	Status string `json:"status"`
+	Rank   string `json:"rank"`
+}
+
+func (q *Queries) GetIssueBoardRank(ctx context.Context, arg GetIssueBoardRankParams) (GetIssueBoardRankRow, error) {
+	row := q.db.QueryRowContext(ctx, getIssueBoardRank, arg.ViewID, arg.ID)
+	var i GetIssueBoardRankRow
+	err := row.Scan(
+		&i.ID,
+		&i.TeamID,
+		&i.Status,
+		&i.Rank,
+	)
+	return i, err
+}
+
 const getIssueByID = `-- name: GetIssueByID :one
 
-SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id
 FROM issues
 WHERE id = ?
 `
@@ -94,15 +179,21 @@ func (q *Queries) GetIssueByID(ctx context.Context, id string) (Issue, error) {
 		&i.TeamID,
 		&i.StartDate,
 		&i.EndDate,
-		&i.Label,
 		&i.OwnerID,
+		&i.Rank,
+		&i.Number,
+		&i.Identifier,
+		&i.ParentID,
+		&i.CycleID,
+		&i.CycleAddedAt,
+		&i.MilestoneID,
 	)
 	return i, err
 }
 
 const getIssueByUserID = `-- name: GetIssueByUserID :many
 
-SELECT DISTINCT i.id, i.title, i.content, i.priority, i.status, i.project_id, i.team_id, i.start_date, i.end_date, i.label, i.owner_id
+SELECT DISTINCT i.id, i.title, i.content, i.priority, i.status, i.project_id, i.team_id, i.start_date, i.end_date, i.owner_id, i.rank, i.number, i.identifier, i.parent_id, i.cycle_id, i.cycle_added_at, i.milestone_id
 FROM issues i
 LEFT JOIN issue_assignees ia ON i.id = ia.issue_id
 WHERE i.owner_id = ? OR ia.user_id = ?
@@ -132,8 +223,14 @@ func (q *Queries) GetIssueByUserID(ctx context.Context, arg GetIssueByUserIDPara
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -148,6 +245,65 @@ func (q *Queries) GetIssueByUserID(ctx context.Context, arg GetIssueByUserIDPara
 	return items, nil
 }
 
+const getLastIssueBoardRank = `-- name: GetLastIssueBoardRank :one
+SELECT CAST(COALESCE(MAX(COALESCE(vr.rank, i.rank)), '') AS TEXT) AS rank
+FROM issues i
+LEFT JOIN view_issue_ranks vr ON vr.issue_id = i.id AND vr.view_id = ?
+WHERE i.team_id = ? AND i.status = ? AND i.id != ?
+`
+
+type GetLastIssueBoardRankParams struct {
+	ViewID string `json:"view_id"`
+	TeamID string `json:"team_id"`
+	Status string `json:"status"`
+	ID     string `json:"id"`
+}
+
+func (q *Queries) GetLastIssueBoardRank(ctx context.Context, arg GetLastIssueBoardRankParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLastIssueBoardRank,
+		arg.ViewID,
+		arg.TeamID,
+		arg.Status,
+		arg.ID,
+	)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getSubIssueProgress = `-- name: GetSubIssueProgress :one
+SELECT COUNT(*) AS total,
+       CAST(COALESCE(SUM(ts.category = 'completed'), 0) AS INTEGER) AS completed
+FROM issues i
+LEFT JOIN team_statuses ts ON ts.team_id = i.team_id AND ts.key = i.status
+WHERE i.parent_id = ? AND COALESCE(ts.category, '') != 'cancelled'
+`
+
+type GetSubIssueProgressRow struct {
+	Total     int64 `json:"total"`
+	Completed int64 `json:"completed"`
+}
+
+func (q *Queries) GetSubIssueProgress(ctx context.Context, parentID sql.NullString) (GetSubIssueProgressRow, error) {
+	row := q.db.QueryRowContext(ctx, getSubIssueProgress, parentID)
+	var i GetSubIssueProgressRow
+	err := row.Scan(&i.Total, &i.Completed)
+	return i, err
+}
+
+const getTeamKey = `-- name: GetTeamKey :one
+SELECT key
+FROM teams
+WHERE id = ?
+`
+
+func (q *Queries) GetTeamKey(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getTeamKey, id)
+	var key string
+	err := row.Scan(&key)
+	return key, err
+}
+
 const listAssigneesByIssueID = `-- name: ListAssigneesByIssueID :many
 SELECT u.id, u.username, u.password_hash, u.email, u.roles
 FROM users u
@@ -184,8 +340,259 @@ func (q *Queries) ListAssigneesByIssueID(ctx context.Context, issueID string) ([
 	return items, nil
 }
 
+const listChildIssues = `-- name: ListChildIssues :many
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id
+FROM issues
+WHERE parent_id = ?
+ORDER BY rank, id
+`
+
+func (q *Queries) ListChildIssues(ctx context.Context, parentID sql.NullString) ([]Issue, error) {
+	rows, err := q.db.QueryContext(ctx, listChildIssues, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Issue{}
+	for rows.Next() {
+		var i Issue
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.Priority,
+			&i.Status,
+			&i.ProjectID,
+			&i.TeamID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIssueAncestorIDs = `-- name: ListIssueAncestorIDs :many
+WITH RECURSIVE ancestors(id) AS (
+    SELECT parent_id FROM issues WHERE id = ? AND parent_id IS NOT NULL
+    UNION
+    SELECT p.parent_id FROM issues p JOIN ancestors a ON p.id = a.id WHERE p.parent_id IS NOT NULL
+)
+SELECT CAST(id AS TEXT) AS id
+FROM ancestors
+`
+
+func (q *Queries) ListIssueAncestorIDs(ctx context.Context, id string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listIssueAncestorIDs, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIssueDescendants = `-- name: ListIssueDescendants :many
+WITH RECURSIVE descendants(id) AS (
+    SELECT id FROM issues WHERE parent_id = ?
+    UNION
+    SELECT c.id FROM issues c JOIN descendants d ON c.parent_id = d.id
+)
+SELECT i.id, i.title, i.content, i.priority, i.status, i.project_id, i.team_id, i.start_date, i.end_date, i.owner_id, i.rank, i.number, i.identifier, i.parent_id, i.cycle_id, i.cycle_added_at, i.milestone_id
+FROM issues i
+JOIN descendants d ON d.id = i.id
+`
+
+func (q *Queries) ListIssueDescendants(ctx context.Context, parentID sql.NullString) ([]Issue, error) {
+	rows, err := q.db.QueryContext(ctx, listIssueDescendants, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Issue{}
+	for rows.Next() {
+		var i Issue
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.Priority,
+			&i.Status,
+			&i.ProjectID,
+			&i.TeamID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIssueEvents = `-- name: ListIssueEvents :many
+SELECT id, issue_id, actor_id, field, old_value, new_value, created_at
+FROM issue_events
+WHERE issue_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type ListIssueEventsParams struct {
+	IssueID string `json:"issue_id"`
+	Limit   int64  `json:"limit"`
+	Offset  int64  `json:"offset"`
+}
+
+func (q *Queries) ListIssueEvents(ctx context.Context, arg ListIssueEventsParams) ([]IssueEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listIssueEvents, arg.IssueID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []IssueEvent{}
+	for rows.Next() {
+		var i IssueEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.IssueID,
+			&i.ActorID,
+			&i.Field,
+			&i.OldValue,
+			&i.NewValue,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIssueIDsByIdentifier = `-- name: ListIssueIDsByIdentifier :many
+SELECT i.id
+FROM issues i
+JOIN teams t ON t.id = i.team_id
+JOIN workspace_members wm ON wm.workspace_id = t.workspace_id
+WHERE i.identifier = ? AND wm.user_id = ?
+`
+
+type ListIssueIDsByIdentifierParams struct {
+	Identifier string `json:"identifier"`
+	UserID     string `json:"user_id"`
+}
+
+func (q *Queries) ListIssueIDsByIdentifier(ctx context.Context, arg ListIssueIDsByIdentifierParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listIssueIDsByIdentifier, arg.Identifier, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIssueIDsByIdentifierAlias = `-- name: ListIssueIDsByIdentifierAlias :many
+SELECT a.issue_id
+FROM issue_identifier_aliases a
+JOIN issues i ON i.id = a.issue_id
+JOIN teams t ON t.id = i.team_id
+JOIN workspace_members wm ON wm.workspace_id = t.workspace_id
+WHERE a.identifier = ? AND wm.user_id = ?
+`
+
+type ListIssueIDsByIdentifierAliasParams struct {
+	Identifier string `json:"identifier"`
+	UserID     string `json:"user_id"`
+}
+
+func (q *Queries) ListIssueIDsByIdentifierAlias(ctx context.Context, arg ListIssueIDsByIdentifierAliasParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listIssueIDsByIdentifierAlias, arg.Identifier, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var issue_id string
+		if err := rows.Scan(&issue_id); err != nil {
+			return nil, err
+		}
+		items = append(items, issue_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listIssuesByProjectID = `-- name: ListIssuesByProjectID :many
-SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id
 FROM issues
 WHERE project_id = ?
 ORDER BY start_date DESC
@@ -210,8 +617,14 @@ func (q *Queries) ListIssuesByProjectID(ctx context.Context, projectID sql.NullS
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -228,7 +641,7 @@ func (q *Queries) ListIssuesByProjectID(ctx context.Context, projectID sql.NullS
 
 const listIssuesByTeamID = `-- name: ListIssuesByTeamID :many
 
-SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id
 FROM issues
 WHERE team_id = ?
 ORDER BY start_date DESC
@@ -253,8 +666,14 @@ func (q *Queries) ListIssuesByTeamID(ctx context.Context, teamID string) ([]Issu
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -269,6 +688,20 @@ func (q *Queries) ListIssuesByTeamID(ctx context.Context, teamID string) ([]Issu
 	return items, nil
 }
 
+const nextIssueNumber = `-- name: NextIssueNumber :one
+INSERT INTO team_issue_sequences (team_id, last_number)
+VALUES (?, 1)
+ON CONFLICT (team_id) DO UPDATE SET last_number = last_number + 1
+RETURNING last_number
+`
+
+func (q *Queries) NextIssueNumber(ctx context.Context, teamID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextIssueNumber, teamID)
+	var last_number int64
+	err := row.Scan(&last_number)
+	return last_number, err
+}
+
 const removeAssigneeFromIssue = `-- name: RemoveAssigneeFromIssue :exec
 DELETE FROM issue_assignees
 WHERE issue_id = ? AND user_id = ?
@@ -283,3 +716,49 @@ func (q *Queries) RemoveAssigneeFromIssue(ctx context.Context, arg RemoveAssigne
 	_, err := q.db.ExecContext(ctx, removeAssigneeFromIssue, arg.IssueID, arg.UserID)
 	return err
 }
+
+const setViewIssueRank = `-- name: SetViewIssueRank :exec
+INSERT INTO view_issue_ranks (view_id, issue_id, rank)
+VALUES (?, ?, ?)
+ON CONFLICT (view_id, issue_id) DO UPDATE SET rank = excluded.rank
+`
+
+type SetViewIssueRankParams struct {
+	ViewID  string `json:"view_id"`
+	IssueID string `json:"issue_id"`
+	Rank    string `json:"rank"`
+}
+
+func (q *Queries) SetViewIssueRank(ctx context.Context, arg SetViewIssueRankParams) error {
+	_, err := q.db.ExecContext(ctx, setViewIssueRank, arg.ViewID, arg.IssueID, arg.Rank)
+	return err
+}
+
+const updateIssueBoardPosition = `-- name: UpdateIssueBoardPosition :exec
+UPDATE issues SET status = ?, rank = ? WHERE id = ?
+`
+
+type UpdateIssueBoardPositionParams struct {
+	Status string `json:"status"`
+	Rank   string `json:"rank"`
+	ID     string `json:"id"`
+}
+
+func (q *Queries) UpdateIssueBoardPosition(ctx context.Context, arg UpdateIssueBoardPositionParams) error {
+	_, err := q.db.ExecContext(ctx, updateIssueBoardPosition, arg.Status, arg.Rank, arg.ID)
+	return err
+}
+
+const updateIssueStatus = `-- name: UpdateIssueStatus :exec
+UPDATE issues SET status = ? WHERE id = ?
+`
+
+type UpdateIssueStatusParams struct {
+	Status string `json:"status"`
+	ID     string `json:"id"`
+}
+
+func (q *Queries) UpdateIssueStatus(ctx context.Context, arg UpdateIssueStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateIssueStatus, arg.Status, arg.ID)
+	return err
+}
@@ -6,25 +6,141 @@ package db
 
 import (
 	"database/sql"
+	"time"
 )
 
+type Cycle struct {
+	ID              string         `json:"id"`
+	TeamID          string         `json:"team_id"`
+	Number          int64          `json:"number"`
+	Name            sql.NullString `json:"name"`
+	StartDate       time.Time      `json:"start_date"`
+	EndDate         time.Time      `json:"end_date"`
+	ClosedAt        sql.NullTime   `json:"closed_at"`
+	ScopeCount      int64          `json:"scope_count"`
+	CompletedCount  int64          `json:"completed_count"`
+	AddedCount      int64          `json:"added_count"`
+	RolledOverCount int64          `json:"rolled_over_count"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
 type Issue struct {
+	ID           string         `json:"id"`
+	Title        string         `json:"title"`
+	Content      sql.NullString `json:"content"`
+	Priority     sql.NullString `json:"priority"`
+	Status       string         `json:"status"`
+	ProjectID    sql.NullString `json:"project_id"`
+	TeamID       string         `json:"team_id"`
+	StartDate    sql.NullTime   `json:"start_date"`
+	EndDate      sql.NullTime   `json:"end_date"`
+	OwnerID      string         `json:"owner_id"`
+	Rank         string         `json:"rank"`
+	Number       int64          `json:"number"`
+	Identifier   string         `json:"identifier"`
+	ParentID     sql.NullString `json:"parent_id"`
+	CycleID      sql.NullString `json:"cycle_id"`
+	CycleAddedAt sql.NullTime   `json:"cycle_added_at"`
+	MilestoneID  sql.NullString `json:"milestone_id"`
+}
+
+type IssueAssignee struct {
+	IssueID string `json:"issue_id"`
+	UserID  string `json:"user_id"`
+}
+
+type IssueComment struct {
+	ID        string       `json:"id"`
+	IssueID   string       `json:"issue_id"`
+	AuthorID  string       `json:"author_id"`
+	Body      string       `json:"body"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
+type IssueCommentEdit struct {
+	ID           string    `json:"id"`
+	CommentID    string    `json:"comment_id"`
+	PreviousBody string    `json:"previous_body"`
+	EditedBy     string    `json:"edited_by"`
+	EditedAt     time.Time `json:"edited_at"`
+}
+
+type IssueCommentMention struct {
+	CommentID string `json:"comment_id"`
+	UserID    string `json:"user_id"`
+}
+
+type IssueEvent struct {
 	ID        string         `json:"id"`
+	IssueID   string         `json:"issue_id"`
+	ActorID   string         `json:"actor_id"`
+	Field     string         `json:"field"`
+	OldValue  sql.NullString `json:"old_value"`
+	NewValue  sql.NullString `json:"new_value"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type IssueIdentifierAlias struct {
+	IssueID    string `json:"issue_id"`
+	Identifier string `json:"identifier"`
+}
+
+type IssueLabel struct {
+	IssueID string `json:"issue_id"`
+	LabelID string `json:"label_id"`
+}
+
+type IssueRelation struct {
+	ID             string    `json:"id"`
+	IssueID        string    `json:"issue_id"`
+	RelatedIssueID string    `json:"related_issue_id"`
+	Type           string    `json:"type"`
+	CreatedBy      string    `json:"created_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type IssueTemplate struct {
+	ID        string         `json:"id"`
+	TeamID    string         `json:"team_id"`
+	Name      string         `json:"name"`
 	Title     string         `json:"title"`
 	Content   sql.NullString `json:"content"`
 	Priority  sql.NullString `json:"priority"`
-	Status    string         `json:"status"`
 	ProjectID sql.NullString `json:"project_id"`
-	TeamID    string         `json:"team_id"`
-	StartDate sql.NullTime   `json:"start_date"`
-	EndDate   sql.NullTime   `json:"end_date"`
-	Label     sql.NullString `json:"label"`
-	OwnerID   string         `json:"owner_id"`
+	CreatedBy string         `json:"created_by"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt sql.NullTime   `json:"updated_at"`
 }
 
-type IssueAssignee struct {
-	IssueID string `json:"issue_id"`
-	UserID  string `json:"user_id"`
+type IssueTemplateAssignee struct {
+	TemplateID string `json:"template_id"`
+	UserID     string `json:"user_id"`
+}
+
+type IssueTemplateLabel struct {
+	TemplateID string `json:"template_id"`
+	LabelID    string `json:"label_id"`
+}
+
+type Label struct {
+	ID          string         `json:"id"`
+	WorkspaceID string         `json:"workspace_id"`
+	TeamID      sql.NullString `json:"team_id"`
+	Name        string         `json:"name"`
+	Color       string         `json:"color"`
+	Description sql.NullString `json:"description"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type Notification struct {
+	ID        string       `json:"id"`
+	UserID    string       `json:"user_id"`
+	IssueID   string       `json:"issue_id"`
+	Type      string       `json:"type"`
+	DueAt     time.Time    `json:"due_at"`
+	CreatedAt time.Time    `json:"created_at"`
+	ReadAt    sql.NullTime `json:"read_at"`
 }
 
 type Project struct {
@@ -37,25 +153,118 @@ type Project struct {
 	LeaderID    interface{} `json:"leader_id"`
 	StartDate   interface{} `json:"start_date"`
 	EndDate     interface{} `json:"end_date"`
-	Label       interface{} `json:"label"`
 	CreatedBy   string      `json:"created_by"`
 }
 
+type ProjectLabel struct {
+	ProjectID string `json:"project_id"`
+	LabelID   string `json:"label_id"`
+}
+
 type ProjectMember struct {
 	ProjectID string `json:"project_id"`
 	UserID    string `json:"user_id"`
 }
 
+type ProjectMilestone struct {
+	ID         string       `json:"id"`
+	ProjectID  string       `json:"project_id"`
+	Name       string       `json:"name"`
+	TargetDate sql.NullTime `json:"target_date"`
+	Position   int64        `json:"position"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+type ProjectUpdate struct {
+	ID        string       `json:"id"`
+	ProjectID string       `json:"project_id"`
+	AuthorID  string       `json:"author_id"`
+	Health    string       `json:"health"`
+	Body      string       `json:"body"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+}
+
+type RecurringIssue struct {
+	ID         string         `json:"id"`
+	TeamID     string         `json:"team_id"`
+	TemplateID sql.NullString `json:"template_id"`
+	Title      string         `json:"title"`
+	Content    sql.NullString `json:"content"`
+	Priority   sql.NullString `json:"priority"`
+	ProjectID  sql.NullString `json:"project_id"`
+	Rule       string         `json:"rule"`
+	StartsAt   time.Time      `json:"starts_at"`
+	NextRunAt  sql.NullTime   `json:"next_run_at"`
+	LastRunAt  sql.NullTime   `json:"last_run_at"`
+	Paused     bool           `json:"paused"`
+	CreatedBy  string         `json:"created_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  sql.NullTime   `json:"updated_at"`
+}
+
+type RecurringIssueRun struct {
+	RecurringID  string         `json:"recurring_id"`
+	ScheduledFor time.Time      `json:"scheduled_for"`
+	IssueID      sql.NullString `json:"issue_id"`
+	Error        sql.NullString `json:"error"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type Session struct {
+	ID                string         `json:"id"`
+	UserID            string         `json:"user_id"`
+	RefreshTokenHash  string         `json:"refresh_token_hash"`
+	PreviousTokenHash sql.NullString `json:"previous_token_hash"`
+	UserAgent         sql.NullString `json:"user_agent"`
+	IpAddress         sql.NullString `json:"ip_address"`
+	CreatedAt         time.Time      `json:"created_at"`
+	LastUsedAt        time.Time      `json:"last_used_at"`
+	ExpiresAt         time.Time      `json:"expires_at"`
+	RevokedAt         sql.NullTime   `json:"revoked_at"`
+}
+
 type Team struct {
 	ID          string      `json:"id"`
 	Name        string      `json:"name"`
 	WorkspaceID string      `json:"workspace_id"`
 	LeaderID    interface{} `json:"leader_id"`
+	Key         string      `json:"key"`
+}
+
+type TeamIssueSequence struct {
+	TeamID     string `json:"team_id"`
+	LastNumber int64  `json:"last_number"`
+}
+
+type TeamIssueVersion struct {
+	TeamID  string `json:"team_id"`
+	Version int64  `json:"version"`
 }
 
 type TeamMember struct {
 	TeamID string `json:"team_id"`
 	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+type TeamStatus struct {
+	TeamID   string `json:"team_id"`
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Position int64  `json:"position"`
+	Color    string `json:"color"`
+}
+
+type TeamTransitionRule struct {
+	ID          string         `json:"id"`
+	TeamID      string         `json:"team_id"`
+	FromStatus  sql.NullString `json:"from_status"`
+	ToStatus    sql.NullString `json:"to_status"`
+	ToCategory  sql.NullString `json:"to_category"`
+	Requirement string         `json:"requirement"`
+	CreatedAt   time.Time      `json:"created_at"`
 }
 
 type User struct {
@@ -67,20 +276,26 @@ type User struct {
 }
 
 type View struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	CreatedBy string `json:"created_by"`
-	TeamID    string `json:"team_id"`
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	CreatedBy string         `json:"created_by"`
+	TeamID    string         `json:"team_id"`
+	Filter    sql.NullString `json:"filter"`
 }
 
 type ViewGroupBy struct {
-	ViewID  string `json:"view_id"`
-	GroupBy string `json:"group_by"`
+	ViewID     string `json:"view_id"`
+	GroupBy    string `json:"group_by"`
+	Position   int64  `json:"position"`
+	GroupOrder string `json:"group_order"`
+	Descending bool   `json:"descending"`
+	ShowEmpty  bool   `json:"show_empty"`
 }
 
-type ViewIssue struct {
+type ViewIssueRank struct {
 	ViewID  string `json:"view_id"`
 	IssueID string `json:"issue_id"`
+	Rank    string `json:"rank"`
 }
 
 type Workspace struct {
@@ -89,7 +304,21 @@ type Workspace struct {
 	OwnerID string `json:"owner_id"`
 }
 
+type WorkspaceInvitation struct {
+	ID          string       `json:"id"`
+	WorkspaceID string       `json:"workspace_id"`
+	Email       string       `json:"email"`
+	Role        string       `json:"role"`
+	TokenHash   string       `json:"token_hash"`
+	InvitedBy   string       `json:"invited_by"`
+	Status      string       `json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+	RespondedAt sql.NullTime `json:"responded_at"`
+}
+
 type WorkspaceMember struct {
 	WorkspaceID string `json:"workspace_id"`
 	UserID      string `json:"user_id"`
+	Role        string `json:"role"`
 }
@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const addMemberToProject = `-- name: AddMemberToProject :exec
@@ -26,8 +28,8 @@ func (q *Queries) AddMemberToProject(ctx context.Context, arg AddMemberToProject
 
 const createProject = `-- name: CreateProject :exec
 INSERT INTO projects (
-  id, name, status, priority, workspace_id, team_id, leader_id, start_date, end_date, label, created_by
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+  id, name, status, priority, workspace_id, team_id, leader_id, start_date, end_date, created_by
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateProjectParams struct {
@@ -40,7 +42,6 @@ type CreateProjectParams struct {
 	LeaderID    interface{} `json:"leader_id"`
 	StartDate   interface{} `json:"start_date"`
 	EndDate     interface{} `json:"end_date"`
-	Label       interface{} `json:"label"`
 	CreatedBy   string      `json:"created_by"`
 }
 
@@ -55,12 +56,63 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) er
 		arg.LeaderID,
 		arg.StartDate,
 		arg.EndDate,
-		arg.Label,
 		arg.CreatedBy,
 	)
 	return err
 }
 
+const createProjectMilestone = `-- name: CreateProjectMilestone :exec
+INSERT INTO project_milestones (id, project_id, name, target_date, position, created_at)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateProjectMilestoneParams struct {
+	ID         string       `json:"id"`
+	ProjectID  string       `json:"project_id"`
+	Name       string       `json:"name"`
+	TargetDate sql.NullTime `json:"target_date"`
+	Position   int64        `json:"position"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+func (q *Queries) CreateProjectMilestone(ctx context.Context, arg CreateProjectMilestoneParams) error {
+	_, err := q.db.ExecContext(ctx, createProjectMilestone,
+		arg.ID,
+		arg.ProjectID,
+		arg.Name,
+		arg.TargetDate,
+		arg.Position,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const createProjectUpdate = `-- name: CreateProjectUpdate :exec
+INSERT INTO project_updates (id, project_id, author_id, health, body, created_at)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateProjectUpdateParams struct {
+	ID        string    `json:"id"`
+	ProjectID string    `json:"project_id"`
+	AuthorID  string    `json:"author_id"`
+	Health    string    `json:"health"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) CreateProjectUpdate(ctx context.Context, arg CreateProjectUpdateParams) error {
+	_, err := q.db.ExecContext(ctx, createProjectUpdate,
+		arg.ID,
+		arg.ProjectID,
+		arg.AuthorID,
+		arg.Health,
+		arg.Body,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const deleteProject = `-- name: DeleteProject :exec
 DELETE FROM projects WHERE id = ?
 `
@@ -70,6 +122,45 @@ func (q *Queries) DeleteProject(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteProjectMilestone = `-- name: DeleteProjectMilestone :exec
+DELETE FROM project_milestones WHERE id = ?
+`
+
+func (q *Queries) DeleteProjectMilestone(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteProjectMilestone, id)
+	return err
+}
+
+const deleteProjectUpdate = `-- name: DeleteProjectUpdate :exec
+DELETE FROM project_updates WHERE id = ?
+`
+
+func (q *Queries) DeleteProjectUpdate(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteProjectUpdate, id)
+	return err
+}
+
+const editProjectUpdate = `-- name: EditProjectUpdate :exec
+UPDATE project_updates SET health = ?, body = ?, updated_at = ?
+WHERE id = ?
+`
+
+type EditProjectUpdateParams struct {
+	Health    string       `json:"health"`
+	Body      string       `json:"body"`
+	UpdatedAt sql.NullTime `json:"updated_at"`
+	ID        string       `json:"id"`
+}
+
+func (q *Queries) EditProjectUpdate(ctx context.Context, arg EditProjectUpdateParams) error {
+	_, err := q.db.ExecContext(ctx, editProjectUpdate,
+		arg.Health,
+		arg.Body,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
 
 const getLeaderByProjectID = `-- name: GetLeaderByProjectID :one
 SELECT leader_id
@@ -98,7 +189,7 @@ func (q *Queries) GetOwnerByProjectID(ctx context.Context, id string) (string, e
 }
 
 const getProjectByID = `-- name: GetProjectByID :one
-SELECT id, name, status, priority, workspace_id, team_id, leader_id, start_date, end_date, label, created_by
+SELECT id, name, status, priority, workspace_id, team_id, leader_id, start_date, end_date, created_by
 FROM projects
 WHERE id = ?
 `
@@ -116,16 +207,80 @@ func (q *Queries) GetProjectByID(ctx context.Context, id string) (Project, error
 		&i.LeaderID,
 		&i.StartDate,
 		&i.EndDate,
-		&i.Label,
 		&i.CreatedBy,
 	)
 	return i, err
 }
 
+const getProjectMilestone = `-- name: GetProjectMilestone :one
+SELECT id, project_id, name, target_date, position, created_at FROM project_milestones
+WHERE id = ?
+`
+
+func (q *Queries) GetProjectMilestone(ctx context.Context, id string) (ProjectMilestone, error) {
+	row := q.db.QueryRowContext(ctx, getProjectMilestone, id)
+	var i ProjectMilestone
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.TargetDate,
+		&i.Position,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProjectProgress = `-- name: GetProjectProgress :one
+SELECT COUNT(*) AS total,
+       CAST(COALESCE(SUM(ts.category = 'completed'), 0) AS INTEGER) AS completed
+FROM issues i
+LEFT JOIN team_statuses ts ON ts.team_id = i.team_id AND ts.key = i.status
+WHERE i.project_id = ? AND COALESCE(ts.category, '') != 'cancelled'
+`
+
+type GetProjectProgressRow struct {
+	Total     int64 `json:"total"`
+	Completed int64 `json:"completed"`
+}
+
+func (q *Queries) GetProjectProgress(ctx context.Context, projectID sql.NullString) (GetProjectProgressRow, error) {
+	row := q.db.QueryRowContext(ctx, getProjectProgress, projectID)
+	var i GetProjectProgressRow
+	err := row.Scan(&i.Total, &i.Completed)
+	return i, err
+}
+
+const getProjectUpdate = `-- name: GetProjectUpdate :one
+SELECT id, project_id, author_id, health, body, created_at, updated_at FROM project_updates
+WHERE id = ?
+`
+
+func (q *Queries) GetProjectUpdate(ctx context.Context, id string) (ProjectUpdate, error) {
+	row := q.db.QueryRowContext(ctx, getProjectUpdate, id)
+	var i ProjectUpdate
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.AuthorID,
+		&i.Health,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getProjectsByUserID = `-- name: GetProjectsByUserID :many
-SELECT DISTINCT p.id, p.name, p.status, p.priority, p.workspace_id, p.team_id, p.leader_id, p.start_date, p.end_date, p.label, p.created_by
+SELECT DISTINCT p.id, p.name, p.status, p.priority, p.workspace_id, p.team_id, p.leader_id, p.start_date, p.end_date, p.created_by, lu.health AS latest_health, lu.created_at AS latest_update_at
 FROM projects p
 LEFT JOIN project_members pm ON p.id = pm.project_id
+LEFT JOIN project_updates lu ON lu.id = (
+  SELECT pu.id FROM project_updates pu
+  WHERE pu.project_id = p.id
+  ORDER BY pu.created_at DESC, pu.id DESC
+  LIMIT 1
+)
 WHERE pm.user_id = ? OR p.created_by = ?
 `
 
@@ -134,15 +289,30 @@ type GetProjectsByUserIDParams struct {
 	CreatedBy string `json:"created_by"`
 }
 
-func (q *Queries) GetProjectsByUserID(ctx context.Context, arg GetProjectsByUserIDParams) ([]Project, error) {
+type GetProjectsByUserIDRow struct {
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	Status         interface{}    `json:"status"`
+	Priority       interface{}    `json:"priority"`
+	WorkspaceID    string         `json:"workspace_id"`
+	TeamID         string         `json:"team_id"`
+	LeaderID       interface{}    `json:"leader_id"`
+	StartDate      interface{}    `json:"start_date"`
+	EndDate        interface{}    `json:"end_date"`
+	CreatedBy      string         `json:"created_by"`
+	LatestHealth   sql.NullString `json:"latest_health"`
+	LatestUpdateAt sql.NullTime   `json:"latest_update_at"`
+}
+
+func (q *Queries) GetProjectsByUserID(ctx context.Context, arg GetProjectsByUserIDParams) ([]GetProjectsByUserIDRow, error) {
 	rows, err := q.db.QueryContext(ctx, getProjectsByUserID, arg.UserID, arg.CreatedBy)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Project{}
+	items := []GetProjectsByUserIDRow{}
 	for rows.Next() {
-		var i Project
+		var i GetProjectsByUserIDRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -153,8 +323,9 @@ func (q *Queries) GetProjectsByUserID(ctx context.Context, arg GetProjectsByUser
 			&i.LeaderID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.CreatedBy,
+			&i.LatestHealth,
+			&i.LatestUpdateAt,
 		); err != nil {
 			return nil, err
 		}
@@ -182,6 +353,63 @@ func (q *Queries) IsProjectExists(ctx context.Context, id string) (int64, error)
 	return count, err
 }
 
+const isProjectMember = `-- name: IsProjectMember :one
+SELECT COUNT(*) AS count
+FROM project_members
+WHERE project_id = ? AND user_id = ?
+`
+
+type IsProjectMemberParams struct {
+	ProjectID string `json:"project_id"`
+	UserID    string `json:"user_id"`
+}
+
+func (q *Queries) IsProjectMember(ctx context.Context, arg IsProjectMemberParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, isProjectMember, arg.ProjectID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const listMilestoneProgress = `-- name: ListMilestoneProgress :many
+SELECT i.milestone_id,
+       COUNT(*) AS total,
+       CAST(COALESCE(SUM(ts.category = 'completed'), 0) AS INTEGER) AS completed
+FROM issues i
+LEFT JOIN team_statuses ts ON ts.team_id = i.team_id AND ts.key = i.status
+WHERE i.project_id = ? AND i.milestone_id IS NOT NULL AND COALESCE(ts.category, '') != 'cancelled'
+GROUP BY i.milestone_id
+`
+
+type ListMilestoneProgressRow struct {
+	MilestoneID sql.NullString `json:"milestone_id"`
+	Total       int64          `json:"total"`
+	Completed   int64          `json:"completed"`
+}
+
+func (q *Queries) ListMilestoneProgress(ctx context.Context, projectID sql.NullString) ([]ListMilestoneProgressRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMilestoneProgress, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListMilestoneProgressRow{}
+	for rows.Next() {
+		var i ListMilestoneProgressRow
+		if err := rows.Scan(&i.MilestoneID, &i.Total, &i.Completed); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProjectMembers = `-- name: ListProjectMembers :many
 SELECT u.id, u.username, u.password_hash, u.email, u.roles
 FROM users u
@@ -218,8 +446,81 @@ func (q *Queries) ListProjectMembers(ctx context.Context, projectID string) ([]U
 	return items, nil
 }
 
+const listProjectMilestones = `-- name: ListProjectMilestones :many
+SELECT id, project_id, name, target_date, position, created_at FROM project_milestones
+WHERE project_id = ?
+ORDER BY position, created_at
+`
+
+func (q *Queries) ListProjectMilestones(ctx context.Context, projectID string) ([]ProjectMilestone, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectMilestones, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProjectMilestone{}
+	for rows.Next() {
+		var i ProjectMilestone
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Name,
+			&i.TargetDate,
+			&i.Position,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectUpdates = `-- name: ListProjectUpdates :many
+SELECT id, project_id, author_id, health, body, created_at, updated_at FROM project_updates
+WHERE project_id = ?
+ORDER BY created_at DESC, id DESC
+`
+
+func (q *Queries) ListProjectUpdates(ctx context.Context, projectID string) ([]ProjectUpdate, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectUpdates, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ProjectUpdate{}
+	for rows.Next() {
+		var i ProjectUpdate
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.AuthorID,
+			&i.Health,
+			&i.Body,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProjectsByWorkspace = `-- name: ListProjectsByWorkspace :many
-SELECT id, name, status, priority, workspace_id, leader_id, start_date, end_date, label
+SELECT id, name, status, priority, workspace_id, leader_id, start_date, end_date
 FROM projects
 WHERE workspace_id = ?
 ORDER BY start_date DESC
@@ -234,7 +535,6 @@ type ListProjectsByWorkspaceRow struct {
 	LeaderID    interface{} `json:"leader_id"`
 	StartDate   interface{} `json:"start_date"`
 	EndDate     interface{} `json:"end_date"`
-	Label       interface{} `json:"label"`
 }
 
 func (q *Queries) ListProjectsByWorkspace(ctx context.Context, workspaceID string) ([]ListProjectsByWorkspaceRow, error) {
@@ -255,7 +555,6 @@ func (q *Queries) ListProjectsByWorkspace(ctx context.Context, workspaceID strin
 			&i.LeaderID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 		); err != nil {
 			return nil, err
 		}
@@ -284,3 +583,34 @@ func (q *Queries) RemoveMemberFromProject(ctx context.Context, arg RemoveMemberF
 	_, err := q.db.ExecContext(ctx, removeMemberFromProject, arg.ProjectID, arg.UserID)
 	return err
 }
+
+const setProjectMilestonePosition = `-- name: SetProjectMilestonePosition :exec
+UPDATE project_milestones SET position = ?
+WHERE id = ?
+`
+
+type SetProjectMilestonePositionParams struct {
+	Position int64  `json:"position"`
+	ID       string `json:"id"`
+}
+
+func (q *Queries) SetProjectMilestonePosition(ctx context.Context, arg SetProjectMilestonePositionParams) error {
+	_, err := q.db.ExecContext(ctx, setProjectMilestonePosition, arg.Position, arg.ID)
+	return err
+}
+
+const updateProjectMilestone = `-- name: UpdateProjectMilestone :exec
+UPDATE project_milestones SET name = ?, target_date = ?
+WHERE id = ?
+`
+
+type UpdateProjectMilestoneParams struct {
+	Name       string       `json:"name"`
+	TargetDate sql.NullTime `json:"target_date"`
+	ID         string       `json:"id"`
+}
+
+func (q *Queries) UpdateProjectMilestone(ctx context.Context, arg UpdateProjectMilestoneParams) error {
+	_, err := q.db.ExecContext(ctx, updateProjectMilestone, arg.Name, arg.TargetDate, arg.ID)
+	return err
+}
@@ -12,24 +12,73 @@ import (
 type Querier interface {
 	AddAssigneeToIssue(ctx context.Context, arg AddAssigneeToIssueParams) error
 	AddGroupByToView(ctx context.Context, arg AddGroupByToViewParams) error
-	AddIssueToView(ctx context.Context, arg AddIssueToViewParams) error
+	AddIssueTemplateAssignee(ctx context.Context, arg AddIssueTemplateAssigneeParams) error
+	AddIssueTemplateLabel(ctx context.Context, arg AddIssueTemplateLabelParams) error
+	AddLabelToIssue(ctx context.Context, arg AddLabelToIssueParams) error
+	AddLabelToProject(ctx context.Context, arg AddLabelToProjectParams) error
 	AddMemberToProject(ctx context.Context, arg AddMemberToProjectParams) error
 	AddMemberToTeam(ctx context.Context, arg AddMemberToTeamParams) error
 	AddMemberToWorkspace(ctx context.Context, arg AddMemberToWorkspaceParams) error
+	AddMentionToComment(ctx context.Context, arg AddMentionToCommentParams) error
+	AddTeamIssueIdentifierAliases(ctx context.Context, teamID string) error
+	AdvanceRecurringIssue(ctx context.Context, arg AdvanceRecurringIssueParams) error
+	ClaimRecurringIssueRun(ctx context.Context, arg ClaimRecurringIssueRunParams) (int64, error)
+	ClearIssueTemplateAssignees(ctx context.Context, templateID string) error
+	ClearIssueTemplateLabels(ctx context.Context, templateID string) error
+	ClearMentionsFromComment(ctx context.Context, commentID string) error
+	CloseCycle(ctx context.Context, arg CloseCycleParams) (int64, error)
+	CountIssueEvents(ctx context.Context, issueID string) (int64, error)
+	CountIssuesByTeamStatus(ctx context.Context, arg CountIssuesByTeamStatusParams) (int64, error)
+	CountUnreadNotifications(ctx context.Context, userID string) (int64, error)
+	CreateComment(ctx context.Context, arg CreateCommentParams) error
+	CreateCommentEdit(ctx context.Context, arg CreateCommentEditParams) error
+	CreateCycle(ctx context.Context, arg CreateCycleParams) error
+	CreateInvitation(ctx context.Context, arg CreateInvitationParams) error
 	CreateIssue(ctx context.Context, arg CreateIssueParams) error
+	CreateIssueEvent(ctx context.Context, arg CreateIssueEventParams) error
+	CreateIssueRelation(ctx context.Context, arg CreateIssueRelationParams) error
+	CreateIssueTemplate(ctx context.Context, arg CreateIssueTemplateParams) error
+	CreateLabel(ctx context.Context, arg CreateLabelParams) error
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (int64, error)
 	CreateProject(ctx context.Context, arg CreateProjectParams) error
+	CreateProjectMilestone(ctx context.Context, arg CreateProjectMilestoneParams) error
+	CreateProjectUpdate(ctx context.Context, arg CreateProjectUpdateParams) error
+	CreateRecurringIssue(ctx context.Context, arg CreateRecurringIssueParams) error
+	CreateSession(ctx context.Context, arg CreateSessionParams) error
 	CreateTeam(ctx context.Context, arg CreateTeamParams) error
+	CreateTeamStatus(ctx context.Context, arg CreateTeamStatusParams) error
+	CreateTransitionRule(ctx context.Context, arg CreateTransitionRuleParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) error
 	CreateView(ctx context.Context, arg CreateViewParams) error
 	CreateWorkspace(ctx context.Context, arg CreateWorkspaceParams) error
+	DeleteComment(ctx context.Context, id string) error
+	DeleteCycle(ctx context.Context, id string) error
 	DeleteIssue(ctx context.Context, id string) error
+	DeleteIssueRelation(ctx context.Context, id string) error
+	DeleteIssueTemplate(ctx context.Context, id string) error
+	DeleteLabel(ctx context.Context, id string) error
 	DeleteProject(ctx context.Context, id string) error
+	DeleteProjectMilestone(ctx context.Context, id string) error
+	DeleteProjectUpdate(ctx context.Context, id string) error
+	DeleteRecurringIssue(ctx context.Context, id string) error
 	DeleteTeam(ctx context.Context, id string) error
+	DeleteTeamStatus(ctx context.Context, arg DeleteTeamStatusParams) error
+	DeleteTransitionRule(ctx context.Context, arg DeleteTransitionRuleParams) (int64, error)
 	DeleteUser(ctx context.Context, id string) error
 	DeleteView(ctx context.Context, id string) error
 	DeleteWorkspace(ctx context.Context, id string) error
+	EditProjectUpdate(ctx context.Context, arg EditProjectUpdateParams) error
+	FinishRecurringIssueRun(ctx context.Context, arg FinishRecurringIssueRunParams) error
+	GetCommentByID(ctx context.Context, id string) (IssueComment, error)
+	GetCycleByID(ctx context.Context, id string) (Cycle, error)
+	GetCycleStats(ctx context.Context, cycleID sql.NullString) (GetCycleStatsRow, error)
+	GetDefaultTeamStatus(ctx context.Context, teamID string) (string, error)
+	GetInvitationByID(ctx context.Context, id string) (WorkspaceInvitation, error)
+	GetIssueBoardRank(ctx context.Context, arg GetIssueBoardRankParams) (GetIssueBoardRankRow, error)
 	GetIssueByID(ctx context.Context, id string) (Issue, error)
 	GetIssueByUserID(ctx context.Context, arg GetIssueByUserIDParams) ([]Issue, error)
+	GetIssueRelation(ctx context.Context, id string) (IssueRelation, error)
+	GetIssueTemplateByID(ctx context.Context, id string) (IssueTemplate, error)
 	GetIssuesByAssignee(ctx context.Context, arg GetIssuesByAssigneeParams) ([]Issue, error)
 	GetIssuesByEndDate(ctx context.Context, arg GetIssuesByEndDateParams) ([]Issue, error)
 	GetIssuesByLabel(ctx context.Context, arg GetIssuesByLabelParams) ([]Issue, error)
@@ -37,51 +86,133 @@ type Querier interface {
 	GetIssuesByProject(ctx context.Context, arg GetIssuesByProjectParams) ([]Issue, error)
 	GetIssuesByStatus(ctx context.Context, arg GetIssuesByStatusParams) ([]Issue, error)
 	GetIssuesByTeamID(ctx context.Context, teamID string) ([]Issue, error)
+	GetLabelByID(ctx context.Context, id string) (Label, error)
+	GetLastIssueBoardRank(ctx context.Context, arg GetLastIssueBoardRankParams) (string, error)
 	GetLeaderByProjectID(ctx context.Context, id string) (interface{}, error)
 	GetLeaderByTeamID(ctx context.Context, id string) (interface{}, error)
+	GetNextCycleNumber(ctx context.Context, teamID string) (int64, error)
+	GetNextOpenCycle(ctx context.Context, arg GetNextOpenCycleParams) (Cycle, error)
 	GetOwnerByProjectID(ctx context.Context, id string) (string, error)
 	GetOwnerByTeamID(ctx context.Context, id string) (string, error)
 	GetProjectByID(ctx context.Context, id string) (Project, error)
-	GetProjectsByUserID(ctx context.Context, arg GetProjectsByUserIDParams) ([]Project, error)
+	GetProjectMilestone(ctx context.Context, id string) (ProjectMilestone, error)
+	GetProjectProgress(ctx context.Context, projectID sql.NullString) (GetProjectProgressRow, error)
+	GetProjectUpdate(ctx context.Context, id string) (ProjectUpdate, error)
+	GetProjectsByUserID(ctx context.Context, arg GetProjectsByUserIDParams) ([]GetProjectsByUserIDRow, error)
+	GetRecurringIssueByID(ctx context.Context, id string) (RecurringIssue, error)
+	GetSessionByID(ctx context.Context, id string) (Session, error)
+	GetSessionByPreviousTokenHash(ctx context.Context, previousTokenHash sql.NullString) (Session, error)
+	GetSessionByRefreshTokenHash(ctx context.Context, refreshTokenHash string) (Session, error)
+	GetSubIssueProgress(ctx context.Context, parentID sql.NullString) (GetSubIssueProgressRow, error)
 	GetTeamByID(ctx context.Context, id string) (Team, error)
 	GetTeamIDByViewID(ctx context.Context, id string) (string, error)
+	GetTeamIssueVersion(ctx context.Context, teamID string) (int64, error)
+	GetTeamKey(ctx context.Context, id string) (string, error)
+	GetTeamMemberRoles(ctx context.Context, arg GetTeamMemberRolesParams) (GetTeamMemberRolesRow, error)
+	GetTeamStatus(ctx context.Context, arg GetTeamStatusParams) (TeamStatus, error)
 	GetTeamsByUserID(ctx context.Context, userID string) ([]Team, error)
 	GetUserByEmailWithPassword(ctx context.Context, email string) (GetUserByEmailWithPasswordRow, error)
 	GetUserByEmailWithoutPassword(ctx context.Context, email string) (GetUserByEmailWithoutPasswordRow, error)
 	GetUserByID(ctx context.Context, id string) (GetUserByIDRow, error)
+	GetUserIDByUsername(ctx context.Context, username string) (string, error)
 	GetViewByID(ctx context.Context, id string) ([]View, error)
 	GetWorkspaceByID(ctx context.Context, id string) (Workspace, error)
 	GetWorkspaceByUserID(ctx context.Context, ownerID string) ([]Workspace, error)
+	GetWorkspaceMemberRole(ctx context.Context, arg GetWorkspaceMemberRoleParams) (string, error)
 	IsMemberInTeam(ctx context.Context, arg IsMemberInTeamParams) (int64, error)
 	IsProjectExists(ctx context.Context, id string) (int64, error)
+	IsProjectMember(ctx context.Context, arg IsProjectMemberParams) (int64, error)
 	IsTeamExists(ctx context.Context, id string) (int64, error)
 	ListAssigneesByIssueID(ctx context.Context, issueID string) ([]User, error)
+	ListChildIssues(ctx context.Context, parentID sql.NullString) ([]Issue, error)
+	ListCommentEditsByCommentID(ctx context.Context, commentID string) ([]IssueCommentEdit, error)
+	ListCommentsByIssueID(ctx context.Context, issueID string) ([]IssueComment, error)
+	ListDueRecurringIssues(ctx context.Context, now sql.NullTime) ([]RecurringIssue, error)
 	ListGroupByViewID(ctx context.Context, viewID string) ([]string, error)
+	ListInvitationsByWorkspaceID(ctx context.Context, workspaceID string) ([]WorkspaceInvitation, error)
+	ListIssueAncestorIDs(ctx context.Context, id string) ([]string, error)
+	ListIssueDescendants(ctx context.Context, parentID sql.NullString) ([]Issue, error)
+	ListIssueEvents(ctx context.Context, arg ListIssueEventsParams) ([]IssueEvent, error)
+	ListIssueIDsByIdentifier(ctx context.Context, arg ListIssueIDsByIdentifierParams) ([]string, error)
+	ListIssueIDsByIdentifierAlias(ctx context.Context, arg ListIssueIDsByIdentifierAliasParams) ([]string, error)
+	ListIssueRelations(ctx context.Context, arg ListIssueRelationsParams) ([]ListIssueRelationsRow, error)
+	ListIssueTemplateAssigneeIDs(ctx context.Context, templateID string) ([]string, error)
+	ListIssueTemplateLabelIDs(ctx context.Context, templateID string) ([]string, error)
 	ListIssuesByProjectID(ctx context.Context, projectID sql.NullString) ([]Issue, error)
 	ListIssuesByTeamID(ctx context.Context, teamID string) ([]Issue, error)
 	ListIssuesByUserID(ctx context.Context, userID string) ([]ListIssuesByUserIDRow, error)
-	ListIssuesByViewID(ctx context.Context, viewID string) ([]Issue, error)
+	ListLabelsByIssueID(ctx context.Context, issueID string) ([]Label, error)
+	ListLabelsByProjectID(ctx context.Context, projectID string) ([]Label, error)
+	ListMentionsByCommentID(ctx context.Context, commentID string) ([]ListMentionsByCommentIDRow, error)
+	ListMilestoneProgress(ctx context.Context, projectID sql.NullString) ([]ListMilestoneProgressRow, error)
+	ListOpenBlockedIssues(ctx context.Context, issueID string) ([]ListOpenBlockedIssuesRow, error)
+	ListPendingInvitationsByEmail(ctx context.Context, email string) ([]ListPendingInvitationsByEmailRow, error)
 	ListProjectMembers(ctx context.Context, projectID string) ([]User, error)
+	ListProjectMilestones(ctx context.Context, projectID string) ([]ProjectMilestone, error)
+	ListProjectUpdates(ctx context.Context, projectID string) ([]ProjectUpdate, error)
 	ListProjectsByWorkspace(ctx context.Context, workspaceID string) ([]ListProjectsByWorkspaceRow, error)
+	ListRecurringIssueRuns(ctx context.Context, arg ListRecurringIssueRunsParams) ([]RecurringIssueRun, error)
+	ListTeamCycles(ctx context.Context, teamID string) ([]Cycle, error)
+	ListTeamIssueTemplates(ctx context.Context, teamID string) ([]IssueTemplate, error)
+	ListTeamKeys(ctx context.Context, workspaceID string) ([]string, error)
+	ListTeamLabels(ctx context.Context, teamID string) ([]Label, error)
 	ListTeamMembers(ctx context.Context, teamID string) ([]ListTeamMembersRow, error)
+	ListTeamRecurringIssues(ctx context.Context, teamID string) ([]RecurringIssue, error)
+	ListTeamStatuses(ctx context.Context, teamID string) ([]TeamStatus, error)
 	ListTeams(ctx context.Context) ([]Team, error)
+	ListTransitionRules(ctx context.Context, teamID string) ([]TeamTransitionRule, error)
+	ListUnfinishedCycleIssueIDs(ctx context.Context, cycleID sql.NullString) ([]string, error)
+	ListUserNotifications(ctx context.Context, arg ListUserNotificationsParams) ([]ListUserNotificationsRow, error)
 	ListUsers(ctx context.Context) ([]ListUsersRow, error)
 	ListViewByTeamID(ctx context.Context, teamID string) ([]View, error)
+	ListViewGroupBys(ctx context.Context, viewID string) ([]ViewGroupBy, error)
 	ListViewsByUser(ctx context.Context, createdBy string) ([]View, error)
+	ListWorkspaceLabels(ctx context.Context, workspaceID string) ([]Label, error)
 	ListWorkspaceMembers(ctx context.Context, workspaceID string) ([]User, error)
 	ListWorkspacesWithMembersByUserID(ctx context.Context, arg ListWorkspacesWithMembersByUserIDParams) ([]ListWorkspacesWithMembersByUserIDRow, error)
+	MarkAllNotificationsRead(ctx context.Context, arg MarkAllNotificationsReadParams) error
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error)
+	MoveIssueLabels(ctx context.Context, arg MoveIssueLabelsParams) error
+	MoveIssuesToTeamStatus(ctx context.Context, arg MoveIssuesToTeamStatusParams) error
+	MoveProjectLabels(ctx context.Context, arg MoveProjectLabelsParams) error
+	NextIssueNumber(ctx context.Context, teamID string) (int64, error)
 	RemoveAssigneeFromIssue(ctx context.Context, arg RemoveAssigneeFromIssueParams) error
 	RemoveGroupByFromView(ctx context.Context, viewID string) error
-	RemoveIssueFromView(ctx context.Context, viewID string) error
+	RemoveLabelFromIssue(ctx context.Context, arg RemoveLabelFromIssueParams) error
+	RemoveLabelFromProject(ctx context.Context, arg RemoveLabelFromProjectParams) error
 	RemoveMemberFromProject(ctx context.Context, arg RemoveMemberFromProjectParams) error
 	RemoveMemberFromTeam(ctx context.Context, arg RemoveMemberFromTeamParams) error
 	RemoveMemberFromWorkspace(ctx context.Context, arg RemoveMemberFromWorkspaceParams) error
 	RenameTeam(ctx context.Context, arg RenameTeamParams) error
 	RenameWorkspace(ctx context.Context, arg RenameWorkspaceParams) error
+	RenumberTeamIssues(ctx context.Context, teamID string) error
+	RespondToInvitation(ctx context.Context, arg RespondToInvitationParams) (int64, error)
+	RevokeInvitation(ctx context.Context, arg RevokeInvitationParams) (int64, error)
+	RevokePendingInvitationsByEmail(ctx context.Context, arg RevokePendingInvitationsByEmailParams) error
+	RevokeSession(ctx context.Context, arg RevokeSessionParams) error
+	RevokeSessionsByUserID(ctx context.Context, arg RevokeSessionsByUserIDParams) error
+	RotateSessionToken(ctx context.Context, arg RotateSessionTokenParams) (int64, error)
+	SetIssueCycle(ctx context.Context, arg SetIssueCycleParams) error
 	SetLeaderToTeam(ctx context.Context, arg SetLeaderToTeamParams) error
+	SetProjectMilestonePosition(ctx context.Context, arg SetProjectMilestonePositionParams) error
+	SetTeamKey(ctx context.Context, arg SetTeamKeyParams) error
+	SetTeamLead(ctx context.Context, arg SetTeamLeadParams) error
+	SetTeamStatusPosition(ctx context.Context, arg SetTeamStatusPositionParams) error
+	SetViewIssueRank(ctx context.Context, arg SetViewIssueRankParams) error
+	SetWorkspaceMemberRole(ctx context.Context, arg SetWorkspaceMemberRoleParams) error
+	UpdateCommentBody(ctx context.Context, arg UpdateCommentBodyParams) error
+	UpdateCycle(ctx context.Context, arg UpdateCycleParams) error
 	UpdateEmail(ctx context.Context, arg UpdateEmailParams) error
+	UpdateIssueBoardPosition(ctx context.Context, arg UpdateIssueBoardPositionParams) error
+	UpdateIssueStatus(ctx context.Context, arg UpdateIssueStatusParams) error
+	UpdateIssueTemplate(ctx context.Context, arg UpdateIssueTemplateParams) error
+	UpdateLabel(ctx context.Context, arg UpdateLabelParams) error
+	UpdateProjectMilestone(ctx context.Context, arg UpdateProjectMilestoneParams) error
+	UpdateRecurringIssue(ctx context.Context, arg UpdateRecurringIssueParams) error
 	UpdateRoles(ctx context.Context, arg UpdateRolesParams) error
+	UpdateTeamStatus(ctx context.Context, arg UpdateTeamStatusParams) error
 	UpdateUsername(ctx context.Context, arg UpdateUsernameParams) error
+	UpdateViewFilter(ctx context.Context, arg UpdateViewFilterParams) error
 	UpdateViewGroupBy(ctx context.Context, arg UpdateViewGroupByParams) error
 	UpdateViewName(ctx context.Context, arg UpdateViewNameParams) error
 	UpdateViewTeamID(ctx context.Context, arg UpdateViewTeamIDParams) error
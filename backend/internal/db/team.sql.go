@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const addMemberToTeam = `-- name: AddMemberToTeam :exec
@@ -25,19 +26,109 @@ func (q *Queries) AddMemberToTeam(ctx context.Context, arg AddMemberToTeamParams
 	return err
 }
 
+const addTeamIssueIdentifierAliases = `-- name: AddTeamIssueIdentifierAliases :exec
+INSERT OR IGNORE INTO issue_identifier_aliases (issue_id, identifier)
+SELECT id, identifier
+FROM issues
+WHERE team_id = ?
+`
+
+func (q *Queries) AddTeamIssueIdentifierAliases(ctx context.Context, teamID string) error {
+	_, err := q.db.ExecContext(ctx, addTeamIssueIdentifierAliases, teamID)
+	return err
+}
+
+const countIssuesByTeamStatus = `-- name: CountIssuesByTeamStatus :one
+SELECT COUNT(*) AS count
+FROM issues
+WHERE team_id = ? AND status = ?
+`
+
+type CountIssuesByTeamStatusParams struct {
+	TeamID string `json:"team_id"`
+	Status string `json:"status"`
+}
+
+func (q *Queries) CountIssuesByTeamStatus(ctx context.Context, arg CountIssuesByTeamStatusParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countIssuesByTeamStatus, arg.TeamID, arg.Status)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTeam = `-- name: CreateTeam :exec
-INSERT INTO teams (id, name, workspace_id)
-VALUES (?, ?, ?)
+INSERT INTO teams (id, name, workspace_id, key)
+VALUES (?, ?, ?, ?)
 `
 
 type CreateTeamParams struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	WorkspaceID string `json:"workspace_id"`
+	Key         string `json:"key"`
 }
 
 func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) error {
-	_, err := q.db.ExecContext(ctx, createTeam, arg.ID, arg.Name, arg.WorkspaceID)
+	_, err := q.db.ExecContext(ctx, createTeam,
+		arg.ID,
+		arg.Name,
+		arg.WorkspaceID,
+		arg.Key,
+	)
+	return err
+}
+
+const createTeamStatus = `-- name: CreateTeamStatus :exec
+INSERT INTO team_statuses (team_id, key, name, category, position, color)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateTeamStatusParams struct {
+	TeamID   string `json:"team_id"`
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Position int64  `json:"position"`
+	Color    string `json:"color"`
+}
+
+func (q *Queries) CreateTeamStatus(ctx context.Context, arg CreateTeamStatusParams) error {
+	_, err := q.db.ExecContext(ctx, createTeamStatus,
+		arg.TeamID,
+		arg.Key,
+		arg.Name,
+		arg.Category,
+		arg.Position,
+		arg.Color,
+	)
+	return err
+}
+
+const createTransitionRule = `-- name: CreateTransitionRule :exec
+INSERT INTO team_transition_rules (id, team_id, from_status, to_status, to_category, requirement, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateTransitionRuleParams struct {
+	ID          string         `json:"id"`
+	TeamID      string         `json:"team_id"`
+	FromStatus  sql.NullString `json:"from_status"`
+	ToStatus    sql.NullString `json:"to_status"`
+	ToCategory  sql.NullString `json:"to_category"`
+	Requirement string         `json:"requirement"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+func (q *Queries) CreateTransitionRule(ctx context.Context, arg CreateTransitionRuleParams) error {
+	_, err := q.db.ExecContext(ctx, createTransitionRule,
+		arg.ID,
+		arg.TeamID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.ToCategory,
+		arg.Requirement,
+		arg.CreatedAt,
+	)
 	return err
 }
 
@@ -50,6 +141,54 @@ func (q *Queries) DeleteTeam(ctx context.Context, id string) error {
 	return err
 }
 
+const deleteTeamStatus = `-- name: DeleteTeamStatus :exec
+DELETE FROM team_statuses
+WHERE team_id = ? AND key = ?
+`
+
+type DeleteTeamStatusParams struct {
+	TeamID string `json:"team_id"`
+	Key    string `json:"key"`
+}
+
+func (q *Queries) DeleteTeamStatus(ctx context.Context, arg DeleteTeamStatusParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTeamStatus, arg.TeamID, arg.Key)
+	return err
+}
+
+const deleteTransitionRule = `-- name: DeleteTransitionRule :execrows
+DELETE FROM team_transition_rules
+WHERE id = ? AND team_id = ?
+`
+
+type DeleteTransitionRuleParams struct {
+	ID     string `json:"id"`
+	TeamID string `json:"team_id"`
+}
+
+func (q *Queries) DeleteTransitionRule(ctx context.Context, arg DeleteTransitionRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTransitionRule, arg.ID, arg.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getDefaultTeamStatus = `-- name: GetDefaultTeamStatus :one
+SELECT key
+FROM team_statuses
+WHERE team_id = ?
+ORDER BY category != 'unstarted', position, key
+LIMIT 1
+`
+
+func (q *Queries) GetDefaultTeamStatus(ctx context.Context, teamID string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getDefaultTeamStatus, teamID)
+	var key string
+	err := row.Scan(&key)
+	return key, err
+}
+
 const getLeaderByTeamID = `-- name: GetLeaderByTeamID :one
 SELECT leader_id
 FROM teams
@@ -79,7 +218,7 @@ func (q *Queries) GetOwnerByTeamID(ctx context.Context, id string) (string, erro
 }
 
 const getTeamByID = `-- name: GetTeamByID :one
-SELECT id, name, workspace_id, leader_id
+SELECT id, name, workspace_id, leader_id, key
 FROM teams
 WHERE id = ?
 `
@@ -92,12 +231,67 @@ func (q *Queries) GetTeamByID(ctx context.Context, id string) (Team, error) {
 		&i.Name,
 		&i.WorkspaceID,
 		&i.LeaderID,
+		&i.Key,
+	)
+	return i, err
+}
+
+const getTeamMemberRoles = `-- name: GetTeamMemberRoles :one
+SELECT t.workspace_id,
+       COALESCE(wm.role, '') AS workspace_role,
+       COALESCE(tm.role, '') AS team_role
+FROM teams t
+LEFT JOIN workspace_members wm ON wm.workspace_id = t.workspace_id AND wm.user_id = ?
+LEFT JOIN team_members tm ON tm.team_id = t.id AND tm.user_id = ?
+WHERE t.id = ?
+`
+
+type GetTeamMemberRolesParams struct {
+	UserID   string `json:"user_id"`
+	UserID_2 string `json:"user_id_2"`
+	ID       string `json:"id"`
+}
+
+type GetTeamMemberRolesRow struct {
+	WorkspaceID   string `json:"workspace_id"`
+	WorkspaceRole string `json:"workspace_role"`
+	TeamRole      string `json:"team_role"`
+}
+
+func (q *Queries) GetTeamMemberRoles(ctx context.Context, arg GetTeamMemberRolesParams) (GetTeamMemberRolesRow, error) {
+	row := q.db.QueryRowContext(ctx, getTeamMemberRoles, arg.UserID, arg.UserID_2, arg.ID)
+	var i GetTeamMemberRolesRow
+	err := row.Scan(&i.WorkspaceID, &i.WorkspaceRole, &i.TeamRole)
+	return i, err
+}
+
+const getTeamStatus = `-- name: GetTeamStatus :one
+SELECT team_id, key, name, category, position, color
+FROM team_statuses
+WHERE team_id = ? AND key = ?
+`
+
+type GetTeamStatusParams struct {
+	TeamID string `json:"team_id"`
+	Key    string `json:"key"`
+}
+
+func (q *Queries) GetTeamStatus(ctx context.Context, arg GetTeamStatusParams) (TeamStatus, error) {
+	row := q.db.QueryRowContext(ctx, getTeamStatus, arg.TeamID, arg.Key)
+	var i TeamStatus
+	err := row.Scan(
+		&i.TeamID,
+		&i.Key,
+		&i.Name,
+		&i.Category,
+		&i.Position,
+		&i.Color,
 	)
 	return i, err
 }
 
 const getTeamsByUserID = `-- name: GetTeamsByUserID :many
-SELECT t.id, t.name, t.workspace_id, t.leader_id
+SELECT t.id, t.name, t.workspace_id, t.leader_id, t.key
 FROM teams t
 JOIN team_members tm ON t.id = tm.team_id
 WHERE tm.user_id = ?
@@ -117,6 +311,7 @@ func (q *Queries) GetTeamsByUserID(ctx context.Context, userID string) ([]Team,
 			&i.Name,
 			&i.WorkspaceID,
 			&i.LeaderID,
+			&i.Key,
 		); err != nil {
 			return nil, err
 		}
@@ -206,6 +401,35 @@ func (q *Queries) ListIssuesByUserID(ctx context.Context, userID string) ([]List
 	return items, nil
 }
 
+const listTeamKeys = `-- name: ListTeamKeys :many
+SELECT key
+FROM teams
+WHERE workspace_id = ?
+`
+
+func (q *Queries) ListTeamKeys(ctx context.Context, workspaceID string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listTeamKeys, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		items = append(items, key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTeamMembers = `-- name: ListTeamMembers :many
 SELECT u.id, u.username, u.email
 FROM users u
@@ -242,8 +466,45 @@ func (q *Queries) ListTeamMembers(ctx context.Context, teamID string) ([]ListTea
 	return items, nil
 }
 
+const listTeamStatuses = `-- name: ListTeamStatuses :many
+SELECT team_id, key, name, category, position, color
+FROM team_statuses
+WHERE team_id = ?
+ORDER BY position, key
+`
+
+func (q *Queries) ListTeamStatuses(ctx context.Context, teamID string) ([]TeamStatus, error) {
+	rows, err := q.db.QueryContext(ctx, listTeamStatuses, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TeamStatus{}
+	for rows.Next() {
+		var i TeamStatus
+		if err := rows.Scan(
+			&i.TeamID,
+			&i.Key,
+			&i.Name,
+			&i.Category,
+			&i.Position,
+			&i.Color,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTeams = `-- name: ListTeams :many
-SELECT id, name, workspace_id, leader_id
+SELECT id, name, workspace_id, leader_id, key
 FROM teams
 ORDER BY name
 `
@@ -262,6 +523,7 @@ func (q *Queries) ListTeams(ctx context.Context) ([]Team, error) {
 			&i.Name,
 			&i.WorkspaceID,
 			&i.LeaderID,
+			&i.Key,
 		); err != nil {
 			return nil, err
 		}
@@ -276,6 +538,61 @@ func (q *Queries) ListTeams(ctx context.Context) ([]Team, error) {
 	return items, nil
 }
 
+const listTransitionRules = `-- name: ListTransitionRules :many
+SELECT id, team_id, from_status, to_status, to_category, requirement, created_at
+FROM team_transition_rules
+WHERE team_id = ?
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListTransitionRules(ctx context.Context, teamID string) ([]TeamTransitionRule, error) {
+	rows, err := q.db.QueryContext(ctx, listTransitionRules, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TeamTransitionRule{}
+	for rows.Next() {
+		var i TeamTransitionRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.ToCategory,
+			&i.Requirement,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveIssuesToTeamStatus = `-- name: MoveIssuesToTeamStatus :exec
+UPDATE issues
+SET status = ?
+WHERE team_id = ? AND status = ?
+`
+
+type MoveIssuesToTeamStatusParams struct {
+	Status   string `json:"status"`
+	TeamID   string `json:"team_id"`
+	Status_2 string `json:"status_2"`
+}
+
+func (q *Queries) MoveIssuesToTeamStatus(ctx context.Context, arg MoveIssuesToTeamStatusParams) error {
+	_, err := q.db.ExecContext(ctx, moveIssuesToTeamStatus, arg.Status, arg.TeamID, arg.Status_2)
+	return err
+}
+
 const removeMemberFromTeam = `-- name: RemoveMemberFromTeam :exec
 DELETE FROM team_members
 WHERE team_id = ? AND user_id = ?
@@ -307,6 +624,17 @@ func (q *Queries) RenameTeam(ctx context.Context, arg RenameTeamParams) error {
 	return err
 }
 
+const renumberTeamIssues = `-- name: RenumberTeamIssues :exec
+UPDATE issues
+SET identifier = (SELECT t.key FROM teams t WHERE t.id = issues.team_id) || '-' || number
+WHERE team_id = ?
+`
+
+func (q *Queries) RenumberTeamIssues(ctx context.Context, teamID string) error {
+	_, err := q.db.ExecContext(ctx, renumberTeamIssues, teamID)
+	return err
+}
+
 const setLeaderToTeam = `-- name: SetLeaderToTeam :exec
 UPDATE teams
 SET leader_id = ?
@@ -322,3 +650,78 @@ func (q *Queries) SetLeaderToTeam(ctx context.Context, arg SetLeaderToTeamParams
 	_, err := q.db.ExecContext(ctx, setLeaderToTeam, arg.LeaderID, arg.ID)
 	return err
 }
+
+const setTeamKey = `-- name: SetTeamKey :exec
+UPDATE teams
+SET key = ?
+WHERE id = ?
+`
+
+type SetTeamKeyParams struct {
+	Key string `json:"key"`
+	ID  string `json:"id"`
+}
+
+func (q *Queries) SetTeamKey(ctx context.Context, arg SetTeamKeyParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamKey, arg.Key, arg.ID)
+	return err
+}
+
+const setTeamLead = `-- name: SetTeamLead :exec
+UPDATE team_members
+SET role = CASE WHEN user_id = ? THEN 'lead' ELSE 'member' END
+WHERE team_id = ? AND (role = 'lead' OR user_id = ?)
+`
+
+type SetTeamLeadParams struct {
+	UserID   string `json:"user_id"`
+	TeamID   string `json:"team_id"`
+	UserID_2 string `json:"user_id_2"`
+}
+
+func (q *Queries) SetTeamLead(ctx context.Context, arg SetTeamLeadParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamLead, arg.UserID, arg.TeamID, arg.UserID_2)
+	return err
+}
+
+const setTeamStatusPosition = `-- name: SetTeamStatusPosition :exec
+UPDATE team_statuses
+SET position = ?
+WHERE team_id = ? AND key = ?
+`
+
+type SetTeamStatusPositionParams struct {
+	Position int64  `json:"position"`
+	TeamID   string `json:"team_id"`
+	Key      string `json:"key"`
+}
+
+func (q *Queries) SetTeamStatusPosition(ctx context.Context, arg SetTeamStatusPositionParams) error {
+	_, err := q.db.ExecContext(ctx, setTeamStatusPosition, arg.Position, arg.TeamID, arg.Key)
+	return err
+}
+
+const updateTeamStatus = `-- name: UpdateTeamStatus :exec
+UPDATE team_statuses
+SET name = ?, category = ?, color = ?
+WHERE team_id = ? AND key = ?
+`
+
+type UpdateTeamStatusParams struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Color    string `json:"color"`
+	TeamID   string `json:"team_id"`
+	Key      string `json:"key"`
+}
+
+func (q *Queries) UpdateTeamStatus(ctx context.Context, arg UpdateTeamStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateTeamStatus,
+		arg.Name,
+		arg.Category,
+		arg.Color,
+		arg.TeamID,
+		arg.Key,
+	)
+	return err
+}
@@ -119,6 +119,19 @@ func (q *Queries) GetUserByID(ctx context.Context, id string) (GetUserByIDRow, e
 	return i, err
 }
 
+const getUserIDByUsername = `-- name: GetUserIDByUsername :one
+SELECT id
+FROM users
+WHERE username = ?
+`
+
+func (q *Queries) GetUserIDByUsername(ctx context.Context, username string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserIDByUsername, username)
+	var id string
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listUsers = `-- name: ListUsers :many
 SELECT id, username, email, roles
 FROM users
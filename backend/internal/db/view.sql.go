@@ -11,45 +11,42 @@ import (
 )
 
 const addGroupByToView = `-- name: AddGroupByToView :exec
-INSERT INTO view_group_bys (view_id, group_by)
-VALUES (?, ?)
+INSERT INTO view_group_bys (view_id, group_by, position, group_order, descending, show_empty)
+VALUES (?, ?, ?, ?, ?, ?)
 `
 
 type AddGroupByToViewParams struct {
-	ViewID  string `json:"view_id"`
-	GroupBy string `json:"group_by"`
+	ViewID     string `json:"view_id"`
+	GroupBy    string `json:"group_by"`
+	Position   int64  `json:"position"`
+	GroupOrder string `json:"group_order"`
+	Descending bool   `json:"descending"`
+	ShowEmpty  bool   `json:"show_empty"`
 }
 
 func (q *Queries) AddGroupByToView(ctx context.Context, arg AddGroupByToViewParams) error {
-	_, err := q.db.ExecContext(ctx, addGroupByToView, arg.ViewID, arg.GroupBy)
-	return err
-}
-
-const addIssueToView = `-- name: AddIssueToView :exec
-INSERT OR IGNORE INTO view_issues (view_id, issue_id)
-VALUES (?, ?)
-`
-
-type AddIssueToViewParams struct {
-	ViewID  string `json:"view_id"`
-	IssueID string `json:"issue_id"`
-}
-
-func (q *Queries) AddIssueToView(ctx context.Context, arg AddIssueToViewParams) error {
-	_, err := q.db.ExecContext(ctx, addIssueToView, arg.ViewID, arg.IssueID)
+	_, err := q.db.ExecContext(ctx, addGroupByToView,
+		arg.ViewID,
+		arg.GroupBy,
+		arg.Position,
+		arg.GroupOrder,
+		arg.Descending,
+		arg.ShowEmpty,
+	)
 	return err
 }
 
 const createView = `-- name: CreateView :exec
-INSERT INTO views (id, name, created_by, team_id)
-VALUES (?, ?, ?, ?)
+INSERT INTO views (id, name, created_by, team_id, filter)
+VALUES (?, ?, ?, ?, ?)
 `
 
 type CreateViewParams struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	CreatedBy string `json:"created_by"`
-	TeamID    string `json:"team_id"`
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	CreatedBy string         `json:"created_by"`
+	TeamID    string         `json:"team_id"`
+	Filter    sql.NullString `json:"filter"`
 }
 
 func (q *Queries) CreateView(ctx context.Context, arg CreateViewParams) error {
@@ -58,6 +55,7 @@ func (q *Queries) CreateView(ctx context.Context, arg CreateViewParams) error {
 		arg.Name,
 		arg.CreatedBy,
 		arg.TeamID,
+		arg.Filter,
 	)
 	return err
 }
@@ -72,7 +70,7 @@ func (q *Queries) DeleteView(ctx context.Context, id string) error {
 }
 
 const getIssuesByAssignee = `-- name: GetIssuesByAssignee :many
-SELECT i.id, i.title, i.content, i.priority, i.status, i.project_id, i.team_id, i.start_date, i.end_date, i.label, i.owner_id
+SELECT i.id, i.title, i.content, i.priority, i.status, i.project_id, i.team_id, i.start_date, i.end_date, i.owner_id, i.rank, i.number, i.identifier, i.parent_id, i.cycle_id, i.cycle_added_at, i.milestone_id
 FROM issues i
 JOIN issue_assignees ia ON ia.issue_id = i.id
 WHERE ia.user_id = ? AND i.team_id = ?
@@ -102,8 +100,14 @@ func (q *Queries) GetIssuesByAssignee(ctx context.Context, arg GetIssuesByAssign
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -119,7 +123,7 @@ func (q *Queries) GetIssuesByAssignee(ctx context.Context, arg GetIssuesByAssign
 }
 
 const getIssuesByEndDate = `-- name: GetIssuesByEndDate :many
-SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id FROM issues
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id FROM issues
 WHERE team_id = ? AND end_date  = ?
 `
 
@@ -147,8 +151,14 @@ func (q *Queries) GetIssuesByEndDate(ctx context.Context, arg GetIssuesByEndDate
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -164,17 +174,18 @@ func (q *Queries) GetIssuesByEndDate(ctx context.Context, arg GetIssuesByEndDate
 }
 
 const getIssuesByLabel = `-- name: GetIssuesByLabel :many
-SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id FROM issues
-WHERE team_id = ? AND Label = ?
+SELECT i.id, i.title, i.content, i.priority, i.status, i.project_id, i.team_id, i.start_date, i.end_date, i.owner_id, i.rank, i.number, i.identifier, i.parent_id, i.cycle_id, i.cycle_added_at, i.milestone_id FROM issues i
+JOIN issue_labels il ON il.issue_id = i.id
+WHERE i.team_id = ? AND il.label_id = ?
 `
 
 type GetIssuesByLabelParams struct {
-	TeamID string         `json:"team_id"`
-	Label  sql.NullString `json:"label"`
+	TeamID  string `json:"team_id"`
+	LabelID string `json:"label_id"`
 }
 
 func (q *Queries) GetIssuesByLabel(ctx context.Context, arg GetIssuesByLabelParams) ([]Issue, error) {
-	rows, err := q.db.QueryContext(ctx, getIssuesByLabel, arg.TeamID, arg.Label)
+	rows, err := q.db.QueryContext(ctx, getIssuesByLabel, arg.TeamID, arg.LabelID)
 	if err != nil {
 		return nil, err
 	}
@@ -192,8 +203,14 @@ func (q *Queries) GetIssuesByLabel(ctx context.Context, arg GetIssuesByLabelPara
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -209,7 +226,7 @@ func (q *Queries) GetIssuesByLabel(ctx context.Context, arg GetIssuesByLabelPara
 }
 
 const getIssuesByPriority = `-- name: GetIssuesByPriority :many
-SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id FROM issues
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id FROM issues
 WHERE team_id = ? AND priority = ?
 `
 
@@ -237,8 +254,14 @@ func (q *Queries) GetIssuesByPriority(ctx context.Context, arg GetIssuesByPriori
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -256,7 +279,7 @@ func (q *Queries) GetIssuesByPriority(ctx context.Context, arg GetIssuesByPriori
 const getIssuesByProject = `-- name: GetIssuesByProject :many
 ;
 
-SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id FROM issues
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id FROM issues
 WHERE team_id = ? AND project_id = ?
 `
 
@@ -284,8 +307,14 @@ func (q *Queries) GetIssuesByProject(ctx context.Context, arg GetIssuesByProject
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -301,7 +330,7 @@ func (q *Queries) GetIssuesByProject(ctx context.Context, arg GetIssuesByProject
 }
 
 const getIssuesByStatus = `-- name: GetIssuesByStatus :many
-SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id FROM issues
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id FROM issues
 WHERE team_id = ? AND status = ?
 `
 
@@ -329,8 +358,14 @@ func (q *Queries) GetIssuesByStatus(ctx context.Context, arg GetIssuesByStatusPa
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -346,7 +381,7 @@ func (q *Queries) GetIssuesByStatus(ctx context.Context, arg GetIssuesByStatusPa
 }
 
 const getIssuesByTeamID = `-- name: GetIssuesByTeamID :many
-SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, label, owner_id FROM issues
+SELECT id, title, content, priority, status, project_id, team_id, start_date, end_date, owner_id, rank, number, identifier, parent_id, cycle_id, cycle_added_at, milestone_id FROM issues
 WHERE team_id = ?
 `
 
@@ -369,8 +404,14 @@ func (q *Queries) GetIssuesByTeamID(ctx context.Context, teamID string) ([]Issue
 			&i.TeamID,
 			&i.StartDate,
 			&i.EndDate,
-			&i.Label,
 			&i.OwnerID,
+			&i.Rank,
+			&i.Number,
+			&i.Identifier,
+			&i.ParentID,
+			&i.CycleID,
+			&i.CycleAddedAt,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -398,8 +439,21 @@ func (q *Queries) GetTeamIDByViewID(ctx context.Context, id string) (string, err
 	return team_id, err
 }
 
+const getTeamIssueVersion = `-- name: GetTeamIssueVersion :one
+SELECT version
+FROM team_issue_versions
+WHERE team_id = ?
+`
+
+func (q *Queries) GetTeamIssueVersion(ctx context.Context, teamID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getTeamIssueVersion, teamID)
+	var version int64
+	err := row.Scan(&version)
+	return version, err
+}
+
 const getViewByID = `-- name: GetViewByID :many
-SELECT id, name, created_by, team_id
+SELECT id, name, created_by, team_id, filter
 FROM views
 WHERE id = ?
 `
@@ -418,6 +472,7 @@ func (q *Queries) GetViewByID(ctx context.Context, id string) ([]View, error) {
 			&i.Name,
 			&i.CreatedBy,
 			&i.TeamID,
+			&i.Filter,
 		); err != nil {
 			return nil, err
 		}
@@ -461,34 +516,27 @@ func (q *Queries) ListGroupByViewID(ctx context.Context, viewID string) ([]strin
 	return items, nil
 }
 
-const listIssuesByViewID = `-- name: ListIssuesByViewID :many
-SELECT i.id, i.title, i.content, i.priority, i.status, i.project_id, i.team_id, i.start_date, i.end_date, i.label, i.owner_id
-FROM issues i
-JOIN view_issues vi ON i.id = vi.issue_id
-WHERE vi.view_id = ?
+const listViewByTeamID = `-- name: ListViewByTeamID :many
+SELECT id, name, created_by, team_id, filter
+FROM views
+WHERE team_id = ?
 `
 
-func (q *Queries) ListIssuesByViewID(ctx context.Context, viewID string) ([]Issue, error) {
-	rows, err := q.db.QueryContext(ctx, listIssuesByViewID, viewID)
+func (q *Queries) ListViewByTeamID(ctx context.Context, teamID string) ([]View, error) {
+	rows, err := q.db.QueryContext(ctx, listViewByTeamID, teamID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Issue{}
+	items := []View{}
 	for rows.Next() {
-		var i Issue
+		var i View
 		if err := rows.Scan(
 			&i.ID,
-			&i.Title,
-			&i.Content,
-			&i.Priority,
-			&i.Status,
-			&i.ProjectID,
+			&i.Name,
+			&i.CreatedBy,
 			&i.TeamID,
-			&i.StartDate,
-			&i.EndDate,
-			&i.Label,
-			&i.OwnerID,
+			&i.Filter,
 		); err != nil {
 			return nil, err
 		}
@@ -503,26 +551,29 @@ func (q *Queries) ListIssuesByViewID(ctx context.Context, viewID string) ([]Issu
 	return items, nil
 }
 
-const listViewByTeamID = `-- name: ListViewByTeamID :many
-SELECT id, name, created_by, team_id
-FROM views
-WHERE team_id = ?
+const listViewGroupBys = `-- name: ListViewGroupBys :many
+SELECT view_id, group_by, position, group_order, descending, show_empty
+FROM view_group_bys
+WHERE view_id = ?
+ORDER BY position, rowid
 `
 
-func (q *Queries) ListViewByTeamID(ctx context.Context, teamID string) ([]View, error) {
-	rows, err := q.db.QueryContext(ctx, listViewByTeamID, teamID)
+func (q *Queries) ListViewGroupBys(ctx context.Context, viewID string) ([]ViewGroupBy, error) {
+	rows, err := q.db.QueryContext(ctx, listViewGroupBys, viewID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []View{}
+	items := []ViewGroupBy{}
 	for rows.Next() {
-		var i View
+		var i ViewGroupBy
 		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.CreatedBy,
-			&i.TeamID,
+			&i.ViewID,
+			&i.GroupBy,
+			&i.Position,
+			&i.GroupOrder,
+			&i.Descending,
+			&i.ShowEmpty,
 		); err != nil {
 			return nil, err
 		}
@@ -538,7 +589,7 @@ func (q *Queries) ListViewByTeamID(ctx context.Context, teamID string) ([]View,
 }
 
 const listViewsByUser = `-- name: ListViewsByUser :many
-SELECT id, name, created_by, team_id
+SELECT id, name, created_by, team_id, filter
 FROM views
 WHERE created_by = ?
 ORDER BY name
@@ -558,6 +609,7 @@ func (q *Queries) ListViewsByUser(ctx context.Context, createdBy string) ([]View
 			&i.Name,
 			&i.CreatedBy,
 			&i.TeamID,
+			&i.Filter,
 		); err != nil {
 			return nil, err
 		}
@@ -582,13 +634,18 @@ func (q *Queries) RemoveGroupByFromView(ctx context.Context, viewID string) erro
 	return err
 }
 
-const removeIssueFromView = `-- name: RemoveIssueFromView :exec
-DELETE FROM view_issues
-WHERE view_id = ?
+const updateViewFilter = `-- name: UpdateViewFilter :exec
+UPDATE views SET filter = ?
+WHERE id = ?
 `
 
-func (q *Queries) RemoveIssueFromView(ctx context.Context, viewID string) error {
-	_, err := q.db.ExecContext(ctx, removeIssueFromView, viewID)
+type UpdateViewFilterParams struct {
+	Filter sql.NullString `json:"filter"`
+	ID     string         `json:"id"`
+}
+
+func (q *Queries) UpdateViewFilter(ctx context.Context, arg UpdateViewFilterParams) error {
+	_, err := q.db.ExecContext(ctx, updateViewFilter, arg.Filter, arg.ID)
 	return err
 }
 
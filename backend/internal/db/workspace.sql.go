@@ -11,17 +11,18 @@ import (
 )
 
 const addMemberToWorkspace = `-- name: AddMemberToWorkspace :exec
-INSERT OR IGNORE INTO workspace_members (workspace_id, user_id)
-VALUES (?, ?)
+INSERT OR IGNORE INTO workspace_members (workspace_id, user_id, role)
+VALUES (?, ?, ?)
 `
 
 type AddMemberToWorkspaceParams struct {
 	WorkspaceID string `json:"workspace_id"`
 	UserID      string `json:"user_id"`
+	Role        string `json:"role"`
 }
 
 func (q *Queries) AddMemberToWorkspace(ctx context.Context, arg AddMemberToWorkspaceParams) error {
-	_, err := q.db.ExecContext(ctx, addMemberToWorkspace, arg.WorkspaceID, arg.UserID)
+	_, err := q.db.ExecContext(ctx, addMemberToWorkspace, arg.WorkspaceID, arg.UserID, arg.Role)
 	return err
 }
 
@@ -90,6 +91,24 @@ func (q *Queries) GetWorkspaceByUserID(ctx context.Context, ownerID string) ([]W
 	return items, nil
 }
 
+const getWorkspaceMemberRole = `-- name: GetWorkspaceMemberRole :one
+SELECT role
+FROM workspace_members
+WHERE workspace_id = ? AND user_id = ?
+`
+
+type GetWorkspaceMemberRoleParams struct {
+	WorkspaceID string `json:"workspace_id"`
+	UserID      string `json:"user_id"`
+}
+
+func (q *Queries) GetWorkspaceMemberRole(ctx context.Context, arg GetWorkspaceMemberRoleParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceMemberRole, arg.WorkspaceID, arg.UserID)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
 const listWorkspaceMembers = `-- name: ListWorkspaceMembers :many
 SELECT u.id, u.username, u.password_hash, u.email, u.roles
 FROM users u
@@ -204,3 +223,20 @@ func (q *Queries) RenameWorkspace(ctx context.Context, arg RenameWorkspaceParams
 	_, err := q.db.ExecContext(ctx, renameWorkspace, arg.Name, arg.ID)
 	return err
 }
+
+const setWorkspaceMemberRole = `-- name: SetWorkspaceMemberRole :exec
+UPDATE workspace_members
+SET role = ?
+WHERE workspace_id = ? AND user_id = ?
+`
+
+type SetWorkspaceMemberRoleParams struct {
+	Role        string `json:"role"`
+	WorkspaceID string `json:"workspace_id"`
+	UserID      string `json:"user_id"`
+}
+
+func (q *Queries) SetWorkspaceMemberRole(ctx context.Context, arg SetWorkspaceMemberRoleParams) error {
+	_, err := q.db.ExecContext(ctx, setWorkspaceMemberRole, arg.Role, arg.WorkspaceID, arg.UserID)
+	return err
+}
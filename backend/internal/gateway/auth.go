@@ -8,4 +8,11 @@ import (
 func SetUpAuthRoutes(api fiber.Router, h *auth.AuthHandler) {
 	api.Post("/login", h.Login)
 	api.Post("/register", h.Register)
+	api.Post("/refresh", h.Refresh)
+	api.Get("/.well-known/jwks.json", h.JWKS)
+}
+
+func SetUpSessionRoutes(api fiber.Router, h *auth.AuthHandler) {
+	api.Post("/logout", h.Logout)
+	api.Post("/logout/all", h.LogoutAll)
 }
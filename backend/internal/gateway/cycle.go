@@ -0,0 +1,15 @@
+package gateway
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/routes"
+)
+
+func SetUpCycleRoutes(api fiber.Router, h *routes.CycleHandler) {
+	api.Get("/teams/:id/cycles", h.ListTeamCycles)
+	api.Post("/teams/:id/cycles", h.CreateCycle)
+	api.Get("/cycles/:id", h.GetCycle)
+	api.Patch("/cycles/:id", h.UpdateCycle)
+	api.Delete("/cycles/:id", h.DeleteCycle)
+	api.Post("/cycles/:id/close", h.CloseCycle)
+}
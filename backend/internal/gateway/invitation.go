@@ -0,0 +1,16 @@
+package gateway
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/routes"
+)
+
+func SetUpInvitationRoutes(api fiber.Router, h *routes.InvitationHandler) {
+	api.Post("/workspace/:workspaceid/invitations", h.CreateInvitation)
+	api.Get("/workspace/:workspaceid/invitations", h.ListWorkspaceInvitations)
+	api.Delete("/workspace/:workspaceid/invitations/:invitationid", h.RevokeInvitation)
+
+	api.Get("/invitations", h.ListMyInvitations)
+	api.Post("/invitations/accept", h.AcceptInvitation)
+	api.Post("/invitations/decline", h.DeclineInvitation)
+}
@@ -8,7 +8,21 @@ import (
 func SetUpIssueRoutes(api fiber.Router, h *routes.IssueHandler) {
 	api.Post("/issues", h.CreateIssue)
 	api.Patch("/issues/:id", h.UpdateIssue)
-	api.Get("/issues", h.GetIssuesByUserID)
+	api.Get("/issues", h.ListIssues)
 	api.Delete("/issues/:id", h.DeleteIssue)
+	api.Get("/issues/:id/history", h.GetIssueHistory)
+	api.Post("/issues/:id/move", h.MoveIssue)
+	api.Get("/issues/:id/labels", h.ListIssueLabels)
+	api.Get("/issues/:id/children", h.ListIssueChildren)
+	api.Get("/issues/:id/relations", h.ListIssueRelations)
+	api.Post("/issues/:id/relations", h.CreateIssueRelation)
+	api.Delete("/issues/:id/relations/:relationId", h.DeleteIssueRelation)
+	api.Post("/issues/:id/duplicate", h.MarkIssueDuplicate)
+
+	api.Post("/issues/:id/comments", h.CreateComment)
+	api.Get("/issues/:id/comments", h.ListComments)
+	api.Patch("/issues/:id/comments/:commentId", h.UpdateComment)
+	api.Delete("/issues/:id/comments/:commentId", h.DeleteComment)
+	api.Get("/issues/:id/comments/:commentId/history", h.GetCommentHistory)
 
 }
@@ -0,0 +1,14 @@
+package gateway
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/routes"
+)
+
+func SetUpIssueTemplateRoutes(api fiber.Router, h *routes.IssueTemplateHandler) {
+	api.Get("/teams/:id/templates", h.ListTeamIssueTemplates)
+	api.Post("/teams/:id/templates", h.CreateIssueTemplate)
+	api.Get("/templates/:id", h.GetIssueTemplate)
+	api.Patch("/templates/:id", h.UpdateIssueTemplate)
+	api.Delete("/templates/:id", h.DeleteIssueTemplate)
+}
@@ -0,0 +1,16 @@
+package gateway
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/routes"
+)
+
+func SetUpLabelRoutes(api fiber.Router, h *routes.LabelHandler) {
+	api.Get("/workspace/:workspaceid/labels", h.ListWorkspaceLabels)
+	api.Post("/workspace/:workspaceid/labels", h.CreateWorkspaceLabel)
+	api.Get("/teams/:id/labels", h.ListTeamLabels)
+	api.Post("/teams/:id/labels", h.CreateTeamLabel)
+	api.Patch("/labels/:id", h.UpdateLabel)
+	api.Delete("/labels/:id", h.DeleteLabel)
+	api.Post("/labels/:id/merge", h.MergeLabel)
+}
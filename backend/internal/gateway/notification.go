@@ -0,0 +1,12 @@
+package gateway
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/routes"
+)
+
+func SetUpNotificationRoutes(api fiber.Router, h *routes.NotificationHandler) {
+	api.Get("/notifications", h.ListNotifications)
+	api.Post("/notifications/read", h.MarkAllNotificationsRead)
+	api.Post("/notifications/:id/read", h.MarkNotificationRead)
+}
@@ -10,4 +10,14 @@ func SetUpProjectsRoutes(api fiber.Router, h *routes.ProjectHandler) {
 	api.Post("/projects", h.CreateProject)
 	api.Patch("/projects/:id", h.UpdateProject)
 	api.Delete("/projects/:id", h.DeleteProject)
+	api.Get("/projects/:id/labels", h.ListProjectLabels)
+	api.Get("/projects/:id/milestones", h.ListProjectMilestones)
+	api.Post("/projects/:id/milestones", h.CreateProjectMilestone)
+	api.Patch("/projects/:id/milestones/:milestoneId", h.UpdateProjectMilestone)
+	api.Delete("/projects/:id/milestones/:milestoneId", h.DeleteProjectMilestone)
+	api.Get("/projects/:id/updates", h.ListProjectUpdates)
+	api.Post("/projects/:id/updates", h.CreateProjectUpdate)
+	api.Get("/projects/:id/updates/:updateId", h.GetProjectUpdate)
+	api.Patch("/projects/:id/updates/:updateId", h.EditProjectUpdate)
+	api.Delete("/projects/:id/updates/:updateId", h.DeleteProjectUpdate)
 }
@@ -0,0 +1,14 @@
+package gateway
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/routes"
+)
+
+func SetUpRecurringIssueRoutes(api fiber.Router, h *routes.RecurringIssueHandler) {
+	api.Get("/teams/:id/recurring-issues", h.ListTeamRecurringIssues)
+	api.Post("/teams/:id/recurring-issues", h.CreateRecurringIssue)
+	api.Get("/recurring-issues/:id", h.GetRecurringIssue)
+	api.Patch("/recurring-issues/:id", h.UpdateRecurringIssue)
+	api.Delete("/recurring-issues/:id", h.DeleteRecurringIssue)
+}
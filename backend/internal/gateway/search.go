@@ -0,0 +1,10 @@
+package gateway
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/routes"
+)
+
+func SetUpSearchRoutes(api fiber.Router, h *routes.SearchHandler) {
+	api.Get("/search", h.Search)
+}
@@ -10,5 +10,12 @@ func SetUpTeamRoutes(api fiber.Router, h *routes.TeamHandler) {
 	api.Get("/teams", h.GetTeamsByUserID)
 	api.Patch("/teams/:id", h.UpdateTeam)
 	api.Delete("/teams/:id", h.DeleteTeam)
+	api.Get("/teams/:id/statuses", h.ListTeamStatuses)
+	api.Post("/teams/:id/statuses", h.CreateTeamStatus)
+	api.Patch("/teams/:id/statuses/:key", h.UpdateTeamStatus)
+	api.Delete("/teams/:id/statuses/:key", h.DeleteTeamStatus)
+	api.Get("/teams/:id/transition-rules", h.ListTransitionRules)
+	api.Post("/teams/:id/transition-rules", h.CreateTransitionRule)
+	api.Delete("/teams/:id/transition-rules/:ruleid", h.DeleteTransitionRule)
 
 }
@@ -10,5 +10,6 @@ func SetUpWorkspaceRoutes(api fiber.Router, h *routes.WorkspaceHandler) {
 	api.Post("/workspace", h.CreateWorkspace)
 	api.Patch("/workspace/:workspaceid", h.UpdateWorkspace)
 	api.Delete("/workspace/:workspaceid", h.DeleteWorkspace)
+	api.Patch("/workspace/:workspaceid/members/:userid", h.UpdateMemberRole)
 
 }
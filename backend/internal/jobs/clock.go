@@ -0,0 +1,37 @@
+// Package jobs runs the background work of the server: creating recurring
+// issues when they are due and reminding users of their issues' due dates.
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Clock tells the jobs what time it is, so tests can set it.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock is the wall clock.
+var SystemClock Clock = systemClock{}
+
+// every calls run right away and then every interval until ctx is done.
+func every(ctx context.Context, interval time.Duration, name string, run func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := run(ctx); err != nil {
+			log.Printf("Failed to run %s: %v", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/recurrence"
+	"github.com/nack098/nakumanager/internal/repositories"
+)
+
+// IssueCreator creates an issue on behalf of a user with the checks of the
+// API, as routes.IssueHandler does. It returns the ID and the identifier of
+// the issue.
+type IssueCreator interface {
+	CreateIssueAs(ctx context.Context, userID string, req models.IssueCreate, templateID string) (string, string, error)
+}
+
+// RecurringIssues creates the issues of recurring definitions when they fall
+// due. Each occurrence is claimed in the database before its issue is created,
+// so a restart or a second server never creates it twice.
+type RecurringIssues struct {
+	Repo     repositories.RecurringIssueRepository
+	Issues   IssueCreator
+	Clock    Clock
+	Interval time.Duration
+}
+
+func NewRecurringIssues(repo repositories.RecurringIssueRepository, issues IssueCreator) *RecurringIssues {
+	return &RecurringIssues{Repo: repo, Issues: issues, Clock: SystemClock, Interval: time.Minute}
+}
+
+// Run checks for due definitions every Interval until ctx is done.
+func (j *RecurringIssues) Run(ctx context.Context) {
+	every(ctx, j.Interval, "recurring issues", j.RunDue)
+}
+
+// RunDue creates an issue for each definition that is due and moves it on to
+// its next occurrence after now. Occurrences missed while the server was down
+// are caught up with a single issue.
+func (j *RecurringIssues) RunDue(ctx context.Context) error {
+	now := j.Clock.Now().UTC()
+	due, err := j.Repo.ListDueRecurringIssues(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, d := range due {
+		j.run(ctx, d, now)
+	}
+	return nil
+}
+
+func (j *RecurringIssues) run(ctx context.Context, d db.RecurringIssue, now time.Time) {
+	scheduled := d.NextRunAt.Time.UTC()
+	claimed, err := j.Repo.ClaimRecurringIssueRun(ctx, d.ID, scheduled)
+	if err != nil {
+		log.Printf("Failed to claim run of recurring issue %s at %s: %v", d.ID, scheduled, err)
+		return
+	}
+	// รอบที่จองไว้แล้วแต่ยังไม่เลื่อนไปรอบถัดไป เช่น server หยุดกลางทาง ก็เลื่อนต่อโดยไม่สร้างซ้ำ
+	if claimed {
+		finish := db.FinishRecurringIssueRunParams{RecurringID: d.ID, ScheduledFor: scheduled}
+		issueID, _, err := j.Issues.CreateIssueAs(ctx, d.CreatedBy, issueFromRecurring(d, scheduled), d.TemplateID.String)
+		if err != nil {
+			log.Printf("Failed to create issue of recurring issue %s: %v", d.ID, err)
+			finish.Error = sql.NullString{String: err.Error(), Valid: true}
+		} else {
+			finish.IssueID = sql.NullString{String: issueID, Valid: true}
+		}
+		if err := j.Repo.FinishRecurringIssueRun(ctx, finish); err != nil {
+			log.Printf("Failed to record run of recurring issue %s: %v", d.ID, err)
+		}
+	}
+
+	var next sql.NullTime
+	if rule, err := recurrence.Parse(d.Rule, d.StartsAt); err != nil {
+		log.Printf("Recurring issue %s has an invalid rule %q: %v", d.ID, d.Rule, err)
+	} else if t, ok := rule.Next(now); ok {
+		next = sql.NullTime{Time: t, Valid: true}
+	}
+	if err := j.Repo.AdvanceRecurringIssue(ctx, db.AdvanceRecurringIssueParams{
+		NextRunAt: next,
+		LastRunAt: sql.NullTime{Time: scheduled, Valid: true},
+		ID:        d.ID,
+	}); err != nil {
+		log.Printf("Failed to advance recurring issue %s: %v", d.ID, err)
+	}
+}
+
+// issueFromRecurring is the issue a definition creates for the occurrence
+// scheduled at the given time. {date} in its title is the day of the
+// occurrence.
+func issueFromRecurring(d db.RecurringIssue, scheduled time.Time) models.IssueCreate {
+	req := models.IssueCreate{
+		TeamID: d.TeamID,
+		Title:  strings.ReplaceAll(d.Title, "{date}", scheduled.Format("2006-01-02")),
+	}
+	if d.Content.Valid {
+		req.Content = &d.Content.String
+	}
+	if d.Priority.Valid {
+		req.Priority = &d.Priority.String
+	}
+	if d.ProjectID.Valid {
+		req.ProjectID = &d.ProjectID.String
+	}
+	return req
+}
@@ -0,0 +1,149 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+type created struct {
+	userID     string
+	req        models.IssueCreate
+	templateID string
+}
+
+// fakeCreator records the issues it is asked to create and fails with err.
+type fakeCreator struct {
+	created []created
+	err     error
+}
+
+func (f *fakeCreator) CreateIssueAs(ctx context.Context, userID string, req models.IssueCreate, templateID string) (string, string, error) {
+	f.created = append(f.created, created{userID, req, templateID})
+	if f.err != nil {
+		return "", "", f.err
+	}
+	return "issue-1", "ENG-1", nil
+}
+
+// Weekly on Monday at nine, due at the start of the test.
+var weeklyReview = db.RecurringIssue{
+	ID:         "rec-1",
+	TeamID:     "team-1",
+	TemplateID: sql.NullString{String: "tpl-1", Valid: true},
+	Title:      "Dependency review {date}",
+	Priority:   sql.NullString{String: "medium", Valid: true},
+	Rule:       "0 9 * * MON",
+	StartsAt:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	NextRunAt:  sql.NullTime{Time: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), Valid: true},
+	CreatedBy:  "user-123",
+}
+
+func newRecurringTestJob(now time.Time) (*RecurringIssues, *mocks.MockRecurringIssueRepo, *fakeCreator) {
+	repo := new(mocks.MockRecurringIssueRepo)
+	creator := &fakeCreator{}
+	job := NewRecurringIssues(repo, creator)
+	job.Clock = &fakeClock{now: now}
+	return job, repo, creator
+}
+
+func TestRunDueCreatesIssue(t *testing.T) {
+	now := time.Date(2026, 1, 5, 9, 0, 30, 0, time.UTC)
+	job, repo, creator := newRecurringTestJob(now)
+	scheduled := weeklyReview.NextRunAt.Time
+
+	repo.On("ListDueRecurringIssues", mock.Anything, now).Return([]db.RecurringIssue{weeklyReview}, nil)
+	repo.On("ClaimRecurringIssueRun", mock.Anything, "rec-1", scheduled).Return(true, nil)
+	repo.On("FinishRecurringIssueRun", mock.Anything, db.FinishRecurringIssueRunParams{
+		IssueID:      sql.NullString{String: "issue-1", Valid: true},
+		RecurringID:  "rec-1",
+		ScheduledFor: scheduled,
+	}).Return(nil).Once()
+	repo.On("AdvanceRecurringIssue", mock.Anything, db.AdvanceRecurringIssueParams{
+		NextRunAt: sql.NullTime{Time: time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC), Valid: true},
+		LastRunAt: sql.NullTime{Time: scheduled, Valid: true},
+		ID:        "rec-1",
+	}).Return(nil).Once()
+
+	require.NoError(t, job.RunDue(context.Background()))
+
+	require.Len(t, creator.created, 1)
+	c := creator.created[0]
+	assert.Equal(t, "user-123", c.userID)
+	assert.Equal(t, "tpl-1", c.templateID)
+	assert.Equal(t, "team-1", c.req.TeamID)
+	assert.Equal(t, "Dependency review 2026-01-05", c.req.Title)
+	assert.Equal(t, "medium", *c.req.Priority)
+	assert.Nil(t, c.req.Content)
+	repo.AssertExpectations(t)
+}
+
+func TestRunDueSkipsClaimedRun(t *testing.T) {
+	// the server stopped after creating the issue but before moving on
+	now := time.Date(2026, 1, 5, 9, 5, 0, 0, time.UTC)
+	job, repo, creator := newRecurringTestJob(now)
+
+	repo.On("ListDueRecurringIssues", mock.Anything, now).Return([]db.RecurringIssue{weeklyReview}, nil)
+	repo.On("ClaimRecurringIssueRun", mock.Anything, "rec-1", weeklyReview.NextRunAt.Time).Return(false, nil)
+	repo.On("AdvanceRecurringIssue", mock.Anything, mock.MatchedBy(func(p db.AdvanceRecurringIssueParams) bool {
+		return p.NextRunAt.Time.Equal(time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC))
+	})).Return(nil).Once()
+
+	require.NoError(t, job.RunDue(context.Background()))
+
+	assert.Empty(t, creator.created)
+	repo.AssertNumberOfCalls(t, "FinishRecurringIssueRun", 0)
+	repo.AssertExpectations(t)
+}
+
+func TestRunDueCatchesUpOnce(t *testing.T) {
+	// down for three weeks: one issue for the missed Mondays, then the next one
+	now := time.Date(2026, 1, 21, 12, 0, 0, 0, time.UTC)
+	job, repo, creator := newRecurringTestJob(now)
+
+	repo.On("ListDueRecurringIssues", mock.Anything, now).Return([]db.RecurringIssue{weeklyReview}, nil)
+	repo.On("ClaimRecurringIssueRun", mock.Anything, "rec-1", weeklyReview.NextRunAt.Time).Return(true, nil)
+	repo.On("FinishRecurringIssueRun", mock.Anything, mock.Anything).Return(nil)
+	repo.On("AdvanceRecurringIssue", mock.Anything, mock.MatchedBy(func(p db.AdvanceRecurringIssueParams) bool {
+		return p.NextRunAt.Time.Equal(time.Date(2026, 1, 26, 9, 0, 0, 0, time.UTC))
+	})).Return(nil).Once()
+
+	require.NoError(t, job.RunDue(context.Background()))
+
+	assert.Len(t, creator.created, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestRunDueRecordsFailure(t *testing.T) {
+	now := time.Date(2026, 1, 5, 9, 0, 30, 0, time.UTC)
+	job, repo, creator := newRecurringTestJob(now)
+	creator.err = errors.New("you are not a member of the team")
+
+	ended := weeklyReview
+	ended.Rule = "FREQ=WEEKLY;BYDAY=MO;BYHOUR=9;BYMINUTE=0;UNTIL=20260105T090000Z"
+
+	repo.On("ListDueRecurringIssues", mock.Anything, now).Return([]db.RecurringIssue{ended}, nil)
+	repo.On("ClaimRecurringIssueRun", mock.Anything, "rec-1", ended.NextRunAt.Time).Return(true, nil)
+	repo.On("FinishRecurringIssueRun", mock.Anything, mock.MatchedBy(func(p db.FinishRecurringIssueRunParams) bool {
+		return !p.IssueID.Valid && p.Error.String == creator.err.Error()
+	})).Return(nil).Once()
+	repo.On("AdvanceRecurringIssue", mock.Anything, mock.MatchedBy(func(p db.AdvanceRecurringIssueParams) bool {
+		return !p.NextRunAt.Valid
+	})).Return(nil).Once()
+
+	require.NoError(t, job.RunDue(context.Background()))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/repositories"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+// DueReminders notifies the owner and the assignees of an open issue once when
+// its end date is less than Window away and once more when it has passed.
+// Moving the end date starts the reminders over. Notifications are stored,
+// then pushed to each user's "user" WebSocket room.
+type DueReminders struct {
+	Repo     repositories.NotificationRepository
+	Clock    Clock
+	Interval time.Duration
+	Window   time.Duration
+}
+
+func NewDueReminders(repo repositories.NotificationRepository) *DueReminders {
+	return &DueReminders{Repo: repo, Clock: SystemClock, Interval: time.Minute, Window: 24 * time.Hour}
+}
+
+// Run sends the reminders that are due every Interval until ctx is done.
+func (j *DueReminders) Run(ctx context.Context) {
+	every(ctx, j.Interval, "due date reminders", j.RunDue)
+}
+
+// RunDue sends the reminders that are due now. An issue that is already
+// overdue when first seen only gets the overdue reminder.
+func (j *DueReminders) RunDue(ctx context.Context) error {
+	now := j.Clock.Now().UTC()
+	due, err := j.Repo.ListDueIssueRecipients(ctx, now.Add(j.Window))
+	if err != nil {
+		return err
+	}
+	for _, d := range due {
+		n := models.Notification{
+			ID:         uuid.New().String(),
+			Type:       models.NotificationDueSoon,
+			IssueID:    d.IssueID,
+			Identifier: d.Identifier,
+			Title:      d.Title,
+			TeamID:     d.TeamID,
+			DueAt:      d.EndDate.UTC(),
+			CreatedAt:  now,
+		}
+		if d.EndDate.Before(now) {
+			n.Type = models.NotificationOverdue
+		}
+		created, err := j.Repo.CreateNotification(ctx, db.CreateNotificationParams{
+			ID:        n.ID,
+			UserID:    d.UserID,
+			IssueID:   n.IssueID,
+			Type:      n.Type,
+			DueAt:     n.DueAt,
+			CreatedAt: n.CreatedAt,
+		})
+		if err != nil {
+			log.Printf("Failed to notify user %s about issue %s: %v", d.UserID, d.IssueID, err)
+			continue
+		}
+		if created {
+			ws.BroadcastToRoom("user", d.UserID, "notification", n)
+		}
+	}
+	return nil
+}
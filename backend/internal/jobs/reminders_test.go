@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/nack098/nakumanager/internal/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueRemindersRunDue(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(mocks.MockNotificationRepo)
+	job := NewDueReminders(repo)
+	job.Clock = &fakeClock{now: now}
+
+	owner, assignee, other := &ws.MockConn{}, &ws.MockConn{}, &ws.MockConn{}
+	ws.SetConnectionForTest(ws.RoomKey("user", "user-owner"), "user-owner", owner)
+	ws.SetConnectionForTest(ws.RoomKey("user", "user-assignee"), "user-assignee", assignee)
+	ws.SetConnectionForTest(ws.RoomKey("user", "user-other"), "user-other", other)
+
+	tomorrow := now.Add(20 * time.Hour)
+	yesterday := now.Add(-20 * time.Hour)
+	repo.On("ListDueIssueRecipients", mock.Anything, now.Add(24*time.Hour)).Return([]models.DueIssueRecipient{
+		{IssueID: "issue-1", Identifier: "ENG-1", Title: "Ship it", TeamID: "team-1", EndDate: tomorrow, UserID: "user-owner"},
+		{IssueID: "issue-2", Identifier: "ENG-2", Title: "Late", TeamID: "team-1", EndDate: yesterday, UserID: "user-assignee"},
+		{IssueID: "issue-1", Identifier: "ENG-1", Title: "Ship it", TeamID: "team-1", EndDate: tomorrow, UserID: "user-other"},
+		{IssueID: "issue-3", Identifier: "ENG-3", Title: "Broken", TeamID: "team-1", EndDate: yesterday, UserID: "user-other"},
+	}, nil)
+	repo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(p db.CreateNotificationParams) bool {
+		return p.UserID == "user-owner" && p.IssueID == "issue-1" && p.Type == models.NotificationDueSoon && p.DueAt.Equal(tomorrow)
+	})).Return(true, nil).Once()
+	repo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(p db.CreateNotificationParams) bool {
+		return p.UserID == "user-assignee" && p.IssueID == "issue-2" && p.Type == models.NotificationOverdue
+	})).Return(true, nil).Once()
+	// already reminded on an earlier run, and a failure that must not stop the rest
+	repo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(p db.CreateNotificationParams) bool {
+		return p.UserID == "user-other" && p.IssueID == "issue-1"
+	})).Return(false, nil).Once()
+	repo.On("CreateNotification", mock.Anything, mock.MatchedBy(func(p db.CreateNotificationParams) bool {
+		return p.UserID == "user-other" && p.IssueID == "issue-3"
+	})).Return(false, errors.New("database is locked")).Once()
+
+	require.NoError(t, job.RunDue(context.Background()))
+	repo.AssertExpectations(t)
+
+	require.Len(t, owner.Messages, 1)
+	msg := owner.Messages[0].(map[string]interface{})
+	assert.Equal(t, "notification", msg["type"])
+	n := msg["data"].(models.Notification)
+	assert.Equal(t, models.NotificationDueSoon, n.Type)
+	assert.Equal(t, "ENG-1", n.Identifier)
+	assert.Equal(t, now, n.CreatedAt)
+
+	require.Len(t, assignee.Messages, 1)
+	assert.Equal(t, models.NotificationOverdue, assignee.Messages[0].(map[string]interface{})["data"].(models.Notification).Type)
+	assert.Empty(t, other.Messages)
+}
@@ -0,0 +1,52 @@
+package mail_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nack098/nakumanager/internal/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	sender, err := mail.NewFileSender(dir)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, sender.Send(context.Background(), mail.Message{
+			To:      "a/b@x.com",
+			Subject: "Invitation",
+			Body:    "hello",
+		}))
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	name := entries[0].Name()
+	assert.True(t, strings.HasSuffix(name, "-a_b@x.com.eml"), name)
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "To: a/b@x.com\r\n")
+	assert.Contains(t, string(data), "Subject: Invitation\r\n")
+	assert.True(t, strings.HasSuffix(string(data), "\r\n\r\nhello"))
+}
+
+func TestNewSenderFromEnv(t *testing.T) {
+	t.Setenv("MAIL_DIR", "")
+	sender, err := mail.NewSenderFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, mail.LogSender{}, sender)
+
+	t.Setenv("MAIL_DIR", t.TempDir())
+	sender, err = mail.NewSenderFromEnv()
+	require.NoError(t, err)
+	assert.IsType(t, &mail.FileSender{}, sender)
+}
@@ -0,0 +1,9 @@
+package model
+
+type CreateComment struct {
+	Body string `json:"body" validate:"required"`
+}
+
+type UpdateComment struct {
+	Body string `json:"body" validate:"required"`
+}
@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// Cycle is a time-boxed iteration of a team. Numbers count up from 1 in each
+// team. Progress is counted live while the cycle is open and frozen when it is
+// closed.
+type Cycle struct {
+	ID        string        `json:"id"`
+	TeamID    string        `json:"team_id"`
+	Number    int64         `json:"number"`
+	Name      string        `json:"name,omitempty"`
+	StartDate time.Time     `json:"start_date"`
+	EndDate   time.Time     `json:"end_date"`
+	ClosedAt  *time.Time    `json:"closed_at,omitempty"`
+	Progress  CycleProgress `json:"progress"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// CycleProgress counts the issues of a cycle. Scope is every issue in it except
+// cancelled ones, Added those of them put in after the cycle started and
+// RolledOver the unfinished ones moved to the next cycle when it was closed.
+type CycleProgress struct {
+	Scope      int64 `json:"scope"`
+	Completed  int64 `json:"completed"`
+	Added      int64 `json:"added"`
+	RolledOver int64 `json:"rolled_over"`
+}
+
+type CreateCycleRequest struct {
+	Name      string    `json:"name,omitempty" validate:"max=64"`
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required"`
+}
+
+type UpdateCycleRequest struct {
+	Name      *string    `json:"name,omitempty" validate:"omitempty,max=64"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+}
+
+// CycleClosed is the result of closing a cycle: its final progress and the
+// cycle its unfinished issues were moved to, if there were any.
+type CycleClosed struct {
+	Cycle      Cycle    `json:"cycle"`
+	Next       *Cycle   `json:"next,omitempty"`
+	RolledOver []string `json:"rolled_over"`
+}
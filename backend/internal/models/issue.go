@@ -9,14 +9,18 @@ type IssueCreate struct {
 	Title     string     `json:"title" validate:"required"`
 	Content   *string    `json:"content,omitempty"`
 	Priority  *string    `json:"priority,omitempty" validate:"omitempty,oneof=low medium high"`
-	Status    string     `json:"status,omitempty" validate:"omitempty,oneof=todo doing done"`
+	Status    string     `json:"status,omitempty"`
 	Assignee  *[]string  `json:"assignee,omitempty"`
 	ProjectID *string    `json:"project_id,omitempty"`
 	TeamID    string     `json:"team_id" validate:"required"`
 	StartDate *time.Time `json:"start_date,omitempty"`
 	EndDate   *time.Time `json:"end_date,omitempty"`
-	Label     *string    `json:"label,omitempty"`
+	Labels    *[]string  `json:"labels,omitempty"`
 	OwnerID   string     `json:"owner_id" validate:"required"`
+	ParentID  *string    `json:"parent_id,omitempty"`
+	CycleID   *string    `json:"cycle_id,omitempty"`
+	// MilestoneID links the issue to a milestone of its project.
+	MilestoneID *string `json:"milestone_id,omitempty"`
 }
 
 type UpdateIssueRequest struct {
@@ -31,6 +35,76 @@ type UpdateIssueRequest struct {
 	TeamID         *string    `json:"team_id,omitempty"`
 	StartDate      *time.Time `json:"start_date,omitempty"`
 	EndDate        *time.Time `json:"end_date,omitempty"`
-	Label          *string    `json:"label,omitempty"`
+	AddLabel       *[]string  `json:"add_labels,omitempty"`
+	RemoveLabel    *[]string  `json:"remove_labels,omitempty"`
 	OwnerID        *string    `json:"owner_id,omitempty"`
+	// ParentID makes the issue a sub-issue; an empty string detaches it.
+	ParentID *string `json:"parent_id,omitempty"`
+	// CycleID puts the issue in a cycle of its team; an empty string takes it
+	// out of its cycle.
+	CycleID *string `json:"cycle_id,omitempty"`
+	// MilestoneID links the issue to a milestone of its project; an empty
+	// string unlinks it.
+	MilestoneID *string `json:"milestone_id,omitempty"`
+	// CascadeStatus moves the open sub-issues in the same team along when
+	// Status changes.
+	CascadeStatus bool `json:"cascade_status,omitempty"`
+}
+
+// SubIssueProgress rolls the sub-issues of an issue up into "3/5 done".
+// Cancelled sub-issues are not counted.
+type SubIssueProgress struct {
+	Completed int64 `json:"completed"`
+	Total     int64 `json:"total"`
+}
+
+// MoveIssueRequest places an issue on a board between two cards of the column
+// of Status, which defaults to the issue's current status. AfterID is the card
+// that ends up above it and BeforeID the one below; either can be left out at
+// the ends of the column, and both to put it at the bottom. Without ViewID the
+// team's board is used.
+type MoveIssueRequest struct {
+	Status   string `json:"status,omitempty"`
+	ViewID   string `json:"view_id,omitempty"`
+	AfterID  string `json:"after_id,omitempty"`
+	BeforeID string `json:"before_id,omitempty"`
+}
+
+// IssueMoved is sent to the clients of a board when one of its cards moves.
+type IssueMoved struct {
+	IssueID  string `json:"issue_id"`
+	ViewID   string `json:"view_id,omitempty"`
+	Status   string `json:"status"`
+	Rank     string `json:"rank"`
+	AfterID  string `json:"after_id,omitempty"`
+	BeforeID string `json:"before_id,omitempty"`
+}
+
+// IssueFilter narrows down the issues returned by the issue listing. Empty
+// fields do not filter; values within one field are OR-ed and fields are AND-ed.
+type IssueFilter struct {
+	Statuses    []string
+	Priorities  []string
+	Labels      []string
+	AssigneeIDs []string
+	Unassigned  bool
+	OwnerIDs    []string
+	ProjectIDs  []string
+	TeamIDs     []string
+	CycleIDs    []string
+	Milestones  []string
+	StartFrom   *time.Time
+	StartTo     *time.Time
+	EndFrom     *time.Time
+	EndTo       *time.Time
+	OverdueAt   *time.Time
+	Text        string
+	Sort        []IssueSort
+	Cursor      string
+	Limit       int
+}
+
+type IssueSort struct {
+	Field string
+	Desc  bool
 }
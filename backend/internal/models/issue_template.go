@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// IssueTemplate pre-fills new issues of a team. Title is a pattern in which
+// {title} is replaced by the title given when the issue is created and {date}
+// by the day it is created on.
+type IssueTemplate struct {
+	ID        string     `json:"id"`
+	TeamID    string     `json:"team_id"`
+	Name      string     `json:"name"`
+	Title     string     `json:"title,omitempty"`
+	Content   string     `json:"content,omitempty"`
+	Priority  string     `json:"priority,omitempty"`
+	ProjectID string     `json:"project_id,omitempty"`
+	Labels    []string   `json:"labels"`
+	Assignees []string   `json:"assignees"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type CreateIssueTemplateRequest struct {
+	Name      string   `json:"name" validate:"required,max=64"`
+	Title     string   `json:"title,omitempty" validate:"max=255"`
+	Content   string   `json:"content,omitempty"`
+	Priority  string   `json:"priority,omitempty" validate:"omitempty,oneof=low medium high"`
+	ProjectID string   `json:"project_id,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+// UpdateIssueTemplateRequest changes the fields that are set. Labels and
+// Assignees replace the lists of the template; an empty string clears
+// Priority or ProjectID.
+type UpdateIssueTemplateRequest struct {
+	Name      *string   `json:"name,omitempty" validate:"omitempty,min=1,max=64"`
+	Title     *string   `json:"title,omitempty" validate:"omitempty,max=255"`
+	Content   *string   `json:"content,omitempty"`
+	Priority  *string   `json:"priority,omitempty"`
+	ProjectID *string   `json:"project_id,omitempty"`
+	Labels    *[]string `json:"labels,omitempty"`
+	Assignees *[]string `json:"assignees,omitempty"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Label tags issues and projects. A label without TeamID belongs to the
+// workspace and can be used by all of its teams; otherwise only the team's
+// issues and projects can use it. Names are unique, ignoring case, within a
+// workspace's shared labels and within each team's labels.
+type Label struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	TeamID      string    `json:"team_id,omitempty"`
+	Name        string    `json:"name"`
+	Color       string    `json:"color"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CreateLabelRequest struct {
+	Name        string `json:"name" validate:"required,max=64"`
+	Color       string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	Description string `json:"description,omitempty" validate:"max=255"`
+}
+
+type UpdateLabelRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=64"`
+	Color       *string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=255"`
+}
+
+// MergeLabelRequest moves everything tagged with a label to the label Into and
+// deletes the first one.
+type MergeLabelRequest struct {
+	Into string `json:"into" validate:"required"`
+}
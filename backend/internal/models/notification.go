@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const (
+	NotificationDueSoon = "due_soon"
+	NotificationOverdue = "overdue"
+)
+
+// Notification tells a user about one of their issues. It is pushed to the
+// user's "user" WebSocket room as a "notification" event when created.
+type Notification struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	IssueID    string     `json:"issue_id"`
+	Identifier string     `json:"identifier"`
+	Title      string     `json:"title"`
+	TeamID     string     `json:"team_id"`
+	DueAt      time.Time  `json:"due_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+}
+
+// DueIssueRecipient is an open issue whose end date has come or is coming,
+// paired with one of the users to remind: its owner or an assignee.
+type DueIssueRecipient struct {
+	IssueID    string
+	Identifier string
+	Title      string
+	TeamID     string
+	EndDate    time.Time
+	UserID     string
+}
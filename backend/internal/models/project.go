@@ -12,7 +12,7 @@ type CreateProject struct {
 	LeaderID    *string   `json:"leader_id"`
 	StartDate   time.Time `json:"start_date"`
 	EndDate     time.Time `json:"end_date"`
-	Label       string    `json:"label"`
+	Labels      []string  `json:"labels"`
 	CreatedBy   string    `json:"created_by" validate:"required"`
 }
 
@@ -24,8 +24,53 @@ type EditProject struct {
 	Priority     *string    `json:"priority"`
 	StartDate    *time.Time `json:"start_date"`
 	EndDate      *time.Time `json:"end_date"`
-	Label        *string    `json:"label"`
 	AddMember    *[]string  `json:"add_member"`
 	RemoveMember *[]string  `json:"remove_member"`
+	AddLabel     *[]string  `json:"add_label"`
+	RemoveLabel  *[]string  `json:"remove_label"`
 	WorkspaceID  *string    `json:"workspace_id"`
 }
+
+// Milestone is a named step of a project. Milestones are ordered by Position
+// and their progress is counted from the issues linked to them.
+type Milestone struct {
+	ID         string     `json:"id"`
+	ProjectID  string     `json:"project_id"`
+	Name       string     `json:"name"`
+	TargetDate *time.Time `json:"target_date,omitempty"`
+	Position   int64      `json:"position"`
+	Progress   Progress   `json:"progress"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Progress counts issues by state. Cancelled issues are left out of Total and
+// Percent is Completed out of Total, or 0 when there are none.
+type Progress struct {
+	Total     int64 `json:"total"`
+	Completed int64 `json:"completed"`
+	Percent   int64 `json:"percent"`
+}
+
+type CreateMilestoneRequest struct {
+	Name       string     `json:"name" validate:"required,max=128"`
+	TargetDate *time.Time `json:"target_date,omitempty"`
+	Position   *int       `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+type UpdateMilestoneRequest struct {
+	Name       *string    `json:"name,omitempty" validate:"omitempty,min=1,max=128"`
+	TargetDate *time.Time `json:"target_date,omitempty"`
+	Position   *int       `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// CreateProjectUpdateRequest posts a status update on a project. Health is how
+// the project is doing and Body the markdown report.
+type CreateProjectUpdateRequest struct {
+	Health string `json:"health" validate:"required,oneof=on_track at_risk off_track"`
+	Body   string `json:"body" validate:"required,max=20000"`
+}
+
+type EditProjectUpdateRequest struct {
+	Health *string `json:"health,omitempty" validate:"omitempty,oneof=on_track at_risk off_track"`
+	Body   *string `json:"body,omitempty" validate:"omitempty,min=1,max=20000"`
+}
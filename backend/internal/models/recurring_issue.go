@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// RecurringIssue creates an issue in its team at every occurrence of Rule, a
+// cron expression or an RRULE in UTC. The issue is filled from TemplateID, if
+// set, with the fields of the definition winning over it. NextRunAt is empty
+// once the rule has no occurrences left.
+type RecurringIssue struct {
+	ID         string              `json:"id"`
+	TeamID     string              `json:"team_id"`
+	TemplateID string              `json:"template_id,omitempty"`
+	Title      string              `json:"title,omitempty"`
+	Content    string              `json:"content,omitempty"`
+	Priority   string              `json:"priority,omitempty"`
+	ProjectID  string              `json:"project_id,omitempty"`
+	Rule       string              `json:"rule"`
+	StartsAt   time.Time           `json:"starts_at"`
+	NextRunAt  *time.Time          `json:"next_run_at,omitempty"`
+	LastRunAt  *time.Time          `json:"last_run_at,omitempty"`
+	Paused     bool                `json:"paused"`
+	CreatedBy  string              `json:"created_by"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  *time.Time          `json:"updated_at,omitempty"`
+	Runs       []RecurringIssueRun `json:"runs,omitempty"`
+}
+
+// RecurringIssueRun is an occurrence of a recurring issue: the issue created
+// for it, or why it could not be.
+type RecurringIssueRun struct {
+	ScheduledFor time.Time `json:"scheduled_for"`
+	IssueID      string    `json:"issue_id,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// CreateRecurringIssueRequest needs a Title unless the template gives one.
+// StartsAt defaults to now.
+type CreateRecurringIssueRequest struct {
+	TemplateID string     `json:"template_id,omitempty"`
+	Title      string     `json:"title,omitempty" validate:"max=255"`
+	Content    string     `json:"content,omitempty"`
+	Priority   string     `json:"priority,omitempty" validate:"omitempty,oneof=low medium high"`
+	ProjectID  string     `json:"project_id,omitempty"`
+	Rule       string     `json:"rule" validate:"required"`
+	StartsAt   *time.Time `json:"starts_at,omitempty"`
+}
+
+// UpdateRecurringIssueRequest changes the fields that are set; an empty string
+// clears TemplateID, Content, Priority or ProjectID.
+type UpdateRecurringIssueRequest struct {
+	TemplateID *string    `json:"template_id,omitempty"`
+	Title      *string    `json:"title,omitempty" validate:"omitempty,max=255"`
+	Content    *string    `json:"content,omitempty"`
+	Priority   *string    `json:"priority,omitempty"`
+	ProjectID  *string    `json:"project_id,omitempty"`
+	Rule       *string    `json:"rule,omitempty"`
+	StartsAt   *time.Time `json:"starts_at,omitempty"`
+	Paused     *bool      `json:"paused,omitempty"`
+}
@@ -0,0 +1,30 @@
+package model
+
+// Types of issue relations. An issue that blocks another is blocked by it from
+// the other side; duplicates and duplicated_by pair up the same way and
+// relates_to is its own inverse.
+const (
+	RelationBlocks       = "blocks"
+	RelationBlockedBy    = "blocked_by"
+	RelationDuplicates   = "duplicates"
+	RelationDuplicatedBy = "duplicated_by"
+	RelationRelatesTo    = "relates_to"
+)
+
+type CreateIssueRelationRequest struct {
+	Type    string `json:"type" validate:"required,oneof=blocks blocked_by duplicates duplicated_by relates_to"`
+	IssueID string `json:"issue_id" validate:"required"`
+}
+
+// MarkDuplicateRequest closes an issue as a duplicate of CanonicalID.
+type MarkDuplicateRequest struct {
+	CanonicalID string `json:"canonical_id" validate:"required"`
+}
+
+// IssueWarning points at another issue affected by an update that went
+// through anyway, such as an open issue still blocked by one just closed.
+type IssueWarning struct {
+	IssueID    string `json:"issue_id"`
+	Identifier string `json:"identifier"`
+	Message    string `json:"message"`
+}
@@ -0,0 +1,13 @@
+package model
+
+type SearchResult struct {
+	Type        string  `json:"type"`
+	ID          string  `json:"id"`
+	Identifier  string  `json:"identifier,omitempty"`
+	WorkspaceID string  `json:"workspace_id"`
+	TeamID      string  `json:"team_id"`
+	ProjectID   *string `json:"project_id,omitempty"`
+	Title       string  `json:"title"`
+	Snippet     string  `json:"snippet,omitempty"`
+	Rank        float64 `json:"rank"`
+}
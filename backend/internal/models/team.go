@@ -1,14 +1,63 @@
 package model
 
+import "time"
+
+// CreateTeam adds a team to a workspace. Key prefixes the identifiers of the
+// team's issues, such as ENG-142. Without one, a key is made from the name.
 type CreateTeam struct {
 	ID          string `json:"id"`
 	Name        string `json:"name" validate:"required"`
 	WorkspaceID string `json:"workspace_id" validate:"required"`
+	Key         string `json:"key,omitempty"`
 }
 
 type UpdateTeamRequest struct {
 	Name          *string   `json:"name"`
+	Key           *string   `json:"key"`
 	AddMembers    *[]string `json:"add_members"`
 	RemoveMembers *[]string `json:"remove_members"`
 	NewLeaderID   *string   `json:"new_leader_id"`
 }
+
+// CreateTeamStatusRequest adds a workflow status to a team. Key is what issues
+// store in their status and cannot be changed later. The status goes after the
+// others unless Position is given.
+type CreateTeamStatusRequest struct {
+	Key      string `json:"key" validate:"required,max=32"`
+	Name     string `json:"name" validate:"required,max=64"`
+	Category string `json:"category" validate:"required,oneof=unstarted started completed cancelled"`
+	Color    string `json:"color" validate:"required,hexcolor"`
+	Position *int   `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+type UpdateTeamStatusRequest struct {
+	Name     *string `json:"name,omitempty" validate:"omitempty,min=1,max=64"`
+	Category *string `json:"category,omitempty" validate:"omitempty,oneof=unstarted started completed cancelled"`
+	Color    *string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	Position *int    `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// TransitionRule is one requirement an issue of the team must meet to move
+// into ToStatus, or into any status of ToCategory, from FromStatus or from any
+// status when FromStatus is empty. Requirement is one of
+//
+//	assignee           the issue must have an assignee
+//	end_date           the issue must have an end date
+//	actor_is_assignee  only an assignee of the issue may move it
+//	forbidden          the move is not allowed at all
+type TransitionRule struct {
+	ID          string    `json:"id"`
+	TeamID      string    `json:"team_id"`
+	FromStatus  string    `json:"from_status,omitempty"`
+	ToStatus    string    `json:"to_status,omitempty"`
+	ToCategory  string    `json:"to_category,omitempty"`
+	Requirement string    `json:"requirement"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CreateTransitionRuleRequest struct {
+	FromStatus  string `json:"from_status,omitempty"`
+	ToStatus    string `json:"to_status,omitempty" validate:"required_without=ToCategory"`
+	ToCategory  string `json:"to_category,omitempty" validate:"omitempty,oneof=unstarted started completed cancelled"`
+	Requirement string `json:"requirement" validate:"required,oneof=assignee end_date actor_is_assignee forbidden"`
+}
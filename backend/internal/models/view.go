@@ -1,11 +1,13 @@
 package model
 
 type CreateView struct {
-	ID        string   `json:"id"`
-	Name      string   `json:"name"`
-	TeamID    string   `json:"team_id"`
-	Assignnee string   `json:"assignee"`
-	GroupBys  []string `json:"group_bys"`
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	TeamID    string            `json:"team_id"`
+	Assignnee string            `json:"assignee"`
+	GroupBys  []string          `json:"group_bys"`
+	Groups    []ViewGroupOption `json:"groups,omitempty"`
+	Filter    *ViewFilter       `json:"filter,omitempty"`
 }
 
 type ViewGroupBy struct {
@@ -13,7 +15,56 @@ type ViewGroupBy struct {
 }
 
 type UpdateViewRequest struct {
-	TeamID   string   `json:"team_id"`
-	Name     string   `json:"name"`
-	GroupBys []string `json:"group_bys"`
+	TeamID   string            `json:"team_id"`
+	Name     string            `json:"name"`
+	GroupBys []string          `json:"group_bys"`
+	Groups   []ViewGroupOption `json:"groups,omitempty"`
+	Filter   *ViewFilter       `json:"filter,omitempty"`
+}
+
+// ViewGroupOption configures one level of grouping. Groups are ordered by
+// Order: "natural" (the default, e.g. the team's status order), "value" or "count",
+// reversed when Desc is set. ShowEmpty also lists the known values that have
+// no issues, such as team members without assigned issues.
+//
+// Views can be created with group_bys, a shorthand for groups with default
+// options, or with groups.
+type ViewGroupOption struct {
+	Field     string `json:"field"`
+	Order     string `json:"order,omitempty"`
+	Desc      bool   `json:"desc,omitempty"`
+	ShowEmpty bool   `json:"show_empty,omitempty"`
+}
+
+// ViewFilter is a group of conditions and nested groups joined with Op, which
+// is "and" (the default) or "or". For example "high priority bugs due this week
+// assigned to me" is
+//
+//	{"op": "and", "conditions": [
+//	    {"field": "priority", "operator": "eq", "value": "high"},
+//	    {"field": "label", "operator": "eq", "value": "bug"},
+//	    {"field": "end_date", "operator": "on_or_after", "value": "start_of_week"},
+//	    {"field": "end_date", "operator": "on_or_before", "value": "end_of_week"},
+//	    {"field": "assignee", "operator": "eq", "value": "me"}]}
+type ViewFilter struct {
+	Op         string          `json:"op,omitempty"`
+	Conditions []ViewCondition `json:"conditions,omitempty"`
+	Groups     []ViewFilter    `json:"groups,omitempty"`
+}
+
+// ViewCondition compares a field with Value, or with Values for the in and
+// not_in operators.
+type ViewCondition struct {
+	Field    string   `json:"field"`
+	Operator string   `json:"operator"`
+	Value    string   `json:"value,omitempty"`
+	Values   []string `json:"values,omitempty"`
+}
+
+type View struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	CreatedBy string      `json:"created_by"`
+	TeamID    string      `json:"team_id"`
+	Filter    *ViewFilter `json:"filter"`
 }
@@ -1,13 +1,18 @@
 package model
 
 type CreateWorkspace struct {
-	ID      string   `json:"id"`
-	Name    string   `json:"name" validate:"required"`
-	Members []string `json:"members"`
+	ID   string `json:"id"`
+	Name string `json:"name" validate:"required"`
 }
 
+// UpdateWorkspaceRequest still accepts add_members only to reject it; members
+// are added through invitations.
 type UpdateWorkspaceRequest struct {
 	Name          *string   `json:"name"`
 	AddMembers    *[]string `json:"add_members"`
 	RemoveMembers *[]string `json:"remove_members"`
 }
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin member guest"`
+}
@@ -0,0 +1,88 @@
+// Package rank makes lexorank-style keys for ordering cards on a board.
+//
+// A key is a string of the digits 0-9 and a-z that never ends with 0, and keys
+// compare as plain strings, so they sort correctly in SQL. There is always a
+// key between two different keys, which lets a card move between two others
+// by writing only the moved card.
+package rank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+var ErrOrder = errors.New("rank: keys are out of order")
+
+// Valid reports whether s is a key. The empty string is not.
+func Valid(s string) bool {
+	if s == "" || s[len(s)-1] == digits[0] {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(digits, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Between returns a key that sorts after a and before b. An empty a stands for
+// the start of the column and an empty b for its end, so Between("", "") is
+// the key of the first card.
+func Between(a, b string) (string, error) {
+	if a != "" && !Valid(a) {
+		return "", fmt.Errorf("rank: invalid key %q", a)
+	}
+	if b != "" && !Valid(b) {
+		return "", fmt.Errorf("rank: invalid key %q", b)
+	}
+	if b != "" && a >= b {
+		return "", ErrOrder
+	}
+	return midpoint(a, b), nil
+}
+
+// midpoint is the shortest key between a and b, where an empty b is past the
+// end. a may be shorter than b, in which case it is read as padded with 0.
+func midpoint(a, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	lo := strings.IndexByte(digits, digitAt(a, 0))
+	hi := len(digits)
+	if b != "" {
+		hi = strings.IndexByte(digits, b[0])
+	}
+	if hi-lo > 1 {
+		return string(digits[(lo+hi)/2])
+	}
+	// ตัวแรกติดกัน ถ้า b ยาวกว่าหนึ่งตัว ตัวแรกของ b อย่างเดียวก็อยู่ระหว่างแล้ว
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(digits[lo]) + midpoint(suffix(a, 1), "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+func suffix(s string, n int) string {
+	if n < len(s) {
+		return s[n:]
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package rank
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b, want string
+	}{
+		{"", "", "i"},
+		{"i", "", "r"},
+		{"", "i", "9"},
+		{"z", "", "zi"},
+		{"", "1", "0i"},
+		{"a", "b", "ai"},
+		{"a", "a1", "a0i"},
+		{"000001i", "000002i", "000002"},
+		{"000003i", "", "i"},
+		{"az", "b", "azi"},
+		{"a", "az", "ah"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, Valid(got))
+		})
+	}
+}
+
+func TestBetweenErrors(t *testing.T) {
+	_, err := Between("b", "a")
+	assert.ErrorIs(t, err, ErrOrder)
+	_, err = Between("a", "a")
+	assert.ErrorIs(t, err, ErrOrder)
+	_, err = Between("a0", "")
+	assert.Error(t, err)
+	_, err = Between("", "A")
+	assert.Error(t, err)
+}
+
+func TestBetweenKeepsOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	keys := []string{}
+	for i := 0; i < 2000; i++ {
+		// แทรกที่ตำแหน่งสุ่ม รวมถึงหัวและท้ายคอลัมน์
+		pos := r.Intn(len(keys) + 1)
+		a, b := "", ""
+		if pos > 0 {
+			a = keys[pos-1]
+		}
+		if pos < len(keys) {
+			b = keys[pos]
+		}
+		k, err := Between(a, b)
+		require.NoError(t, err)
+		require.True(t, (a == "" || a < k) && (b == "" || k < b), "%q < %q < %q", a, k, b)
+		keys = append(keys[:pos], append([]string{k}, keys[pos:]...)...)
+	}
+	assert.True(t, sort.StringsAreSorted(keys))
+}
@@ -0,0 +1,454 @@
+// Package recurrence works out when a recurring issue is due. A rule is either
+// a cron expression of five fields (minute, hour, day of month, month, day of
+// week), one of @hourly, @daily, @weekly, @monthly and @yearly, or an iCalendar
+// RRULE such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO". Rules run in UTC at a
+// precision of one minute.
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears bounds how far Next looks ahead, so a rule that can never match
+// such as "0 0 31 2 *" does not loop forever.
+const searchYears = 5
+
+type freq int
+
+const (
+	cron freq = iota
+	daily
+	weekly
+	monthly
+	yearly
+)
+
+// nthWeekday is a day such as the first Monday (N 1) or the last Friday
+// (N -1) of the month.
+type nthWeekday struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// Rule is a parsed recurrence. The sets are bitmasks; an empty day of month or
+// weekday set matches every day.
+type Rule struct {
+	minutes   uint64
+	hours     uint32
+	months    uint16
+	monthDays uint32
+	// lastDays counts from the end of the month: bit 1 is the last day.
+	lastDays uint32
+	weekdays uint8
+	nth      []nthWeekday
+	// dayOr is cron's rule that a day matches when either the day of month or
+	// the weekday does, if both are given.
+	dayOr bool
+
+	freq     freq
+	interval int
+	start    time.Time
+	until    time.Time
+}
+
+var macros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// Parse reads a cron expression or an RRULE. Occurrences start at start, which
+// is also where an RRULE takes the parts it leaves out from and counts its
+// INTERVAL from.
+func Parse(rule string, start time.Time) (*Rule, error) {
+	rule = strings.TrimSpace(rule)
+	start = start.UTC()
+	if m, ok := macros[strings.ToLower(rule)]; ok {
+		rule = m
+	}
+	upper := strings.ToUpper(rule)
+	if strings.HasPrefix(upper, "RRULE:") || strings.Contains(upper, "FREQ=") {
+		return parseRRule(strings.TrimPrefix(upper, "RRULE:"), start)
+	}
+	return parseCron(rule, start)
+}
+
+// Next returns the first occurrence after the given time, or false when there
+// is none left.
+func (r *Rule) Next(after time.Time) (time.Time, bool) {
+	t := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	if t.Before(r.start) {
+		t = r.start.Truncate(time.Minute)
+		if t.Before(r.start) {
+			t = t.Add(time.Minute)
+		}
+	}
+	limit := t.AddDate(searchYears, 0, 0)
+	for t.Before(limit) {
+		if !r.until.IsZero() && t.After(r.until) {
+			return time.Time{}, false
+		}
+		if !r.matchDay(t) || !r.inPeriod(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if r.hours&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if r.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func (r *Rule) matchDay(t time.Time) bool {
+	if r.months&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	hasMonthDay := r.monthDays != 0 || r.lastDays != 0
+	hasWeekday := r.weekdays != 0 || len(r.nth) != 0
+	monthDay, weekday := r.matchMonthDay(t), r.matchWeekday(t)
+	if r.dayOr && hasMonthDay && hasWeekday {
+		return monthDay || weekday
+	}
+	return monthDay && weekday
+}
+
+func (r *Rule) matchMonthDay(t time.Time) bool {
+	if r.monthDays == 0 && r.lastDays == 0 {
+		return true
+	}
+	fromEnd := daysIn(t) - t.Day() + 1
+	return r.monthDays&(1<<uint(t.Day())) != 0 || r.lastDays&(1<<uint(fromEnd)) != 0
+}
+
+func (r *Rule) matchWeekday(t time.Time) bool {
+	if r.weekdays == 0 && len(r.nth) == 0 {
+		return true
+	}
+	if r.weekdays&(1<<uint(t.Weekday())) != 0 {
+		return true
+	}
+	for _, n := range r.nth {
+		if n.Weekday != t.Weekday() {
+			continue
+		}
+		if n.N > 0 && (t.Day()-1)/7+1 == n.N {
+			return true
+		}
+		if n.N < 0 && (daysIn(t)-t.Day())/7+1 == -n.N {
+			return true
+		}
+	}
+	return false
+}
+
+// inPeriod reports whether t falls in a period the INTERVAL of an RRULE
+// keeps, counting from the period of the start.
+func (r *Rule) inPeriod(t time.Time) bool {
+	if r.interval <= 1 {
+		return true
+	}
+	var n int
+	switch r.freq {
+	case daily:
+		n = int(dayOf(t).Sub(dayOf(r.start)).Hours() / 24)
+	case weekly:
+		n = int(weekOf(t).Sub(weekOf(r.start)).Hours() / (24 * 7))
+	case monthly:
+		n = (t.Year()-r.start.Year())*12 + int(t.Month()) - int(r.start.Month())
+	case yearly:
+		n = t.Year() - r.start.Year()
+	}
+	return n%r.interval == 0
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekOf returns the Monday the week of t starts on.
+func weekOf(t time.Time) time.Time {
+	d := dayOf(t)
+	return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+}
+
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+var monthNames = []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+var weekdayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+func parseCron(rule string, start time.Time) (*Rule, error) {
+	fields := strings.Fields(rule)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression must have 5 fields, got %d", len(fields))
+	}
+	minutes, err := parseCronField(fields[0], 0, 59, nil)
+	if err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	hours, err := parseCronField(fields[1], 0, 23, nil)
+	if err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	monthDays, err := parseCronField(fields[2], 1, 31, nil)
+	if err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	months, err := parseCronField(fields[3], 1, 12, monthNames)
+	if err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	weekdays, err := parseCronField(fields[4], 0, 7, weekdayNames)
+	if err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	// 7 เป็นวันอาทิตย์เหมือน 0
+	if weekdays&(1<<7) != 0 {
+		weekdays = weekdays&^(1<<7) | 1
+	}
+
+	r := &Rule{
+		minutes: minutes,
+		hours:   uint32(hours),
+		months:  uint16(months),
+		dayOr:   true,
+		freq:    cron,
+		start:   start,
+	}
+	if !strings.HasPrefix(fields[2], "*") {
+		r.monthDays = uint32(monthDays)
+	}
+	if !strings.HasPrefix(fields[4], "*") {
+		r.weekdays = uint8(weekdays)
+	}
+	return r, nil
+}
+
+// parseCronField reads a list of values, ranges and steps such as "1-5",
+// "*/15" or "MON,WED". names, if given, are accepted for the values from min.
+func parseCronField(field string, min, max int, names []string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rng, step = part[:i], s
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = cronValue(bounds[0], names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = cronValue(bounds[1], names); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, names []string) (int, error) {
+	for i, name := range names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+var rruleFreqs = map[string]freq{"DAILY": daily, "WEEKLY": weekly, "MONTHLY": monthly, "YEARLY": yearly}
+var rruleDays = map[string]time.Weekday{"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday}
+
+func parseRRule(rule string, start time.Time) (*Rule, error) {
+	r := &Rule{interval: 1, start: start}
+	parts := map[string]string{}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+		parts[kv[0]] = kv[1]
+	}
+
+	f, ok := rruleFreqs[parts["FREQ"]]
+	if !ok {
+		return nil, fmt.Errorf("FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY")
+	}
+	r.freq = f
+
+	for key, value := range parts {
+		var err error
+		switch key {
+		case "FREQ", "WKST":
+		case "INTERVAL":
+			if r.interval, err = strconv.Atoi(value); err != nil || r.interval < 1 {
+				return nil, fmt.Errorf("INTERVAL must be a positive number")
+			}
+		case "UNTIL":
+			if r.until, err = parseUntil(value); err != nil {
+				return nil, err
+			}
+		case "BYMINUTE":
+			if r.minutes, err = parseRRuleList(value, 0, 59); err != nil {
+				return nil, fmt.Errorf("BYMINUTE: %w", err)
+			}
+		case "BYHOUR":
+			var hours uint64
+			if hours, err = parseRRuleList(value, 0, 23); err != nil {
+				return nil, fmt.Errorf("BYHOUR: %w", err)
+			}
+			r.hours = uint32(hours)
+		case "BYMONTH":
+			var months uint64
+			if months, err = parseRRuleList(value, 1, 12); err != nil {
+				return nil, fmt.Errorf("BYMONTH: %w", err)
+			}
+			r.months = uint16(months)
+		case "BYMONTHDAY":
+			if err = r.parseMonthDays(value); err != nil {
+				return nil, err
+			}
+		case "BYDAY":
+			if err = r.parseDays(value); err != nil {
+				return nil, err
+			}
+		case "COUNT":
+			return nil, fmt.Errorf("COUNT is not supported, use UNTIL")
+		default:
+			return nil, fmt.Errorf("%s is not supported", key)
+		}
+	}
+
+	// ส่วนที่ไม่ได้ระบุใช้ค่าจากเวลาเริ่ม เช่น WEEKLY เฉยๆ คือวันเดียวกับวันเริ่มของทุกสัปดาห์
+	if r.minutes == 0 {
+		r.minutes = 1 << uint(start.Minute())
+	}
+	if r.hours == 0 {
+		r.hours = 1 << uint(start.Hour())
+	}
+	noDay := r.monthDays == 0 && r.lastDays == 0 && r.weekdays == 0 && len(r.nth) == 0
+	switch r.freq {
+	case weekly:
+		if r.weekdays == 0 {
+			r.weekdays = 1 << uint(start.Weekday())
+		}
+	case monthly:
+		if noDay {
+			r.monthDays = 1 << uint(start.Day())
+		}
+	case yearly:
+		if r.months == 0 {
+			r.months = 1 << uint(start.Month())
+		}
+		if noDay {
+			r.monthDays = 1 << uint(start.Day())
+		}
+	}
+	if r.months == 0 {
+		r.months = 0x1ffe
+	}
+	return r, nil
+}
+
+func parseRRuleList(value string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, s := range strings.Split(value, ",") {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < min || v > max {
+			return 0, fmt.Errorf("%q is out of range %d-%d", s, min, max)
+		}
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+func (r *Rule) parseMonthDays(value string) error {
+	for _, s := range strings.Split(value, ",") {
+		v, err := strconv.Atoi(s)
+		switch {
+		case err != nil || v == 0 || v < -31 || v > 31:
+			return fmt.Errorf("BYMONTHDAY: %q is out of range", s)
+		case v > 0:
+			r.monthDays |= 1 << uint(v)
+		default:
+			r.lastDays |= 1 << uint(-v)
+		}
+	}
+	return nil
+}
+
+// parseDays reads BYDAY. An ordinal such as 1MO or -1FR picks one weekday of
+// the month and is only allowed with FREQ=MONTHLY.
+func (r *Rule) parseDays(value string) error {
+	for _, s := range strings.Split(value, ",") {
+		if len(s) < 2 {
+			return fmt.Errorf("BYDAY: invalid day %q", s)
+		}
+		day, ok := rruleDays[s[len(s)-2:]]
+		if !ok {
+			return fmt.Errorf("BYDAY: invalid day %q", s)
+		}
+		if len(s) == 2 {
+			r.weekdays |= 1 << uint(day)
+			continue
+		}
+		n, err := strconv.Atoi(s[:len(s)-2])
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return fmt.Errorf("BYDAY: invalid day %q", s)
+		}
+		if r.freq != monthly {
+			return fmt.Errorf("BYDAY: %q needs FREQ=MONTHLY", s)
+		}
+		r.nth = append(r.nth, nthWeekday{N: n, Weekday: day})
+	}
+	return nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// ทั้งวันสุดท้ายยังนับอยู่
+				t = t.Add(24*time.Hour - time.Minute)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("UNTIL must be a date such as 20261231 or 20261231T090000Z")
+}
@@ -0,0 +1,88 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	// วันจันทร์
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	at := func(s string) time.Time {
+		v, err := time.Parse("2006-01-02 15:04", s)
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+		want  []string
+	}{
+		{"cron weekdays at nine", "0 9 * * 1-5", at("2026-01-09 09:00"), []string{"2026-01-12 09:00", "2026-01-13 09:00"}},
+		{"cron every fifteen minutes", "*/15 * * * *", at("2026-01-05 10:07"), []string{"2026-01-05 10:15", "2026-01-05 10:30"}},
+		{"cron day of month or weekday", "0 0 15 * FRI", at("2026-02-01 00:00"), []string{"2026-02-06 00:00", "2026-02-13 00:00", "2026-02-15 00:00", "2026-02-20 00:00"}},
+		{"cron sunday as 7", "30 8 * * 7", at("2026-01-05 00:00"), []string{"2026-01-11 08:30"}},
+		{"cron named months", "0 0 1 JAN,JUL *", at("2026-01-05 00:00"), []string{"2026-07-01 00:00", "2027-01-01 00:00"}},
+		{"macro", "@monthly", at("2026-01-05 00:00"), []string{"2026-02-01 00:00", "2026-03-01 00:00"}},
+		{"not before the start", "0 * * * *", at("2025-12-01 00:00"), []string{"2026-01-05 09:00", "2026-01-05 10:00"}},
+		{"rrule weekly takes the start's day and time", "FREQ=WEEKLY", at("2026-01-05 09:00"), []string{"2026-01-12 09:00", "2026-01-19 09:00"}},
+		{"rrule every other week", "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", at("2026-01-05 09:00"), []string{"2026-01-08 09:00", "2026-01-19 09:00", "2026-01-22 09:00"}},
+		{"rrule last day of the month", "FREQ=MONTHLY;BYMONTHDAY=-1;BYHOUR=17;BYMINUTE=0", at("2026-01-05 09:00"), []string{"2026-01-31 17:00", "2026-02-28 17:00"}},
+		{"rrule first monday", "FREQ=MONTHLY;BYDAY=1MO", at("2026-01-05 09:00"), []string{"2026-02-02 09:00", "2026-03-02 09:00"}},
+		{"rrule quarterly", "FREQ=MONTHLY;INTERVAL=3", at("2026-01-05 09:00"), []string{"2026-04-05 09:00", "2026-07-05 09:00"}},
+		{"rrule until", "FREQ=DAILY;UNTIL=20260107", at("2026-01-05 09:00"), []string{"2026-01-06 09:00", "2026-01-07 09:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule, start)
+			require.NoError(t, err)
+
+			after := tt.after
+			for _, want := range tt.want {
+				next, ok := r.Next(after)
+				require.True(t, ok)
+				assert.Equal(t, at(want), next)
+				after = next
+			}
+		})
+	}
+}
+
+func TestNextEnds(t *testing.T) {
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	r, err := Parse("FREQ=DAILY;UNTIL=20260106T090000Z", start)
+	require.NoError(t, err)
+	_, ok := r.Next(time.Date(2026, 1, 6, 9, 0, 0, 0, time.UTC))
+	assert.False(t, ok, "no occurrence after UNTIL")
+
+	r, err = Parse("0 0 31 2 *", start)
+	require.NoError(t, err)
+	_, ok = r.Next(start)
+	assert.False(t, ok, "February 31st never comes")
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"0 9 * *",
+		"60 * * * *",
+		"0 9 * * MON-FOO",
+		"*/0 * * * *",
+		"FREQ=HOURLY",
+		"FREQ=WEEKLY;COUNT=3",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;INTERVAL=0",
+	} {
+		_, err := Parse(rule, time.Now())
+		assert.Error(t, err, rule)
+	}
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type CommentRepository interface {
+	CreateComment(ctx context.Context, data db.CreateCommentParams) error
+	GetCommentByID(ctx context.Context, id string) (db.IssueComment, error)
+	ListCommentsByIssueID(ctx context.Context, issueID string) ([]db.IssueComment, error)
+	UpdateComment(ctx context.Context, id, body, editedBy string) error
+	DeleteComment(ctx context.Context, id string) error
+	ListCommentEdits(ctx context.Context, commentID string) ([]db.IssueCommentEdit, error)
+	SetMentions(ctx context.Context, commentID string, usernames []string) ([]db.ListMentionsByCommentIDRow, error)
+	ListMentions(ctx context.Context, commentID string) ([]db.ListMentionsByCommentIDRow, error)
+}
+
+type commentRepo struct {
+	queries *db.Queries
+	rawDb   *sql.DB
+}
+
+func NewCommentRepository(dbConn *sql.DB) CommentRepository {
+	return &commentRepo{
+		queries: db.New(dbConn),
+		rawDb:   dbConn,
+	}
+}
+
+func (r *commentRepo) CreateComment(ctx context.Context, data db.CreateCommentParams) error {
+	return r.queries.CreateComment(ctx, data)
+}
+
+func (r *commentRepo) GetCommentByID(ctx context.Context, id string) (db.IssueComment, error) {
+	return r.queries.GetCommentByID(ctx, id)
+}
+
+func (r *commentRepo) ListCommentsByIssueID(ctx context.Context, issueID string) ([]db.IssueComment, error) {
+	return r.queries.ListCommentsByIssueID(ctx, issueID)
+}
+
+// UpdateComment replaces the comment body and keeps the previous body as an
+// edit history entry, both in a single transaction.
+func (r *commentRepo) UpdateComment(ctx context.Context, id, body, editedBy string) error {
+	tx, err := r.rawDb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := r.queries.WithTx(tx)
+
+	current, err := q.GetCommentByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if err := q.CreateCommentEdit(ctx, db.CreateCommentEditParams{
+		ID:           uuid.New().String(),
+		CommentID:    id,
+		PreviousBody: current.Body,
+		EditedBy:     editedBy,
+		EditedAt:     now,
+	}); err != nil {
+		return err
+	}
+
+	if err := q.UpdateCommentBody(ctx, db.UpdateCommentBodyParams{
+		Body:      body,
+		UpdatedAt: sql.NullTime{Time: now, Valid: true},
+		ID:        id,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *commentRepo) DeleteComment(ctx context.Context, id string) error {
+	return r.queries.DeleteComment(ctx, id)
+}
+
+func (r *commentRepo) ListCommentEdits(ctx context.Context, commentID string) ([]db.IssueCommentEdit, error) {
+	return r.queries.ListCommentEditsByCommentID(ctx, commentID)
+}
+
+// SetMentions replaces the mentions of a comment with the given usernames.
+// Usernames that do not belong to any user are ignored.
+func (r *commentRepo) SetMentions(ctx context.Context, commentID string, usernames []string) ([]db.ListMentionsByCommentIDRow, error) {
+	if err := r.queries.ClearMentionsFromComment(ctx, commentID); err != nil {
+		return nil, err
+	}
+
+	for _, username := range usernames {
+		userID, err := r.queries.GetUserIDByUsername(ctx, username)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return nil, err
+		}
+		if err := r.queries.AddMentionToComment(ctx, db.AddMentionToCommentParams{
+			CommentID: commentID,
+			UserID:    userID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.queries.ListMentionsByCommentID(ctx, commentID)
+}
+
+func (r *commentRepo) ListMentions(ctx context.Context, commentID string) ([]db.ListMentionsByCommentIDRow, error) {
+	return r.queries.ListMentionsByCommentID(ctx, commentID)
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type CycleRepository interface {
+	CreateCycle(ctx context.Context, data db.CreateCycleParams) error
+	CreateCycleTx(ctx context.Context, tx *sql.Tx, data db.CreateCycleParams) error
+	GetCycleByID(ctx context.Context, id string) (db.Cycle, error)
+	ListTeamCycles(ctx context.Context, teamID string) ([]db.Cycle, error)
+	GetNextCycleNumber(ctx context.Context, teamID string) (int64, error)
+	GetNextCycleNumberTx(ctx context.Context, tx *sql.Tx, teamID string) (int64, error)
+	GetNextOpenCycleTx(ctx context.Context, tx *sql.Tx, cycle db.Cycle) (db.Cycle, error)
+	UpdateCycle(ctx context.Context, data db.UpdateCycleParams) error
+	DeleteCycle(ctx context.Context, id string) error
+	CloseCycleTx(ctx context.Context, tx *sql.Tx, data db.CloseCycleParams) (bool, error)
+	GetCycleStats(ctx context.Context, cycleID string) (db.GetCycleStatsRow, error)
+	GetCycleStatsTx(ctx context.Context, tx *sql.Tx, cycleID string) (db.GetCycleStatsRow, error)
+	ListUnfinishedCycleIssueIDsTx(ctx context.Context, tx *sql.Tx, cycleID string) ([]string, error)
+	SetIssueCycleTx(ctx context.Context, tx *sql.Tx, data db.SetIssueCycleParams) error
+}
+
+type cycleRepo struct {
+	queries *db.Queries
+}
+
+func NewCycleRepository(dbConn *sql.DB) CycleRepository {
+	return &cycleRepo{queries: db.New(dbConn)}
+}
+
+func (r *cycleRepo) CreateCycle(ctx context.Context, data db.CreateCycleParams) error {
+	return r.queries.CreateCycle(ctx, data)
+}
+
+func (r *cycleRepo) CreateCycleTx(ctx context.Context, tx *sql.Tx, data db.CreateCycleParams) error {
+	return r.queries.WithTx(tx).CreateCycle(ctx, data)
+}
+
+func (r *cycleRepo) GetCycleByID(ctx context.Context, id string) (db.Cycle, error) {
+	return r.queries.GetCycleByID(ctx, id)
+}
+
+// ListTeamCycles returns the cycles of the team in order of their numbers.
+func (r *cycleRepo) ListTeamCycles(ctx context.Context, teamID string) ([]db.Cycle, error) {
+	return r.queries.ListTeamCycles(ctx, teamID)
+}
+
+func (r *cycleRepo) GetNextCycleNumber(ctx context.Context, teamID string) (int64, error) {
+	return r.queries.GetNextCycleNumber(ctx, teamID)
+}
+
+func (r *cycleRepo) GetNextCycleNumberTx(ctx context.Context, tx *sql.Tx, teamID string) (int64, error) {
+	return r.queries.WithTx(tx).GetNextCycleNumber(ctx, teamID)
+}
+
+// GetNextOpenCycleTx returns the first cycle of the same team after cycle that
+// is not closed yet, or sql.ErrNoRows when there is none.
+func (r *cycleRepo) GetNextOpenCycleTx(ctx context.Context, tx *sql.Tx, cycle db.Cycle) (db.Cycle, error) {
+	return r.queries.WithTx(tx).GetNextOpenCycle(ctx, db.GetNextOpenCycleParams{TeamID: cycle.TeamID, Number: cycle.Number})
+}
+
+func (r *cycleRepo) UpdateCycle(ctx context.Context, data db.UpdateCycleParams) error {
+	return r.queries.UpdateCycle(ctx, data)
+}
+
+// DeleteCycle deletes the cycle. Its issues stay but no longer have a cycle.
+func (r *cycleRepo) DeleteCycle(ctx context.Context, id string) error {
+	return r.queries.DeleteCycle(ctx, id)
+}
+
+// CloseCycleTx reports whether the cycle was closed. It is not when it had
+// been closed already.
+func (r *cycleRepo) CloseCycleTx(ctx context.Context, tx *sql.Tx, data db.CloseCycleParams) (bool, error) {
+	n, err := r.queries.WithTx(tx).CloseCycle(ctx, data)
+	return n > 0, err
+}
+
+// GetCycleStats counts the issues currently in the cycle.
+func (r *cycleRepo) GetCycleStats(ctx context.Context, cycleID string) (db.GetCycleStatsRow, error) {
+	return r.queries.GetCycleStats(ctx, sql.NullString{String: cycleID, Valid: true})
+}
+
+func (r *cycleRepo) GetCycleStatsTx(ctx context.Context, tx *sql.Tx, cycleID string) (db.GetCycleStatsRow, error) {
+	return r.queries.WithTx(tx).GetCycleStats(ctx, sql.NullString{String: cycleID, Valid: true})
+}
+
+// ListUnfinishedCycleIssueIDsTx returns the issues of the cycle that are
+// neither completed nor cancelled.
+func (r *cycleRepo) ListUnfinishedCycleIssueIDsTx(ctx context.Context, tx *sql.Tx, cycleID string) ([]string, error) {
+	return r.queries.WithTx(tx).ListUnfinishedCycleIssueIDs(ctx, sql.NullString{String: cycleID, Valid: true})
+}
+
+func (r *cycleRepo) SetIssueCycleTx(ctx context.Context, tx *sql.Tx, data db.SetIssueCycleParams) error {
+	return r.queries.WithTx(tx).SetIssueCycle(ctx, data)
+}
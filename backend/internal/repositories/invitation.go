@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type InvitationRepository interface {
+	CreateInvitation(ctx context.Context, data db.CreateInvitationParams) error
+	GetInvitationByID(ctx context.Context, id string) (db.WorkspaceInvitation, error)
+	ListInvitationsByWorkspaceID(ctx context.Context, workspaceID string) ([]db.WorkspaceInvitation, error)
+	ListPendingInvitationsByEmail(ctx context.Context, email string) ([]db.ListPendingInvitationsByEmailRow, error)
+	RevokeInvitation(ctx context.Context, id, workspaceID string) (bool, error)
+	AcceptInvitation(ctx context.Context, invitation db.WorkspaceInvitation, userID string) (bool, error)
+	DeclineInvitation(ctx context.Context, invitation db.WorkspaceInvitation) (bool, error)
+}
+
+type invitationRepo struct {
+	queries *db.Queries
+	rawDb   *sql.DB
+}
+
+func NewInvitationRepository(dbConn *sql.DB) InvitationRepository {
+	return &invitationRepo{
+		queries: db.New(dbConn),
+		rawDb:   dbConn,
+	}
+}
+
+// CreateInvitation stores a new invitation and revokes any pending invitation
+// for the same email in the workspace, so only the latest token can be used.
+func (r *invitationRepo) CreateInvitation(ctx context.Context, data db.CreateInvitationParams) error {
+	tx, err := r.rawDb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := r.queries.WithTx(tx)
+
+	if err := q.RevokePendingInvitationsByEmail(ctx, db.RevokePendingInvitationsByEmailParams{
+		RespondedAt: sql.NullTime{Time: data.CreatedAt, Valid: true},
+		WorkspaceID: data.WorkspaceID,
+		Email:       data.Email,
+	}); err != nil {
+		return err
+	}
+
+	if err := q.CreateInvitation(ctx, data); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *invitationRepo) GetInvitationByID(ctx context.Context, id string) (db.WorkspaceInvitation, error) {
+	return r.queries.GetInvitationByID(ctx, id)
+}
+
+func (r *invitationRepo) ListInvitationsByWorkspaceID(ctx context.Context, workspaceID string) ([]db.WorkspaceInvitation, error) {
+	return r.queries.ListInvitationsByWorkspaceID(ctx, workspaceID)
+}
+
+func (r *invitationRepo) ListPendingInvitationsByEmail(ctx context.Context, email string) ([]db.ListPendingInvitationsByEmailRow, error) {
+	return r.queries.ListPendingInvitationsByEmail(ctx, email)
+}
+
+func (r *invitationRepo) RevokeInvitation(ctx context.Context, id, workspaceID string) (bool, error) {
+	n, err := r.queries.RevokeInvitation(ctx, db.RevokeInvitationParams{
+		RespondedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:          id,
+		WorkspaceID: workspaceID,
+	})
+	return n == 1, err
+}
+
+// AcceptInvitation marks the invitation as accepted and adds the user to the
+// workspace in one transaction. It returns false when the invitation was no
+// longer pending, e.g. because the token was already used.
+func (r *invitationRepo) AcceptInvitation(ctx context.Context, invitation db.WorkspaceInvitation, userID string) (bool, error) {
+	tx, err := r.rawDb.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	q := r.queries.WithTx(tx)
+
+	n, err := q.RespondToInvitation(ctx, db.RespondToInvitationParams{
+		Status:      "accepted",
+		RespondedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:          invitation.ID,
+		TokenHash:   invitation.TokenHash,
+	})
+	if err != nil || n != 1 {
+		return false, err
+	}
+
+	// ถ้าเป็นสมาชิกอยู่แล้ว role เดิมจะไม่ถูกเปลี่ยน
+	if err := q.AddMemberToWorkspace(ctx, db.AddMemberToWorkspaceParams{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      userID,
+		Role:        invitation.Role,
+	}); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *invitationRepo) DeclineInvitation(ctx context.Context, invitation db.WorkspaceInvitation) (bool, error) {
+	n, err := r.queries.RespondToInvitation(ctx, db.RespondToInvitationParams{
+		Status:      "declined",
+		RespondedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:          invitation.ID,
+		TokenHash:   invitation.TokenHash,
+	})
+	return n == 1, err
+}
@@ -2,27 +2,53 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 
 	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
 )
 
 type IssueRepository interface {
 	AddAssigneeToIssue(ctx context.Context, data db.AddAssigneeToIssueParams) error
 	CreateIssue(ctx context.Context, data db.CreateIssueParams) error
+	CreateIssueTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueParams) error
 	DeleteIssue(ctx context.Context, id string) error
 	GetIssueByID(ctx context.Context, id string) (db.Issue, error)
 	ListAssigneesByIssueID(ctx context.Context, issueID string) ([]db.User, error)
 	ListIssuesByTeamID(ctx context.Context, teamID string) ([]db.Issue, error)
 	RemoveAssigneeFromIssue(ctx context.Context, data db.RemoveAssigneeFromIssueParams) error
+	AddAssigneeToIssueTx(ctx context.Context, tx *sql.Tx, data db.AddAssigneeToIssueParams) error
+	RemoveAssigneeFromIssueTx(ctx context.Context, tx *sql.Tx, data db.RemoveAssigneeFromIssueParams) error
 	GetIssueByUserID(ctx context.Context, userID string) ([]db.Issue, error)
+	ListIssues(ctx context.Context, userID string, filter models.IssueFilter) ([]db.Issue, string, error)
+	CreateIssueEventTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueEventParams) error
+	ListIssueEvents(ctx context.Context, issueID string, limit, offset int64) ([]db.IssueEvent, error)
+	CountIssueEvents(ctx context.Context, issueID string) (int64, error)
+	GetTeamIDByViewID(ctx context.Context, viewID string) (string, error)
+	GetIssueBoardRankTx(ctx context.Context, tx *sql.Tx, data db.GetIssueBoardRankParams) (db.GetIssueBoardRankRow, error)
+	GetLastIssueBoardRankTx(ctx context.Context, tx *sql.Tx, data db.GetLastIssueBoardRankParams) (string, error)
+	MoveIssueTx(ctx context.Context, tx *sql.Tx, viewID, issueID, status, rank string) error
+	NextIssueNumber(ctx context.Context, teamID string) (int64, string, error)
+	ListIssueIDsByIdentifier(ctx context.Context, identifier, userID string) ([]string, error)
+	ListChildIssues(ctx context.Context, parentID string) ([]db.Issue, error)
+	ListIssueDescendants(ctx context.Context, issueID string) ([]db.Issue, error)
+	ListIssueAncestorIDs(ctx context.Context, issueID string) ([]string, error)
+	GetSubIssueProgress(ctx context.Context, parentID string) (db.GetSubIssueProgressRow, error)
+	SetIssueStatusTx(ctx context.Context, tx *sql.Tx, issueID, status string) error
+	DeleteIssuesTx(ctx context.Context, tx *sql.Tx, ids []string) error
 }
 
 type issueRepo struct {
 	queries *db.Queries
+	rawDb   *sql.DB
 }
 
-func NewIssueRepository(q *db.Queries) IssueRepository {
-	return &issueRepo{queries: q}
+func NewIssueRepository(dbConn *sql.DB) IssueRepository {
+	return &issueRepo{
+		queries: db.New(dbConn),
+		rawDb:   dbConn,
+	}
 }
 
 func (r *issueRepo) AddAssigneeToIssue(ctx context.Context, data db.AddAssigneeToIssueParams) error {
@@ -33,6 +59,10 @@ func (r *issueRepo) CreateIssue(ctx context.Context, data db.CreateIssueParams)
 	return r.queries.CreateIssue(ctx, data)
 }
 
+func (r *issueRepo) CreateIssueTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueParams) error {
+	return r.queries.WithTx(tx).CreateIssue(ctx, data)
+}
+
 func (r *issueRepo) DeleteIssue(ctx context.Context, id string) error {
 	return r.queries.DeleteIssue(ctx, id)
 }
@@ -53,6 +83,119 @@ func (r *issueRepo) RemoveAssigneeFromIssue(ctx context.Context, data db.RemoveA
 	return r.queries.RemoveAssigneeFromIssue(ctx, data)
 }
 
+func (r *issueRepo) AddAssigneeToIssueTx(ctx context.Context, tx *sql.Tx, data db.AddAssigneeToIssueParams) error {
+	return r.queries.WithTx(tx).AddAssigneeToIssue(ctx, data)
+}
+
+func (r *issueRepo) RemoveAssigneeFromIssueTx(ctx context.Context, tx *sql.Tx, data db.RemoveAssigneeFromIssueParams) error {
+	return r.queries.WithTx(tx).RemoveAssigneeFromIssue(ctx, data)
+}
+
 func (r *issueRepo) GetIssueByUserID(ctx context.Context, userID string) ([]db.Issue, error) {
 	return r.queries.GetIssueByUserID(ctx, db.GetIssueByUserIDParams{UserID: userID, OwnerID: userID})
 }
+
+func (r *issueRepo) CreateIssueEventTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueEventParams) error {
+	return r.queries.WithTx(tx).CreateIssueEvent(ctx, data)
+}
+
+func (r *issueRepo) ListIssueEvents(ctx context.Context, issueID string, limit, offset int64) ([]db.IssueEvent, error) {
+	return r.queries.ListIssueEvents(ctx, db.ListIssueEventsParams{
+		IssueID: issueID,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+func (r *issueRepo) CountIssueEvents(ctx context.Context, issueID string) (int64, error) {
+	return r.queries.CountIssueEvents(ctx, issueID)
+}
+
+func (r *issueRepo) GetTeamIDByViewID(ctx context.Context, viewID string) (string, error) {
+	return r.queries.GetTeamIDByViewID(ctx, viewID)
+}
+
+func (r *issueRepo) GetIssueBoardRankTx(ctx context.Context, tx *sql.Tx, data db.GetIssueBoardRankParams) (db.GetIssueBoardRankRow, error) {
+	return r.queries.WithTx(tx).GetIssueBoardRank(ctx, data)
+}
+
+func (r *issueRepo) GetLastIssueBoardRankTx(ctx context.Context, tx *sql.Tx, data db.GetLastIssueBoardRankParams) (string, error) {
+	return r.queries.WithTx(tx).GetLastIssueBoardRank(ctx, data)
+}
+
+// MoveIssueTx sets the status of the issue and its rank on the board of the
+// view, or on the team's board when viewID is empty.
+func (r *issueRepo) MoveIssueTx(ctx context.Context, tx *sql.Tx, viewID, issueID, status, rank string) error {
+	q := r.queries.WithTx(tx)
+	if viewID == "" {
+		return q.UpdateIssueBoardPosition(ctx, db.UpdateIssueBoardPositionParams{Status: status, Rank: rank, ID: issueID})
+	}
+	if err := q.UpdateIssueStatus(ctx, db.UpdateIssueStatusParams{Status: status, ID: issueID}); err != nil {
+		return err
+	}
+	return q.SetViewIssueRank(ctx, db.SetViewIssueRankParams{ViewID: viewID, IssueID: issueID, Rank: rank})
+}
+
+// NextIssueNumber takes the next number of the team's sequence and returns it
+// with the identifier it makes, such as ENG-142. A number is never handed out
+// twice, even when the issue it was taken for is not created.
+func (r *issueRepo) NextIssueNumber(ctx context.Context, teamID string) (int64, string, error) {
+	number, err := r.queries.NextIssueNumber(ctx, teamID)
+	if err != nil {
+		return 0, "", err
+	}
+	key, err := r.queries.GetTeamKey(ctx, teamID)
+	if err != nil {
+		return 0, "", err
+	}
+	return number, fmt.Sprintf("%s-%d", key, number), nil
+}
+
+// ListIssueIDsByIdentifier returns the issues with the identifier in the
+// workspaces the user belongs to. Keys are unique within a workspace, so there
+// is more than one only when the user is in several workspaces using the key.
+// When no issue has the identifier now, the issues that had it before their
+// team's key changed are returned instead.
+func (r *issueRepo) ListIssueIDsByIdentifier(ctx context.Context, identifier, userID string) ([]string, error) {
+	ids, err := r.queries.ListIssueIDsByIdentifier(ctx, db.ListIssueIDsByIdentifierParams{Identifier: identifier, UserID: userID})
+	if err != nil || len(ids) > 0 {
+		return ids, err
+	}
+	return r.queries.ListIssueIDsByIdentifierAlias(ctx, db.ListIssueIDsByIdentifierAliasParams{Identifier: identifier, UserID: userID})
+}
+
+func (r *issueRepo) ListChildIssues(ctx context.Context, parentID string) ([]db.Issue, error) {
+	return r.queries.ListChildIssues(ctx, sql.NullString{String: parentID, Valid: true})
+}
+
+// ListIssueDescendants returns the sub-issues of the issue, their sub-issues
+// and so on.
+func (r *issueRepo) ListIssueDescendants(ctx context.Context, issueID string) ([]db.Issue, error) {
+	return r.queries.ListIssueDescendants(ctx, sql.NullString{String: issueID, Valid: true})
+}
+
+// ListIssueAncestorIDs returns the parent of the issue, its parent and so on up
+// to the top of the hierarchy.
+func (r *issueRepo) ListIssueAncestorIDs(ctx context.Context, issueID string) ([]string, error) {
+	return r.queries.ListIssueAncestorIDs(ctx, issueID)
+}
+
+// GetSubIssueProgress counts the sub-issues of the issue and how many of them
+// are completed. Cancelled sub-issues are left out of both.
+func (r *issueRepo) GetSubIssueProgress(ctx context.Context, parentID string) (db.GetSubIssueProgressRow, error) {
+	return r.queries.GetSubIssueProgress(ctx, sql.NullString{String: parentID, Valid: true})
+}
+
+func (r *issueRepo) SetIssueStatusTx(ctx context.Context, tx *sql.Tx, issueID, status string) error {
+	return r.queries.WithTx(tx).UpdateIssueStatus(ctx, db.UpdateIssueStatusParams{Status: status, ID: issueID})
+}
+
+func (r *issueRepo) DeleteIssuesTx(ctx context.Context, tx *sql.Tx, ids []string) error {
+	q := r.queries.WithTx(tx)
+	for _, id := range ids {
+		if err := q.DeleteIssue(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
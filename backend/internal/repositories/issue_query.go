@@ -0,0 +1,303 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/sqlbuilder"
+)
+
+var ErrInvalidIssueFilter = errors.New("invalid issue filter")
+
+const defaultIssueLimit = 50
+
+const issueColumns = "i.id, i.title, i.content, i.priority, i.status, i.project_id, i.team_id, i.start_date, i.end_date, i.owner_id, i.rank, i.number, i.identifier, i.parent_id, i.cycle_id, i.cycle_added_at, i.milestone_id"
+
+// issueSortKeys are the sort fields accepted by ListIssues. Every key is text
+// and never NULL, so the values of the last row can be stored in a cursor.
+var issueSortKeys = map[string]string{
+	"title":      "i.title COLLATE NOCASE",
+	"status":     "COALESCE((SELECT printf('%04d', ts.position) FROM team_statuses ts WHERE ts.team_id = i.team_id AND ts.key = i.status), '9999')",
+	"priority":   "CASE i.priority WHEN 'low' THEN '1' WHEN 'medium' THEN '2' WHEN 'high' THEN '3' ELSE '0' END",
+	"start_date": "COALESCE(" + sqlbuilder.Time("i.start_date") + ", '')",
+	"end_date":   "COALESCE(" + sqlbuilder.Time("i.end_date") + ", '')",
+	"rank":       "i.rank", // ลำดับบน board ของทีม เช่น sort=status,rank
+}
+
+var defaultIssueSort = []models.IssueSort{{Field: "start_date", Desc: true}}
+
+type issueCursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	ID     string   `json:"id"`
+}
+
+func sortSignature(sorts []models.IssueSort) string {
+	parts := make([]string, len(sorts))
+	for i, s := range sorts {
+		if s.Desc {
+			parts[i] = "-" + s.Field
+		} else {
+			parts[i] = s.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func encodeIssueCursor(c issueCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeIssueCursor(s string) (issueCursor, error) {
+	var c issueCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
+
+// visibleTeamsSQL selects the teams whose issues and projects the user may
+// view: the teams they belong to and every team of a workspace they own or
+// administer. It binds the user ID twice.
+const visibleTeamsSQL = `SELECT tm.team_id FROM team_members tm
+		JOIN teams t ON t.id = tm.team_id
+		JOIN workspace_members wm ON wm.workspace_id = t.workspace_id AND wm.user_id = tm.user_id
+		WHERE tm.user_id = ?
+		UNION
+		SELECT t.id FROM teams t
+		JOIN workspace_members wm ON wm.workspace_id = t.workspace_id
+		WHERE wm.user_id = ? AND wm.role IN ('owner', 'admin')`
+
+// issueVisibleTo matches the issues the user may view, following the same
+// rules as authz.IssueView.
+func issueVisibleTo(userID string) sqlbuilder.Expr {
+	return sqlbuilder.Raw(`i.team_id IN (`+visibleTeamsSQL+`) OR (i.owner_id = ? AND EXISTS (
+		SELECT 1 FROM teams t
+		JOIN workspace_members wm ON wm.workspace_id = t.workspace_id
+		WHERE t.id = i.team_id AND wm.user_id = ?
+	))`, userID, userID, userID, userID)
+}
+
+// projectVisibleTo matches the projects the user may view, following the same
+// rules as authz.ProjectView.
+func projectVisibleTo(userID string) sqlbuilder.Expr {
+	return sqlbuilder.Raw(`p.team_id IN (`+visibleTeamsSQL+`)`, userID, userID)
+}
+
+// issueOverdue matches the open issues whose end date is before t. An issue is
+// open until its status is in the completed or cancelled category.
+func issueOverdue(t time.Time) sqlbuilder.Expr {
+	return sqlbuilder.And(
+		sqlbuilder.Compare(sqlbuilder.Time("i.end_date"), sqlbuilder.OpLt, sqlbuilder.TimeArg(t)),
+		sqlbuilder.Raw(`COALESCE((SELECT ts.category FROM team_statuses ts WHERE ts.team_id = i.team_id AND ts.key = i.status), '') NOT IN ('completed', 'cancelled')`),
+	)
+}
+
+func issueFilterExprs(f models.IssueFilter) []sqlbuilder.Expr {
+	where := []sqlbuilder.Expr{}
+
+	if len(f.Statuses) > 0 {
+		where = append(where, sqlbuilder.In("i.status", f.Statuses))
+	}
+	if len(f.Priorities) > 0 {
+		where = append(where, sqlbuilder.In("i.priority", f.Priorities))
+	}
+	if len(f.Labels) > 0 {
+		// label รับได้ทั้ง id และชื่อ (ไม่สนตัวพิมพ์)
+		match := sqlbuilder.Or(sqlbuilder.In("l.id", f.Labels), sqlbuilder.In("l.name COLLATE NOCASE", f.Labels))
+		where = append(where, sqlbuilder.Raw(`EXISTS (SELECT 1 FROM issue_labels il
+			JOIN labels l ON l.id = il.label_id
+			WHERE il.issue_id = i.id AND (`+match.SQL+`))`, match.Args...))
+	}
+	if len(f.ProjectIDs) > 0 {
+		where = append(where, sqlbuilder.In("i.project_id", f.ProjectIDs))
+	}
+	if len(f.TeamIDs) > 0 {
+		where = append(where, sqlbuilder.In("i.team_id", f.TeamIDs))
+	}
+	if len(f.CycleIDs) > 0 {
+		where = append(where, sqlbuilder.In("i.cycle_id", f.CycleIDs))
+	}
+	if len(f.Milestones) > 0 {
+		where = append(where, sqlbuilder.In("i.milestone_id", f.Milestones))
+	}
+	if len(f.OwnerIDs) > 0 {
+		where = append(where, sqlbuilder.In("i.owner_id", f.OwnerIDs))
+	}
+
+	// assignee กับ unassigned ใช้ร่วมกันได้ เช่น "ของฉันหรือยังไม่มีคนรับ"
+	assignee := []sqlbuilder.Expr{}
+	if len(f.AssigneeIDs) > 0 {
+		in := sqlbuilder.In("ia.user_id", f.AssigneeIDs)
+		assignee = append(assignee, sqlbuilder.Raw(
+			"EXISTS (SELECT 1 FROM issue_assignees ia WHERE ia.issue_id = i.id AND "+in.SQL+")", in.Args...))
+	}
+	if f.Unassigned {
+		assignee = append(assignee, sqlbuilder.Raw("NOT EXISTS (SELECT 1 FROM issue_assignees ia WHERE ia.issue_id = i.id)"))
+	}
+	if len(assignee) > 0 {
+		where = append(where, sqlbuilder.Or(assignee...))
+	}
+
+	if f.StartFrom != nil {
+		where = append(where, sqlbuilder.Compare(sqlbuilder.Time("i.start_date"), sqlbuilder.OpGte, sqlbuilder.TimeArg(*f.StartFrom)))
+	}
+	if f.StartTo != nil {
+		where = append(where, sqlbuilder.Compare(sqlbuilder.Time("i.start_date"), sqlbuilder.OpLte, sqlbuilder.TimeArg(*f.StartTo)))
+	}
+	if f.EndFrom != nil {
+		where = append(where, sqlbuilder.Compare(sqlbuilder.Time("i.end_date"), sqlbuilder.OpGte, sqlbuilder.TimeArg(*f.EndFrom)))
+	}
+	if f.EndTo != nil {
+		where = append(where, sqlbuilder.Compare(sqlbuilder.Time("i.end_date"), sqlbuilder.OpLte, sqlbuilder.TimeArg(*f.EndTo)))
+	}
+	if f.OverdueAt != nil {
+		where = append(where, issueOverdue(*f.OverdueAt))
+	}
+
+	if text := strings.TrimSpace(f.Text); text != "" {
+		where = append(where, sqlbuilder.Or(
+			sqlbuilder.Contains("i.title", text),
+			sqlbuilder.Contains("i.content", text),
+		))
+	}
+
+	return where
+}
+
+// afterCursor matches the rows that come after the cursor in the given order,
+// i.e. (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... with id as the last key.
+func afterCursor(keys []string, sorts []models.IssueSort, c issueCursor) sqlbuilder.Expr {
+	keys = append(append([]string{}, keys...), "i.id")
+	values := append(append([]string{}, c.Values...), c.ID)
+	descs := make([]bool, 0, len(keys))
+	for _, s := range sorts {
+		descs = append(descs, s.Desc)
+	}
+	descs = append(descs, false)
+
+	alternatives := []sqlbuilder.Expr{}
+	for j := range keys {
+		terms := []sqlbuilder.Expr{}
+		for m := 0; m < j; m++ {
+			terms = append(terms, sqlbuilder.Eq(keys[m], values[m]))
+		}
+		op := sqlbuilder.OpGt
+		if descs[j] {
+			op = sqlbuilder.OpLt
+		}
+		terms = append(terms, sqlbuilder.Compare(keys[j], op, values[j]))
+		alternatives = append(alternatives, sqlbuilder.And(terms...))
+	}
+	return sqlbuilder.Or(alternatives...)
+}
+
+func buildIssueListQuery(userID string, f models.IssueFilter) (string, []interface{}, []models.IssueSort, error) {
+	sorts := f.Sort
+	if len(sorts) == 0 {
+		sorts = defaultIssueSort
+	}
+
+	keys := make([]string, len(sorts))
+	seen := map[string]bool{}
+	for i, s := range sorts {
+		key, ok := issueSortKeys[s.Field]
+		if !ok {
+			return "", nil, nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidIssueFilter, s.Field)
+		}
+		if seen[s.Field] {
+			return "", nil, nil, fmt.Errorf("%w: duplicate sort field %q", ErrInvalidIssueFilter, s.Field)
+		}
+		seen[s.Field] = true
+		keys[i] = key
+	}
+
+	where := append([]sqlbuilder.Expr{issueVisibleTo(userID)}, issueFilterExprs(f)...)
+
+	if f.Cursor != "" {
+		c, err := decodeIssueCursor(f.Cursor)
+		if err != nil || c.Sort != sortSignature(sorts) || len(c.Values) != len(keys) || c.ID == "" {
+			return "", nil, nil, fmt.Errorf("%w: cursor does not match this query", ErrInvalidIssueFilter)
+		}
+		where = append(where, afterCursor(keys, sorts, c))
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultIssueLimit
+	}
+
+	orderBy := make([]sqlbuilder.Order, 0, len(keys)+1)
+	for i, key := range keys {
+		orderBy = append(orderBy, sqlbuilder.Order{Expr: key, Desc: sorts[i].Desc})
+	}
+	orderBy = append(orderBy, sqlbuilder.Order{Expr: "i.id"})
+
+	query, args := sqlbuilder.Select{
+		Columns: append([]string{issueColumns}, keys...),
+		From:    "issues i",
+		Where:   where,
+		OrderBy: orderBy,
+		// อ่านเกินหนึ่งแถวเพื่อดูว่ายังมีหน้าถัดไปหรือไม่
+		Limit: limit + 1,
+	}.Build()
+
+	return query, args, sorts, nil
+}
+
+// ListIssues returns one page of the issues visible to the user that match
+// the filter, and the cursor of the next page or "" on the last page.
+func (r *issueRepo) ListIssues(ctx context.Context, userID string, f models.IssueFilter) ([]db.Issue, string, error) {
+	query, args, sorts, err := buildIssueListQuery(userID, f)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := r.rawDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultIssueLimit
+	}
+
+	issues := []db.Issue{}
+	var last []string
+	for rows.Next() {
+		var i db.Issue
+		values := make([]string, len(sorts))
+		dest := []interface{}{
+			&i.ID, &i.Title, &i.Content, &i.Priority, &i.Status, &i.ProjectID,
+			&i.TeamID, &i.StartDate, &i.EndDate, &i.OwnerID, &i.Rank, &i.Number, &i.Identifier, &i.ParentID, &i.CycleID, &i.CycleAddedAt, &i.MilestoneID,
+		}
+		for k := range values {
+			dest = append(dest, &values[k])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, "", err
+		}
+		if len(issues) == limit {
+			return issues, encodeIssueCursor(issueCursor{
+				Sort:   sortSignature(sorts),
+				Values: last,
+				ID:     issues[len(issues)-1].ID,
+			}), rows.Err()
+		}
+		issues = append(issues, i)
+		last = values
+	}
+	return issues, "", rows.Err()
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type IssueTemplateRepository interface {
+	CreateIssueTemplateTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueTemplateParams) error
+	GetIssueTemplateByID(ctx context.Context, id string) (db.IssueTemplate, error)
+	ListTeamIssueTemplates(ctx context.Context, teamID string) ([]db.IssueTemplate, error)
+	UpdateIssueTemplateTx(ctx context.Context, tx *sql.Tx, data db.UpdateIssueTemplateParams) error
+	DeleteIssueTemplate(ctx context.Context, id string) error
+	SetIssueTemplateLabelsTx(ctx context.Context, tx *sql.Tx, templateID string, labelIDs []string) error
+	SetIssueTemplateAssigneesTx(ctx context.Context, tx *sql.Tx, templateID string, userIDs []string) error
+	ListIssueTemplateLabelIDs(ctx context.Context, templateID string) ([]string, error)
+	ListIssueTemplateAssigneeIDs(ctx context.Context, templateID string) ([]string, error)
+}
+
+type issueTemplateRepo struct {
+	queries *db.Queries
+}
+
+func NewIssueTemplateRepository(dbConn *sql.DB) IssueTemplateRepository {
+	return &issueTemplateRepo{queries: db.New(dbConn)}
+}
+
+func (r *issueTemplateRepo) CreateIssueTemplateTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueTemplateParams) error {
+	return r.queries.WithTx(tx).CreateIssueTemplate(ctx, data)
+}
+
+func (r *issueTemplateRepo) GetIssueTemplateByID(ctx context.Context, id string) (db.IssueTemplate, error) {
+	return r.queries.GetIssueTemplateByID(ctx, id)
+}
+
+// ListTeamIssueTemplates returns the templates of the team by name.
+func (r *issueTemplateRepo) ListTeamIssueTemplates(ctx context.Context, teamID string) ([]db.IssueTemplate, error) {
+	return r.queries.ListTeamIssueTemplates(ctx, teamID)
+}
+
+func (r *issueTemplateRepo) UpdateIssueTemplateTx(ctx context.Context, tx *sql.Tx, data db.UpdateIssueTemplateParams) error {
+	return r.queries.WithTx(tx).UpdateIssueTemplate(ctx, data)
+}
+
+func (r *issueTemplateRepo) DeleteIssueTemplate(ctx context.Context, id string) error {
+	return r.queries.DeleteIssueTemplate(ctx, id)
+}
+
+// SetIssueTemplateLabelsTx replaces the labels of the template.
+func (r *issueTemplateRepo) SetIssueTemplateLabelsTx(ctx context.Context, tx *sql.Tx, templateID string, labelIDs []string) error {
+	q := r.queries.WithTx(tx)
+	if err := q.ClearIssueTemplateLabels(ctx, templateID); err != nil {
+		return err
+	}
+	for _, id := range labelIDs {
+		if err := q.AddIssueTemplateLabel(ctx, db.AddIssueTemplateLabelParams{TemplateID: templateID, LabelID: id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetIssueTemplateAssigneesTx replaces the assignees of the template.
+func (r *issueTemplateRepo) SetIssueTemplateAssigneesTx(ctx context.Context, tx *sql.Tx, templateID string, userIDs []string) error {
+	q := r.queries.WithTx(tx)
+	if err := q.ClearIssueTemplateAssignees(ctx, templateID); err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		if err := q.AddIssueTemplateAssignee(ctx, db.AddIssueTemplateAssigneeParams{TemplateID: templateID, UserID: id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *issueTemplateRepo) ListIssueTemplateLabelIDs(ctx context.Context, templateID string) ([]string, error) {
+	return r.queries.ListIssueTemplateLabelIDs(ctx, templateID)
+}
+
+func (r *issueTemplateRepo) ListIssueTemplateAssigneeIDs(ctx context.Context, templateID string) ([]string, error) {
+	return r.queries.ListIssueTemplateAssigneeIDs(ctx, templateID)
+}
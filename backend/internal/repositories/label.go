@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type LabelRepository interface {
+	CreateLabel(ctx context.Context, data db.CreateLabelParams) error
+	GetLabelByID(ctx context.Context, id string) (db.Label, error)
+	ListWorkspaceLabels(ctx context.Context, workspaceID string) ([]db.Label, error)
+	ListTeamLabels(ctx context.Context, teamID string) ([]db.Label, error)
+	UpdateLabel(ctx context.Context, data db.UpdateLabelParams) error
+	DeleteLabel(ctx context.Context, id string) error
+	MergeLabel(ctx context.Context, sourceID, targetID string) error
+	ListLabelsByIssueID(ctx context.Context, issueID string) ([]db.Label, error)
+	ListLabelsByProjectID(ctx context.Context, projectID string) ([]db.Label, error)
+	AddLabelToIssue(ctx context.Context, issueID, labelID string) error
+	RemoveLabelFromIssue(ctx context.Context, issueID, labelID string) error
+	AddLabelToIssueTx(ctx context.Context, tx *sql.Tx, issueID, labelID string) error
+	RemoveLabelFromIssueTx(ctx context.Context, tx *sql.Tx, issueID, labelID string) error
+	AddLabelToProject(ctx context.Context, projectID, labelID string) error
+	RemoveLabelFromProject(ctx context.Context, projectID, labelID string) error
+}
+
+type labelRepo struct {
+	queries *db.Queries
+	rawDb   *sql.DB
+}
+
+func NewLabelRepository(dbConn *sql.DB) LabelRepository {
+	return &labelRepo{
+		queries: db.New(dbConn),
+		rawDb:   dbConn,
+	}
+}
+
+func (r *labelRepo) CreateLabel(ctx context.Context, data db.CreateLabelParams) error {
+	return r.queries.CreateLabel(ctx, data)
+}
+
+func (r *labelRepo) GetLabelByID(ctx context.Context, id string) (db.Label, error) {
+	return r.queries.GetLabelByID(ctx, id)
+}
+
+// ListWorkspaceLabels returns the labels shared by every team of the workspace.
+func (r *labelRepo) ListWorkspaceLabels(ctx context.Context, workspaceID string) ([]db.Label, error) {
+	return r.queries.ListWorkspaceLabels(ctx, workspaceID)
+}
+
+// ListTeamLabels returns the labels the issues of the team can use: the
+// team's own labels and those of its workspace.
+func (r *labelRepo) ListTeamLabels(ctx context.Context, teamID string) ([]db.Label, error) {
+	return r.queries.ListTeamLabels(ctx, teamID)
+}
+
+func (r *labelRepo) UpdateLabel(ctx context.Context, data db.UpdateLabelParams) error {
+	return r.queries.UpdateLabel(ctx, data)
+}
+
+func (r *labelRepo) DeleteLabel(ctx context.Context, id string) error {
+	return r.queries.DeleteLabel(ctx, id)
+}
+
+// MergeLabel gives the issues and projects labelled with source the target
+// label instead, then deletes source.
+func (r *labelRepo) MergeLabel(ctx context.Context, sourceID, targetID string) error {
+	tx, err := r.rawDb.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := r.queries.WithTx(tx)
+	if err := q.MoveIssueLabels(ctx, db.MoveIssueLabelsParams{TargetID: targetID, SourceID: sourceID}); err != nil {
+		return err
+	}
+	if err := q.MoveProjectLabels(ctx, db.MoveProjectLabelsParams{TargetID: targetID, SourceID: sourceID}); err != nil {
+		return err
+	}
+	if err := q.DeleteLabel(ctx, sourceID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *labelRepo) ListLabelsByIssueID(ctx context.Context, issueID string) ([]db.Label, error) {
+	return r.queries.ListLabelsByIssueID(ctx, issueID)
+}
+
+func (r *labelRepo) ListLabelsByProjectID(ctx context.Context, projectID string) ([]db.Label, error) {
+	return r.queries.ListLabelsByProjectID(ctx, projectID)
+}
+
+func (r *labelRepo) AddLabelToIssue(ctx context.Context, issueID, labelID string) error {
+	return r.queries.AddLabelToIssue(ctx, db.AddLabelToIssueParams{IssueID: issueID, LabelID: labelID})
+}
+
+func (r *labelRepo) RemoveLabelFromIssue(ctx context.Context, issueID, labelID string) error {
+	return r.queries.RemoveLabelFromIssue(ctx, db.RemoveLabelFromIssueParams{IssueID: issueID, LabelID: labelID})
+}
+
+func (r *labelRepo) AddLabelToIssueTx(ctx context.Context, tx *sql.Tx, issueID, labelID string) error {
+	return r.queries.WithTx(tx).AddLabelToIssue(ctx, db.AddLabelToIssueParams{IssueID: issueID, LabelID: labelID})
+}
+
+func (r *labelRepo) RemoveLabelFromIssueTx(ctx context.Context, tx *sql.Tx, issueID, labelID string) error {
+	return r.queries.WithTx(tx).RemoveLabelFromIssue(ctx, db.RemoveLabelFromIssueParams{IssueID: issueID, LabelID: labelID})
+}
+
+func (r *labelRepo) AddLabelToProject(ctx context.Context, projectID, labelID string) error {
+	return r.queries.AddLabelToProject(ctx, db.AddLabelToProjectParams{ProjectID: projectID, LabelID: labelID})
+}
+
+func (r *labelRepo) RemoveLabelFromProject(ctx context.Context, projectID, labelID string) error {
+	return r.queries.RemoveLabelFromProject(ctx, db.RemoveLabelFromProjectParams{ProjectID: projectID, LabelID: labelID})
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/sqlbuilder"
+)
+
+type NotificationRepository interface {
+	ListDueIssueRecipients(ctx context.Context, until time.Time) ([]models.DueIssueRecipient, error)
+	CreateNotification(ctx context.Context, data db.CreateNotificationParams) (bool, error)
+	ListUserNotifications(ctx context.Context, userID string, limit, offset int64) ([]db.ListUserNotificationsRow, error)
+	CountUnreadNotifications(ctx context.Context, userID string) (int64, error)
+	MarkNotificationRead(ctx context.Context, id, userID string, at time.Time) (bool, error)
+	MarkAllNotificationsRead(ctx context.Context, userID string, at time.Time) error
+}
+
+type notificationRepo struct {
+	queries *db.Queries
+	rawDb   *sql.DB
+}
+
+func NewNotificationRepository(dbConn *sql.DB) NotificationRepository {
+	return &notificationRepo{
+		queries: db.New(dbConn),
+		rawDb:   dbConn,
+	}
+}
+
+// ListDueIssueRecipients returns the open issues whose end date is before
+// until, once for the owner and once for each assignee. Pairs already told
+// that the issue is overdue for its current end date are left out, so old
+// issues are not read again on every run.
+func (r *notificationRepo) ListDueIssueRecipients(ctx context.Context, until time.Time) ([]models.DueIssueRecipient, error) {
+	query, args := sqlbuilder.Select{
+		Columns: []string{"i.id, i.identifier, i.title, i.team_id, i.end_date, r.user_id"},
+		From:    "issues i",
+		Joins: []sqlbuilder.Expr{sqlbuilder.Raw(`JOIN (SELECT id AS issue_id, owner_id AS user_id FROM issues
+			UNION SELECT issue_id, user_id FROM issue_assignees) r ON r.issue_id = i.id`)},
+		Where: []sqlbuilder.Expr{
+			issueOverdue(until),
+			sqlbuilder.Raw(`NOT EXISTS (SELECT 1 FROM notifications n
+				WHERE n.user_id = r.user_id AND n.issue_id = i.id AND n.type = 'overdue'
+				AND ` + sqlbuilder.Time("n.due_at") + ` = ` + sqlbuilder.Time("i.end_date") + `)`),
+		},
+		OrderBy: []sqlbuilder.Order{{Expr: sqlbuilder.Time("i.end_date")}, {Expr: "i.id"}, {Expr: "r.user_id"}},
+	}.Build()
+
+	rows, err := r.rawDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := []models.DueIssueRecipient{}
+	for rows.Next() {
+		var d models.DueIssueRecipient
+		if err := rows.Scan(&d.IssueID, &d.Identifier, &d.Title, &d.TeamID, &d.EndDate, &d.UserID); err != nil {
+			return nil, err
+		}
+		due = append(due, d)
+	}
+	return due, rows.Err()
+}
+
+// CreateNotification reports whether the notification was created. It is not
+// when the user was already sent the same reminder.
+func (r *notificationRepo) CreateNotification(ctx context.Context, data db.CreateNotificationParams) (bool, error) {
+	n, err := r.queries.CreateNotification(ctx, data)
+	return n > 0, err
+}
+
+func (r *notificationRepo) ListUserNotifications(ctx context.Context, userID string, limit, offset int64) ([]db.ListUserNotificationsRow, error) {
+	return r.queries.ListUserNotifications(ctx, db.ListUserNotificationsParams{UserID: userID, Limit: limit, Offset: offset})
+}
+
+func (r *notificationRepo) CountUnreadNotifications(ctx context.Context, userID string) (int64, error) {
+	return r.queries.CountUnreadNotifications(ctx, userID)
+}
+
+// MarkNotificationRead reports whether the user has the notification. One
+// that was already read keeps its first read time.
+func (r *notificationRepo) MarkNotificationRead(ctx context.Context, id, userID string, at time.Time) (bool, error) {
+	n, err := r.queries.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ReadAt: sql.NullTime{Time: at, Valid: true},
+		ID:     id,
+		UserID: userID,
+	})
+	return n > 0, err
+}
+
+func (r *notificationRepo) MarkAllNotificationsRead(ctx context.Context, userID string, at time.Time) error {
+	return r.queries.MarkAllNotificationsRead(ctx, db.MarkAllNotificationsReadParams{
+		ReadAt: sql.NullTime{Time: at, Valid: true},
+		UserID: userID,
+	})
+}
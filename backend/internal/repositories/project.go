@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/nack098/nakumanager/internal/db"
 	models "github.com/nack098/nakumanager/internal/models"
@@ -11,12 +12,26 @@ type ProjectRepository interface {
 	CreateProject(ctx context.Context, data models.CreateProject) error
 	DeleteProject(ctx context.Context, id string) error
 	GetProjectByID(ctx context.Context, id string) (db.Project, error)
-	GetProjectsByUserID(ctx context.Context, userID string) ([]db.Project, error)
+	GetProjectsByUserID(ctx context.Context, userID string) ([]db.GetProjectsByUserIDRow, error)
 	IsProjectExists(ctx context.Context, projectID string) (bool, error)
+	IsProjectMember(ctx context.Context, projectID, userID string) (bool, error)
 	GetOwnerByProjectID(ctx context.Context, projectID string) (string, error)
 	GetLeaderByProjectID(ctx context.Context, projectID string) (string, error)
 	AddMemberToProject(ctx context.Context, projectID, userID string) error
 	RemoveMemberFromProject(ctx context.Context, projectID, userID string) error
+	CreateMilestoneTx(ctx context.Context, tx *sql.Tx, data db.CreateProjectMilestoneParams) error
+	GetMilestoneByID(ctx context.Context, id string) (db.ProjectMilestone, error)
+	ListMilestones(ctx context.Context, projectID string) ([]db.ProjectMilestone, error)
+	UpdateMilestoneTx(ctx context.Context, tx *sql.Tx, data db.UpdateProjectMilestoneParams) error
+	SetMilestoneOrderTx(ctx context.Context, tx *sql.Tx, ids []string) error
+	DeleteMilestone(ctx context.Context, id string) error
+	ListMilestoneProgress(ctx context.Context, projectID string) ([]db.ListMilestoneProgressRow, error)
+	GetProjectProgress(ctx context.Context, projectID string) (db.GetProjectProgressRow, error)
+	CreateProjectUpdate(ctx context.Context, data db.CreateProjectUpdateParams) error
+	GetProjectUpdateByID(ctx context.Context, id string) (db.ProjectUpdate, error)
+	ListProjectUpdates(ctx context.Context, projectID string) ([]db.ProjectUpdate, error)
+	EditProjectUpdate(ctx context.Context, data db.EditProjectUpdateParams) error
+	DeleteProjectUpdate(ctx context.Context, id string) error
 }
 
 type projectRepo struct {
@@ -39,7 +54,6 @@ func (r *projectRepo) CreateProject(ctx context.Context, data models.CreateProje
 		Priority:    data.Priority,
 		StartDate:   data.StartDate,
 		EndDate:     data.EndDate,
-		Label:       data.Label,
 		CreatedBy:   data.CreatedBy,
 	})
 }
@@ -52,7 +66,9 @@ func (r *projectRepo) GetProjectByID(ctx context.Context, id string) (db.Project
 	return r.queries.GetProjectByID(ctx, id)
 }
 
-func (r *projectRepo) GetProjectsByUserID(ctx context.Context, userID string) ([]db.Project, error) {
+// GetProjectsByUserID returns the projects the user is a member or creator of,
+// with the health of the latest update posted on each.
+func (r *projectRepo) GetProjectsByUserID(ctx context.Context, userID string) ([]db.GetProjectsByUserIDRow, error) {
 	return r.queries.GetProjectsByUserID(ctx, db.GetProjectsByUserIDParams{UserID: userID, CreatedBy: userID})
 }
 
@@ -64,6 +80,17 @@ func (r *projectRepo) IsProjectExists(ctx context.Context, projectID string) (bo
 	return exists > 0, nil
 }
 
+func (r *projectRepo) IsProjectMember(ctx context.Context, projectID, userID string) (bool, error) {
+	count, err := r.queries.IsProjectMember(ctx, db.IsProjectMemberParams{
+		ProjectID: projectID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (r *projectRepo) GetOwnerByProjectID(ctx context.Context, projectID string) (string, error) {
 	return r.queries.GetOwnerByProjectID(ctx, projectID)
 }
@@ -92,3 +119,68 @@ func (r *projectRepo) RemoveMemberFromProject(ctx context.Context, projectID, us
 		UserID:    userID,
 	})
 }
+
+func (r *projectRepo) CreateMilestoneTx(ctx context.Context, tx *sql.Tx, data db.CreateProjectMilestoneParams) error {
+	return r.queries.WithTx(tx).CreateProjectMilestone(ctx, data)
+}
+
+func (r *projectRepo) GetMilestoneByID(ctx context.Context, id string) (db.ProjectMilestone, error) {
+	return r.queries.GetProjectMilestone(ctx, id)
+}
+
+// ListMilestones returns the milestones of the project in order.
+func (r *projectRepo) ListMilestones(ctx context.Context, projectID string) ([]db.ProjectMilestone, error) {
+	return r.queries.ListProjectMilestones(ctx, projectID)
+}
+
+func (r *projectRepo) UpdateMilestoneTx(ctx context.Context, tx *sql.Tx, data db.UpdateProjectMilestoneParams) error {
+	return r.queries.WithTx(tx).UpdateProjectMilestone(ctx, data)
+}
+
+// SetMilestoneOrderTx numbers the milestones in the order of ids.
+func (r *projectRepo) SetMilestoneOrderTx(ctx context.Context, tx *sql.Tx, ids []string) error {
+	q := r.queries.WithTx(tx)
+	for i, id := range ids {
+		if err := q.SetProjectMilestonePosition(ctx, db.SetProjectMilestonePositionParams{Position: int64(i), ID: id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMilestone deletes the milestone. Its issues stay in the project.
+func (r *projectRepo) DeleteMilestone(ctx context.Context, id string) error {
+	return r.queries.DeleteProjectMilestone(ctx, id)
+}
+
+// ListMilestoneProgress counts the issues of each milestone of the project
+// that has any. Cancelled issues are not counted.
+func (r *projectRepo) ListMilestoneProgress(ctx context.Context, projectID string) ([]db.ListMilestoneProgressRow, error) {
+	return r.queries.ListMilestoneProgress(ctx, sql.NullString{String: projectID, Valid: true})
+}
+
+// GetProjectProgress counts the issues of the project, cancelled ones aside.
+func (r *projectRepo) GetProjectProgress(ctx context.Context, projectID string) (db.GetProjectProgressRow, error) {
+	return r.queries.GetProjectProgress(ctx, sql.NullString{String: projectID, Valid: true})
+}
+
+func (r *projectRepo) CreateProjectUpdate(ctx context.Context, data db.CreateProjectUpdateParams) error {
+	return r.queries.CreateProjectUpdate(ctx, data)
+}
+
+func (r *projectRepo) GetProjectUpdateByID(ctx context.Context, id string) (db.ProjectUpdate, error) {
+	return r.queries.GetProjectUpdate(ctx, id)
+}
+
+// ListProjectUpdates returns the updates posted on the project, newest first.
+func (r *projectRepo) ListProjectUpdates(ctx context.Context, projectID string) ([]db.ProjectUpdate, error) {
+	return r.queries.ListProjectUpdates(ctx, projectID)
+}
+
+func (r *projectRepo) EditProjectUpdate(ctx context.Context, data db.EditProjectUpdateParams) error {
+	return r.queries.EditProjectUpdate(ctx, data)
+}
+
+func (r *projectRepo) DeleteProjectUpdate(ctx context.Context, id string) error {
+	return r.queries.DeleteProjectUpdate(ctx, id)
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type RecurringIssueRepository interface {
+	CreateRecurringIssue(ctx context.Context, data db.CreateRecurringIssueParams) error
+	GetRecurringIssueByID(ctx context.Context, id string) (db.RecurringIssue, error)
+	ListTeamRecurringIssues(ctx context.Context, teamID string) ([]db.RecurringIssue, error)
+	UpdateRecurringIssue(ctx context.Context, data db.UpdateRecurringIssueParams) error
+	DeleteRecurringIssue(ctx context.Context, id string) error
+	ListDueRecurringIssues(ctx context.Context, now time.Time) ([]db.RecurringIssue, error)
+	AdvanceRecurringIssue(ctx context.Context, data db.AdvanceRecurringIssueParams) error
+	ClaimRecurringIssueRun(ctx context.Context, recurringID string, scheduledFor time.Time) (bool, error)
+	FinishRecurringIssueRun(ctx context.Context, data db.FinishRecurringIssueRunParams) error
+	ListRecurringIssueRuns(ctx context.Context, recurringID string, limit int64) ([]db.RecurringIssueRun, error)
+}
+
+type recurringIssueRepo struct {
+	queries *db.Queries
+}
+
+func NewRecurringIssueRepository(dbConn *sql.DB) RecurringIssueRepository {
+	return &recurringIssueRepo{queries: db.New(dbConn)}
+}
+
+func (r *recurringIssueRepo) CreateRecurringIssue(ctx context.Context, data db.CreateRecurringIssueParams) error {
+	return r.queries.CreateRecurringIssue(ctx, data)
+}
+
+func (r *recurringIssueRepo) GetRecurringIssueByID(ctx context.Context, id string) (db.RecurringIssue, error) {
+	return r.queries.GetRecurringIssueByID(ctx, id)
+}
+
+func (r *recurringIssueRepo) ListTeamRecurringIssues(ctx context.Context, teamID string) ([]db.RecurringIssue, error) {
+	return r.queries.ListTeamRecurringIssues(ctx, teamID)
+}
+
+func (r *recurringIssueRepo) UpdateRecurringIssue(ctx context.Context, data db.UpdateRecurringIssueParams) error {
+	return r.queries.UpdateRecurringIssue(ctx, data)
+}
+
+func (r *recurringIssueRepo) DeleteRecurringIssue(ctx context.Context, id string) error {
+	return r.queries.DeleteRecurringIssue(ctx, id)
+}
+
+// ListDueRecurringIssues returns the definitions that are not paused and were
+// due to run at or before now, the most overdue first.
+func (r *recurringIssueRepo) ListDueRecurringIssues(ctx context.Context, now time.Time) ([]db.RecurringIssue, error) {
+	return r.queries.ListDueRecurringIssues(ctx, sql.NullTime{Time: now.UTC(), Valid: true})
+}
+
+func (r *recurringIssueRepo) AdvanceRecurringIssue(ctx context.Context, data db.AdvanceRecurringIssueParams) error {
+	return r.queries.AdvanceRecurringIssue(ctx, data)
+}
+
+// ClaimRecurringIssueRun records that the occurrence is being created. It
+// returns false when it already was, by this or another server.
+func (r *recurringIssueRepo) ClaimRecurringIssueRun(ctx context.Context, recurringID string, scheduledFor time.Time) (bool, error) {
+	n, err := r.queries.ClaimRecurringIssueRun(ctx, db.ClaimRecurringIssueRunParams{
+		RecurringID:  recurringID,
+		ScheduledFor: scheduledFor.UTC(),
+		CreatedAt:    time.Now().UTC(),
+	})
+	return n > 0, err
+}
+
+func (r *recurringIssueRepo) FinishRecurringIssueRun(ctx context.Context, data db.FinishRecurringIssueRunParams) error {
+	return r.queries.FinishRecurringIssueRun(ctx, data)
+}
+
+// ListRecurringIssueRuns returns the latest occurrences of the definition.
+func (r *recurringIssueRepo) ListRecurringIssueRuns(ctx context.Context, recurringID string, limit int64) ([]db.RecurringIssueRun, error) {
+	return r.queries.ListRecurringIssueRuns(ctx, db.ListRecurringIssueRunsParams{RecurringID: recurringID, Limit: limit})
+}
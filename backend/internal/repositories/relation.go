@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type RelationRepository interface {
+	CreateIssueRelation(ctx context.Context, data db.CreateIssueRelationParams) error
+	CreateIssueRelationTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueRelationParams) error
+	GetIssueRelation(ctx context.Context, id string) (db.IssueRelation, error)
+	DeleteIssueRelation(ctx context.Context, id string) error
+	ListIssueRelations(ctx context.Context, issueID string) ([]db.ListIssueRelationsRow, error)
+	ListOpenBlockedIssues(ctx context.Context, issueID string) ([]db.ListOpenBlockedIssuesRow, error)
+}
+
+type relationRepo struct {
+	queries *db.Queries
+}
+
+func NewRelationRepository(dbConn *sql.DB) RelationRepository {
+	return &relationRepo{queries: db.New(dbConn)}
+}
+
+func (r *relationRepo) CreateIssueRelation(ctx context.Context, data db.CreateIssueRelationParams) error {
+	return r.queries.CreateIssueRelation(ctx, data)
+}
+
+func (r *relationRepo) CreateIssueRelationTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueRelationParams) error {
+	return r.queries.WithTx(tx).CreateIssueRelation(ctx, data)
+}
+
+func (r *relationRepo) GetIssueRelation(ctx context.Context, id string) (db.IssueRelation, error) {
+	return r.queries.GetIssueRelation(ctx, id)
+}
+
+func (r *relationRepo) DeleteIssueRelation(ctx context.Context, id string) error {
+	return r.queries.DeleteIssueRelation(ctx, id)
+}
+
+// ListIssueRelations returns the relations of the issue seen from its side:
+// a relation stored as blocks on the other issue comes back as blocked_by.
+func (r *relationRepo) ListIssueRelations(ctx context.Context, issueID string) ([]db.ListIssueRelationsRow, error) {
+	return r.queries.ListIssueRelations(ctx, db.ListIssueRelationsParams{IssueID: issueID, RelatedIssueID: issueID})
+}
+
+// ListOpenBlockedIssues returns the issues the issue blocks that are neither
+// completed nor cancelled.
+func (r *relationRepo) ListOpenBlockedIssues(ctx context.Context, issueID string) ([]db.ListOpenBlockedIssuesRow, error) {
+	return r.queries.ListOpenBlockedIssues(ctx, issueID)
+}
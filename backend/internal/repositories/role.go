@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type RoleRepository interface {
+	GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (string, error)
+	GetTeamRoles(ctx context.Context, teamID, userID string) (db.GetTeamMemberRolesRow, error)
+	SetWorkspaceRole(ctx context.Context, workspaceID, userID, role string) error
+	SetTeamLead(ctx context.Context, teamID, userID string) error
+}
+
+type roleRepo struct {
+	queries *db.Queries
+}
+
+func NewRoleRepository(q *db.Queries) RoleRepository {
+	return &roleRepo{queries: q}
+}
+
+func (r *roleRepo) GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (string, error) {
+	return r.queries.GetWorkspaceMemberRole(ctx, db.GetWorkspaceMemberRoleParams{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+	})
+}
+
+func (r *roleRepo) GetTeamRoles(ctx context.Context, teamID, userID string) (db.GetTeamMemberRolesRow, error) {
+	return r.queries.GetTeamMemberRoles(ctx, db.GetTeamMemberRolesParams{
+		UserID:   userID,
+		UserID_2: userID,
+		ID:       teamID,
+	})
+}
+
+func (r *roleRepo) SetWorkspaceRole(ctx context.Context, workspaceID, userID, role string) error {
+	return r.queries.SetWorkspaceMemberRole(ctx, db.SetWorkspaceMemberRoleParams{
+		Role:        role,
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+	})
+}
+
+func (r *roleRepo) SetTeamLead(ctx context.Context, teamID, userID string) error {
+	return r.queries.SetTeamLead(ctx, db.SetTeamLeadParams{
+		UserID:   userID,
+		TeamID:   teamID,
+		UserID_2: userID,
+	})
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"html"
+	"regexp"
+	"strings"
+
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/sqlbuilder"
+)
+
+type SearchRepository interface {
+	Search(ctx context.Context, userID, query string, limit, offset int) ([]models.SearchResult, error)
+}
+
+type searchRepo struct {
+	rawDb *sql.DB
+}
+
+func NewSearchRepository(dbConn *sql.DB) SearchRepository {
+	return &searchRepo{rawDb: dbConn}
+}
+
+const maxSearchTerms = 10
+
+var searchTermPattern = regexp.MustCompile(`[\pL\pN\pM_]+`)
+
+// Private use characters mark the matches in highlight() and snippet(), so the
+// text can be HTML-escaped before the markers become <mark> tags. In SQL they
+// are written as char(57344) and char(57345).
+const (
+	matchStart = "\ue000"
+	matchEnd   = "\ue001"
+)
+
+var matchMarkers = strings.NewReplacer(matchStart, "<mark>", matchEnd, "</mark>")
+
+// ftsQuery turns free text into an FTS5 query that matches documents containing
+// every word, the last one as a prefix so results show up while typing. Words
+// are quoted, so FTS5 operators in the input have no effect.
+func ftsQuery(text string) string {
+	terms := searchTermPattern.FindAllString(text, maxSearchTerms)
+	for i, term := range terms {
+		terms[i] = `"` + term + `"`
+	}
+	if len(terms) > 0 {
+		terms[len(terms)-1] += "*"
+	}
+	return strings.Join(terms, " ")
+}
+
+func highlightHTML(s string) string {
+	return matchMarkers.Replace(html.EscapeString(s))
+}
+
+// Search returns the issues and projects visible to the user that match the
+// query, best match first. Title matches weigh more than content matches.
+func (r *searchRepo) Search(ctx context.Context, userID, query string, limit, offset int) ([]models.SearchResult, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return []models.SearchResult{}, nil
+	}
+
+	issues, issueArgs := sqlbuilder.Select{
+		Columns: []string{
+			"'issue'", "i.id", "i.identifier", "t.workspace_id", "i.team_id", "i.project_id",
+			"highlight(issues_fts, 1, char(57344), char(57345))",
+			"snippet(issues_fts, 2, char(57344), char(57345), '…', 16)",
+			"bm25(issues_fts, 0.0, 10.0, 1.0) AS rank",
+		},
+		From: "issues_fts JOIN issues i ON i.id = issues_fts.issue_id JOIN teams t ON t.id = i.team_id",
+		Where: []sqlbuilder.Expr{
+			sqlbuilder.Raw("issues_fts MATCH ?", match),
+			issueVisibleTo(userID),
+		},
+	}.Build()
+
+	projects, projectArgs := sqlbuilder.Select{
+		Columns: []string{
+			"'project'", "p.id", "''", "p.workspace_id", "p.team_id", "NULL",
+			"highlight(projects_fts, 1, char(57344), char(57345))",
+			"''",
+			"bm25(projects_fts, 0.0, 10.0)",
+		},
+		From: "projects_fts JOIN projects p ON p.id = projects_fts.project_id",
+		Where: []sqlbuilder.Expr{
+			sqlbuilder.Raw("projects_fts MATCH ?", match),
+			projectVisibleTo(userID),
+		},
+	}.Build()
+
+	args := append(issueArgs, projectArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := r.rawDb.QueryContext(ctx, issues+" UNION ALL "+projects+" ORDER BY rank, 2 LIMIT ? OFFSET ?", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	for rows.Next() {
+		var res models.SearchResult
+		var projectID sql.NullString
+		if err := rows.Scan(&res.Type, &res.ID, &res.Identifier, &res.WorkspaceID, &res.TeamID, &projectID, &res.Title, &res.Snippet, &res.Rank); err != nil {
+			return nil, err
+		}
+		if projectID.Valid {
+			res.ProjectID = &projectID.String
+		}
+		res.Title = highlightHTML(res.Title)
+		res.Snippet = highlightHTML(res.Snippet)
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
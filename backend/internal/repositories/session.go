@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, data db.CreateSessionParams) error
+	GetSessionByID(ctx context.Context, id string) (db.Session, error)
+	GetSessionByRefreshTokenHash(ctx context.Context, hash string) (db.Session, error)
+	GetSessionByPreviousTokenHash(ctx context.Context, hash string) (db.Session, error)
+	RotateSessionToken(ctx context.Context, data db.RotateSessionTokenParams) (int64, error)
+	RevokeSession(ctx context.Context, id string) error
+	RevokeSessionsByUserID(ctx context.Context, userID string) error
+}
+
+type sessionRepo struct {
+	queries *db.Queries
+}
+
+func NewSessionRepository(q *db.Queries) SessionRepository {
+	return &sessionRepo{queries: q}
+}
+
+func (r *sessionRepo) CreateSession(ctx context.Context, data db.CreateSessionParams) error {
+	return r.queries.CreateSession(ctx, data)
+}
+
+func (r *sessionRepo) GetSessionByID(ctx context.Context, id string) (db.Session, error) {
+	return r.queries.GetSessionByID(ctx, id)
+}
+
+func (r *sessionRepo) GetSessionByRefreshTokenHash(ctx context.Context, hash string) (db.Session, error) {
+	return r.queries.GetSessionByRefreshTokenHash(ctx, hash)
+}
+
+func (r *sessionRepo) GetSessionByPreviousTokenHash(ctx context.Context, hash string) (db.Session, error) {
+	return r.queries.GetSessionByPreviousTokenHash(ctx, sql.NullString{String: hash, Valid: true})
+}
+
+func (r *sessionRepo) RotateSessionToken(ctx context.Context, data db.RotateSessionTokenParams) (int64, error) {
+	return r.queries.RotateSessionToken(ctx, data)
+}
+
+func (r *sessionRepo) RevokeSession(ctx context.Context, id string) error {
+	return r.queries.RevokeSession(ctx, db.RevokeSessionParams{
+		RevokedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:        id,
+	})
+}
+
+func (r *sessionRepo) RevokeSessionsByUserID(ctx context.Context, userID string) error {
+	return r.queries.RevokeSessionsByUserID(ctx, db.RevokeSessionsByUserIDParams{
+		RevokedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		UserID:    userID,
+	})
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/nack098/nakumanager/internal/db"
@@ -21,6 +22,19 @@ type TeamRepository interface {
 	IsTeamExists(ctx context.Context, teamID string) (bool, error)
 	RenameTeam(ctx context.Context, data db.RenameTeamParams) error
 	SetLeaderToTeam(ctx context.Context, data db.SetLeaderToTeamParams) error
+	ListTeamStatuses(ctx context.Context, teamID string) ([]db.TeamStatus, error)
+	GetTeamStatus(ctx context.Context, teamID, key string) (db.TeamStatus, error)
+	GetDefaultTeamStatus(ctx context.Context, teamID string) (string, error)
+	CountIssuesByTeamStatus(ctx context.Context, teamID, key string) (int64, error)
+	CreateTeamStatusTx(ctx context.Context, tx *sql.Tx, data db.CreateTeamStatusParams) error
+	UpdateTeamStatusTx(ctx context.Context, tx *sql.Tx, data db.UpdateTeamStatusParams) error
+	DeleteTeamStatusTx(ctx context.Context, tx *sql.Tx, teamID, key, moveTo string) error
+	SetTeamStatusOrderTx(ctx context.Context, tx *sql.Tx, teamID string, keys []string) error
+	ListTransitionRules(ctx context.Context, teamID string) ([]db.TeamTransitionRule, error)
+	CreateTransitionRule(ctx context.Context, data db.CreateTransitionRuleParams) error
+	DeleteTransitionRule(ctx context.Context, teamID, id string) (int64, error)
+	ListTeamKeys(ctx context.Context, workspaceID string) ([]string, error)
+	SetTeamKeyTx(ctx context.Context, tx *sql.Tx, teamID, key string) error
 }
 
 type teamRepo struct {
@@ -44,6 +58,7 @@ func (r *teamRepo) CreateTeam(ctx context.Context, data models.CreateTeam) error
 		ID:          data.ID,
 		Name:        data.Name,
 		WorkspaceID: data.WorkspaceID,
+		Key:         data.Key,
 	}
 	return r.queries.CreateTeam(ctx, model)
 }
@@ -105,3 +120,83 @@ func (r *teamRepo) RenameTeam(ctx context.Context, data db.RenameTeamParams) err
 func (r *teamRepo) SetLeaderToTeam(ctx context.Context, data db.SetLeaderToTeamParams) error {
 	return r.queries.SetLeaderToTeam(ctx, data)
 }
+
+func (r *teamRepo) ListTeamStatuses(ctx context.Context, teamID string) ([]db.TeamStatus, error) {
+	return r.queries.ListTeamStatuses(ctx, teamID)
+}
+
+func (r *teamRepo) GetTeamStatus(ctx context.Context, teamID, key string) (db.TeamStatus, error) {
+	return r.queries.GetTeamStatus(ctx, db.GetTeamStatusParams{TeamID: teamID, Key: key})
+}
+
+// GetDefaultTeamStatus returns the status new issues get when none is given:
+// the first unstarted status of the team, or its first status if it has no
+// unstarted one.
+func (r *teamRepo) GetDefaultTeamStatus(ctx context.Context, teamID string) (string, error) {
+	return r.queries.GetDefaultTeamStatus(ctx, teamID)
+}
+
+func (r *teamRepo) CountIssuesByTeamStatus(ctx context.Context, teamID, key string) (int64, error) {
+	return r.queries.CountIssuesByTeamStatus(ctx, db.CountIssuesByTeamStatusParams{TeamID: teamID, Status: key})
+}
+
+func (r *teamRepo) CreateTeamStatusTx(ctx context.Context, tx *sql.Tx, data db.CreateTeamStatusParams) error {
+	return r.queries.WithTx(tx).CreateTeamStatus(ctx, data)
+}
+
+func (r *teamRepo) UpdateTeamStatusTx(ctx context.Context, tx *sql.Tx, data db.UpdateTeamStatusParams) error {
+	return r.queries.WithTx(tx).UpdateTeamStatus(ctx, data)
+}
+
+// DeleteTeamStatusTx deletes a status of the team. The issues in it are moved
+// to moveTo first; without moveTo the status must not be in use.
+func (r *teamRepo) DeleteTeamStatusTx(ctx context.Context, tx *sql.Tx, teamID, key, moveTo string) error {
+	q := r.queries.WithTx(tx)
+	if moveTo != "" {
+		if err := q.MoveIssuesToTeamStatus(ctx, db.MoveIssuesToTeamStatusParams{Status: moveTo, TeamID: teamID, Status_2: key}); err != nil {
+			return err
+		}
+	}
+	return q.DeleteTeamStatus(ctx, db.DeleteTeamStatusParams{TeamID: teamID, Key: key})
+}
+
+// SetTeamStatusOrderTx numbers the statuses of the team in the order of keys.
+func (r *teamRepo) SetTeamStatusOrderTx(ctx context.Context, tx *sql.Tx, teamID string, keys []string) error {
+	q := r.queries.WithTx(tx)
+	for i, key := range keys {
+		if err := q.SetTeamStatusPosition(ctx, db.SetTeamStatusPositionParams{Position: int64(i), TeamID: teamID, Key: key}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *teamRepo) ListTransitionRules(ctx context.Context, teamID string) ([]db.TeamTransitionRule, error) {
+	return r.queries.ListTransitionRules(ctx, teamID)
+}
+
+func (r *teamRepo) CreateTransitionRule(ctx context.Context, data db.CreateTransitionRuleParams) error {
+	return r.queries.CreateTransitionRule(ctx, data)
+}
+
+func (r *teamRepo) DeleteTransitionRule(ctx context.Context, teamID, id string) (int64, error) {
+	return r.queries.DeleteTransitionRule(ctx, db.DeleteTransitionRuleParams{ID: id, TeamID: teamID})
+}
+
+func (r *teamRepo) ListTeamKeys(ctx context.Context, workspaceID string) ([]string, error) {
+	return r.queries.ListTeamKeys(ctx, workspaceID)
+}
+
+// SetTeamKeyTx changes the key of the team and the identifiers of its issues
+// with it. The numbers of the issues stay the same, and the old identifiers are
+// kept as aliases so links to them still work.
+func (r *teamRepo) SetTeamKeyTx(ctx context.Context, tx *sql.Tx, teamID, key string) error {
+	q := r.queries.WithTx(tx)
+	if err := q.AddTeamIssueIdentifierAliases(ctx, teamID); err != nil {
+		return err
+	}
+	if err := q.SetTeamKey(ctx, db.SetTeamKeyParams{Key: key, ID: teamID}); err != nil {
+		return err
+	}
+	return q.RenumberTeamIssues(ctx, teamID)
+}
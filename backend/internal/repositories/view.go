@@ -3,25 +3,31 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"strings"
 
 	"github.com/nack098/nakumanager/internal/db"
+	"github.com/nack098/nakumanager/internal/sqlbuilder"
 )
 
 type ViewRepository interface {
 	AddGroupByToView(ctx context.Context, data db.AddGroupByToViewParams) error
-	AddIssueToView(ctx context.Context, data db.AddIssueToViewParams) error
-	AddIssueToViewTx(ctx context.Context, tx *sql.Tx, params db.AddIssueToViewParams) error
 	CreateView(ctx context.Context, data db.CreateViewParams) error
 	DeleteView(ctx context.Context, id string) error
 	GetViewByID(ctx context.Context, id string) ([]db.View, error)
 	UpdateViewName(ctx context.Context, id string, name string) error
 	RemoveGroupByFromView(ctx context.Context, id string) error
-	RemoveIssueFromView(ctx context.Context, id string) error
+	ListViewGroupBys(ctx context.Context, id string) ([]db.ViewGroupBy, error)
+	ListGroupValues(ctx context.Context, teamID string, field string) ([]string, error)
 	ListViewByTeamID(ctx context.Context, teamID string) ([]db.View, error)
 	GetViewsByGroupBys(ctx context.Context, groupBys []string) ([]db.View, error)
 	UpdateViewTeamID(ctx context.Context, id string, teamID string) error
+	UpdateViewFilter(ctx context.Context, id string, filter sql.NullString) error
 	GetTeamIDByViewID(ctx context.Context, id string) (string, error)
+	ListViewIssues(ctx context.Context, viewID, teamID string, filter sqlbuilder.Expr) ([]db.Issue, error)
+	ListViewIssueAssignees(ctx context.Context, teamID string, filter sqlbuilder.Expr) (map[string][]string, error)
+	ListViewIssueLabels(ctx context.Context, teamID string, filter sqlbuilder.Expr) (map[string][]string, error)
+	GetTeamIssueVersion(ctx context.Context, teamID string) (int64, error)
 }
 
 type viewRepo struct {
@@ -40,15 +46,6 @@ func (r *viewRepo) AddGroupByToView(ctx context.Context, data db.AddGroupByToVie
 	return r.db.AddGroupByToView(ctx, data)
 }
 
-func (r *viewRepo) AddIssueToView(ctx context.Context, data db.AddIssueToViewParams) error {
-	return r.db.AddIssueToView(ctx, data)
-}
-
-func (r *viewRepo) AddIssueToViewTx(ctx context.Context, tx *sql.Tx, params db.AddIssueToViewParams) error {
-	q := db.New(tx)
-	return q.AddIssueToView(ctx, params)
-}
-
 func (r *viewRepo) CreateView(ctx context.Context, data db.CreateViewParams) error {
 	return r.db.CreateView(ctx, data)
 }
@@ -72,8 +69,49 @@ func (r *viewRepo) RemoveGroupByFromView(ctx context.Context, id string) error {
 	return r.db.RemoveGroupByFromView(ctx, id)
 }
 
-func (r *viewRepo) RemoveIssueFromView(ctx context.Context, id string) error {
-	return r.db.RemoveIssueFromView(ctx, id)
+func (r *viewRepo) ListViewGroupBys(ctx context.Context, id string) ([]db.ViewGroupBy, error) {
+	return r.db.ListViewGroupBys(ctx, id)
+}
+
+// groupValueQueries select the known values of a group-by field in a team, in
+// the order their groups are shown by default.
+var groupValueQueries = map[string]string{
+	"status":     `SELECT key FROM team_statuses WHERE team_id = ? ORDER BY position, key`,
+	"assignee":   `SELECT u.id FROM team_members tm JOIN users u ON u.id = tm.user_id WHERE tm.team_id = ? ORDER BY u.username`,
+	"project_id": `SELECT id FROM projects WHERE team_id = ? ORDER BY name`,
+	"label":      `SELECT l.id FROM labels l JOIN teams t ON t.workspace_id = l.workspace_id WHERE t.id = ? AND (l.team_id IS NULL OR l.team_id = t.id) ORDER BY l.name COLLATE NOCASE, l.id`,
+}
+
+// ListGroupValues returns every value a group-by field can take in the team,
+// in their natural order. Fields without a fixed set of values, such as
+// end_date, have none.
+func (r *viewRepo) ListGroupValues(ctx context.Context, teamID string, field string) ([]string, error) {
+	switch field {
+	case "priority":
+		return []string{"high", "medium", "low"}, nil
+	case "team_id":
+		return []string{teamID}, nil
+	}
+
+	query, ok := groupValueQueries[field]
+	if !ok {
+		return []string{}, nil
+	}
+	rows, err := r.rawDb.QueryContext(ctx, query, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
 }
 
 func (r *viewRepo) ListViewByTeamID(ctx context.Context, teamID string) ([]db.View, error) {
@@ -82,7 +120,7 @@ func (r *viewRepo) ListViewByTeamID(ctx context.Context, teamID string) ([]db.Vi
 
 func (r *viewRepo) GetViewsByGroupBys(ctx context.Context, groupBys []string) ([]db.View, error) {
 	query := `
-    SELECT v.id, v.name, v.created_by, v.team_id
+    SELECT v.id, v.name, v.created_by, v.team_id, v.filter
 	FROM views v
 	JOIN view_group_bys vg ON v.id = vg.view_id
 	WHERE vg.group_by IN (?` + strings.Repeat(",?", len(groupBys)-1) + `)
@@ -105,7 +143,7 @@ func (r *viewRepo) GetViewsByGroupBys(ctx context.Context, groupBys []string) ([
 	var views []db.View
 	for rows.Next() {
 		var v db.View
-		if err := rows.Scan(&v.ID, &v.Name, &v.CreatedBy, &v.TeamID); err != nil {
+		if err := rows.Scan(&v.ID, &v.Name, &v.CreatedBy, &v.TeamID, &v.Filter); err != nil {
 			return nil, err
 		}
 		views = append(views, v)
@@ -120,6 +158,95 @@ func (r *viewRepo) UpdateViewTeamID(ctx context.Context, id string, teamID strin
 	})
 }
 
+func (r *viewRepo) UpdateViewFilter(ctx context.Context, id string, filter sql.NullString) error {
+	return r.db.UpdateViewFilter(ctx, db.UpdateViewFilterParams{
+		ID:     id,
+		Filter: filter,
+	})
+}
+
 func (r *viewRepo) GetTeamIDByViewID(ctx context.Context, id string) (string, error) {
 	return r.db.GetTeamIDByViewID(ctx, id)
 }
+
+// ListViewIssues returns the team's issues matching filter, which is usually a
+// compiled view filter on the issues table aliased as i. They are in the order
+// of the view's board, where an issue not moved on the view's board keeps its
+// place on the team's board. Rank is set to the position on the view's board.
+func (r *viewRepo) ListViewIssues(ctx context.Context, viewID, teamID string, filter sqlbuilder.Expr) ([]db.Issue, error) {
+	const rank = "COALESCE(vr.rank, i.rank)"
+	query, args := sqlbuilder.Select{
+		Columns: []string{strings.Replace(issueColumns, "i.rank", rank, 1)},
+		From:    "issues i",
+		Joins:   []sqlbuilder.Expr{sqlbuilder.Raw("LEFT JOIN view_issue_ranks vr ON vr.issue_id = i.id AND vr.view_id = ?", viewID)},
+		Where:   []sqlbuilder.Expr{sqlbuilder.Eq("i.team_id", teamID), filter},
+		OrderBy: []sqlbuilder.Order{{Expr: rank}, {Expr: "i.start_date", Desc: true}, {Expr: "i.id"}},
+	}.Build()
+
+	rows, err := r.rawDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	issues := []db.Issue{}
+	for rows.Next() {
+		var i db.Issue
+		if err := rows.Scan(
+			&i.ID, &i.Title, &i.Content, &i.Priority, &i.Status, &i.ProjectID,
+			&i.TeamID, &i.StartDate, &i.EndDate, &i.OwnerID, &i.Rank, &i.Number, &i.Identifier, &i.ParentID, &i.CycleID, &i.CycleAddedAt, &i.MilestoneID,
+		); err != nil {
+			return nil, err
+		}
+		issues = append(issues, i)
+	}
+	return issues, rows.Err()
+}
+
+// ListViewIssueAssignees returns the assignees of the issues ListViewIssues
+// returns for the same filter, keyed by issue ID.
+func (r *viewRepo) ListViewIssueAssignees(ctx context.Context, teamID string, filter sqlbuilder.Expr) (map[string][]string, error) {
+	return r.listViewIssueRelated(ctx, "issue_assignees ia", "ia.user_id", teamID, filter)
+}
+
+// ListViewIssueLabels returns the label IDs of the issues ListViewIssues
+// returns for the same filter, keyed by issue ID.
+func (r *viewRepo) ListViewIssueLabels(ctx context.Context, teamID string, filter sqlbuilder.Expr) (map[string][]string, error) {
+	return r.listViewIssueRelated(ctx, "issue_labels il", "il.label_id", teamID, filter)
+}
+
+func (r *viewRepo) listViewIssueRelated(ctx context.Context, table, col, teamID string, filter sqlbuilder.Expr) (map[string][]string, error) {
+	alias := strings.SplitN(col, ".", 2)[0]
+	query, args := sqlbuilder.Select{
+		Columns: []string{alias + ".issue_id", col},
+		From:    table + " JOIN issues i ON i.id = " + alias + ".issue_id",
+		Where:   []sqlbuilder.Expr{sqlbuilder.Eq("i.team_id", teamID), filter},
+		OrderBy: []sqlbuilder.Order{{Expr: alias + ".issue_id"}, {Expr: col}},
+	}.Build()
+
+	rows, err := r.rawDb.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	related := map[string][]string{}
+	for rows.Next() {
+		var issueID, value string
+		if err := rows.Scan(&issueID, &value); err != nil {
+			return nil, err
+		}
+		related[issueID] = append(related[issueID], value)
+	}
+	return related, rows.Err()
+}
+
+// GetTeamIssueVersion returns a number that grows whenever an issue of the
+// team, or its assignees or labels, is written. A team without issues is at version 0.
+func (r *viewRepo) GetTeamIssueVersion(ctx context.Context, teamID string) (int64, error) {
+	version, err := r.db.GetTeamIssueVersion(ctx, teamID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return version, err
+}
@@ -10,7 +10,7 @@ type WorkspaceRepository interface {
 	CreateWorkspace(ctx context.Context, id string, name string, ownerID string) error
 	GetWorkspaceByID(ctx context.Context, id string) (db.Workspace, error)
 	DeleteWorkspace(ctx context.Context, id string) error
-	AddMemberToWorkspace(ctx context.Context, workspaceID, userID string) error
+	AddMemberToWorkspace(ctx context.Context, workspaceID, userID, role string) error
 	RemoveMemberFromWorkspace(ctx context.Context, workspaceID, userID string) error
 	RenameWorkspace(ctx context.Context, id string, newName string) error
 	ListWorkspacesWithMembersByUserID(ctx context.Context, userID string) ([]db.ListWorkspacesWithMembersByUserIDRow, error)
@@ -42,10 +42,11 @@ func (r *workspaceRepo) DeleteWorkspace(ctx context.Context, id string) error {
 }
 
 
-func (r *workspaceRepo) AddMemberToWorkspace(ctx context.Context, workspaceID, userID string) error {
+func (r *workspaceRepo) AddMemberToWorkspace(ctx context.Context, workspaceID, userID, role string) error {
 	return r.queries.AddMemberToWorkspace(ctx, db.AddMemberToWorkspaceParams{
 		WorkspaceID: workspaceID,
 		UserID:      userID,
+		Role:        role,
 	})
 }
 
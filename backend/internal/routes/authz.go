@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+)
+
+// authorize checks the action against the user's roles and writes 403 with msg
+// when it is not allowed. On failure the error response is already written.
+func authorize(c *fiber.Ctx, a *authz.Authorizer, action authz.Action, r authz.Resource, msg string) bool {
+	return check(c, allow(c.Context(), a, c.Locals("userID").(string), action, r, msg))
+}
+
+// allow checks the action against the user's roles and fails with 403 and msg
+// when it is not allowed.
+func allow(ctx context.Context, a *authz.Authorizer, userID string, action authz.Action, r authz.Resource, msg string) error {
+	ok, err := a.Can(ctx, userID, action, r)
+	if err != nil {
+		return &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to check permissions"}
+	}
+	if !ok {
+		return &StatusError{Status: fiber.StatusForbidden, Message: msg}
+	}
+	return nil
+}
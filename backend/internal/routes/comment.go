@@ -0,0 +1,254 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+// loadIssueFor fetches the issue and checks that the user may perform the action
+// on it. On failure the error response is already written.
+func (h *IssueHandler) loadIssueFor(c *fiber.Ctx, issueID string, action authz.Action) (db.Issue, bool) {
+	issue, err := h.Repo.GetIssueByID(c.Context(), issueID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue not found"})
+			return db.Issue{}, false
+		}
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch issue"})
+		return db.Issue{}, false
+	}
+
+	if !authorize(c, h.Authz, action, authz.ForIssue(issue), "you are not authorized to access this issue") {
+		return db.Issue{}, false
+	}
+
+	return issue, true
+}
+
+// loadCommentFor fetches a comment of the issue and checks that the user may
+// perform the action on it. On failure the error response is already written.
+func (h *IssueHandler) loadCommentFor(c *fiber.Ctx, issue db.Issue, commentID string, action authz.Action) (db.IssueComment, bool) {
+	comment, err := h.CommentRepo.GetCommentByID(c.Context(), commentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "comment not found"})
+			return db.IssueComment{}, false
+		}
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch comment"})
+		return db.IssueComment{}, false
+	}
+
+	if comment.IssueID != issue.ID {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "comment not found"})
+		return db.IssueComment{}, false
+	}
+
+	if !authorize(c, h.Authz, action, authz.ForComment(issue, comment), "you can only modify your own comments") {
+		return db.IssueComment{}, false
+	}
+
+	return comment, true
+}
+
+func (h *IssueHandler) CreateComment(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	if issueID == "" || issueID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	var req models.CreateComment
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "comment body is required"})
+	}
+
+	userID := c.Locals("userID").(string)
+	ctx := c.Context()
+
+	if _, ok := h.loadIssueFor(c, issueID, authz.IssueComment); !ok {
+		return nil
+	}
+
+	comment := db.CreateCommentParams{
+		ID:        uuid.New().String(),
+		IssueID:   issueID,
+		AuthorID:  userID,
+		Body:      req.Body,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := h.CommentRepo.CreateComment(ctx, comment); err != nil {
+		log.Printf("Failed to create comment: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create comment"})
+	}
+
+	mentions, err := h.CommentRepo.SetMentions(ctx, comment.ID, ParseMentions(req.Body))
+	if err != nil {
+		log.Printf("Failed to save mentions for comment %s: %v", comment.ID, err)
+	}
+
+	payload := fiber.Map{
+		"comment":  comment,
+		"mentions": mentions,
+	}
+	ws.BroadcastToRoom("issue", issueID, "comment_created", payload)
+
+	return c.Status(fiber.StatusCreated).JSON(payload)
+}
+
+func (h *IssueHandler) ListComments(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	if issueID == "" || issueID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := h.loadIssueFor(c, issueID, authz.IssueView); !ok {
+		return nil
+	}
+
+	comments, err := h.CommentRepo.ListCommentsByIssueID(c.Context(), issueID)
+	if err != nil {
+		log.Printf("Failed to list comments: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch comments"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"comments": comments})
+}
+
+func (h *IssueHandler) UpdateComment(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	commentID := c.Params("commentId")
+	if issueID == "" || issueID == "undefined" || commentID == "" || commentID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue or comment ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	var req models.UpdateComment
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "comment body is required"})
+	}
+
+	userID := c.Locals("userID").(string)
+	ctx := c.Context()
+
+	issue, ok := h.loadIssueFor(c, issueID, authz.IssueView)
+	if !ok {
+		return nil
+	}
+	comment, ok := h.loadCommentFor(c, issue, commentID, authz.CommentUpdate)
+	if !ok {
+		return nil
+	}
+
+	if comment.Body == req.Body {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "comment unchanged"})
+	}
+
+	if err := h.CommentRepo.UpdateComment(ctx, commentID, req.Body, userID); err != nil {
+		log.Printf("Failed to update comment %s: %v", commentID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update comment"})
+	}
+
+	mentions, err := h.CommentRepo.SetMentions(ctx, commentID, ParseMentions(req.Body))
+	if err != nil {
+		log.Printf("Failed to save mentions for comment %s: %v", commentID, err)
+	}
+
+	ws.BroadcastToRoom("issue", issueID, "comment_updated", fiber.Map{
+		"comment_id": commentID,
+		"body":       req.Body,
+		"mentions":   mentions,
+	})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "comment updated successfully"})
+}
+
+func (h *IssueHandler) DeleteComment(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	commentID := c.Params("commentId")
+	if issueID == "" || issueID == "undefined" || commentID == "" || commentID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue or comment ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	issue, ok := h.loadIssueFor(c, issueID, authz.IssueView)
+	if !ok {
+		return nil
+	}
+	if _, ok := h.loadCommentFor(c, issue, commentID, authz.CommentDelete); !ok {
+		return nil
+	}
+
+	if err := h.CommentRepo.DeleteComment(c.Context(), commentID); err != nil {
+		log.Printf("Failed to delete comment %s: %v", commentID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete comment"})
+	}
+
+	ws.BroadcastToRoom("issue", issueID, "comment_deleted", fiber.Map{"comment_id": commentID})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "comment deleted successfully"})
+}
+
+func (h *IssueHandler) GetCommentHistory(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	commentID := c.Params("commentId")
+	if issueID == "" || issueID == "undefined" || commentID == "" || commentID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue or comment ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	ctx := c.Context()
+
+	if _, ok := h.loadIssueFor(c, issueID, authz.IssueView); !ok {
+		return nil
+	}
+
+	comment, err := h.CommentRepo.GetCommentByID(ctx, commentID)
+	if err != nil || comment.IssueID != issueID {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "comment not found"})
+	}
+
+	edits, err := h.CommentRepo.ListCommentEdits(ctx, commentID)
+	if err != nil {
+		log.Printf("Failed to list edits of comment %s: %v", commentID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch comment history"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"comment": comment,
+		"edits":   edits,
+	})
+}
@@ -0,0 +1,340 @@
+package routes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "no mentions", text: "just a comment", want: []string{}},
+		{name: "single mention", text: "@alice please check", want: []string{"alice"}},
+		{name: "multiple and duplicate", text: "cc @bob, @carol and @bob again", want: []string{"bob", "carol"}},
+		{name: "trailing punctuation", text: "thanks @dave.", want: []string{"dave"}},
+		{name: "email is not a mention", text: "mail me at eve@example.com", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, routes.ParseMentions(tt.text))
+		})
+	}
+}
+
+var (
+	noRoles              = db.GetTeamMemberRolesRow{}
+	teamMemberRoles      = db.GetTeamMemberRolesRow{WorkspaceID: "ws-1", WorkspaceRole: "member", TeamRole: "member"}
+	workspaceMemberRoles = db.GetTeamMemberRolesRow{WorkspaceID: "ws-1", WorkspaceRole: "member"}
+	teamLeadRoles        = db.GetTeamMemberRolesRow{WorkspaceID: "ws-1", WorkspaceRole: "member", TeamRole: "lead"}
+)
+
+func TestCreateComment(t *testing.T) {
+	app := fiber.New()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockCommentRepo := new(mocks.MockCommentRepo)
+	handler := routes.IssueHandler{
+		Repo:        mockRepo,
+		CommentRepo: mockCommentRepo,
+		Authz:       authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app.Use(withUserID("user-123"))
+	app.Post("/issues/:id/comments", handler.CreateComment)
+
+	tests := []struct {
+		name       string
+		issueID    string
+		rawBody    []byte
+		setupMocks func()
+		wantStatus int
+	}{
+		{
+			name:    "success with mentions",
+			issueID: "issue-1",
+			rawBody: mustJSON(models.CreateComment{Body: "hey @alice"}),
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-1").
+					Return(db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "someone"}, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				mockCommentRepo.On("CreateComment", mock.Anything, mock.MatchedBy(func(p db.CreateCommentParams) bool {
+					return p.IssueID == "issue-1" && p.AuthorID == "user-123" && p.Body == "hey @alice"
+				})).Return(nil)
+				mockCommentRepo.On("SetMentions", mock.Anything, mock.Anything, []string{"alice"}).
+					Return([]db.ListMentionsByCommentIDRow{{ID: "user-alice", Username: "alice"}}, nil)
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name:       "invalid body",
+			issueID:    "issue-1",
+			rawBody:    []byte(`{bad`),
+			setupMocks: func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "empty body",
+			issueID:    "issue-1",
+			rawBody:    mustJSON(models.CreateComment{Body: "   "}),
+			setupMocks: func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:    "issue not found",
+			issueID: "missing",
+			rawBody: mustJSON(models.CreateComment{Body: "hello"}),
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "missing").Return(db.Issue{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name:    "not a team member",
+			issueID: "issue-2",
+			rawBody: mustJSON(models.CreateComment{Body: "hello"}),
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-2").
+					Return(db.Issue{ID: "issue-2", TeamID: "team-2", OwnerID: "someone"}, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-2", "user-123").Return(workspaceMemberRoles, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:    "create fails",
+			issueID: "issue-3",
+			rawBody: mustJSON(models.CreateComment{Body: "hello"}),
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-3").
+					Return(db.Issue{ID: "issue-3", TeamID: "team-1", OwnerID: "user-123"}, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(workspaceMemberRoles, nil)
+				mockCommentRepo.On("CreateComment", mock.Anything, mock.Anything).Return(errors.New("db error"))
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodPost, "/issues/"+tt.issueID+"/comments", bytes.NewReader(tt.rawBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			mockRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+			mockCommentRepo.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestUpdateComment(t *testing.T) {
+	app := fiber.New()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockCommentRepo := new(mocks.MockCommentRepo)
+	handler := routes.IssueHandler{
+		Repo:        mockRepo,
+		CommentRepo: mockCommentRepo,
+		Authz:       authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app.Use(withUserID("user-123"))
+	app.Patch("/issues/:id/comments/:commentId", handler.UpdateComment)
+
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+
+	ownIssue := db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-123"}
+
+	tests := []struct {
+		name       string
+		commentID  string
+		body       string
+		setupMocks func()
+		wantStatus int
+	}{
+		{
+			name:      "success",
+			commentID: "c1",
+			body:      "edited @bob",
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(ownIssue, nil)
+				mockCommentRepo.On("GetCommentByID", mock.Anything, "c1").
+					Return(db.IssueComment{ID: "c1", IssueID: "issue-1", AuthorID: "user-123", Body: "original"}, nil)
+				mockCommentRepo.On("UpdateComment", mock.Anything, "c1", "edited @bob", "user-123").Return(nil)
+				mockCommentRepo.On("SetMentions", mock.Anything, "c1", []string{"bob"}).
+					Return([]db.ListMentionsByCommentIDRow{}, nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:      "not the author",
+			commentID: "c2",
+			body:      "edited",
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(ownIssue, nil)
+				mockCommentRepo.On("GetCommentByID", mock.Anything, "c2").
+					Return(db.IssueComment{ID: "c2", IssueID: "issue-1", AuthorID: "other"}, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:      "comment belongs to another issue",
+			commentID: "c3",
+			body:      "edited",
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(ownIssue, nil)
+				mockCommentRepo.On("GetCommentByID", mock.Anything, "c3").
+					Return(db.IssueComment{ID: "c3", IssueID: "issue-9", AuthorID: "user-123"}, nil)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name:      "update fails",
+			commentID: "c4",
+			body:      "edited",
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(ownIssue, nil)
+				mockCommentRepo.On("GetCommentByID", mock.Anything, "c4").
+					Return(db.IssueComment{ID: "c4", IssueID: "issue-1", AuthorID: "user-123", Body: "original"}, nil)
+				mockCommentRepo.On("UpdateComment", mock.Anything, "c4", "edited", "user-123").Return(errors.New("db error"))
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			req := httptest.NewRequest(http.MethodPatch, "/issues/issue-1/comments/"+tt.commentID, bytes.NewReader(mustJSON(models.UpdateComment{Body: tt.body})))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			mockRepo.ExpectedCalls = nil
+			mockCommentRepo.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	app := fiber.New()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockCommentRepo := new(mocks.MockCommentRepo)
+	handler := routes.IssueHandler{
+		Repo:        mockRepo,
+		CommentRepo: mockCommentRepo,
+		Authz:       authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app.Use(withUserID("user-123"))
+	app.Delete("/issues/:id/comments/:commentId", handler.DeleteComment)
+
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	mockRepo.On("GetIssueByID", mock.Anything, "issue-1").
+		Return(db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-123"}, nil)
+	mockCommentRepo.On("GetCommentByID", mock.Anything, "c1").
+		Return(db.IssueComment{ID: "c1", IssueID: "issue-1", AuthorID: "user-123"}, nil)
+	mockCommentRepo.On("DeleteComment", mock.Anything, "c1").Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/issues/issue-1/comments/c1", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	mockCommentRepo.AssertExpectations(t)
+}
+
+func TestDeleteComment_Roles(t *testing.T) {
+	app := fiber.New()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockCommentRepo := new(mocks.MockCommentRepo)
+	handler := routes.IssueHandler{
+		Repo:        mockRepo,
+		CommentRepo: mockCommentRepo,
+		Authz:       authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app.Use(withUserID("user-123"))
+	app.Delete("/issues/:id/comments/:commentId", handler.DeleteComment)
+
+	tests := []struct {
+		name       string
+		roles      db.GetTeamMemberRolesRow
+		wantStatus int
+	}{
+		{name: "team lead removes another member's comment", roles: teamLeadRoles, wantStatus: fiber.StatusOK},
+		{name: "workspace admin removes another member's comment", roles: db.GetTeamMemberRolesRow{WorkspaceID: "ws-1", WorkspaceRole: "admin"}, wantStatus: fiber.StatusOK},
+		{name: "team member cannot remove another member's comment", roles: teamMemberRoles, wantStatus: fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(tt.roles, nil)
+			mockRepo.On("GetIssueByID", mock.Anything, "issue-1").
+				Return(db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "other"}, nil)
+			mockCommentRepo.On("GetCommentByID", mock.Anything, "c1").
+				Return(db.IssueComment{ID: "c1", IssueID: "issue-1", AuthorID: "other"}, nil)
+			mockCommentRepo.On("DeleteComment", mock.Anything, "c1").Return(nil).Maybe()
+
+			req := httptest.NewRequest(http.MethodDelete, "/issues/issue-1/comments/c1", nil)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			mockRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+			mockCommentRepo.ExpectedCalls = nil
+		})
+	}
+	mockCommentRepo.AssertNumberOfCalls(t, "DeleteComment", 2)
+}
+
+func TestListComments(t *testing.T) {
+	app := fiber.New()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockCommentRepo := new(mocks.MockCommentRepo)
+	handler := routes.IssueHandler{
+		Repo:        mockRepo,
+		CommentRepo: mockCommentRepo,
+		Authz:       authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app.Use(withUserID("user-123"))
+	app.Get("/issues/:id/comments", handler.ListComments)
+
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(workspaceMemberRoles, nil)
+	mockRepo.On("GetIssueByID", mock.Anything, "issue-1").
+		Return(db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-123"}, nil)
+	mockCommentRepo.On("ListCommentsByIssueID", mock.Anything, "issue-1").
+		Return([]db.IssueComment{{ID: "c1", IssueID: "issue-1", Body: "first"}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/issues/issue-1/comments", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,469 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/repositories"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+type CycleHandler struct {
+	DB        *sql.DB
+	Repo      repositories.CycleRepository
+	TeamRepo  repositories.TeamRepository
+	IssueRepo repositories.IssueRepository
+	Authz     *authz.Authorizer
+}
+
+func NewCycleHandler(db *sql.DB, repo repositories.CycleRepository, teamRepo repositories.TeamRepository, issueRepo repositories.IssueRepository, roleRepo repositories.RoleRepository) *CycleHandler {
+	return &CycleHandler{
+		DB:        db,
+		Repo:      repo,
+		TeamRepo:  teamRepo,
+		IssueRepo: issueRepo,
+		Authz:     authz.NewAuthorizer(roleRepo),
+	}
+}
+
+// toCycle returns c with its progress: the counts frozen when it was closed,
+// or those of the issues in it now while it is open.
+func (h *CycleHandler) toCycle(ctx context.Context, c db.Cycle) (models.Cycle, error) {
+	cycle := models.Cycle{
+		ID:        c.ID,
+		TeamID:    c.TeamID,
+		Number:    c.Number,
+		Name:      c.Name.String,
+		StartDate: c.StartDate,
+		EndDate:   c.EndDate,
+		CreatedAt: c.CreatedAt,
+	}
+	if c.ClosedAt.Valid {
+		cycle.ClosedAt = &c.ClosedAt.Time
+		cycle.Progress = models.CycleProgress{
+			Scope:      c.ScopeCount,
+			Completed:  c.CompletedCount,
+			Added:      c.AddedCount,
+			RolledOver: c.RolledOverCount,
+		}
+		return cycle, nil
+	}
+
+	stats, err := h.Repo.GetCycleStats(ctx, c.ID)
+	if err != nil {
+		return cycle, err
+	}
+	cycle.Progress = models.CycleProgress{Scope: stats.Scope, Completed: stats.Completed, Added: stats.Added}
+	return cycle, nil
+}
+
+// overlappingCycle returns the number of a cycle of the team, other than the
+// cycle id, whose dates overlap start to end, or 0 if there is none.
+func (h *CycleHandler) overlappingCycle(ctx context.Context, teamID, id string, start, end time.Time) (int64, error) {
+	cycles, err := h.Repo.ListTeamCycles(ctx, teamID)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range cycles {
+		if c.ID != id && c.StartDate.Before(end) && start.Before(c.EndDate) {
+			return c.Number, nil
+		}
+	}
+	return 0, nil
+}
+
+// loadCycle returns the cycle of the :id param if the user may act on it. On
+// failure the error response is already written.
+func (h *CycleHandler) loadCycle(c *fiber.Ctx, action authz.Action) (db.Cycle, bool) {
+	cycle, err := h.Repo.GetCycleByID(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "cycle not found"})
+			return cycle, false
+		}
+		log.Printf("Failed to get cycle %s: %v", c.Params("id"), err)
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch cycle"})
+		return cycle, false
+	}
+	if !authorize(c, h.Authz, action, authz.ForCycle(cycle), "you are not authorized to access this cycle") {
+		return cycle, false
+	}
+	return cycle, true
+}
+
+// ListTeamCycles returns the cycles of the team with their progress.
+func (h *CycleHandler) ListTeamCycles(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+	if !authorize(c, h.Authz, authz.TeamView, authz.ForTeam(teamID), "you are not a member of this team") {
+		return nil
+	}
+
+	ctx := c.Context()
+	rows, err := h.Repo.ListTeamCycles(ctx, teamID)
+	if err != nil {
+		log.Printf("Failed to list cycles of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list cycles"})
+	}
+	cycles := make([]models.Cycle, 0, len(rows))
+	for _, row := range rows {
+		cycle, err := h.toCycle(ctx, row)
+		if err != nil {
+			log.Printf("Failed to count issues of cycle %s: %v", row.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list cycles"})
+		}
+		cycles = append(cycles, cycle)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"cycles": cycles})
+}
+
+// CreateCycle adds a cycle to the team, numbered after its last one. Cycles of
+// a team may not overlap.
+func (h *CycleHandler) CreateCycle(c *fiber.Ctx) error {
+	var req models.CreateCycleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+	start, end := req.StartDate.UTC(), req.EndDate.UTC()
+	if !end.After(start) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "end_date must be after start_date"})
+	}
+
+	ctx := c.Context()
+	teamID := c.Params("id")
+	teamExists, err := h.TeamRepo.IsTeamExists(ctx, teamID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check team"})
+	}
+	if !teamExists {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
+	}
+	if !authorize(c, h.Authz, authz.CycleCreate, authz.ForTeam(teamID), "you are not allowed to plan cycles of this team") {
+		return nil
+	}
+
+	overlap, err := h.overlappingCycle(ctx, teamID, "", start, end)
+	if err != nil {
+		log.Printf("Failed to list cycles of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create cycle"})
+	}
+	if overlap != 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("dates overlap cycle %d", overlap)})
+	}
+
+	number, err := h.Repo.GetNextCycleNumber(ctx, teamID)
+	if err != nil {
+		log.Printf("Failed to number cycle of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create cycle"})
+	}
+	row := db.CreateCycleParams{
+		ID:        uuid.New().String(),
+		TeamID:    teamID,
+		Number:    number,
+		Name:      sql.NullString{String: req.Name, Valid: req.Name != ""},
+		StartDate: start,
+		EndDate:   end,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := h.Repo.CreateCycle(ctx, row); err != nil {
+		log.Printf("Failed to create cycle of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create cycle"})
+	}
+
+	cycle := models.Cycle{
+		ID:        row.ID,
+		TeamID:    row.TeamID,
+		Number:    row.Number,
+		Name:      req.Name,
+		StartDate: row.StartDate,
+		EndDate:   row.EndDate,
+		CreatedAt: row.CreatedAt,
+	}
+	ws.BroadcastToRoom("team", teamID, "cycle_created", cycle)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "cycle created successfully",
+		"cycle":   cycle,
+	})
+}
+
+func (h *CycleHandler) GetCycle(c *fiber.Ctx) error {
+	row, ok := h.loadCycle(c, authz.TeamView)
+	if !ok {
+		return nil
+	}
+
+	cycle, err := h.toCycle(c.Context(), row)
+	if err != nil {
+		log.Printf("Failed to count issues of cycle %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch cycle"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"cycle": cycle})
+}
+
+// UpdateCycle renames or reschedules a cycle that is not closed yet.
+func (h *CycleHandler) UpdateCycle(c *fiber.Ctx) error {
+	var req models.UpdateCycleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	row, ok := h.loadCycle(c, authz.CycleUpdate)
+	if !ok {
+		return nil
+	}
+	if row.ClosedAt.Valid {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "cycle is closed"})
+	}
+
+	if req.Name != nil {
+		row.Name = ToNullString(req.Name)
+	}
+	if req.StartDate != nil {
+		row.StartDate = req.StartDate.UTC()
+	}
+	if req.EndDate != nil {
+		row.EndDate = req.EndDate.UTC()
+	}
+	if !row.EndDate.After(row.StartDate) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "end_date must be after start_date"})
+	}
+
+	ctx := c.Context()
+	if req.StartDate != nil || req.EndDate != nil {
+		overlap, err := h.overlappingCycle(ctx, row.TeamID, row.ID, row.StartDate, row.EndDate)
+		if err != nil {
+			log.Printf("Failed to list cycles of team %s: %v", row.TeamID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update cycle"})
+		}
+		if overlap != 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("dates overlap cycle %d", overlap)})
+		}
+	}
+
+	if err := h.Repo.UpdateCycle(ctx, db.UpdateCycleParams{
+		Name:      row.Name,
+		StartDate: row.StartDate,
+		EndDate:   row.EndDate,
+		ID:        row.ID,
+	}); err != nil {
+		log.Printf("Failed to update cycle %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update cycle"})
+	}
+
+	cycle, err := h.toCycle(ctx, row)
+	if err != nil {
+		log.Printf("Failed to count issues of cycle %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update cycle"})
+	}
+	ws.BroadcastToRoom("team", row.TeamID, "cycle_updated", cycle)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "cycle updated successfully",
+		"cycle":   cycle,
+	})
+}
+
+// DeleteCycle deletes the cycle. Its issues are kept without a cycle.
+func (h *CycleHandler) DeleteCycle(c *fiber.Ctx) error {
+	cycle, ok := h.loadCycle(c, authz.CycleDelete)
+	if !ok {
+		return nil
+	}
+
+	if err := h.Repo.DeleteCycle(c.Context(), cycle.ID); err != nil {
+		log.Printf("Failed to delete cycle %s: %v", cycle.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete cycle"})
+	}
+
+	ws.BroadcastToRoom("team", cycle.TeamID, "cycle_deleted", fiber.Map{"id": cycle.ID})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "cycle deleted successfully"})
+}
+
+// CloseCycle freezes the progress of the cycle and moves its unfinished issues
+// to the next open cycle of the team. When there is none, a cycle of the same
+// length starting where this one ends is created for them.
+func (h *CycleHandler) CloseCycle(c *fiber.Ctx) error {
+	row, ok := h.loadCycle(c, authz.CycleUpdate)
+	if !ok {
+		return nil
+	}
+	if row.ClosedAt.Valid {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "cycle is already closed"})
+	}
+
+	ctx := c.Context()
+	userID := c.Locals("userID").(string)
+
+	// ทุกอย่างอ่านใน transaction เดียวกับที่ปิด ตัวเลขที่เก็บจึงตรงกับ issue ที่ย้ายจริง
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+	}
+	defer tx.Rollback()
+
+	stats, err := h.Repo.GetCycleStatsTx(ctx, tx, row.ID)
+	if err != nil {
+		log.Printf("Failed to count issues of cycle %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+	}
+	unfinished, err := h.Repo.ListUnfinishedCycleIssueIDsTx(ctx, tx, row.ID)
+	if err != nil {
+		log.Printf("Failed to list unfinished issues of cycle %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+	}
+
+	var next *db.Cycle
+	var created *db.CreateCycleParams
+	now := time.Now().UTC()
+	if len(unfinished) > 0 {
+		n, err := h.Repo.GetNextOpenCycleTx(ctx, tx, row)
+		switch {
+		case err == nil:
+			next = &n
+		case errors.Is(err, sql.ErrNoRows):
+			number, err := h.Repo.GetNextCycleNumberTx(ctx, tx, row.TeamID)
+			if err != nil {
+				log.Printf("Failed to number cycle of team %s: %v", row.TeamID, err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+			}
+			created = &db.CreateCycleParams{
+				ID:        uuid.New().String(),
+				TeamID:    row.TeamID,
+				Number:    number,
+				StartDate: row.EndDate,
+				EndDate:   row.EndDate.Add(row.EndDate.Sub(row.StartDate)),
+				CreatedAt: now,
+			}
+			next = &db.Cycle{
+				ID:        created.ID,
+				TeamID:    created.TeamID,
+				Number:    created.Number,
+				StartDate: created.StartDate,
+				EndDate:   created.EndDate,
+				CreatedAt: created.CreatedAt,
+			}
+		default:
+			log.Printf("Failed to find the cycle after %s: %v", row.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+		}
+	}
+
+	if created != nil {
+		if err := h.Repo.CreateCycleTx(ctx, tx, *created); err != nil {
+			log.Printf("Failed to create the cycle after %s: %v", row.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+		}
+	}
+	for _, issueID := range unfinished {
+		// issue ที่ยกไปนับเป็นงานที่วางแผนไว้ของรอบถัดไป ไม่ใช่งานที่เพิ่มเข้ามากลางรอบ
+		if err := h.Repo.SetIssueCycleTx(ctx, tx, db.SetIssueCycleParams{
+			CycleID:      sql.NullString{String: next.ID, Valid: true},
+			CycleAddedAt: sql.NullTime{Time: next.StartDate, Valid: true},
+			ID:           issueID,
+		}); err != nil {
+			log.Printf("Failed to move issue %s to cycle %s: %v", issueID, next.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+		}
+		if err := h.IssueRepo.CreateIssueEventTx(ctx, tx, db.CreateIssueEventParams{
+			ID:        uuid.New().String(),
+			IssueID:   issueID,
+			ActorID:   userID,
+			Field:     "cycle_id",
+			OldValue:  sql.NullString{String: row.ID, Valid: true},
+			NewValue:  sql.NullString{String: next.ID, Valid: true},
+			CreatedAt: now,
+		}); err != nil {
+			log.Printf("Failed to record moving issue %s to cycle %s: %v", issueID, next.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+		}
+	}
+	row.ClosedAt = sql.NullTime{Time: now, Valid: true}
+	row.ScopeCount = stats.Scope
+	row.CompletedCount = stats.Completed
+	row.AddedCount = stats.Added
+	row.RolledOverCount = int64(len(unfinished))
+	closed, err := h.Repo.CloseCycleTx(ctx, tx, db.CloseCycleParams{
+		ClosedAt:        row.ClosedAt,
+		ScopeCount:      row.ScopeCount,
+		CompletedCount:  row.CompletedCount,
+		AddedCount:      row.AddedCount,
+		RolledOverCount: row.RolledOverCount,
+		ID:              row.ID,
+	})
+	if err != nil {
+		log.Printf("Failed to close cycle %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+	}
+	// มีคนปิดไปก่อนระหว่างนั้น ยกเลิกทั้งหมดรวมถึงการย้าย issue
+	if !closed {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "cycle is already closed"})
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit closing cycle %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to close cycle"})
+	}
+
+	// cycle ที่ปิดแล้วใช้ตัวเลขที่เก็บไว้ ไม่ต้อง query
+	closedCycle, _ := h.toCycle(ctx, row)
+	result := models.CycleClosed{Cycle: closedCycle, RolledOver: unfinished}
+	if next != nil {
+		n, err := h.toCycle(ctx, *next)
+		if err != nil {
+			log.Printf("Failed to count issues of cycle %s: %v", next.ID, err)
+		}
+		result.Next = &n
+		for _, issueID := range unfinished {
+			ws.BroadcastToRoom("issue", issueID, "issue_updated", models.UpdateIssueRequest{ID: issueID, CycleID: &next.ID})
+		}
+	}
+	ws.BroadcastToRoom("team", row.TeamID, "cycle_closed", result)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "cycle closed successfully",
+		"result":  result,
+	})
+}
+
+// checkCycle checks that the issues of the team can be put in the cycle
+// cycleID. On failure the error response is already written.
+func (h *IssueHandler) checkCycle(c *fiber.Ctx, teamID, cycleID string) bool {
+	return check(c, h.openTeamCycle(c.Context(), teamID, cycleID))
+}
+
+// openTeamCycle checks that the issues of the team can be put in the cycle
+// cycleID: it must be one of the team's cycles and not closed yet.
+func (h *IssueHandler) openTeamCycle(ctx context.Context, teamID, cycleID string) error {
+	cycle, err := h.CycleRepo.GetCycleByID(ctx, cycleID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &StatusError{Status: fiber.StatusBadRequest, Message: "cycle not found"}
+		}
+		log.Printf("Failed to get cycle %s: %v", cycleID, err)
+		return &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to check cycle"}
+	}
+	if cycle.TeamID != teamID {
+		return &StatusError{Status: fiber.StatusBadRequest, Message: fmt.Sprintf("cycle %d belongs to another team", cycle.Number)}
+	}
+	if cycle.ClosedAt.Valid {
+		return &StatusError{Status: fiber.StatusBadRequest, Message: fmt.Sprintf("cycle %d is closed", cycle.Number)}
+	}
+	return nil
+}
@@ -0,0 +1,414 @@
+package routes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	cycleStart = time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	cycleEnd   = cycleStart.AddDate(0, 0, 14)
+)
+
+func TestCreateCycle(t *testing.T) {
+	mockCycleRepo := new(mocks.MockCycleRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.CycleHandler{
+		Repo:     mockCycleRepo,
+		TeamRepo: mockTeamRepo,
+		Authz:    authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/teams/:id/cycles", handler.CreateCycle)
+
+	team := func(roles db.GetTeamMemberRolesRow) {
+		mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(roles, nil)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "numbered after the last cycle",
+			body: `{"name":"Sprint","start_date":"2026-01-19T00:00:00Z","end_date":"2026-02-02T00:00:00Z"}`,
+			setup: func() {
+				team(teamMemberRoles)
+				mockCycleRepo.On("ListTeamCycles", mock.Anything, "team-1").Return([]db.Cycle{
+					{ID: "cycle-1", TeamID: "team-1", Number: 1, StartDate: cycleStart, EndDate: cycleEnd},
+				}, nil)
+				mockCycleRepo.On("GetNextCycleNumber", mock.Anything, "team-1").Return(int64(2), nil)
+				mockCycleRepo.On("CreateCycle", mock.Anything, mock.MatchedBy(func(p db.CreateCycleParams) bool {
+					return p.TeamID == "team-1" && p.Number == 2 && p.Name.String == "Sprint" && p.StartDate.Equal(cycleEnd)
+				})).Return(nil).Once()
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "overlapping dates",
+			body: `{"start_date":"2026-01-12T00:00:00Z","end_date":"2026-01-26T00:00:00Z"}`,
+			setup: func() {
+				team(teamMemberRoles)
+				mockCycleRepo.On("ListTeamCycles", mock.Anything, "team-1").Return([]db.Cycle{
+					{ID: "cycle-1", TeamID: "team-1", Number: 1, StartDate: cycleStart, EndDate: cycleEnd},
+				}, nil)
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:       "ends before it starts",
+			body:       `{"start_date":"2026-01-19T00:00:00Z","end_date":"2026-01-12T00:00:00Z"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "missing dates",
+			body:       `{"name":"Sprint"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "not a member of the team",
+			body: `{"start_date":"2026-01-19T00:00:00Z","end_date":"2026-02-02T00:00:00Z"}`,
+			setup: func() {
+				team(workspaceMemberRoles)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPost, "/teams/team-1/cycles", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusCreated {
+				var body struct {
+					Cycle models.Cycle `json:"cycle"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, int64(2), body.Cycle.Number)
+			}
+
+			mockCycleRepo.ExpectedCalls = nil
+			mockTeamRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockCycleRepo.AssertNumberOfCalls(t, "CreateCycle", 1)
+}
+
+func TestListTeamCycles(t *testing.T) {
+	mockCycleRepo := new(mocks.MockCycleRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.CycleHandler{
+		Repo:  mockCycleRepo,
+		Authz: authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Get("/teams/:id/cycles", handler.ListTeamCycles)
+
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	mockCycleRepo.On("ListTeamCycles", mock.Anything, "team-1").Return([]db.Cycle{
+		{
+			ID: "cycle-1", TeamID: "team-1", Number: 1, StartDate: cycleStart, EndDate: cycleEnd,
+			ClosedAt:   sql.NullTime{Time: cycleEnd, Valid: true},
+			ScopeCount: 8, CompletedCount: 5, AddedCount: 2, RolledOverCount: 3,
+		},
+		{ID: "cycle-2", TeamID: "team-1", Number: 2, StartDate: cycleEnd, EndDate: cycleEnd.AddDate(0, 0, 14)},
+	}, nil)
+	mockCycleRepo.On("GetCycleStats", mock.Anything, "cycle-2").Return(db.GetCycleStatsRow{Scope: 4, Completed: 1, Added: 1}, nil).Once()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/teams/team-1/cycles", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Cycles []models.Cycle `json:"cycles"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Cycles, 2)
+	assert.Equal(t, models.CycleProgress{Scope: 8, Completed: 5, Added: 2, RolledOver: 3}, body.Cycles[0].Progress)
+	assert.NotNil(t, body.Cycles[0].ClosedAt)
+	assert.Equal(t, models.CycleProgress{Scope: 4, Completed: 1, Added: 1}, body.Cycles[1].Progress)
+	mockCycleRepo.AssertExpectations(t)
+}
+
+func TestCloseCycle(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockCycleRepo := new(mocks.MockCycleRepo)
+	mockIssueRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.CycleHandler{
+		DB:        mockDB,
+		Repo:      mockCycleRepo,
+		IssueRepo: mockIssueRepo,
+		Authz:     authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/cycles/:id/close", handler.CloseCycle)
+
+	open := db.Cycle{ID: "cycle-1", TeamID: "team-1", Number: 1, StartDate: cycleStart, EndDate: cycleEnd}
+	closed := open
+	closed.ClosedAt = sql.NullTime{Time: cycleEnd, Valid: true}
+	next := db.Cycle{ID: "cycle-3", TeamID: "team-1", Number: 3, StartDate: cycleEnd.AddDate(0, 0, 7), EndDate: cycleEnd.AddDate(0, 0, 21)}
+
+	tests := []struct {
+		name       string
+		cycle      db.Cycle
+		roles      db.GetTeamMemberRolesRow
+		setup      func()
+		wantStatus int
+		check      func(t *testing.T, result models.CycleClosed)
+	}{
+		{
+			name:  "rolls unfinished issues into a new cycle",
+			cycle: open,
+			roles: teamMemberRoles,
+			setup: func() {
+				sqlMock.ExpectBegin()
+				mockCycleRepo.On("GetCycleStatsTx", mock.Anything, mock.Anything, "cycle-1").Return(db.GetCycleStatsRow{Scope: 5, Completed: 3, Added: 1}, nil)
+				mockCycleRepo.On("ListUnfinishedCycleIssueIDsTx", mock.Anything, mock.Anything, "cycle-1").Return([]string{"issue-1", "issue-2"}, nil)
+				mockCycleRepo.On("GetNextOpenCycleTx", mock.Anything, mock.Anything, open).Return(db.Cycle{}, sql.ErrNoRows)
+				mockCycleRepo.On("GetNextCycleNumberTx", mock.Anything, mock.Anything, "team-1").Return(int64(2), nil)
+				mockCycleRepo.On("CreateCycleTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateCycleParams) bool {
+					return p.Number == 2 && p.StartDate.Equal(cycleEnd) && p.EndDate.Equal(cycleEnd.AddDate(0, 0, 14))
+				})).Return(nil).Once()
+				mockCycleRepo.On("SetIssueCycleTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.SetIssueCycleParams) bool {
+					return p.CycleID.Valid && p.CycleID.String != "cycle-1" && p.CycleAddedAt.Time.Equal(cycleEnd)
+				})).Return(nil).Twice()
+				mockIssueRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.Field == "cycle_id" && e.OldValue.String == "cycle-1" && e.ActorID == "user-123"
+				})).Return(nil).Twice()
+				mockCycleRepo.On("CloseCycleTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CloseCycleParams) bool {
+					return p.ID == "cycle-1" && p.ClosedAt.Valid && p.ScopeCount == 5 && p.CompletedCount == 3 && p.AddedCount == 1 && p.RolledOverCount == 2
+				})).Return(true, nil).Once()
+				sqlMock.ExpectCommit()
+				// progress of the new cycle after the move
+				mockCycleRepo.On("GetCycleStats", mock.Anything, mock.Anything).Return(db.GetCycleStatsRow{Scope: 2}, nil)
+			},
+			wantStatus: fiber.StatusOK,
+			check: func(t *testing.T, result models.CycleClosed) {
+				assert.Equal(t, []string{"issue-1", "issue-2"}, result.RolledOver)
+				assert.Equal(t, models.CycleProgress{Scope: 5, Completed: 3, Added: 1, RolledOver: 2}, result.Cycle.Progress)
+				require.NotNil(t, result.Next)
+				assert.Equal(t, int64(2), result.Next.Number)
+			},
+		},
+		{
+			name:  "rolls into the next open cycle",
+			cycle: open,
+			roles: teamMemberRoles,
+			setup: func() {
+				sqlMock.ExpectBegin()
+				mockCycleRepo.On("GetCycleStatsTx", mock.Anything, mock.Anything, "cycle-1").Return(db.GetCycleStatsRow{Scope: 1}, nil)
+				mockCycleRepo.On("ListUnfinishedCycleIssueIDsTx", mock.Anything, mock.Anything, "cycle-1").Return([]string{"issue-1"}, nil)
+				mockCycleRepo.On("GetNextOpenCycleTx", mock.Anything, mock.Anything, open).Return(next, nil)
+				mockCycleRepo.On("SetIssueCycleTx", mock.Anything, mock.Anything, db.SetIssueCycleParams{
+					CycleID:      sql.NullString{String: "cycle-3", Valid: true},
+					CycleAddedAt: sql.NullTime{Time: next.StartDate, Valid: true},
+					ID:           "issue-1",
+				}).Return(nil).Once()
+				mockIssueRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				mockCycleRepo.On("CloseCycleTx", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+				sqlMock.ExpectCommit()
+				mockCycleRepo.On("GetCycleStats", mock.Anything, "cycle-3").Return(db.GetCycleStatsRow{Scope: 1}, nil)
+			},
+			wantStatus: fiber.StatusOK,
+			check: func(t *testing.T, result models.CycleClosed) {
+				require.NotNil(t, result.Next)
+				assert.Equal(t, "cycle-3", result.Next.ID)
+			},
+		},
+		{
+			name:       "already closed",
+			cycle:      closed,
+			roles:      teamMemberRoles,
+			setup:      func() {},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:  "closed by someone else meanwhile",
+			cycle: open,
+			roles: teamMemberRoles,
+			setup: func() {
+				sqlMock.ExpectBegin()
+				mockCycleRepo.On("GetCycleStatsTx", mock.Anything, mock.Anything, "cycle-1").Return(db.GetCycleStatsRow{}, nil)
+				mockCycleRepo.On("ListUnfinishedCycleIssueIDsTx", mock.Anything, mock.Anything, "cycle-1").Return([]string{}, nil)
+				mockCycleRepo.On("CloseCycleTx", mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
+				sqlMock.ExpectRollback()
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:       "guest cannot close",
+			cycle:      open,
+			roles:      db.GetTeamMemberRolesRow{WorkspaceID: "ws-1", WorkspaceRole: "guest", TeamRole: "member"},
+			setup:      func() {},
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCycleRepo.On("GetCycleByID", mock.Anything, "cycle-1").Return(tt.cycle, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(tt.roles, nil)
+			tt.setup()
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/cycles/cycle-1/close", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+
+			if tt.check != nil {
+				var body struct {
+					Result models.CycleClosed `json:"result"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				tt.check(t, body.Result)
+			}
+
+			mockCycleRepo.ExpectedCalls = nil
+			mockIssueRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockCycleRepo.AssertNumberOfCalls(t, "CreateCycleTx", 1)
+	mockCycleRepo.AssertNumberOfCalls(t, "CloseCycleTx", 3)
+}
+
+func TestUpdateIssueCycle(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockCycleRepo := new(mocks.MockCycleRepo)
+	handler := routes.IssueHandler{DB: mockDB, Repo: mockRepo, TeamRepo: mockTeamRepo, CycleRepo: mockCycleRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Patch("/issues/:id", handler.UpdateIssue)
+
+	patch := func(body string) int {
+		req := httptest.NewRequest(http.MethodPatch, "/issues/issue-1", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "put in a cycle",
+			body: `{"cycle_id":"cycle-2"}`,
+			setup: func() {
+				mockCycleRepo.On("GetCycleByID", mock.Anything, "cycle-2").Return(db.Cycle{ID: "cycle-2", TeamID: "team-1", Number: 2}, nil)
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(`UPDATE issues SET cycle_id = \?, cycle_added_at = \? WHERE id = \?`).
+					WithArgs(sql.NullString{String: "cycle-2", Valid: true}, sqlmock.AnyArg(), "issue-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.Field == "cycle_id" && e.OldValue.String == "cycle-1" && e.NewValue.String == "cycle-2"
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "take out of its cycle",
+			body: `{"cycle_id":""}`,
+			setup: func() {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(`UPDATE issues SET cycle_id = \?, cycle_added_at = \? WHERE id = \?`).
+					WithArgs(sql.NullString{}, sql.NullTime{}, "issue-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "cycle of another team",
+			body: `{"cycle_id":"cycle-x"}`,
+			setup: func() {
+				mockCycleRepo.On("GetCycleByID", mock.Anything, "cycle-x").Return(db.Cycle{ID: "cycle-x", TeamID: "team-2", Number: 1}, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "closed cycle",
+			body: `{"cycle_id":"cycle-0"}`,
+			setup: func() {
+				mockCycleRepo.On("GetCycleByID", mock.Anything, "cycle-0").Return(db.Cycle{
+					ID: "cycle-0", TeamID: "team-1", ClosedAt: sql.NullTime{Time: cycleEnd, Valid: true},
+				}, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "unknown cycle",
+			body: `{"cycle_id":"missing"}`,
+			setup: func() {
+				mockCycleRepo.On("GetCycleByID", mock.Anything, "missing").Return(db.Cycle{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(db.Issue{
+				ID: "issue-1", TeamID: "team-1", OwnerID: "user-123", Status: "todo",
+				CycleID: sql.NullString{String: "cycle-1", Valid: true},
+			}, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+			tt.setup()
+
+			assert.Equal(t, tt.wantStatus, patch(tt.body))
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+
+			mockRepo.ExpectedCalls = nil
+			mockCycleRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRepo.AssertNumberOfCalls(t, "CreateIssueEventTx", 2)
+}
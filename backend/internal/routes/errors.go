@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StatusError is an error that is answered with an HTTP status. Message is
+// shown to the client as is, so it must not carry internal details.
+type StatusError struct {
+	Status  int
+	Message string
+	Detail  string
+}
+
+func (e *StatusError) Error() string {
+	if e.Detail != "" {
+		return e.Message + ": " + e.Detail
+	}
+	return e.Message
+}
+
+// writeError writes the error response of err: its status and message for a
+// StatusError and 500 for anything else.
+func writeError(c *fiber.Ctx, err error) error {
+	var se *StatusError
+	if !errors.As(err, &se) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal server error"})
+	}
+	body := fiber.Map{"error": se.Message}
+	if se.Detail != "" {
+		body["detail"] = se.Detail
+	}
+	return c.Status(se.Status).JSON(body)
+}
+
+// check writes the error response of err, if any, and reports whether there
+// was none.
+func check(c *fiber.Ctx, err error) bool {
+	if err == nil {
+		return true
+	}
+	writeError(c, err)
+	return false
+}
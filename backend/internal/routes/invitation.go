@@ -0,0 +1,318 @@
+package routes
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	"github.com/nack098/nakumanager/internal/mail"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/repositories"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+var InvitationTTL = time.Hour * 24 * 7
+
+// InvitationTokens signs and verifies invitation tokens. It is implemented by auth.KeySet.
+type InvitationTokens interface {
+	SignInvitation(invitationID, email string, expiresAt time.Time) (string, error)
+	ParseInvitation(token string) (string, error)
+}
+
+type InvitationHandler struct {
+	Repo          repositories.InvitationRepository
+	WorkspaceRepo repositories.WorkspaceRepository
+	UserRepo      repositories.UserRepository
+	RoleRepo      repositories.RoleRepository
+	Authz         *authz.Authorizer
+	Tokens        InvitationTokens
+	Mailer        mail.Sender
+	// BaseURL is used to build the accept link in the invitation email.
+	BaseURL string
+}
+
+func NewInvitationHandler(repo repositories.InvitationRepository, workspaceRepo repositories.WorkspaceRepository, userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, tokens InvitationTokens, mailer mail.Sender) *InvitationHandler {
+	return &InvitationHandler{
+		Repo:          repo,
+		WorkspaceRepo: workspaceRepo,
+		UserRepo:      userRepo,
+		RoleRepo:      roleRepo,
+		Authz:         authz.NewAuthorizer(roleRepo),
+		Tokens:        tokens,
+		Mailer:        mailer,
+		BaseURL:       "http://localhost:8080",
+	}
+}
+
+// token ถูกเก็บเป็น hash เท่านั้น
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toInvitation(i db.WorkspaceInvitation) models.Invitation {
+	inv := models.Invitation{
+		ID:          i.ID,
+		WorkspaceID: i.WorkspaceID,
+		Email:       i.Email,
+		Role:        i.Role,
+		InvitedBy:   i.InvitedBy,
+		Status:      i.Status,
+		CreatedAt:   i.CreatedAt,
+		ExpiresAt:   i.ExpiresAt,
+	}
+	if i.RespondedAt.Valid {
+		inv.RespondedAt = &i.RespondedAt.Time
+	}
+	return inv
+}
+
+func (h *InvitationHandler) CreateInvitation(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	workspaceID := c.Params("workspaceid")
+
+	var req models.CreateInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "a valid email and a role of admin, member or guest are required"})
+	}
+	if req.Role == "" {
+		req.Role = string(authz.RoleMember)
+	}
+
+	workspace, err := h.WorkspaceRepo.GetWorkspaceByID(c.Context(), workspaceID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "workspace not found"})
+	}
+
+	if !authorize(c, h.Authz, authz.WorkspaceManageMembers, authz.ForWorkspace(workspaceID), "no permission to invite members") {
+		return nil
+	}
+	if req.Role == string(authz.RoleAdmin) {
+		if !authorize(c, h.Authz, authz.WorkspaceManageAdmins, authz.ForWorkspace(workspaceID), "only the owner can invite admins") {
+			return nil
+		}
+	}
+
+	// ผู้ใช้ที่เป็นสมาชิกอยู่แล้วไม่ต้องเชิญซ้ำ
+	if user, err := h.UserRepo.GetUserByEmail(c.Context(), req.Email); err == nil {
+		_, err := h.RoleRepo.GetWorkspaceRole(c.Context(), workspaceID, user.ID)
+		if err == nil {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "user is already a member of this workspace"})
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check membership"})
+		}
+	}
+
+	now := time.Now().UTC()
+	params := db.CreateInvitationParams{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		Email:       req.Email,
+		Role:        req.Role,
+		InvitedBy:   userID,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(InvitationTTL),
+	}
+
+	token, err := h.Tokens.SignInvitation(params.ID, params.Email, params.ExpiresAt)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create invitation token"})
+	}
+	params.TokenHash = hashInvitationToken(token)
+
+	if err := h.Repo.CreateInvitation(c.Context(), params); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create invitation"})
+	}
+
+	err = h.Mailer.Send(c.Context(), mail.Message{
+		To:      params.Email,
+		Subject: fmt.Sprintf("You have been invited to %s", workspace.Name),
+		Body: fmt.Sprintf(
+			"You have been invited to join the workspace %q as %s.\n\nAccept the invitation:\n%s/invitations/accept?token=%s\n\nThe invitation expires on %s.\n",
+			workspace.Name, params.Role, strings.TrimRight(h.BaseURL, "/"), url.QueryEscape(token), params.ExpiresAt.Format(time.RFC1123),
+		),
+	})
+	if err != nil {
+		log.Printf("Failed to send invitation %s: %v", params.ID, err)
+		if _, err := h.Repo.RevokeInvitation(c.Context(), params.ID, workspaceID); err != nil {
+			log.Printf("Failed to revoke invitation %s: %v", params.ID, err)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to send invitation email"})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":    "invitation sent successfully",
+		"invitation": toInvitation(db.WorkspaceInvitation{ID: params.ID, WorkspaceID: workspaceID, Email: params.Email, Role: params.Role, InvitedBy: userID, Status: "pending", CreatedAt: now, ExpiresAt: params.ExpiresAt}),
+	})
+}
+
+func (h *InvitationHandler) ListWorkspaceInvitations(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspaceid")
+
+	if !authorize(c, h.Authz, authz.WorkspaceManageMembers, authz.ForWorkspace(workspaceID), "no permission to view invitations") {
+		return nil
+	}
+
+	invitations, err := h.Repo.ListInvitationsByWorkspaceID(c.Context(), workspaceID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch invitations"})
+	}
+
+	res := make([]models.Invitation, 0, len(invitations))
+	for _, inv := range invitations {
+		res = append(res, toInvitation(inv))
+	}
+	return c.JSON(res)
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspaceid")
+	invitationID := c.Params("invitationid")
+
+	if !authorize(c, h.Authz, authz.WorkspaceManageMembers, authz.ForWorkspace(workspaceID), "no permission to revoke invitations") {
+		return nil
+	}
+
+	revoked, err := h.Repo.RevokeInvitation(c.Context(), invitationID, workspaceID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to revoke invitation"})
+	}
+	if !revoked {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "pending invitation not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "invitation revoked successfully"})
+}
+
+// ListMyInvitations returns the pending invitations sent to the email of the current user.
+func (h *InvitationHandler) ListMyInvitations(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	user, err := h.UserRepo.GetUserByID(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch user"})
+	}
+
+	invitations, err := h.Repo.ListPendingInvitationsByEmail(c.Context(), strings.ToLower(user.Email))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch invitations"})
+	}
+
+	now := time.Now()
+	res := make([]db.ListPendingInvitationsByEmailRow, 0, len(invitations))
+	for _, inv := range invitations {
+		if inv.ExpiresAt.After(now) {
+			res = append(res, inv)
+		}
+	}
+	return c.JSON(res)
+}
+
+// loadInvitationForUser verifies the token and checks that the invitation is
+// still pending and was sent to the current user. On failure the error
+// response is already written.
+func (h *InvitationHandler) loadInvitationForUser(c *fiber.Ctx) (db.WorkspaceInvitation, bool) {
+	userID := c.Locals("userID").(string)
+
+	var req models.InvitationTokenRequest
+	if err := c.BodyParser(&req); err != nil || validate.Struct(req) != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "token is required"})
+		return db.WorkspaceInvitation{}, false
+	}
+
+	invitationID, err := h.Tokens.ParseInvitation(req.Token)
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid or expired invitation token"})
+		return db.WorkspaceInvitation{}, false
+	}
+
+	invitation, err := h.Repo.GetInvitationByID(c.Context(), invitationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "invitation not found"})
+		} else {
+			c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch invitation"})
+		}
+		return db.WorkspaceInvitation{}, false
+	}
+	if invitation.TokenHash != hashInvitationToken(req.Token) {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid or expired invitation token"})
+		return db.WorkspaceInvitation{}, false
+	}
+	if invitation.Status != "pending" {
+		c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "invitation is no longer pending"})
+		return db.WorkspaceInvitation{}, false
+	}
+	if !invitation.ExpiresAt.After(time.Now()) {
+		c.Status(fiber.StatusGone).JSON(fiber.Map{"error": "invitation has expired"})
+		return db.WorkspaceInvitation{}, false
+	}
+
+	user, err := h.UserRepo.GetUserByID(c.Context(), userID)
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch user"})
+		return db.WorkspaceInvitation{}, false
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "this invitation was sent to a different email address"})
+		return db.WorkspaceInvitation{}, false
+	}
+
+	return invitation, true
+}
+
+func (h *InvitationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	invitation, ok := h.loadInvitationForUser(c)
+	if !ok {
+		return nil
+	}
+
+	accepted, err := h.Repo.AcceptInvitation(c.Context(), invitation, userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to accept invitation"})
+	}
+	if !accepted {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "invitation is no longer pending"})
+	}
+
+	ws.BroadcastToRoom("workspace", invitation.WorkspaceID, "workspace_member_joined", fiber.Map{
+		"user_id": userID,
+		"role":    invitation.Role,
+	})
+
+	return c.JSON(fiber.Map{"message": "invitation accepted", "workspace_id": invitation.WorkspaceID})
+}
+
+func (h *InvitationHandler) DeclineInvitation(c *fiber.Ctx) error {
+	invitation, ok := h.loadInvitationForUser(c)
+	if !ok {
+		return nil
+	}
+
+	declined, err := h.Repo.DeclineInvitation(c.Context(), invitation)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to decline invitation"})
+	}
+	if !declined {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "invitation is no longer pending"})
+	}
+
+	return c.JSON(fiber.Map{"message": "invitation declined"})
+}
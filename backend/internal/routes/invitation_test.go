@@ -0,0 +1,321 @@
+package routes_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nack098/nakumanager/internal/db"
+	"github.com/nack098/nakumanager/internal/mail"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+)
+
+type fakeInvitationTokens struct{}
+
+func (fakeInvitationTokens) SignInvitation(invitationID, email string, expiresAt time.Time) (string, error) {
+	return "token-" + invitationID, nil
+}
+
+func (fakeInvitationTokens) ParseInvitation(token string) (string, error) {
+	if !strings.HasPrefix(token, "token-") {
+		return "", errors.New("invalid token")
+	}
+	return strings.TrimPrefix(token, "token-"), nil
+}
+
+type recordingSender struct {
+	sent []mail.Message
+	err  error
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg mail.Message) error {
+	s.sent = append(s.sent, msg)
+	return s.err
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type invitationMocks struct {
+	repo      *mocks.MockInvitationRepo
+	workspace *mocks.MockWorkspaceRepo
+	user      *mocks.MockUserRepo
+	role      *mocks.MockRoleRepo
+	sender    *recordingSender
+}
+
+func newInvitationTestApp(userID string) (*fiber.App, invitationMocks) {
+	m := invitationMocks{
+		repo:      new(mocks.MockInvitationRepo),
+		workspace: new(mocks.MockWorkspaceRepo),
+		user:      new(mocks.MockUserRepo),
+		role:      new(mocks.MockRoleRepo),
+		sender:    &recordingSender{},
+	}
+	h := routes.NewInvitationHandler(m.repo, m.workspace, m.user, m.role, fakeInvitationTokens{}, m.sender)
+	h.BaseURL = "https://app.example.com"
+
+	app := fiber.New()
+	app.Use(withUserID(userID))
+	app.Post("/workspace/:workspaceid/invitations", h.CreateInvitation)
+	app.Get("/workspace/:workspaceid/invitations", h.ListWorkspaceInvitations)
+	app.Delete("/workspace/:workspaceid/invitations/:invitationid", h.RevokeInvitation)
+	app.Get("/invitations", h.ListMyInvitations)
+	app.Post("/invitations/accept", h.AcceptInvitation)
+	app.Post("/invitations/decline", h.DeclineInvitation)
+	return app, m
+}
+
+func doJSON(t *testing.T, app *fiber.App, method, url, body string) (int, string) {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	b, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+func TestCreateInvitation(t *testing.T) {
+	t.Run("admin invites by email", func(t *testing.T) {
+		app, m := newInvitationTestApp("user-1")
+		m.workspace.On("GetWorkspaceByID", mock.Anything, "ws-1").Return(db.Workspace{ID: "ws-1", Name: "Acme"}, nil)
+		m.role.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-1").Return("admin", nil)
+		m.user.On("GetUserByEmail", mock.Anything, "bob@example.com").Return(nil, sql.ErrNoRows)
+		m.repo.On("CreateInvitation", mock.Anything, mock.MatchedBy(func(p db.CreateInvitationParams) bool {
+			return p.Email == "bob@example.com" && p.Role == "member" && p.InvitedBy == "user-1" &&
+				p.TokenHash == hashToken("token-"+p.ID) && p.ExpiresAt.After(p.CreatedAt)
+		})).Return(nil)
+
+		status, body := doJSON(t, app, http.MethodPost, "/workspace/ws-1/invitations", `{"email":" Bob@Example.com "}`)
+
+		assert.Equal(t, fiber.StatusCreated, status)
+		assert.Contains(t, body, "invitation sent successfully")
+		assert.NotContains(t, body, "token")
+		require.Len(t, m.sender.sent, 1)
+		assert.Equal(t, "bob@example.com", m.sender.sent[0].To)
+		assert.Contains(t, m.sender.sent[0].Body, "https://app.example.com/invitations/accept?token=token-")
+		m.repo.AssertExpectations(t)
+	})
+
+	t.Run("invalid email", func(t *testing.T) {
+		app, _ := newInvitationTestApp("user-1")
+		status, _ := doJSON(t, app, http.MethodPost, "/workspace/ws-1/invitations", `{"email":"not-an-email"}`)
+		assert.Equal(t, fiber.StatusBadRequest, status)
+	})
+
+	t.Run("owner role cannot be invited", func(t *testing.T) {
+		app, _ := newInvitationTestApp("user-1")
+		status, _ := doJSON(t, app, http.MethodPost, "/workspace/ws-1/invitations", `{"email":"bob@example.com","role":"owner"}`)
+		assert.Equal(t, fiber.StatusBadRequest, status)
+	})
+
+	t.Run("member cannot invite", func(t *testing.T) {
+		app, m := newInvitationTestApp("user-1")
+		m.workspace.On("GetWorkspaceByID", mock.Anything, "ws-1").Return(db.Workspace{ID: "ws-1"}, nil)
+		m.role.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-1").Return("member", nil)
+
+		status, _ := doJSON(t, app, http.MethodPost, "/workspace/ws-1/invitations", `{"email":"bob@example.com"}`)
+		assert.Equal(t, fiber.StatusForbidden, status)
+		assert.Empty(t, m.sender.sent)
+	})
+
+	t.Run("admin cannot invite admins", func(t *testing.T) {
+		app, m := newInvitationTestApp("user-1")
+		m.workspace.On("GetWorkspaceByID", mock.Anything, "ws-1").Return(db.Workspace{ID: "ws-1"}, nil)
+		m.role.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-1").Return("admin", nil)
+
+		status, body := doJSON(t, app, http.MethodPost, "/workspace/ws-1/invitations", `{"email":"bob@example.com","role":"admin"}`)
+		assert.Equal(t, fiber.StatusForbidden, status)
+		assert.Contains(t, body, "only the owner can invite admins")
+	})
+
+	t.Run("already a member", func(t *testing.T) {
+		app, m := newInvitationTestApp("user-1")
+		m.workspace.On("GetWorkspaceByID", mock.Anything, "ws-1").Return(db.Workspace{ID: "ws-1"}, nil)
+		m.role.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-1").Return("owner", nil)
+		m.user.On("GetUserByEmail", mock.Anything, "bob@example.com").Return(db.GetUserByEmailWithoutPasswordRow{ID: "user-2"}, nil)
+		m.role.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-2").Return("guest", nil)
+
+		status, _ := doJSON(t, app, http.MethodPost, "/workspace/ws-1/invitations", `{"email":"bob@example.com"}`)
+		assert.Equal(t, fiber.StatusConflict, status)
+	})
+
+	t.Run("mail failure revokes the invitation", func(t *testing.T) {
+		app, m := newInvitationTestApp("user-1")
+		m.sender.err = errors.New("smtp down")
+		m.workspace.On("GetWorkspaceByID", mock.Anything, "ws-1").Return(db.Workspace{ID: "ws-1"}, nil)
+		m.role.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-1").Return("owner", nil)
+		m.user.On("GetUserByEmail", mock.Anything, "bob@example.com").Return(nil, sql.ErrNoRows)
+		m.repo.On("CreateInvitation", mock.Anything, mock.Anything).Return(nil)
+		m.repo.On("RevokeInvitation", mock.Anything, mock.Anything, "ws-1").Return(true, nil)
+
+		status, _ := doJSON(t, app, http.MethodPost, "/workspace/ws-1/invitations", `{"email":"bob@example.com"}`)
+		assert.Equal(t, fiber.StatusInternalServerError, status)
+		m.repo.AssertExpectations(t)
+	})
+}
+
+func TestRespondToInvitation(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	pending := func() db.WorkspaceInvitation {
+		return db.WorkspaceInvitation{
+			ID:          "inv-1",
+			WorkspaceID: "ws-1",
+			Email:       "bob@example.com",
+			Role:        "guest",
+			TokenHash:   hashToken("token-inv-1"),
+			Status:      "pending",
+			ExpiresAt:   expiresAt,
+		}
+	}
+	bob := db.GetUserByIDRow{ID: "user-2", Email: "Bob@example.com"}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		invitation func() db.WorkspaceInvitation
+		user       db.GetUserByIDRow
+		setupMocks func(m invitationMocks)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "accept",
+			path:       "/invitations/accept",
+			body:       `{"token":"token-inv-1"}`,
+			invitation: pending,
+			user:       bob,
+			setupMocks: func(m invitationMocks) {
+				m.repo.On("AcceptInvitation", mock.Anything, pending(), "user-2").Return(true, nil)
+			},
+			wantStatus: fiber.StatusOK,
+			wantBody:   "invitation accepted",
+		},
+		{
+			name:       "decline",
+			path:       "/invitations/decline",
+			body:       `{"token":"token-inv-1"}`,
+			invitation: pending,
+			user:       bob,
+			setupMocks: func(m invitationMocks) {
+				m.repo.On("DeclineInvitation", mock.Anything, pending()).Return(true, nil)
+			},
+			wantStatus: fiber.StatusOK,
+			wantBody:   "invitation declined",
+		},
+		{
+			name:       "missing token",
+			path:       "/invitations/accept",
+			body:       `{}`,
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "bad signature",
+			path:       "/invitations/accept",
+			body:       `{"token":"forged"}`,
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "token does not match the stored hash",
+			path:       "/invitations/accept",
+			body:       `{"token":"token-inv-1"}`,
+			invitation: func() db.WorkspaceInvitation { i := pending(); i.TokenHash = hashToken("older"); return i },
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "already used",
+			path:       "/invitations/accept",
+			body:       `{"token":"token-inv-1"}`,
+			invitation: func() db.WorkspaceInvitation { i := pending(); i.Status = "accepted"; return i },
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:       "expired",
+			path:       "/invitations/accept",
+			body:       `{"token":"token-inv-1"}`,
+			invitation: func() db.WorkspaceInvitation { i := pending(); i.ExpiresAt = time.Now().Add(-time.Minute); return i },
+			wantStatus: fiber.StatusGone,
+		},
+		{
+			name:       "sent to someone else",
+			path:       "/invitations/accept",
+			body:       `{"token":"token-inv-1"}`,
+			invitation: pending,
+			user:       db.GetUserByIDRow{ID: "user-2", Email: "eve@example.com"},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:       "used concurrently",
+			path:       "/invitations/accept",
+			body:       `{"token":"token-inv-1"}`,
+			invitation: pending,
+			user:       bob,
+			setupMocks: func(m invitationMocks) {
+				m.repo.On("AcceptInvitation", mock.Anything, pending(), "user-2").Return(false, nil)
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, m := newInvitationTestApp("user-2")
+			if tt.invitation != nil {
+				m.repo.On("GetInvitationByID", mock.Anything, "inv-1").Return(tt.invitation(), nil)
+			}
+			m.user.On("GetUserByID", mock.Anything, "user-2").Return(tt.user, nil).Maybe()
+			if tt.setupMocks != nil {
+				tt.setupMocks(m)
+			}
+
+			status, body := doJSON(t, app, http.MethodPost, tt.path, tt.body)
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Contains(t, body, tt.wantBody)
+			m.repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevokeInvitation(t *testing.T) {
+	app, m := newInvitationTestApp("user-1")
+	m.role.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-1").Return("admin", nil)
+	m.repo.On("RevokeInvitation", mock.Anything, "inv-1", "ws-1").Return(true, nil)
+	m.repo.On("RevokeInvitation", mock.Anything, "inv-2", "ws-1").Return(false, nil)
+
+	status, _ := doJSON(t, app, http.MethodDelete, "/workspace/ws-1/invitations/inv-1", "")
+	assert.Equal(t, fiber.StatusOK, status)
+
+	status, _ = doJSON(t, app, http.MethodDelete, "/workspace/ws-1/invitations/inv-2", "")
+	assert.Equal(t, fiber.StatusNotFound, status)
+}
+
+func TestListMyInvitations(t *testing.T) {
+	app, m := newInvitationTestApp("user-2")
+	m.user.On("GetUserByID", mock.Anything, "user-2").Return(db.GetUserByIDRow{ID: "user-2", Email: "Bob@Example.com"}, nil)
+	m.repo.On("ListPendingInvitationsByEmail", mock.Anything, "bob@example.com").Return([]db.ListPendingInvitationsByEmailRow{
+		{ID: "inv-1", WorkspaceName: "Acme", ExpiresAt: time.Now().Add(time.Hour)},
+		{ID: "inv-old", WorkspaceName: "Old", ExpiresAt: time.Now().Add(-time.Hour)},
+	}, nil)
+
+	status, body := doJSON(t, app, http.MethodGet, "/invitations", "")
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Contains(t, body, "inv-1")
+	assert.NotContains(t, body, "inv-old")
+}
@@ -1,33 +1,49 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/go-playground/validator"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
 	"github.com/nack098/nakumanager/internal/db"
 	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/rank"
 	"github.com/nack098/nakumanager/internal/repositories"
 	"github.com/nack098/nakumanager/internal/ws"
 )
 
 type IssueHandler struct {
-	DB          *sql.DB
-	Repo        repositories.IssueRepository
-	TeamRepo    repositories.TeamRepository
-	ProjectRepo repositories.ProjectRepository
+	DB           *sql.DB
+	Repo         repositories.IssueRepository
+	TeamRepo     repositories.TeamRepository
+	ProjectRepo  repositories.ProjectRepository
+	CommentRepo  repositories.CommentRepository
+	LabelRepo    repositories.LabelRepository
+	RelationRepo repositories.RelationRepository
+	CycleRepo    repositories.CycleRepository
+	TemplateRepo repositories.IssueTemplateRepository
+	Authz        *authz.Authorizer
 }
 
-func NewIssueHandler(db *sql.DB, repo repositories.IssueRepository, teamRepo repositories.TeamRepository, projectRepo repositories.ProjectRepository) *IssueHandler {
+func NewIssueHandler(db *sql.DB, repo repositories.IssueRepository, teamRepo repositories.TeamRepository, projectRepo repositories.ProjectRepository, commentRepo repositories.CommentRepository, labelRepo repositories.LabelRepository, relationRepo repositories.RelationRepository, cycleRepo repositories.CycleRepository, templateRepo repositories.IssueTemplateRepository, roleRepo repositories.RoleRepository) *IssueHandler {
 	return &IssueHandler{
-		DB:          db,
-		Repo:        repo,
-		TeamRepo:    teamRepo,
-		ProjectRepo: projectRepo,
+		DB:           db,
+		Repo:         repo,
+		TeamRepo:     teamRepo,
+		ProjectRepo:  projectRepo,
+		CommentRepo:  commentRepo,
+		LabelRepo:    labelRepo,
+		RelationRepo: relationRepo,
+		CycleRepo:    cycleRepo,
+		TemplateRepo: templateRepo,
+		Authz:        authz.NewAuthorizer(roleRepo),
 	}
 }
 
@@ -37,72 +53,134 @@ func (h *IssueHandler) CreateIssue(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 	}
 
-	userID := c.Locals("userID").(string)
+	issueID, identifier, err := h.CreateIssueAs(c.Context(), c.Locals("userID").(string), issueReq, c.Query("template"))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":    "Issue created successfully",
+		"issueID":    issueID,
+		"identifier": identifier,
+	})
+}
+
+// CreateIssueAs creates an issue on behalf of the user with the checks and
+// defaults of the API, so background jobs create issues the same way as
+// CreateIssue. templateID works as the ?template= param. It returns the ID and
+// the identifier of the issue. Errors the client should see are StatusErrors.
+func (h *IssueHandler) CreateIssueAs(ctx context.Context, userID string, issueReq models.IssueCreate, templateID string) (string, string, error) {
 	issueReq.OwnerID = userID
 
-	if err := validator.New().Struct(&issueReq); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":  "Validation failed",
-			"detail": err.Error(),
-		})
+	// ค่าจาก template ใช้เฉพาะช่องที่ไม่ได้ส่งมา
+	if templateID != "" {
+		if err := h.applyTemplate(ctx, userID, templateID, &issueReq); err != nil {
+			return "", "", err
+		}
 	}
 
-	ctx := c.Context()
+	if err := validator.New().Struct(&issueReq); err != nil {
+		return "", "", &StatusError{Status: fiber.StatusBadRequest, Message: "Validation failed", Detail: err.Error()}
+	}
 
 	// ตรวจสอบทีม
 	teamExists, err := h.TeamRepo.IsTeamExists(ctx, issueReq.TeamID)
 	if err != nil || !teamExists {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Team not found"})
+		return "", "", &StatusError{Status: fiber.StatusBadRequest, Message: "Team not found"}
 	}
 
-	isMember, err := h.TeamRepo.IsMemberInTeam(ctx, issueReq.TeamID, userID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check team membership"})
-	}
-	if !isMember {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "you are not a member of the team"})
+	if err := allow(ctx, h.Authz, userID, authz.IssueCreate, authz.ForTeam(issueReq.TeamID), "you are not a member of the team"); err != nil {
+		return "", "", err
 	}
 
-	// ตรวจสอบโปรเจกต์
-	if issueReq.ProjectID != nil {
-		projectExists, err := h.ProjectRepo.IsProjectExists(ctx, *issueReq.ProjectID)
-		if err != nil || !projectExists {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Project not found"})
+	// โปรเจกต์ต้องเป็นของทีมเดียวกับ issue และผู้สร้างต้องอยู่ในโปรเจกต์
+	if issueReq.ProjectID != nil && *issueReq.ProjectID != "" {
+		project, err := projectOfTeam(ctx, h.ProjectRepo, h.TeamRepo, *issueReq.ProjectID, issueReq.TeamID)
+		if err != nil {
+			return "", "", err
+		}
+		if err := h.canAddToProject(ctx, project, userID); err != nil {
+			return "", "", err
 		}
 	}
 
 	// กำหนดค่า default
 	if issueReq.Status == "" {
-		issueReq.Status = "todo"
+		status, err := h.TeamRepo.GetDefaultTeamStatus(ctx, issueReq.TeamID)
+		if err != nil {
+			log.Printf("Failed to get the default status of team %s: %v", issueReq.TeamID, err)
+			return "", "", errCreateIssue
+		}
+		issueReq.Status = status
+	} else if _, err := h.teamStatus(ctx, issueReq.TeamID, issueReq.Status); err != nil {
+		return "", "", err
+	}
+	if issueReq.Labels != nil {
+		if err := usableLabels(ctx, h.LabelRepo, issueReq.TeamID, *issueReq.Labels); err != nil {
+			return "", "", err
+		}
+	}
+	if issueReq.ParentID != nil && *issueReq.ParentID != "" {
+		parentID, err := h.parentOf(ctx, userID, "", *issueReq.ParentID)
+		if err != nil {
+			return "", "", err
+		}
+		issueReq.ParentID = &parentID
+	}
+	if issueReq.CycleID != nil && *issueReq.CycleID != "" {
+		if err := h.openTeamCycle(ctx, issueReq.TeamID, *issueReq.CycleID); err != nil {
+			return "", "", err
+		}
+	}
+	if issueReq.MilestoneID != nil && *issueReq.MilestoneID != "" {
+		projectID := ""
+		if issueReq.ProjectID != nil {
+			projectID = *issueReq.ProjectID
+		}
+		if err := h.projectMilestone(ctx, projectID, *issueReq.MilestoneID); err != nil {
+			return "", "", err
+		}
 	}
 	if issueReq.Priority == nil {
 		def := "low"
 		issueReq.Priority = &def
 	}
+	now := time.Now().UTC()
 	if issueReq.StartDate == nil {
-		now := time.Now().UTC()
 		issueReq.StartDate = &now
 	}
 
 	// สร้าง issue
 	issueReq.ID = uuid.New().String()
+
+	number, identifier, err := h.Repo.NextIssueNumber(ctx, issueReq.TeamID)
+	if err != nil {
+		log.Printf("Failed to number issue in team %s: %v", issueReq.TeamID, err)
+		return "", "", errCreateIssue
+	}
+
 	body := db.CreateIssueParams{
-		ID:        issueReq.ID,
-		Title:     issueReq.Title,
-		Content:   ToNullString(issueReq.Content),
-		Priority:  ToNullString(issueReq.Priority),
-		Status:    issueReq.Status,
-		ProjectID: ToNullString(issueReq.ProjectID),
-		TeamID:    issueReq.TeamID,
-		StartDate: ToNullTime(issueReq.StartDate),
-		EndDate:   ToNullTime(issueReq.EndDate),
-		Label:     ToNullString(issueReq.Label),
-		OwnerID:   issueReq.OwnerID,
-	}
-
-	if err := h.Repo.CreateIssue(ctx, body); err != nil {
+		ID:          issueReq.ID,
+		Title:       issueReq.Title,
+		Content:     ToNullString(issueReq.Content),
+		Priority:    ToNullString(issueReq.Priority),
+		Status:      issueReq.Status,
+		ProjectID:   ToNullString(issueReq.ProjectID),
+		TeamID:      issueReq.TeamID,
+		StartDate:   ToNullTime(issueReq.StartDate),
+		EndDate:     ToNullTime(issueReq.EndDate),
+		OwnerID:     issueReq.OwnerID,
+		Number:      number,
+		Identifier:  identifier,
+		ParentID:    ToNullString(issueReq.ParentID),
+		CycleID:     ToNullString(issueReq.CycleID),
+		MilestoneID: ToNullString(issueReq.MilestoneID),
+	}
+	body.CycleAddedAt = sql.NullTime{Time: now, Valid: body.CycleID.Valid}
+
+	if err := h.insertIssueLast(ctx, body); err != nil {
 		log.Printf("Failed to create issue: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create issue"})
+		return "", "", errCreateIssue
 	}
 
 	// เพิ่ม assignees ถ้ามี
@@ -127,10 +205,127 @@ func (h *IssueHandler) CreateIssue(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"message": "Issue created successfully",
-		"issueID": issueReq.ID,
+	if issueReq.Labels != nil {
+		for _, labelID := range *issueReq.Labels {
+			if err := h.LabelRepo.AddLabelToIssue(ctx, issueReq.ID, labelID); err != nil {
+				log.Printf("Failed to add label %s: %v", labelID, err)
+			}
+		}
+	}
+
+	return issueReq.ID, identifier, nil
+}
+
+// insertIssueLast creates the issue at the end of its column on the team's
+// board. The last rank is read in the transaction of the insert, so issues
+// created at the same time don't get the same rank.
+func (h *IssueHandler) insertIssueLast(ctx context.Context, body db.CreateIssueParams) error {
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	last, err := h.Repo.GetLastIssueBoardRankTx(ctx, tx, db.GetLastIssueBoardRankParams{
+		TeamID: body.TeamID,
+		Status: body.Status,
+		ID:     body.ID,
 	})
+	if err != nil {
+		return err
+	}
+	body.Rank, err = rank.Between(last, "")
+	if err != nil {
+		return err
+	}
+	if err := h.Repo.CreateIssueTx(ctx, tx, body); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+var errCreateIssue = &StatusError{Status: fiber.StatusInternalServerError, Message: "Failed to create issue"}
+
+// lookupStatus returns the workflow status of the team. It writes a 400
+// response and returns false when status is not one of them.
+func (h *IssueHandler) lookupStatus(c *fiber.Ctx, teamID, status string) (db.TeamStatus, bool) {
+	s, err := h.teamStatus(c.Context(), teamID, status)
+	return s, check(c, err)
+}
+
+// teamStatus returns the workflow status of the team. It fails with 400 when
+// status is not one of them.
+func (h *IssueHandler) teamStatus(ctx context.Context, teamID, status string) (db.TeamStatus, error) {
+	s, err := h.TeamRepo.GetTeamStatus(ctx, teamID, status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s, &StatusError{Status: fiber.StatusBadRequest, Message: fmt.Sprintf("status %s does not exist in the team", status)}
+		}
+		log.Printf("Failed to get status %s of team %s: %v", status, teamID, err)
+		return s, &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to check status"}
+	}
+	return s, nil
+}
+
+// checkProject checks that issues of the team can be put in the project. On
+// failure the error response is already written.
+func (h *IssueHandler) checkProject(c *fiber.Ctx, projectID, teamID string) (db.Project, bool) {
+	return checkProjectTeam(c, h.ProjectRepo, h.TeamRepo, projectID, teamID)
+}
+
+// checkProjectTeam checks that the project exists and belongs to the team, and
+// so to the team's workspace. On failure the error response is already
+// written.
+func checkProjectTeam(c *fiber.Ctx, projectRepo repositories.ProjectRepository, teamRepo repositories.TeamRepository, projectID, teamID string) (db.Project, bool) {
+	project, err := projectOfTeam(c.Context(), projectRepo, teamRepo, projectID, teamID)
+	return project, check(c, err)
+}
+
+// projectOfTeam returns the project after checking that it exists and belongs
+// to the team, and so to the team's workspace.
+func projectOfTeam(ctx context.Context, projectRepo repositories.ProjectRepository, teamRepo repositories.TeamRepository, projectID, teamID string) (db.Project, error) {
+	project, err := projectRepo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return project, &StatusError{Status: fiber.StatusBadRequest, Message: "Project not found"}
+		}
+		log.Printf("Failed to get project %s: %v", projectID, err)
+		return project, &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to check project"}
+	}
+	if project.TeamID == teamID {
+		return project, nil
+	}
+
+	team, err := teamRepo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		log.Printf("Failed to get team %s: %v", teamID, err)
+		return project, &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to check team"}
+	}
+	if team.WorkspaceID != project.WorkspaceID {
+		return project, &StatusError{Status: fiber.StatusBadRequest, Message: fmt.Sprintf("project %s belongs to another workspace than team %s", project.Name, team.Name)}
+	}
+	return project, &StatusError{Status: fiber.StatusBadRequest, Message: fmt.Sprintf("project %s belongs to another team than %s", project.Name, team.Name)}
+}
+
+// checkProjectMember checks that the user may add issues to the project. On
+// failure the error response is already written.
+func (h *IssueHandler) checkProjectMember(c *fiber.Ctx, project db.Project, userID string) bool {
+	return check(c, h.canAddToProject(c.Context(), project, userID))
+}
+
+// canAddToProject checks that the user may add issues to the project: a
+// member of it, or someone who may update it such as its creator, its leader
+// or a lead of its team.
+func (h *IssueHandler) canAddToProject(ctx context.Context, project db.Project, userID string) error {
+	member, err := h.ProjectRepo.IsProjectMember(ctx, project.ID, userID)
+	if err != nil {
+		log.Printf("Failed to check member %s of project %s: %v", userID, project.ID, err)
+		return &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to check project members"}
+	}
+	if member {
+		return nil
+	}
+	return allow(ctx, h.Authz, userID, authz.ProjectUpdate, authz.ForProject(project), fmt.Sprintf("you are not a member of project %s", project.Name))
 }
 
 func (h *IssueHandler) UpdateIssue(c *fiber.Ctx) error {
@@ -147,6 +342,10 @@ func (h *IssueHandler) UpdateIssue(c *fiber.Ctx) error {
 			"error": "missing issue ID",
 		})
 	}
+	var ok bool
+	if req.ID, ok = h.resolveIssueID(c, req.ID); !ok {
+		return nil
+	}
 
 	userID := c.Locals("userID").(string)
 
@@ -164,20 +363,130 @@ func (h *IssueHandler) UpdateIssue(c *fiber.Ctx) error {
 		})
 	}
 
-	isOwner := issue.OwnerID == userID
-	isTeamMember, err := h.TeamRepo.IsMemberInTeam(ctx, issue.TeamID, userID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to check team membership",
-		})
+	if !authorize(c, h.Authz, authz.IssueUpdate, authz.ForIssue(issue), "you are not authorized to update this issue") {
+		return nil
 	}
 
-	if !isOwner && !isTeamMember {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "you are not authorized to update this issue",
-		})
+	if req.ParentID != nil && *req.ParentID != "" {
+		parentID, ok := h.checkParent(c, issue.ID, *req.ParentID)
+		if !ok {
+			return nil
+		}
+		req.ParentID = &parentID
+	}
+
+	// cycle ต้องเป็นของทีมที่ issue จะอยู่หลังแก้ไข issue ที่ย้ายทีมจึงออกจาก cycle ของทีมเดิม
+	targetTeamID := issue.TeamID
+	if req.TeamID != nil {
+		targetTeamID = *req.TeamID
+	}
+	if req.CycleID != nil && *req.CycleID != "" {
+		if *req.CycleID == issue.CycleID.String && targetTeamID == issue.TeamID {
+			req.CycleID = nil
+		} else if !h.checkCycle(c, targetTeamID, *req.CycleID) {
+			return nil
+		}
+	} else if req.CycleID == nil && targetTeamID != issue.TeamID && issue.CycleID.Valid {
+		none := ""
+		req.CycleID = &none
+	}
+
+	// โปรเจกต์ต้องเป็นของทีมที่ issue จะอยู่หลังแก้ไข ย้ายเข้าโปรเจกต์ใหม่ได้เฉพาะสมาชิกของโปรเจกต์นั้น
+	projectID := issue.ProjectID.String
+	if req.ProjectID != nil {
+		projectID = *req.ProjectID
+	}
+	if projectID != "" && (projectID != issue.ProjectID.String || targetTeamID != issue.TeamID) {
+		project, ok := h.checkProject(c, projectID, targetTeamID)
+		if !ok {
+			return nil
+		}
+		if projectID != issue.ProjectID.String && !h.checkProjectMember(c, project, userID) {
+			return nil
+		}
+	}
+
+	// milestone ต้องเป็นของโปรเจกต์ที่ issue จะอยู่หลังแก้ไข
+	if req.MilestoneID != nil && *req.MilestoneID != "" {
+		if *req.MilestoneID == issue.MilestoneID.String && projectID == issue.ProjectID.String {
+			req.MilestoneID = nil
+		} else if !h.checkMilestone(c, projectID, *req.MilestoneID) {
+			return nil
+		}
+	} else if req.MilestoneID == nil && projectID != issue.ProjectID.String && issue.MilestoneID.Valid {
+		none := ""
+		req.MilestoneID = &none
+	}
+
+	// status ต้องมีอยู่ในทีมที่ issue จะอยู่หลังแก้ไข
+	var cascade *statusCascade
+	closing := false
+	if req.Status != nil || req.TeamID != nil {
+		teamID, status := issue.TeamID, issue.Status
+		if req.TeamID != nil {
+			teamID = *req.TeamID
+		}
+		if req.Status != nil {
+			status = *req.Status
+		}
+		target, ok := h.lookupStatus(c, teamID, status)
+		if !ok {
+			return nil
+		}
+		if status != issue.Status && !h.checkTransition(c, issue, target, userID, req.AddAssignee, req.RemoveAssignee, req.EndDate != nil || issue.EndDate.Valid) {
+			return nil
+		}
+		closing = status != issue.Status && isClosedCategory(target.Category)
+		if req.CascadeStatus && status != issue.Status {
+			children, err := h.cascadeTargets(ctx, issue, target, userID)
+			if err != nil {
+				log.Printf("Failed to collect sub-issues of %s: %v", issue.ID, err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "failed to fetch sub-issues",
+				})
+			}
+			cascade = &statusCascade{Status: status, Issues: children}
+		}
 	}
 
+	var currentAssignees map[string]bool
+	if req.AddAssignee != nil || req.RemoveAssignee != nil {
+		assignees, err := h.Repo.ListAssigneesByIssueID(ctx, issue.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to fetch assignees",
+			})
+		}
+		currentAssignees = make(map[string]bool, len(assignees))
+		for _, assignee := range assignees {
+			currentAssignees[assignee.ID] = true
+		}
+	}
+
+	var currentLabels map[string]bool
+	if req.AddLabel != nil || req.RemoveLabel != nil {
+		teamID := issue.TeamID
+		if req.TeamID != nil {
+			teamID = *req.TeamID
+		}
+		if req.AddLabel != nil && !checkLabels(c, h.LabelRepo, teamID, *req.AddLabel) {
+			return nil
+		}
+		labels, err := h.LabelRepo.ListLabelsByIssueID(ctx, issue.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to fetch labels",
+			})
+		}
+		currentLabels = make(map[string]bool, len(labels))
+		for _, label := range labels {
+			currentLabels[label.ID] = true
+		}
+	}
+
+	changes := diffIssue(issue, req)
+
+	// ผู้รับผิดชอบและ label ที่เปลี่ยนจะถูกเขียนใน transaction เดียวกับ event ใน applyIssueUpdate
 	if req.AddAssignee != nil {
 		for _, assigneeID := range *req.AddAssignee {
 			valid, err := h.TeamRepo.IsMemberInTeam(ctx, issue.TeamID, assigneeID)
@@ -189,14 +498,14 @@ func (h *IssueHandler) UpdateIssue(c *fiber.Ctx) error {
 				log.Printf("User %s is not a member of the team %s", assigneeID, issue.TeamID)
 				continue
 			}
-			if err := h.Repo.AddAssigneeToIssue(ctx, db.AddAssigneeToIssueParams{
-				IssueID: issue.ID,
-				UserID:  assigneeID,
-			}); err != nil {
-				log.Printf("Error adding assignee %s: %v", assigneeID, err)
-			} else {
-				log.Printf("Successfully added assignee %s to issue %s", assigneeID, issue.ID)
+			if currentAssignees[assigneeID] {
+				continue
 			}
+			currentAssignees[assigneeID] = true
+			changes = append(changes, issueFieldChange{
+				Field: "assignees",
+				New:   sql.NullString{String: assigneeID, Valid: true},
+			})
 		}
 	}
 
@@ -211,20 +520,64 @@ func (h *IssueHandler) UpdateIssue(c *fiber.Ctx) error {
 				log.Printf("User %s is not a member of the team %s", assigneeID, issue.TeamID)
 				continue
 			}
-			if err := h.Repo.RemoveAssigneeFromIssue(ctx, db.RemoveAssigneeFromIssueParams{
-				IssueID: issue.ID,
-				UserID:  assigneeID,
-			}); err != nil {
-				log.Printf("Error removing assignee %s: %v", assigneeID, err)
-			} else {
-				log.Printf("Successfully removed assignee %s from issue %s", assigneeID, issue.ID)
+			if !currentAssignees[assigneeID] {
+				continue
+			}
+			delete(currentAssignees, assigneeID)
+			changes = append(changes, issueFieldChange{
+				Field: "assignees",
+				Old:   sql.NullString{String: assigneeID, Valid: true},
+			})
+		}
+	}
+
+	if req.AddLabel != nil {
+		for _, labelID := range *req.AddLabel {
+			if currentLabels[labelID] {
+				continue
 			}
+			currentLabels[labelID] = true
+			changes = append(changes, issueFieldChange{
+				Field: "labels",
+				New:   sql.NullString{String: labelID, Valid: true},
+			})
 		}
 	}
 
-	query, args := buildUpdateIssueQuery(req)
-	if query != "" {
-		if _, err := h.DB.ExecContext(ctx, query, args...); err != nil {
+	if req.RemoveLabel != nil {
+		for _, labelID := range *req.RemoveLabel {
+			if !currentLabels[labelID] {
+				continue
+			}
+			delete(currentLabels, labelID)
+			changes = append(changes, issueFieldChange{
+				Field: "labels",
+				Old:   sql.NullString{String: labelID, Valid: true},
+			})
+		}
+	}
+
+	// issue ที่ย้ายทีมได้เลขใหม่ต่อจาก issue ของทีมปลายทาง
+	var renumber *issueNumber
+	if req.TeamID != nil && *req.TeamID != issue.TeamID {
+		number, identifier, err := h.Repo.NextIssueNumber(ctx, *req.TeamID)
+		if err != nil {
+			log.Printf("Failed to number issue %s in team %s: %v", issue.ID, *req.TeamID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "failed to update issue",
+			})
+		}
+		renumber = &issueNumber{Number: number, Identifier: identifier}
+		changes = append(changes, issueFieldChange{
+			Field: "identifier",
+			Old:   sql.NullString{String: issue.Identifier, Valid: true},
+			New:   sql.NullString{String: identifier, Valid: true},
+		})
+	}
+
+	query, args := buildUpdateIssueQuery(req, renumber, time.Now().UTC())
+	if query != "" || len(changes) > 0 {
+		if err := h.applyIssueUpdate(ctx, issue.ID, userID, query, args, changes, cascade); err != nil {
 			log.Println("Update issue failed:", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"error": "failed to update issue",
@@ -233,12 +586,26 @@ func (h *IssueHandler) UpdateIssue(c *fiber.Ctx) error {
 	}
 
 	ws.BroadcastToRoom("issue", req.ID, "issue_updated", req)
+	if cascade != nil {
+		for _, child := range cascade.Issues {
+			ws.BroadcastToRoom("issue", child.ID, "issue_updated", models.UpdateIssueRequest{ID: child.ID, Status: &cascade.Status})
+		}
+	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+	resp := fiber.Map{
 		"message": "issue updated successfully",
-	})
+	}
+	if closing {
+		if warnings := h.blockingWarnings(ctx, issue.ID); len(warnings) > 0 {
+			resp["warnings"] = warnings
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
 }
 
+// DeleteIssue deletes an issue. Its sub-issues become top-level issues, or
+// with ?children=delete are deleted along with it.
 func (h *IssueHandler) DeleteIssue(c *fiber.Ctx) error {
 	issue_id := c.Params("id")
 	if issue_id == "" || issue_id == "undefined" {
@@ -246,8 +613,16 @@ func (h *IssueHandler) DeleteIssue(c *fiber.Ctx) error {
 			"error": "missing issue ID",
 		})
 	}
-
-	userID := c.Locals("userID").(string)
+	children := c.Query("children", deleteChildrenDetach)
+	if children != deleteChildrenDetach && children != deleteChildrenDelete {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "children must be detach or delete",
+		})
+	}
+	issue_id, ok := h.resolveIssueID(c, issue_id)
+	if !ok {
+		return nil
+	}
 
 	ctx := c.Context()
 
@@ -263,20 +638,15 @@ func (h *IssueHandler) DeleteIssue(c *fiber.Ctx) error {
 		})
 	}
 
-	isOwner := issue.OwnerID == userID
-	isTeamMember, err := h.TeamRepo.IsMemberInTeam(ctx, issue.TeamID, userID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "failed to check team membership",
-		})
+	if !authorize(c, h.Authz, authz.IssueDelete, authz.ForIssue(issue), "you are not authorized to delete this issue") {
+		return nil
 	}
 
-	if !isOwner && !isTeamMember {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "you are not authorized to delete this issue",
-		})
+	if children == deleteChildrenDelete {
+		return h.deleteIssueTree(c, issue)
 	}
 
+	// sub-issue กลายเป็น issue ระดับบนผ่าน ON DELETE SET NULL
 	if err := h.Repo.DeleteIssue(ctx, issue_id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "failed to delete issue",
@@ -288,17 +658,29 @@ func (h *IssueHandler) DeleteIssue(c *fiber.Ctx) error {
 	})
 }
 
-func (h *IssueHandler) GetIssuesByUserID(c *fiber.Ctx) error {
+// ListIssues returns the issues the user can see, filtered, sorted and paged
+// according to the query string. See parseIssueFilter for the parameters.
+func (h *IssueHandler) ListIssues(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
 
-	issues, err := h.Repo.GetIssueByUserID(c.Context(), userID)
+	filter, err := parseIssueFilter(c, userID)
 	if err != nil {
-		log.Printf("Failed to get issues by user ID: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	issues, nextCursor, err := h.Repo.ListIssues(c.Context(), userID, filter)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidIssueFilter) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		log.Printf("Failed to list issues: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to get issues",
 		})
 	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"issues": issues,
+		"issues":      issues,
+		"next_cursor": nextCursor,
 	})
 }
@@ -0,0 +1,198 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/go-playground/validator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/rank"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+// MoveIssue moves a card on a kanban board. The new status and the rank
+// between the neighbouring cards are written in one transaction, then the
+// other clients on the board are told so they can reorder.
+func (h *IssueHandler) MoveIssue(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	if issueID == "" || issueID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	var req models.MoveIssueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validator.New().Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":  "Validation failed",
+			"detail": err.Error(),
+		})
+	}
+	for _, id := range []*string{&req.AfterID, &req.BeforeID} {
+		if *id == "" {
+			continue
+		}
+		if *id, ok = h.resolveIssueID(c, *id); !ok {
+			return nil
+		}
+	}
+	if req.AfterID == issueID || req.BeforeID == issueID || (req.AfterID != "" && req.AfterID == req.BeforeID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "after_id and before_id must be two other issues"})
+	}
+
+	userID := c.Locals("userID").(string)
+	ctx := c.Context()
+
+	issue, ok := h.loadIssueFor(c, issueID, authz.IssueUpdate)
+	if !ok {
+		return nil
+	}
+	closing := false
+	if req.Status == "" {
+		req.Status = issue.Status
+	} else if req.Status != issue.Status {
+		target, ok := h.lookupStatus(c, issue.TeamID, req.Status)
+		if !ok || !h.checkTransition(c, issue, target, userID, nil, nil, issue.EndDate.Valid) {
+			return nil
+		}
+		closing = isClosedCategory(target.Category)
+	}
+
+	if req.ViewID != "" {
+		teamID, err := h.Repo.GetTeamIDByViewID(ctx, req.ViewID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "view not found"})
+			}
+			log.Printf("Failed to get view %s: %v", req.ViewID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch view"})
+		}
+		if teamID != issue.TeamID {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "the view belongs to another team"})
+		}
+	}
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to move issue"})
+	}
+	defer tx.Rollback()
+
+	// อ่านตำแหน่งของการ์ดข้างเคียงใน transaction เดียวกับที่เขียน
+	var after, before string
+	if req.AfterID == "" && req.BeforeID == "" {
+		after, err = h.Repo.GetLastIssueBoardRankTx(ctx, tx, db.GetLastIssueBoardRankParams{
+			ViewID: req.ViewID,
+			TeamID: issue.TeamID,
+			Status: req.Status,
+			ID:     issue.ID,
+		})
+		if err != nil {
+			log.Printf("Failed to get the last rank of %s: %v", req.Status, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to move issue"})
+		}
+	}
+	for _, n := range []struct {
+		id   string
+		rank *string
+	}{{req.AfterID, &after}, {req.BeforeID, &before}} {
+		if n.id == "" {
+			continue
+		}
+		row, err := h.Repo.GetIssueBoardRankTx(ctx, tx, db.GetIssueBoardRankParams{ViewID: req.ViewID, ID: n.id})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("issue %s not found", n.id)})
+			}
+			log.Printf("Failed to get the rank of issue %s: %v", n.id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to move issue"})
+		}
+		if row.TeamID != issue.TeamID || row.Status != req.Status {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": fmt.Sprintf("issue %s is not in the %s column of this board", n.id, req.Status),
+			})
+		}
+		*n.rank = row.Rank
+	}
+
+	newRank, err := rank.Between(after, before)
+	if err != nil {
+		// ลำดับของการ์ดข้างเคียงเปลี่ยนไปแล้ว client ต้องโหลด board ใหม่
+		log.Printf("Cannot rank issue %s between %q and %q: %v", issue.ID, after, before, err)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "the board has changed, reload it and try again"})
+	}
+
+	if err := h.Repo.MoveIssueTx(ctx, tx, req.ViewID, issue.ID, req.Status, newRank); err != nil {
+		log.Printf("Failed to move issue %s: %v", issue.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to move issue"})
+	}
+	statusChanged := req.Status != issue.Status
+	if statusChanged {
+		if err := h.Repo.CreateIssueEventTx(ctx, tx, db.CreateIssueEventParams{
+			ID:        uuid.New().String(),
+			IssueID:   issue.ID,
+			ActorID:   userID,
+			Field:     "status",
+			OldValue:  sql.NullString{String: issue.Status, Valid: true},
+			NewValue:  sql.NullString{String: req.Status, Valid: true},
+			CreatedAt: time.Now().UTC(),
+		}); err != nil {
+			log.Printf("Failed to record the move of issue %s: %v", issue.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to move issue"})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit the move of issue %s: %v", issue.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to move issue"})
+	}
+
+	moved := models.IssueMoved{
+		IssueID:  issue.ID,
+		ViewID:   req.ViewID,
+		Status:   req.Status,
+		Rank:     newRank,
+		AfterID:  req.AfterID,
+		BeforeID: req.BeforeID,
+	}
+	if req.ViewID == "" {
+		ws.BroadcastToRoom("team", issue.TeamID, "issue_moved", moved)
+	} else {
+		ws.BroadcastToRoom("view", req.ViewID, "issue_moved", moved)
+		// การ์ดบน board ของทีมเปลี่ยนคอลัมน์ แต่ยังคงตำแหน่งเดิมของทีม
+		if statusChanged {
+			ws.BroadcastToRoom("team", issue.TeamID, "issue_moved", models.IssueMoved{
+				IssueID: issue.ID,
+				Status:  req.Status,
+				Rank:    issue.Rank,
+			})
+		}
+	}
+	if statusChanged {
+		ws.BroadcastToRoom("issue", issue.ID, "issue_updated", models.UpdateIssueRequest{ID: issue.ID, Status: &req.Status})
+	}
+
+	resp := fiber.Map{
+		"message": "issue moved successfully",
+		"issue":   moved,
+	}
+	if closing {
+		if warnings := h.blockingWarnings(ctx, issue.ID); len(warnings) > 0 {
+			resp["warnings"] = warnings
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
@@ -0,0 +1,215 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/workflow"
+)
+
+// Values of the children query parameter of DeleteIssue.
+const (
+	deleteChildrenDetach = "detach"
+	deleteChildrenDelete = "delete"
+)
+
+// statusCascade moves the sub-issues in Issues into Status along with their
+// parent.
+type statusCascade struct {
+	Status string
+	Issues []db.Issue
+}
+
+// checkParent resolves parentID and checks that it can become the parent of
+// the issue issueID. On failure the error response is already written.
+func (h *IssueHandler) checkParent(c *fiber.Ctx, issueID, parentID string) (string, bool) {
+	parentID, err := h.parentOf(c.Context(), c.Locals("userID").(string), issueID, parentID)
+	return parentID, check(c, err)
+}
+
+// parentOf resolves parentID and checks that it can become the parent of the
+// issue issueID, which is empty for a new issue. The user must be able to see
+// the parent and the link must not close a loop.
+func (h *IssueHandler) parentOf(ctx context.Context, userID, issueID, parentID string) (string, error) {
+	parentID, err := h.issueIDFor(ctx, userID, parentID)
+	if err != nil {
+		return "", err
+	}
+	if parentID == issueID {
+		return "", &StatusError{Status: fiber.StatusBadRequest, Message: "an issue cannot be its own parent"}
+	}
+
+	parent, err := h.Repo.GetIssueByID(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", &StatusError{Status: fiber.StatusBadRequest, Message: "parent issue not found"}
+		}
+		log.Printf("Failed to get parent issue %s: %v", parentID, err)
+		return "", &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to fetch parent issue"}
+	}
+	if err := allow(ctx, h.Authz, userID, authz.IssueView, authz.ForIssue(parent), "you are not authorized to access the parent issue"); err != nil {
+		return "", err
+	}
+	if issueID == "" {
+		return parent.ID, nil
+	}
+
+	// parent ต้องไม่เป็น sub-issue ของ issue นี้ ไม่งั้นจะวนเป็นวง
+	ancestors, err := h.Repo.ListIssueAncestorIDs(ctx, parent.ID)
+	if err != nil {
+		log.Printf("Failed to list ancestors of issue %s: %v", parent.ID, err)
+		return "", &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to check parent issue"}
+	}
+	for _, id := range ancestors {
+		if id == issueID {
+			return "", &StatusError{Status: fiber.StatusBadRequest, Message: fmt.Sprintf("issue %s is a sub-issue of this issue", parent.ID)}
+		}
+	}
+	return parent.ID, nil
+}
+
+// ListIssueChildren returns the direct sub-issues of an issue and how many of
+// them are done.
+func (h *IssueHandler) ListIssueChildren(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	if issueID == "" || issueID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := h.loadIssueFor(c, issueID, authz.IssueView); !ok {
+		return nil
+	}
+
+	ctx := c.Context()
+	children, err := h.Repo.ListChildIssues(ctx, issueID)
+	if err != nil {
+		log.Printf("Failed to list sub-issues of %s: %v", issueID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch sub-issues"})
+	}
+	progress, err := h.Repo.GetSubIssueProgress(ctx, issueID)
+	if err != nil {
+		log.Printf("Failed to count sub-issues of %s: %v", issueID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch sub-issues"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"children": children,
+		"progress": models.SubIssueProgress{Completed: progress.Completed, Total: progress.Total},
+	})
+}
+
+// cascadeTargets returns the sub-issues of issue, at any depth, that move to
+// the status to along with it: those in the same team that are still open and
+// whose own move the transition rules allow. The others keep their status.
+func (h *IssueHandler) cascadeTargets(ctx context.Context, issue db.Issue, to db.TeamStatus, userID string) ([]db.Issue, error) {
+	descendants, err := h.Repo.ListIssueDescendants(ctx, issue.ID)
+	if err != nil || len(descendants) == 0 {
+		return nil, err
+	}
+	statuses, err := h.TeamRepo.ListTeamStatuses(ctx, to.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	categories := make(map[string]string, len(statuses))
+	for _, s := range statuses {
+		categories[s.Key] = s.Category
+	}
+	rows, err := h.TeamRepo.ListTransitionRules(ctx, to.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]models.TransitionRule, 0, len(rows))
+	for _, r := range rows {
+		rules = append(rules, toTransitionRule(r))
+	}
+
+	targets := []db.Issue{}
+	for _, child := range descendants {
+		if child.TeamID != to.TeamID || child.Status == to.Key {
+			continue
+		}
+		if isClosedCategory(categories[child.Status]) {
+			continue
+		}
+		t := workflow.Transition{
+			From:       child.Status,
+			To:         to.Key,
+			ToCategory: to.Category,
+			ActorID:    userID,
+			HasEndDate: child.EndDate.Valid,
+		}
+		if needsAssignees(rules, t) {
+			t.Assignees, err = h.assigneesAfter(ctx, child, nil, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(workflow.Check(rules, t)) > 0 {
+			continue
+		}
+		targets = append(targets, child)
+	}
+	return targets, nil
+}
+
+func needsAssignees(rules []models.TransitionRule, t workflow.Transition) bool {
+	for _, r := range rules {
+		if !workflow.Applies(r, t.From, t.To, t.ToCategory) {
+			continue
+		}
+		if r.Requirement == workflow.RequireAssignee || r.Requirement == workflow.RequireActorAssignee {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteIssueTree deletes issue and all of its sub-issues in one transaction.
+// Nothing is deleted unless the user may delete every one of them.
+func (h *IssueHandler) deleteIssueTree(c *fiber.Ctx, issue db.Issue) error {
+	ctx := c.Context()
+	descendants, err := h.Repo.ListIssueDescendants(ctx, issue.ID)
+	if err != nil {
+		log.Printf("Failed to list sub-issues of %s: %v", issue.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch sub-issues"})
+	}
+
+	ids := make([]string, 0, len(descendants)+1)
+	for _, child := range descendants {
+		if !authorize(c, h.Authz, authz.IssueDelete, authz.ForIssue(child), fmt.Sprintf("you are not authorized to delete sub-issue %s", child.ID)) {
+			return nil
+		}
+		ids = append(ids, child.ID)
+	}
+	ids = append(ids, issue.ID)
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete issue"})
+	}
+	defer tx.Rollback()
+
+	if err := h.Repo.DeleteIssuesTx(ctx, tx, ids); err != nil {
+		log.Printf("Failed to delete issue %s and its sub-issues: %v", issue.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete issue"})
+	}
+	if err := tx.Commit(); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete issue"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "issue deleted successfully",
+		"deleted": len(ids),
+	})
+}
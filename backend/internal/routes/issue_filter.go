@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	models "github.com/nack098/nakumanager/internal/models"
+)
+
+const (
+	defaultIssuePageSize = 50
+	maxIssuePageSize     = 200
+)
+
+// splitList reads a comma separated query parameter. "me" is replaced with the
+// current user so clients can ask for e.g. assignee=me.
+func splitList(c *fiber.Ctx, key, userID string) []string {
+	values := []string{}
+	for _, v := range strings.Split(c.Query(key), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if v == "me" {
+			v = userID
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// parseDateParam accepts RFC 3339 timestamps and plain dates. A plain date used
+// as the end of a range covers the whole day.
+func parseDateParam(c *fiber.Ctx, key string, endOfRange bool) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or an RFC 3339 timestamp", key)
+	}
+	if endOfRange {
+		t = t.Add(24*time.Hour - time.Millisecond)
+	}
+	return &t, nil
+}
+
+func parseIssueSort(raw string) []models.IssueSort {
+	sorts := []models.IssueSort{}
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		desc := strings.HasPrefix(field, "-")
+		sorts = append(sorts, models.IssueSort{Field: strings.TrimPrefix(field, "-"), Desc: desc})
+	}
+	return sorts
+}
+
+// parseIssueFilter builds the issue filter from the query string, e.g.
+// ?status=todo,doing&assignee=me&end_to=2024-06-30&sort=-priority,title&limit=20.
+// overdue=true keeps the open issues whose end date has passed.
+func parseIssueFilter(c *fiber.Ctx, userID string) (models.IssueFilter, error) {
+	f := models.IssueFilter{
+		Statuses:   splitList(c, "status", userID),
+		Priorities: splitList(c, "priority", userID),
+		Labels:     splitList(c, "label", userID),
+		OwnerIDs:   splitList(c, "owner", userID),
+		ProjectIDs: splitList(c, "project", userID),
+		TeamIDs:    splitList(c, "team", userID),
+		CycleIDs:   splitList(c, "cycle", userID),
+		Milestones: splitList(c, "milestone", userID),
+		Text:       strings.TrimSpace(c.Query("q")),
+		Sort:       parseIssueSort(c.Query("sort")),
+		Cursor:     c.Query("cursor"),
+		Limit:      c.QueryInt("limit", defaultIssuePageSize),
+	}
+
+	for _, id := range splitList(c, "assignee", userID) {
+		if id == "none" {
+			f.Unassigned = true
+			continue
+		}
+		f.AssigneeIDs = append(f.AssigneeIDs, id)
+	}
+
+	var err error
+	if f.StartFrom, err = parseDateParam(c, "start_from", false); err != nil {
+		return f, err
+	}
+	if f.StartTo, err = parseDateParam(c, "start_to", true); err != nil {
+		return f, err
+	}
+	if f.EndFrom, err = parseDateParam(c, "end_from", false); err != nil {
+		return f, err
+	}
+	if f.EndTo, err = parseDateParam(c, "end_to", true); err != nil {
+		return f, err
+	}
+
+	if raw := c.Query("overdue"); raw != "" {
+		overdue, err := strconv.ParseBool(raw)
+		if err != nil {
+			return f, fmt.Errorf("overdue must be true or false")
+		}
+		if overdue {
+			now := time.Now()
+			f.OverdueAt = &now
+		}
+	}
+
+	if f.Limit < 1 || f.Limit > maxIssuePageSize {
+		return f, fmt.Errorf("limit must be between 1 and %d", maxIssuePageSize)
+	}
+
+	return f, nil
+}
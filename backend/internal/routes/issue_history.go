@@ -0,0 +1,193 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+)
+
+type issueFieldChange struct {
+	Field string
+	Old   sql.NullString
+	New   sql.NullString
+}
+
+func formatNullTime(t sql.NullTime) sql.NullString {
+	if !t.Valid {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.Time.UTC().Format(time.RFC3339), Valid: true}
+}
+
+func sameNullString(a, b sql.NullString) bool {
+	if !a.Valid || !b.Valid {
+		return a.Valid == b.Valid
+	}
+	return a.String == b.String
+}
+
+// diffIssue returns the fields of the request that actually change the issue,
+// with their values before and after the update.
+func diffIssue(issue db.Issue, req models.UpdateIssueRequest) []issueFieldChange {
+	changes := []issueFieldChange{}
+	compare := func(field string, old sql.NullString, next *string) {
+		if next == nil {
+			return
+		}
+		newValue := ToNullString(next)
+		if !sameNullString(ToNullString(&old.String), newValue) {
+			changes = append(changes, issueFieldChange{Field: field, Old: old, New: newValue})
+		}
+	}
+	compareTime := func(field string, old sql.NullTime, next *time.Time) {
+		if next == nil {
+			return
+		}
+		oldValue := formatNullTime(old)
+		newValue := formatNullTime(ToNullTime(next))
+		if !sameNullString(oldValue, newValue) {
+			changes = append(changes, issueFieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	valid := func(s string) sql.NullString {
+		return sql.NullString{String: s, Valid: true}
+	}
+
+	compare("title", valid(issue.Title), req.Title)
+	compare("content", issue.Content, req.Content)
+	compare("priority", issue.Priority, req.Priority)
+	compare("status", valid(issue.Status), req.Status)
+	compare("project_id", issue.ProjectID, req.ProjectID)
+	compare("team_id", valid(issue.TeamID), req.TeamID)
+	compareTime("start_date", issue.StartDate, req.StartDate)
+	compareTime("end_date", issue.EndDate, req.EndDate)
+	compare("owner_id", valid(issue.OwnerID), req.OwnerID)
+	compare("parent_id", issue.ParentID, req.ParentID)
+	compare("cycle_id", issue.CycleID, req.CycleID)
+	compare("milestone_id", issue.MilestoneID, req.MilestoneID)
+
+	return changes
+}
+
+// applyIssueUpdate runs the update query and records the field changes as
+// issue events in the same transaction. Assignee and label changes are
+// written there as well, right before their events. cascade, when not nil,
+// moves sub-issues to the new status in that transaction too.
+func (h *IssueHandler) applyIssueUpdate(ctx context.Context, issueID, actorID, query string, args []interface{}, changes []issueFieldChange, cascade *statusCascade) error {
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if query != "" {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, change := range changes {
+		if err := h.applyIssueLinkChange(ctx, tx, issueID, change); err != nil {
+			return err
+		}
+		if err := h.Repo.CreateIssueEventTx(ctx, tx, db.CreateIssueEventParams{
+			ID:        uuid.New().String(),
+			IssueID:   issueID,
+			ActorID:   actorID,
+			Field:     change.Field,
+			OldValue:  change.Old,
+			NewValue:  change.New,
+			CreatedAt: now,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if cascade != nil {
+		for _, child := range cascade.Issues {
+			if err := h.Repo.SetIssueStatusTx(ctx, tx, child.ID, cascade.Status); err != nil {
+				return err
+			}
+			if err := h.Repo.CreateIssueEventTx(ctx, tx, db.CreateIssueEventParams{
+				ID:        uuid.New().String(),
+				IssueID:   child.ID,
+				ActorID:   actorID,
+				Field:     "status",
+				OldValue:  sql.NullString{String: child.Status, Valid: true},
+				NewValue:  sql.NullString{String: cascade.Status, Valid: true},
+				CreatedAt: now,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// applyIssueLinkChange adds or removes the assignee or label named by an
+// "assignees" or "labels" change. Other changes are left to the update query.
+func (h *IssueHandler) applyIssueLinkChange(ctx context.Context, tx *sql.Tx, issueID string, change issueFieldChange) error {
+	switch change.Field {
+	case "assignees":
+		if change.New.Valid {
+			return h.Repo.AddAssigneeToIssueTx(ctx, tx, db.AddAssigneeToIssueParams{IssueID: issueID, UserID: change.New.String})
+		}
+		return h.Repo.RemoveAssigneeFromIssueTx(ctx, tx, db.RemoveAssigneeFromIssueParams{IssueID: issueID, UserID: change.Old.String})
+	case "labels":
+		if change.New.Valid {
+			return h.LabelRepo.AddLabelToIssueTx(ctx, tx, issueID, change.New.String)
+		}
+		return h.LabelRepo.RemoveLabelFromIssueTx(ctx, tx, issueID, change.Old.String)
+	}
+	return nil
+}
+
+func (h *IssueHandler) GetIssueHistory(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	if issueID == "" || issueID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	limit, offset, err := parsePagination(c, 50, 100)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	ctx := c.Context()
+
+	if _, ok := h.loadIssueFor(c, issueID, authz.IssueView); !ok {
+		return nil
+	}
+
+	events, err := h.Repo.ListIssueEvents(ctx, issueID, int64(limit), int64(offset))
+	if err != nil {
+		log.Printf("Failed to list events of issue %s: %v", issueID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch issue history"})
+	}
+
+	total, err := h.Repo.CountIssueEvents(ctx, issueID)
+	if err != nil {
+		log.Printf("Failed to count events of issue %s: %v", issueID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch issue history"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"events": events,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
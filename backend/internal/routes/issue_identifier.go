@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// issueIdentifierPattern matches identifiers such as ENG-142: the key of the
+// team and the number of the issue in it. Issue IDs are UUIDs and never match.
+var issueIdentifierPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,9}-[0-9]+$`)
+
+// issueNumber is the number of an issue in its team and the identifier it
+// makes.
+type issueNumber struct {
+	Number     int64
+	Identifier string
+}
+
+// resolveIssueID returns the ID of the issue id refers to. id is either the ID
+// itself or the issue's identifier, which is looked up in the workspaces of the
+// user. On failure the error response is already written.
+func (h *IssueHandler) resolveIssueID(c *fiber.Ctx, id string) (string, bool) {
+	id, err := h.issueIDFor(c.Context(), c.Locals("userID").(string), id)
+	return id, check(c, err)
+}
+
+// issueIDFor is resolveIssueID outside of a request.
+func (h *IssueHandler) issueIDFor(ctx context.Context, userID, id string) (string, error) {
+	if !issueIdentifierPattern.MatchString(id) {
+		return id, nil
+	}
+
+	ids, err := h.Repo.ListIssueIDsByIdentifier(ctx, id, userID)
+	if err != nil {
+		log.Printf("Failed to look up issue %s: %v", id, err)
+		return "", &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to fetch issue"}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", &StatusError{Status: fiber.StatusNotFound, Message: "issue not found"}
+	case 1:
+		return ids[0], nil
+	default:
+		return "", &StatusError{Status: fiber.StatusConflict, Message: fmt.Sprintf("%s is used in more than one of your workspaces, use the issue ID", id)}
+	}
+}
@@ -0,0 +1,393 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+// isClosedCategory reports whether issues in a status of the category are
+// done with, one way or the other.
+func isClosedCategory(category string) bool {
+	return category == "completed" || category == "cancelled"
+}
+
+// inverseRelation returns the type of a relation seen from the other issue.
+func inverseRelation(t string) string {
+	switch t {
+	case models.RelationBlocks:
+		return models.RelationBlockedBy
+	case models.RelationBlockedBy:
+		return models.RelationBlocks
+	case models.RelationDuplicates:
+		return models.RelationDuplicatedBy
+	case models.RelationDuplicatedBy:
+		return models.RelationDuplicates
+	}
+	return t
+}
+
+// storedRelation returns the row kept for issueID having the relation t to
+// relatedID. Only blocks, duplicates and relates_to are stored; the inverse
+// types are stored the other way round.
+func storedRelation(issueID, relatedID, t string) (from, to, stored string) {
+	if t == models.RelationBlockedBy || t == models.RelationDuplicatedBy {
+		return relatedID, issueID, inverseRelation(t)
+	}
+	return issueID, relatedID, t
+}
+
+// loadRelatedIssue fetches the other end of a relation and checks that the user
+// can see it. On failure the error response is already written.
+func (h *IssueHandler) loadRelatedIssue(c *fiber.Ctx, issueID, what string) (db.Issue, bool) {
+	issue, err := h.Repo.GetIssueByID(c.Context(), issueID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": what + " not found"})
+			return db.Issue{}, false
+		}
+		log.Printf("Failed to get issue %s: %v", issueID, err)
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch " + what})
+		return db.Issue{}, false
+	}
+	if !authorize(c, h.Authz, authz.IssueView, authz.ForIssue(issue), "you are not authorized to access the "+what) {
+		return db.Issue{}, false
+	}
+	return issue, true
+}
+
+// broadcastRelation tells the clients watching either issue of the relation.
+func broadcastRelation(event string, rel db.IssueRelation) {
+	ws.BroadcastToRoom("issue", rel.IssueID, event, rel)
+	ws.BroadcastToRoom("issue", rel.RelatedIssueID, event, rel)
+}
+
+// blockingWarnings lists the open issues that issueID still blocks. Closing a
+// blocker is allowed, so a failure here is only logged.
+func (h *IssueHandler) blockingWarnings(ctx context.Context, issueID string) []models.IssueWarning {
+	blocked, err := h.RelationRepo.ListOpenBlockedIssues(ctx, issueID)
+	if err != nil {
+		log.Printf("Failed to list issues blocked by %s: %v", issueID, err)
+		return nil
+	}
+	warnings := make([]models.IssueWarning, 0, len(blocked))
+	for _, b := range blocked {
+		name := b.Identifier
+		if name == "" {
+			name = b.ID
+		}
+		warnings = append(warnings, models.IssueWarning{
+			IssueID:    b.ID,
+			Identifier: b.Identifier,
+			Message:    fmt.Sprintf("%s is still open and blocked by this issue", name),
+		})
+	}
+	return warnings
+}
+
+// ListIssueRelations returns the relations of an issue from its side.
+func (h *IssueHandler) ListIssueRelations(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	if issueID == "" || issueID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := h.loadIssueFor(c, issueID, authz.IssueView); !ok {
+		return nil
+	}
+
+	relations, err := h.RelationRepo.ListIssueRelations(c.Context(), issueID)
+	if err != nil {
+		log.Printf("Failed to list relations of issue %s: %v", issueID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch relations"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"relations": relations})
+}
+
+// CreateIssueRelation links the issue to another one. The other issue gets
+// the inverse relation: blocking an issue makes this one block it, and so on.
+func (h *IssueHandler) CreateIssueRelation(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	if issueID == "" || issueID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	var req models.CreateIssueRelationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":  "Validation failed",
+			"detail": err.Error(),
+		})
+	}
+	relatedID, ok := h.resolveIssueID(c, req.IssueID)
+	if !ok {
+		return nil
+	}
+	if relatedID == issueID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "an issue cannot be related to itself"})
+	}
+
+	userID := c.Locals("userID").(string)
+	ctx := c.Context()
+
+	if _, ok := h.loadIssueFor(c, issueID, authz.IssueUpdate); !ok {
+		return nil
+	}
+	related, ok := h.loadRelatedIssue(c, relatedID, "related issue")
+	if !ok {
+		return nil
+	}
+
+	existing, err := h.RelationRepo.ListIssueRelations(ctx, issueID)
+	if err != nil {
+		log.Printf("Failed to list relations of issue %s: %v", issueID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create relation"})
+	}
+	from, to, stored := storedRelation(issueID, relatedID, req.Type)
+	for _, r := range existing {
+		if _, _, s := storedRelation(issueID, r.RelatedIssueID, r.Type); r.RelatedIssueID == relatedID && s == stored {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("the issues are already linked as %s", r.Type)})
+		}
+	}
+
+	rel := db.IssueRelation{
+		ID:             uuid.New().String(),
+		IssueID:        from,
+		RelatedIssueID: to,
+		Type:           stored,
+		CreatedBy:      userID,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := h.RelationRepo.CreateIssueRelation(ctx, db.CreateIssueRelationParams(rel)); err != nil {
+		log.Printf("Failed to create relation: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create relation"})
+	}
+
+	broadcastRelation("issue_relation_created", rel)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"relation": db.ListIssueRelationsRow{
+			ID:             rel.ID,
+			Type:           req.Type,
+			RelatedIssueID: related.ID,
+			Identifier:     related.Identifier,
+			Title:          related.Title,
+			Status:         related.Status,
+			CreatedBy:      rel.CreatedBy,
+			CreatedAt:      rel.CreatedAt,
+		},
+	})
+}
+
+// DeleteIssueRelation removes a relation of the issue, which removes it from
+// the other issue as well.
+func (h *IssueHandler) DeleteIssueRelation(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	relationID := c.Params("relationId")
+	if issueID == "" || issueID == "undefined" || relationID == "" || relationID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue or relation ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	ctx := c.Context()
+
+	if _, ok := h.loadIssueFor(c, issueID, authz.IssueUpdate); !ok {
+		return nil
+	}
+
+	rel, err := h.RelationRepo.GetIssueRelation(ctx, relationID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Failed to get relation %s: %v", relationID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch relation"})
+	}
+	if err != nil || (rel.IssueID != issueID && rel.RelatedIssueID != issueID) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "relation not found"})
+	}
+
+	if err := h.RelationRepo.DeleteIssueRelation(ctx, rel.ID); err != nil {
+		log.Printf("Failed to delete relation %s: %v", rel.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete relation"})
+	}
+
+	broadcastRelation("issue_relation_deleted", rel)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "relation deleted successfully"})
+}
+
+// MarkIssueDuplicate closes the issue as a duplicate of the canonical one: it
+// links the two with a duplicates relation and moves the issue into the first
+// cancelled status of its team, or the first completed one if there is none.
+func (h *IssueHandler) MarkIssueDuplicate(c *fiber.Ctx) error {
+	issueID := c.Params("id")
+	if issueID == "" || issueID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing issue ID"})
+	}
+	issueID, ok := h.resolveIssueID(c, issueID)
+	if !ok {
+		return nil
+	}
+
+	var req models.MarkDuplicateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":  "Validation failed",
+			"detail": err.Error(),
+		})
+	}
+	canonicalID, ok := h.resolveIssueID(c, req.CanonicalID)
+	if !ok {
+		return nil
+	}
+	if canonicalID == issueID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "an issue cannot duplicate itself"})
+	}
+
+	userID := c.Locals("userID").(string)
+	ctx := c.Context()
+
+	issue, ok := h.loadIssueFor(c, issueID, authz.IssueUpdate)
+	if !ok {
+		return nil
+	}
+	canonical, ok := h.loadRelatedIssue(c, canonicalID, "canonical issue")
+	if !ok {
+		return nil
+	}
+
+	existing, err := h.RelationRepo.ListIssueRelations(ctx, issue.ID)
+	if err != nil {
+		log.Printf("Failed to list relations of issue %s: %v", issue.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to mark issue as duplicate"})
+	}
+	linked := false
+	for _, r := range existing {
+		if r.RelatedIssueID != canonical.ID {
+			continue
+		}
+		switch r.Type {
+		case models.RelationDuplicates:
+			linked = true
+		case models.RelationDuplicatedBy:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "the canonical issue is a duplicate of this issue"})
+		}
+	}
+
+	statuses, err := h.TeamRepo.ListTeamStatuses(ctx, issue.TeamID)
+	if err != nil {
+		log.Printf("Failed to list statuses of team %s: %v", issue.TeamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to mark issue as duplicate"})
+	}
+	alreadyClosed := false
+	var closed *db.TeamStatus
+	for _, category := range []string{"cancelled", "completed"} {
+		for i := range statuses {
+			if statuses[i].Key == issue.Status && isClosedCategory(statuses[i].Category) {
+				alreadyClosed = true
+			}
+			if closed == nil && statuses[i].Category == category {
+				closed = &statuses[i]
+			}
+		}
+	}
+
+	// issue ที่ปิดไปแล้วคงสถานะเดิมไว้
+	status := issue.Status
+	if !alreadyClosed {
+		if closed == nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": "the team has no status to close the issue in"})
+		}
+		if !h.checkTransition(c, issue, *closed, userID, nil, nil, issue.EndDate.Valid) {
+			return nil
+		}
+		status = closed.Key
+	}
+
+	rel := db.IssueRelation{
+		ID:             uuid.New().String(),
+		IssueID:        issue.ID,
+		RelatedIssueID: canonical.ID,
+		Type:           models.RelationDuplicates,
+		CreatedBy:      userID,
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to mark issue as duplicate"})
+	}
+	defer tx.Rollback()
+
+	if !linked {
+		if err := h.RelationRepo.CreateIssueRelationTx(ctx, tx, db.CreateIssueRelationParams(rel)); err != nil {
+			log.Printf("Failed to link duplicate %s to %s: %v", issue.ID, canonical.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to mark issue as duplicate"})
+		}
+	}
+	if status != issue.Status {
+		if err := h.Repo.SetIssueStatusTx(ctx, tx, issue.ID, status); err != nil {
+			log.Printf("Failed to close duplicate %s: %v", issue.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to mark issue as duplicate"})
+		}
+		if err := h.Repo.CreateIssueEventTx(ctx, tx, db.CreateIssueEventParams{
+			ID:        uuid.New().String(),
+			IssueID:   issue.ID,
+			ActorID:   userID,
+			Field:     "status",
+			OldValue:  sql.NullString{String: issue.Status, Valid: true},
+			NewValue:  sql.NullString{String: status, Valid: true},
+			CreatedAt: rel.CreatedAt,
+		}); err != nil {
+			log.Printf("Failed to record closing duplicate %s: %v", issue.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to mark issue as duplicate"})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit marking %s as duplicate: %v", issue.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to mark issue as duplicate"})
+	}
+
+	if !linked {
+		broadcastRelation("issue_relation_created", rel)
+	}
+	resp := fiber.Map{
+		"message": "issue marked as duplicate",
+		"status":  status,
+	}
+	if status != issue.Status {
+		ws.BroadcastToRoom("issue", issue.ID, "issue_updated", models.UpdateIssueRequest{ID: issue.ID, Status: &status})
+		if warnings := h.blockingWarnings(ctx, issue.ID); len(warnings) > 0 {
+			resp["warnings"] = warnings
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
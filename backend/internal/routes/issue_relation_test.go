@@ -0,0 +1,407 @@
+package routes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateIssueRelation(t *testing.T) {
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRelationRepo := new(mocks.MockRelationRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{
+		Repo:         mockRepo,
+		RelationRepo: mockRelationRepo,
+		Authz:        authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/issues/:id/relations", handler.CreateIssueRelation)
+
+	issues := func() {
+		mockRepo.On("GetIssueByID", mock.Anything, "issue-1").
+			Return(db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-123"}, nil)
+		mockRepo.On("GetIssueByID", mock.Anything, "issue-2").
+			Return(db.Issue{ID: "issue-2", TeamID: "team-1", Identifier: "ENG-2", Title: "Other"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "blocked by is stored as blocks on the other issue",
+			body: `{"type":"blocked_by","issue_id":"issue-2"}`,
+			setup: func() {
+				issues()
+				mockRelationRepo.On("ListIssueRelations", mock.Anything, "issue-1").Return([]db.ListIssueRelationsRow{
+					{ID: "rel-0", Type: "relates_to", RelatedIssueID: "issue-2"},
+				}, nil)
+				mockRelationRepo.On("CreateIssueRelation", mock.Anything, mock.MatchedBy(func(p db.CreateIssueRelationParams) bool {
+					return p.IssueID == "issue-2" && p.RelatedIssueID == "issue-1" && p.Type == "blocks" && p.CreatedBy == "user-123"
+				})).Return(nil).Once()
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "inverse of an existing relation",
+			body: `{"type":"blocks","issue_id":"issue-2"}`,
+			setup: func() {
+				issues()
+				mockRelationRepo.On("ListIssueRelations", mock.Anything, "issue-1").Return([]db.ListIssueRelationsRow{
+					{ID: "rel-1", Type: "blocked_by", RelatedIssueID: "issue-2"},
+				}, nil)
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:       "itself",
+			body:       `{"type":"relates_to","issue_id":"issue-1"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "unknown type",
+			body:       `{"type":"causes","issue_id":"issue-2"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "related issue not found",
+			body: `{"type":"relates_to","issue_id":"missing"}`,
+			setup: func() {
+				issues()
+				mockRepo.On("GetIssueByID", mock.Anything, "missing").Return(db.Issue{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "related issue the user cannot see",
+			body: `{"type":"relates_to","issue_id":"secret"}`,
+			setup: func() {
+				issues()
+				mockRepo.On("GetIssueByID", mock.Anything, "secret").Return(db.Issue{ID: "secret", TeamID: "team-2"}, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-2", "user-123").Return(noRoles, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPost, "/issues/issue-1/relations", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusCreated {
+				var body struct {
+					Relation db.ListIssueRelationsRow `json:"relation"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, "blocked_by", body.Relation.Type)
+				assert.Equal(t, "ENG-2", body.Relation.Identifier)
+			}
+
+			mockRepo.ExpectedCalls = nil
+			mockRelationRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRelationRepo.AssertNumberOfCalls(t, "CreateIssueRelation", 1)
+}
+
+func TestDeleteIssueRelation(t *testing.T) {
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRelationRepo := new(mocks.MockRelationRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{
+		Repo:         mockRepo,
+		RelationRepo: mockRelationRepo,
+		Authz:        authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Delete("/issues/:id/relations/:relationId", handler.DeleteIssueRelation)
+
+	mockRepo.On("GetIssueByID", mock.Anything, "issue-2").Return(db.Issue{ID: "issue-2", TeamID: "team-1"}, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+
+	tests := []struct {
+		name       string
+		relationID string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name:       "relation of the issue",
+			relationID: "rel-1",
+			setup: func() {
+				mockRelationRepo.On("GetIssueRelation", mock.Anything, "rel-1").
+					Return(db.IssueRelation{ID: "rel-1", IssueID: "issue-1", RelatedIssueID: "issue-2", Type: "blocks"}, nil)
+				mockRelationRepo.On("DeleteIssueRelation", mock.Anything, "rel-1").Return(nil).Once()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "relation of other issues",
+			relationID: "rel-other",
+			setup: func() {
+				mockRelationRepo.On("GetIssueRelation", mock.Anything, "rel-other").
+					Return(db.IssueRelation{ID: "rel-other", IssueID: "issue-7", RelatedIssueID: "issue-8", Type: "blocks"}, nil)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name:       "unknown relation",
+			relationID: "missing",
+			setup: func() {
+				mockRelationRepo.On("GetIssueRelation", mock.Anything, "missing").Return(db.IssueRelation{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/issues/issue-2/relations/"+tt.relationID, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			mockRelationRepo.ExpectedCalls = nil
+		})
+	}
+	mockRelationRepo.AssertNumberOfCalls(t, "DeleteIssueRelation", 1)
+}
+
+func TestListIssueRelations(t *testing.T) {
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRelationRepo := new(mocks.MockRelationRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{
+		Repo:         mockRepo,
+		RelationRepo: mockRelationRepo,
+		Authz:        authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Get("/issues/:id/relations", handler.ListIssueRelations)
+
+	mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(db.Issue{ID: "issue-1", TeamID: "team-1"}, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	mockRelationRepo.On("ListIssueRelations", mock.Anything, "issue-1").Return([]db.ListIssueRelationsRow{
+		{ID: "rel-1", Type: "blocked_by", RelatedIssueID: "issue-2"},
+		{ID: "rel-2", Type: "duplicates", RelatedIssueID: "issue-3"},
+	}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/issues/issue-1/relations", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Relations []db.ListIssueRelationsRow `json:"relations"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Relations, 2)
+	assert.Equal(t, "blocked_by", body.Relations[0].Type)
+}
+
+func TestMarkIssueDuplicate(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRelationRepo := new(mocks.MockRelationRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{
+		DB:           mockDB,
+		Repo:         mockRepo,
+		TeamRepo:     mockTeamRepo,
+		RelationRepo: mockRelationRepo,
+		Authz:        authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/issues/:id/duplicate", handler.MarkIssueDuplicate)
+
+	statuses := []db.TeamStatus{
+		{TeamID: "team-1", Key: "todo", Category: "unstarted"},
+		{TeamID: "team-1", Key: "done", Category: "completed"},
+		{TeamID: "team-1", Key: "duplicate", Category: "cancelled"},
+	}
+	issues := func(status string, relations []db.ListIssueRelationsRow) {
+		mockRepo.On("GetIssueByID", mock.Anything, "dup").
+			Return(db.Issue{ID: "dup", TeamID: "team-1", OwnerID: "user-123", Status: status}, nil)
+		mockRepo.On("GetIssueByID", mock.Anything, "canonical").
+			Return(db.Issue{ID: "canonical", TeamID: "team-1"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+		mockRelationRepo.On("ListIssueRelations", mock.Anything, "dup").Return(relations, nil)
+		mockTeamRepo.On("ListTeamStatuses", mock.Anything, "team-1").Return(statuses, nil)
+		mockTeamRepo.On("ListTransitionRules", mock.Anything, "team-1").Return([]db.TeamTransitionRule{}, nil)
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		setup        func()
+		wantStatus   int
+		wantIssue    string
+		wantWarnings int
+	}{
+		{
+			name: "links and closes as cancelled",
+			body: `{"canonical_id":"canonical"}`,
+			setup: func() {
+				issues("todo", []db.ListIssueRelationsRow{})
+				sqlMock.ExpectBegin()
+				mockRelationRepo.On("CreateIssueRelationTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateIssueRelationParams) bool {
+					return p.IssueID == "dup" && p.RelatedIssueID == "canonical" && p.Type == models.RelationDuplicates
+				})).Return(nil).Once()
+				mockRepo.On("SetIssueStatusTx", mock.Anything, mock.Anything, "dup", "duplicate").Return(nil).Once()
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.IssueID == "dup" && e.Field == "status" && e.OldValue.String == "todo" && e.NewValue.String == "duplicate"
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+				mockRelationRepo.On("ListOpenBlockedIssues", mock.Anything, "dup").Return([]db.ListOpenBlockedIssuesRow{
+					{ID: "blocked", Identifier: "ENG-9", Status: "todo"},
+				}, nil)
+			},
+			wantStatus:   fiber.StatusOK,
+			wantIssue:    "duplicate",
+			wantWarnings: 1,
+		},
+		{
+			name: "closed issue keeps its status",
+			body: `{"canonical_id":"canonical"}`,
+			setup: func() {
+				issues("done", []db.ListIssueRelationsRow{})
+				sqlMock.ExpectBegin()
+				mockRelationRepo.On("CreateIssueRelationTx", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+			wantIssue:  "done",
+		},
+		{
+			name: "canonical is a duplicate of the issue",
+			body: `{"canonical_id":"canonical"}`,
+			setup: func() {
+				issues("todo", []db.ListIssueRelationsRow{{ID: "rel-1", Type: "duplicated_by", RelatedIssueID: "canonical"}})
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:       "itself",
+			body:       `{"canonical_id":"dup"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPost, "/issues/dup/duplicate", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+
+			if tt.wantStatus == fiber.StatusOK {
+				var body struct {
+					Status   string                `json:"status"`
+					Warnings []models.IssueWarning `json:"warnings"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.wantIssue, body.Status)
+				assert.Len(t, body.Warnings, tt.wantWarnings)
+			}
+
+			mockRepo.ExpectedCalls = nil
+			mockTeamRepo.ExpectedCalls = nil
+			mockRelationRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRepo.AssertNumberOfCalls(t, "SetIssueStatusTx", 1)
+	mockRelationRepo.AssertNumberOfCalls(t, "CreateIssueRelationTx", 2)
+}
+
+func TestCloseBlockingIssueWarns(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRelationRepo := new(mocks.MockRelationRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{
+		DB:           mockDB,
+		Repo:         mockRepo,
+		TeamRepo:     mockTeamRepo,
+		RelationRepo: mockRelationRepo,
+		Authz:        authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Put("/issues/:id", handler.UpdateIssue)
+
+	mockRepo.On("GetIssueByID", mock.Anything, "issue-1").
+		Return(db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-123", Status: "todo"}, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "done").
+		Return(db.TeamStatus{TeamID: "team-1", Key: "done", Category: "completed"}, nil)
+	mockTeamRepo.On("ListTransitionRules", mock.Anything, "team-1").Return([]db.TeamTransitionRule{}, nil)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(`UPDATE issues SET status = \? WHERE id = \?`).
+		WithArgs("done", "issue-1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	sqlMock.ExpectCommit()
+	mockRelationRepo.On("ListOpenBlockedIssues", mock.Anything, "issue-1").Return([]db.ListOpenBlockedIssuesRow{
+		{ID: "issue-2", Identifier: "ENG-2", Status: "todo"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/issues/issue-1", bytes.NewReader([]byte(`{"status":"done"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Warnings []models.IssueWarning `json:"warnings"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Warnings, 1)
+	assert.Equal(t, "issue-2", body.Warnings[0].IssueID)
+	assert.Equal(t, "ENG-2", body.Warnings[0].Identifier)
+}
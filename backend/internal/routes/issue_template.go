@@ -0,0 +1,429 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/repositories"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+type IssueTemplateHandler struct {
+	DB          *sql.DB
+	Repo        repositories.IssueTemplateRepository
+	TeamRepo    repositories.TeamRepository
+	ProjectRepo repositories.ProjectRepository
+	LabelRepo   repositories.LabelRepository
+	Authz       *authz.Authorizer
+}
+
+func NewIssueTemplateHandler(db *sql.DB, repo repositories.IssueTemplateRepository, teamRepo repositories.TeamRepository, projectRepo repositories.ProjectRepository, labelRepo repositories.LabelRepository, roleRepo repositories.RoleRepository) *IssueTemplateHandler {
+	return &IssueTemplateHandler{
+		DB:          db,
+		Repo:        repo,
+		TeamRepo:    teamRepo,
+		ProjectRepo: projectRepo,
+		LabelRepo:   labelRepo,
+		Authz:       authz.NewAuthorizer(roleRepo),
+	}
+}
+
+// toIssueTemplate returns t with the ids of its labels and assignees.
+func toIssueTemplate(ctx context.Context, repo repositories.IssueTemplateRepository, t db.IssueTemplate) (models.IssueTemplate, error) {
+	template := models.IssueTemplate{
+		ID:        t.ID,
+		TeamID:    t.TeamID,
+		Name:      t.Name,
+		Title:     t.Title,
+		Content:   t.Content.String,
+		Priority:  t.Priority.String,
+		ProjectID: t.ProjectID.String,
+		CreatedBy: t.CreatedBy,
+		CreatedAt: t.CreatedAt,
+	}
+	if t.UpdatedAt.Valid {
+		template.UpdatedAt = &t.UpdatedAt.Time
+	}
+
+	var err error
+	if template.Labels, err = repo.ListIssueTemplateLabelIDs(ctx, t.ID); err != nil {
+		return template, err
+	}
+	if template.Assignees, err = repo.ListIssueTemplateAssigneeIDs(ctx, t.ID); err != nil {
+		return template, err
+	}
+	if template.Labels == nil {
+		template.Labels = []string{}
+	}
+	if template.Assignees == nil {
+		template.Assignees = []string{}
+	}
+	return template, nil
+}
+
+// expandTemplateTitle fills the title pattern of a template. {title} is
+// replaced by title and {date} by the day of now. A title given without a
+// {title} placeholder to put it in replaces the pattern.
+func expandTemplateTitle(pattern, title string, now time.Time) string {
+	if title != "" && !strings.Contains(pattern, "{title}") {
+		return title
+	}
+	return strings.NewReplacer("{title}", title, "{date}", now.Format("2006-01-02")).Replace(pattern)
+}
+
+// loadTemplate returns the template of the :id param if the user may act on
+// it. On failure the error response is already written.
+func (h *IssueTemplateHandler) loadTemplate(c *fiber.Ctx, action authz.Action) (db.IssueTemplate, bool) {
+	template, err := h.Repo.GetIssueTemplateByID(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "template not found"})
+			return template, false
+		}
+		log.Printf("Failed to get template %s: %v", c.Params("id"), err)
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch template"})
+		return template, false
+	}
+	if !authorize(c, h.Authz, action, authz.ForIssueTemplate(template), "you are not authorized to access this template") {
+		return template, false
+	}
+	return template, true
+}
+
+// checkTemplateFields checks that the project, labels and assignees of a
+// template can be used in issues of the team. On failure the error response is
+// already written.
+func (h *IssueTemplateHandler) checkTemplateFields(c *fiber.Ctx, teamID, projectID string, labels, assignees []string) bool {
+	if projectID != "" {
+		if _, ok := checkProjectTeam(c, h.ProjectRepo, h.TeamRepo, projectID, teamID); !ok {
+			return false
+		}
+	}
+	if !checkLabels(c, h.LabelRepo, teamID, labels) {
+		return false
+	}
+	for _, userID := range assignees {
+		member, err := h.TeamRepo.IsMemberInTeam(c.Context(), teamID, userID)
+		if err != nil {
+			log.Printf("Failed to check assignee %s: %v", userID, err)
+			c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check assignees"})
+			return false
+		}
+		if !member {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("assignee %s is not a member of the team", userID)})
+			return false
+		}
+	}
+	return true
+}
+
+// ListTeamIssueTemplates returns the templates of the team by name.
+func (h *IssueTemplateHandler) ListTeamIssueTemplates(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+	if !authorize(c, h.Authz, authz.TeamView, authz.ForTeam(teamID), "you are not a member of this team") {
+		return nil
+	}
+
+	ctx := c.Context()
+	rows, err := h.Repo.ListTeamIssueTemplates(ctx, teamID)
+	if err != nil {
+		log.Printf("Failed to list templates of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list templates"})
+	}
+	templates := make([]models.IssueTemplate, 0, len(rows))
+	for _, row := range rows {
+		template, err := toIssueTemplate(ctx, h.Repo, row)
+		if err != nil {
+			log.Printf("Failed to load template %s: %v", row.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list templates"})
+		}
+		templates = append(templates, template)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"templates": templates})
+}
+
+// CreateIssueTemplate adds a template to the team.
+func (h *IssueTemplateHandler) CreateIssueTemplate(c *fiber.Ctx) error {
+	var req models.CreateIssueTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	ctx := c.Context()
+	teamID := c.Params("id")
+	teamExists, err := h.TeamRepo.IsTeamExists(ctx, teamID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check team"})
+	}
+	if !teamExists {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
+	}
+	if !authorize(c, h.Authz, authz.TemplateCreate, authz.ForTeam(teamID), "you are not allowed to add templates to this team") {
+		return nil
+	}
+	if !h.checkTemplateFields(c, teamID, req.ProjectID, req.Labels, req.Assignees) {
+		return nil
+	}
+
+	row := db.CreateIssueTemplateParams{
+		ID:        uuid.New().String(),
+		TeamID:    teamID,
+		Name:      req.Name,
+		Title:     req.Title,
+		Content:   sql.NullString{String: req.Content, Valid: req.Content != ""},
+		Priority:  sql.NullString{String: req.Priority, Valid: req.Priority != ""},
+		ProjectID: sql.NullString{String: req.ProjectID, Valid: req.ProjectID != ""},
+		CreatedBy: c.Locals("userID").(string),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create template"})
+	}
+	defer tx.Rollback()
+
+	if err := h.Repo.CreateIssueTemplateTx(ctx, tx, row); err != nil {
+		log.Printf("Failed to create template of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create template"})
+	}
+	if err := h.Repo.SetIssueTemplateLabelsTx(ctx, tx, row.ID, req.Labels); err != nil {
+		log.Printf("Failed to set labels of template %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create template"})
+	}
+	if err := h.Repo.SetIssueTemplateAssigneesTx(ctx, tx, row.ID, req.Assignees); err != nil {
+		log.Printf("Failed to set assignees of template %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create template"})
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit template of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create template"})
+	}
+
+	template := models.IssueTemplate{
+		ID:        row.ID,
+		TeamID:    row.TeamID,
+		Name:      row.Name,
+		Title:     row.Title,
+		Content:   req.Content,
+		Priority:  req.Priority,
+		ProjectID: req.ProjectID,
+		Labels:    req.Labels,
+		Assignees: req.Assignees,
+		CreatedBy: row.CreatedBy,
+		CreatedAt: row.CreatedAt,
+	}
+	if template.Labels == nil {
+		template.Labels = []string{}
+	}
+	if template.Assignees == nil {
+		template.Assignees = []string{}
+	}
+	ws.BroadcastToRoom("team", teamID, "issue_template_created", template)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":  "template created successfully",
+		"template": template,
+	})
+}
+
+// GetIssueTemplate returns the template.
+func (h *IssueTemplateHandler) GetIssueTemplate(c *fiber.Ctx) error {
+	row, ok := h.loadTemplate(c, authz.TeamView)
+	if !ok {
+		return nil
+	}
+	template, err := toIssueTemplate(c.Context(), h.Repo, row)
+	if err != nil {
+		log.Printf("Failed to load template %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch template"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"template": template})
+}
+
+// UpdateIssueTemplate changes the fields of the template that are set in the
+// request.
+func (h *IssueTemplateHandler) UpdateIssueTemplate(c *fiber.Ctx) error {
+	var req models.UpdateIssueTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+	if req.Priority != nil && *req.Priority != "" {
+		if err := validate.Var(*req.Priority, "oneof=low medium high"); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "priority must be low, medium or high"})
+		}
+	}
+
+	row, ok := h.loadTemplate(c, authz.TemplateUpdate)
+	if !ok {
+		return nil
+	}
+
+	params := db.UpdateIssueTemplateParams{
+		Name:      row.Name,
+		Title:     row.Title,
+		Content:   row.Content,
+		Priority:  row.Priority,
+		ProjectID: row.ProjectID,
+		UpdatedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:        row.ID,
+	}
+	if req.Name != nil {
+		params.Name = *req.Name
+	}
+	if req.Title != nil {
+		params.Title = *req.Title
+	}
+	if req.Content != nil {
+		params.Content = sql.NullString{String: *req.Content, Valid: *req.Content != ""}
+	}
+	if req.Priority != nil {
+		params.Priority = sql.NullString{String: *req.Priority, Valid: *req.Priority != ""}
+	}
+	if req.ProjectID != nil {
+		params.ProjectID = sql.NullString{String: *req.ProjectID, Valid: *req.ProjectID != ""}
+	}
+
+	// ตรวจเฉพาะค่าที่เปลี่ยน ค่าเดิมอาจใช้ไม่ได้แล้วแต่ไม่ควรทำให้แก้ส่วนอื่นไม่ได้
+	projectID := ""
+	if req.ProjectID != nil {
+		projectID = *req.ProjectID
+	}
+	var labels, assignees []string
+	if req.Labels != nil {
+		labels = *req.Labels
+	}
+	if req.Assignees != nil {
+		assignees = *req.Assignees
+	}
+	if !h.checkTemplateFields(c, row.TeamID, projectID, labels, assignees) {
+		return nil
+	}
+
+	ctx := c.Context()
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update template"})
+	}
+	defer tx.Rollback()
+
+	if err := h.Repo.UpdateIssueTemplateTx(ctx, tx, params); err != nil {
+		log.Printf("Failed to update template %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update template"})
+	}
+	if req.Labels != nil {
+		if err := h.Repo.SetIssueTemplateLabelsTx(ctx, tx, row.ID, labels); err != nil {
+			log.Printf("Failed to set labels of template %s: %v", row.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update template"})
+		}
+	}
+	if req.Assignees != nil {
+		if err := h.Repo.SetIssueTemplateAssigneesTx(ctx, tx, row.ID, assignees); err != nil {
+			log.Printf("Failed to set assignees of template %s: %v", row.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update template"})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit template %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update template"})
+	}
+
+	row.Name, row.Title, row.Content, row.Priority, row.ProjectID, row.UpdatedAt = params.Name, params.Title, params.Content, params.Priority, params.ProjectID, params.UpdatedAt
+	template, err := toIssueTemplate(ctx, h.Repo, row)
+	if err != nil {
+		log.Printf("Failed to load template %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch template"})
+	}
+	ws.BroadcastToRoom("team", row.TeamID, "issue_template_updated", template)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":  "template updated successfully",
+		"template": template,
+	})
+}
+
+// DeleteIssueTemplate removes the template. Issues created from it are kept.
+func (h *IssueTemplateHandler) DeleteIssueTemplate(c *fiber.Ctx) error {
+	row, ok := h.loadTemplate(c, authz.TemplateDelete)
+	if !ok {
+		return nil
+	}
+	if err := h.Repo.DeleteIssueTemplate(c.Context(), row.ID); err != nil {
+		log.Printf("Failed to delete template %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete template"})
+	}
+	ws.BroadcastToRoom("team", row.TeamID, "issue_template_deleted", fiber.Map{"id": row.ID})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "template deleted successfully"})
+}
+
+// applyTemplate fills the fields of req that are not set from the template:
+// request fields win over template fields. The template must be of the team
+// of req when one is given, and the user must be able to see it.
+func (h *IssueHandler) applyTemplate(ctx context.Context, userID, templateID string, req *models.IssueCreate) error {
+	template, err := h.TemplateRepo.GetIssueTemplateByID(ctx, templateID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &StatusError{Status: fiber.StatusBadRequest, Message: "template not found"}
+		}
+		log.Printf("Failed to get template %s: %v", templateID, err)
+		return errFetchTemplate
+	}
+	if req.TeamID == "" {
+		req.TeamID = template.TeamID
+	} else if req.TeamID != template.TeamID {
+		return &StatusError{Status: fiber.StatusBadRequest, Message: "template belongs to another team"}
+	}
+	if err := allow(ctx, h.Authz, userID, authz.TeamView, authz.ForIssueTemplate(template), "you are not a member of the team of this template"); err != nil {
+		return err
+	}
+
+	req.Title = expandTemplateTitle(template.Title, req.Title, time.Now().UTC())
+	if req.Content == nil && template.Content.Valid {
+		req.Content = &template.Content.String
+	}
+	if req.Priority == nil && template.Priority.Valid {
+		req.Priority = &template.Priority.String
+	}
+	if req.ProjectID == nil && template.ProjectID.Valid {
+		req.ProjectID = &template.ProjectID.String
+	}
+	if req.Labels == nil {
+		labels, err := h.TemplateRepo.ListIssueTemplateLabelIDs(ctx, template.ID)
+		if err != nil {
+			log.Printf("Failed to list labels of template %s: %v", template.ID, err)
+			return errFetchTemplate
+		}
+		req.Labels = &labels
+	}
+	if req.Assignee == nil {
+		assignees, err := h.TemplateRepo.ListIssueTemplateAssigneeIDs(ctx, template.ID)
+		if err != nil {
+			log.Printf("Failed to list assignees of template %s: %v", template.ID, err)
+			return errFetchTemplate
+		}
+		req.Assignee = &assignees
+	}
+	return nil
+}
+
+var errFetchTemplate = &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to fetch template"}
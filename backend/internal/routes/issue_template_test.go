@@ -0,0 +1,349 @@
+package routes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var bugTemplate = db.IssueTemplate{
+	ID:        "tpl-1",
+	TeamID:    "team-1",
+	Name:      "Bug report",
+	Title:     "[Bug] {title}",
+	Content:   sql.NullString{String: "## Steps to reproduce", Valid: true},
+	Priority:  sql.NullString{String: "high", Valid: true},
+	CreatedBy: "user-456",
+	CreatedAt: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+}
+
+func TestCreateIssueTemplate(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockTemplateRepo := new(mocks.MockIssueTemplateRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockProjRepo := new(mocks.MockProjectRepo)
+	mockLabelRepo := new(mocks.MockLabelRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueTemplateHandler{
+		DB:          mockDB,
+		Repo:        mockTemplateRepo,
+		TeamRepo:    mockTeamRepo,
+		ProjectRepo: mockProjRepo,
+		LabelRepo:   mockLabelRepo,
+		Authz:       authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/teams/:id/templates", handler.CreateIssueTemplate)
+
+	team := func(roles db.GetTeamMemberRolesRow) {
+		mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(roles, nil)
+		mockLabelRepo.On("ListTeamLabels", mock.Anything, "team-1").Return([]db.Label{{ID: "label-1"}}, nil)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "with labels and assignees",
+			body: `{"name":"Bug report","title":"[Bug] {title}","priority":"high","labels":["label-1"],"assignees":["user-456"]}`,
+			setup: func() {
+				team(teamMemberRoles)
+				mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-456").Return(true, nil)
+				sqlMock.ExpectBegin()
+				mockTemplateRepo.On("CreateIssueTemplateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateIssueTemplateParams) bool {
+					return p.TeamID == "team-1" && p.Title == "[Bug] {title}" && p.Priority.String == "high" && !p.ProjectID.Valid && p.CreatedBy == "user-123"
+				})).Return(nil).Once()
+				mockTemplateRepo.On("SetIssueTemplateLabelsTx", mock.Anything, mock.Anything, mock.Anything, []string{"label-1"}).Return(nil)
+				mockTemplateRepo.On("SetIssueTemplateAssigneesTx", mock.Anything, mock.Anything, mock.Anything, []string{"user-456"}).Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "assignee outside the team",
+			body: `{"name":"Bug report","assignees":["user-999"]}`,
+			setup: func() {
+				team(teamMemberRoles)
+				mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-999").Return(false, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "label of another team",
+			body: `{"name":"Bug report","labels":["label-x"]}`,
+			setup: func() {
+				team(teamMemberRoles)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "project of another team",
+			body: `{"name":"Bug report","project_id":"proj-2"}`,
+			setup: func() {
+				team(teamMemberRoles)
+				mockProjRepo.On("GetProjectByID", mock.Anything, "proj-2").Return(db.Project{ID: "proj-2", Name: "Other", WorkspaceID: "ws-1", TeamID: "team-2"}, nil)
+				mockTeamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(db.Team{ID: "team-1", Name: "Core", WorkspaceID: "ws-1"}, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "missing name",
+			body:       `{"title":"{title}"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "not a member of the team",
+			body: `{"name":"Bug report"}`,
+			setup: func() {
+				team(workspaceMemberRoles)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPost, "/teams/team-1/templates", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusCreated {
+				var body struct {
+					Template models.IssueTemplate `json:"template"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, []string{"label-1"}, body.Template.Labels)
+				assert.Equal(t, []string{"user-456"}, body.Template.Assignees)
+			}
+
+			mockTemplateRepo.ExpectedCalls = nil
+			mockTeamRepo.ExpectedCalls = nil
+			mockProjRepo.ExpectedCalls = nil
+			mockLabelRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockTemplateRepo.AssertNumberOfCalls(t, "CreateIssueTemplateTx", 1)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestUpdateIssueTemplate(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockTemplateRepo := new(mocks.MockIssueTemplateRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueTemplateHandler{
+		DB:    mockDB,
+		Repo:  mockTemplateRepo,
+		Authz: authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Patch("/templates/:id", handler.UpdateIssueTemplate)
+
+	mockTemplateRepo.On("GetIssueTemplateByID", mock.Anything, "tpl-1").Return(bugTemplate, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	sqlMock.ExpectBegin()
+	mockTemplateRepo.On("UpdateIssueTemplateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.UpdateIssueTemplateParams) bool {
+		// fields left out of the request keep their values
+		return p.ID == "tpl-1" && p.Name == "Bug report" && p.Title == "{title}" && !p.Priority.Valid && p.Content.String == "## Steps to reproduce" && p.UpdatedAt.Valid
+	})).Return(nil).Once()
+	mockTemplateRepo.On("SetIssueTemplateLabelsTx", mock.Anything, mock.Anything, "tpl-1", []string{}).Return(nil).Once()
+	sqlMock.ExpectCommit()
+	mockTemplateRepo.On("ListIssueTemplateLabelIDs", mock.Anything, "tpl-1").Return([]string{}, nil)
+	mockTemplateRepo.On("ListIssueTemplateAssigneeIDs", mock.Anything, "tpl-1").Return([]string{"user-456"}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/templates/tpl-1", strings.NewReader(`{"title":"{title}","priority":"","labels":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Template models.IssueTemplate `json:"template"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "{title}", body.Template.Title)
+	assert.Empty(t, body.Template.Priority)
+	assert.Equal(t, []string{"user-456"}, body.Template.Assignees)
+	mockTemplateRepo.AssertNumberOfCalls(t, "SetIssueTemplateAssigneesTx", 0)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestDeleteIssueTemplate(t *testing.T) {
+	mockTemplateRepo := new(mocks.MockIssueTemplateRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueTemplateHandler{
+		Repo:  mockTemplateRepo,
+		Authz: authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Delete("/templates/:id", handler.DeleteIssueTemplate)
+
+	own := bugTemplate
+	own.ID, own.CreatedBy = "tpl-own", "user-123"
+
+	tests := []struct {
+		name       string
+		template   db.IssueTemplate
+		roles      db.GetTeamMemberRolesRow
+		wantStatus int
+	}{
+		{"own template", own, teamMemberRoles, fiber.StatusOK},
+		{"template of another member", bugTemplate, teamMemberRoles, fiber.StatusForbidden},
+		{"lead deletes any template", bugTemplate, teamLeadRoles, fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTemplateRepo.On("GetIssueTemplateByID", mock.Anything, tt.template.ID).Return(tt.template, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(tt.roles, nil)
+			mockTemplateRepo.On("DeleteIssueTemplate", mock.Anything, tt.template.ID).Return(nil)
+
+			req := httptest.NewRequest(http.MethodDelete, "/templates/"+tt.template.ID, nil)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			mockTemplateRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockTemplateRepo.AssertNumberOfCalls(t, "DeleteIssueTemplate", 2)
+}
+
+func TestCreateIssueFromTemplate(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockLabelRepo := new(mocks.MockLabelRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockTemplateRepo := new(mocks.MockIssueTemplateRepo)
+	handler := routes.IssueHandler{
+		DB:           mockDB,
+		Repo:         mockRepo,
+		TeamRepo:     mockTeamRepo,
+		LabelRepo:    mockLabelRepo,
+		TemplateRepo: mockTemplateRepo,
+		Authz:        authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/issues", handler.CreateIssue)
+
+	mockTemplateRepo.On("GetIssueTemplateByID", mock.Anything, "tpl-1").Return(bugTemplate, nil)
+	mockTemplateRepo.On("GetIssueTemplateByID", mock.Anything, "missing").Return(db.IssueTemplate{}, sql.ErrNoRows)
+	mockTemplateRepo.On("ListIssueTemplateLabelIDs", mock.Anything, "tpl-1").Return([]string{"label-1"}, nil)
+	mockTemplateRepo.On("ListIssueTemplateAssigneeIDs", mock.Anything, "tpl-1").Return([]string{"user-456"}, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+	mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+	mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-456").Return(true, nil)
+	mockLabelRepo.On("ListTeamLabels", mock.Anything, "team-1").Return([]db.Label{{ID: "label-1"}, {ID: "label-2"}}, nil)
+	mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+
+	tests := []struct {
+		name       string
+		template   string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name:     "template fills what the request leaves out",
+			template: "tpl-1",
+			body:     `{"title":"Login fails","priority":"medium"}`,
+			setup: func() {
+				sqlMock.ExpectBegin()
+				mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateIssueParams) bool {
+					return p.TeamID == "team-1" && p.Title == "[Bug] Login fails" && p.Priority.String == "medium" && p.Content.String == "## Steps to reproduce"
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+				mockRepo.On("AddAssigneeToIssue", mock.Anything, mock.MatchedBy(func(p db.AddAssigneeToIssueParams) bool {
+					return p.UserID == "user-456"
+				})).Return(nil).Once()
+				mockLabelRepo.On("AddLabelToIssue", mock.Anything, mock.Anything, "label-1").Return(nil).Once()
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name:     "request labels replace the template's",
+			template: "tpl-1",
+			body:     `{"title":"Crash","team_id":"team-1","labels":["label-2"]}`,
+			setup: func() {
+				sqlMock.ExpectBegin()
+				mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				sqlMock.ExpectCommit()
+				mockRepo.On("AddAssigneeToIssue", mock.Anything, mock.Anything).Return(nil).Once()
+				mockLabelRepo.On("AddLabelToIssue", mock.Anything, mock.Anything, "label-2").Return(nil).Once()
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name:       "template of another team",
+			template:   "tpl-1",
+			body:       `{"title":"Crash","team_id":"team-2"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "unknown template",
+			template:   "missing",
+			body:       `{"title":"Crash","team_id":"team-1"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPost, "/issues?template="+tt.template, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+	mockRepo.AssertNumberOfCalls(t, "CreateIssueTx", 2)
+	mockRepo.AssertNumberOfCalls(t, "AddAssigneeToIssue", 2)
+	mockLabelRepo.AssertNumberOfCalls(t, "AddLabelToIssue", 2)
+}
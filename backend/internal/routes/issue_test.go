@@ -5,16 +5,21 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
 	"github.com/nack098/nakumanager/internal/db"
 	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/repositories"
 	"github.com/nack098/nakumanager/internal/routes"
 	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/nack098/nakumanager/internal/ws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -31,22 +36,45 @@ func TestNewIssueHandler(t *testing.T) {
 	mockRepo := new(mocks.MockIssueRepo)
 	projectRepo := new(mocks.MockProjectRepo)
 	teamRepo := new(mocks.MockTeamRepository)
+	commentRepo := new(mocks.MockCommentRepo)
+	labelRepo := new(mocks.MockLabelRepo)
+	relationRepo := new(mocks.MockRelationRepo)
+	cycleRepo := new(mocks.MockCycleRepo)
+	templateRepo := new(mocks.MockIssueTemplateRepo)
+	roleRepo := new(mocks.MockRoleRepo)
 
-	handler := routes.NewIssueHandler(db, mockRepo, teamRepo, projectRepo)
+	handler := routes.NewIssueHandler(db, mockRepo, teamRepo, projectRepo, commentRepo, labelRepo, relationRepo, cycleRepo, templateRepo, roleRepo)
 
 	assert.Equal(t, db, handler.DB)
 	assert.Equal(t, mockRepo, handler.Repo)
 	assert.Equal(t, teamRepo, handler.TeamRepo)
 	assert.Equal(t, projectRepo, handler.ProjectRepo)
+	assert.Equal(t, commentRepo, handler.CommentRepo)
+	assert.Equal(t, labelRepo, handler.LabelRepo)
+	assert.Equal(t, relationRepo, handler.RelationRepo)
+	assert.Equal(t, cycleRepo, handler.CycleRepo)
+	assert.Equal(t, templateRepo, handler.TemplateRepo)
+	assert.NotNil(t, handler.Authz)
 }
 
 func TestCreateIssue(t *testing.T) {
 	app := fiber.New()
-	db := &sql.DB{}
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
 	mockRepo := new(mocks.MockIssueRepo)
 	mockTeamRepo := new(mocks.MockTeamRepository)
 	mockProjRepo := new(mocks.MockProjectRepo)
-	handler := routes.IssueHandler{db, mockRepo, mockTeamRepo, mockProjRepo}
+	mockLabelRepo := new(mocks.MockLabelRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{
+		DB:          mockDB,
+		Repo:        mockRepo,
+		TeamRepo:    mockTeamRepo,
+		ProjectRepo: mockProjRepo,
+		LabelRepo:   mockLabelRepo,
+		Authz:       authz.NewAuthorizer(mockRoleRepo),
+	}
 
 	app.Use(withUserID("user-123"))
 	app.Post("/issues", handler.CreateIssue)
@@ -70,11 +98,20 @@ func TestCreateIssue(t *testing.T) {
 			wantStatus: fiber.StatusCreated,
 			setupMocks: mocks{
 				repo: func() {
-					mockRepo.On("CreateIssue", mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(nil)
+					sqlMock.ExpectBegin()
+					mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.GetLastIssueBoardRankParams) bool {
+						return p.TeamID == "team-1" && p.Status == "todo" && p.ViewID == ""
+					})).Return("i", nil)
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+					mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateIssueParams) bool {
+						return p.Rank == "r" && p.Number == 7 && p.Identifier == "ENG-7"
+					})).Return(nil)
+					sqlMock.ExpectCommit()
 				},
 				team: func() {
 					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 				},
 				project: func() {},
 			},
@@ -85,11 +122,103 @@ func TestCreateIssue(t *testing.T) {
 			wantStatus: fiber.StatusInternalServerError,
 			setupMocks: mocks{
 				repo: func() {
-					mockRepo.On("CreateIssue", mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(assert.AnError)
+					sqlMock.ExpectBegin()
+					mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+					mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(assert.AnError)
+					sqlMock.ExpectRollback()
+				},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				},
+				project: func() {},
+			},
+		},
+		{
+			name:       "rank lookup fails",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "Test Issue"},
+			wantStatus: fiber.StatusInternalServerError,
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+					sqlMock.ExpectBegin()
+					mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", assert.AnError)
+					sqlMock.ExpectRollback()
+				},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				},
+				project: func() {},
+			},
+		},
+		{
+			name:       "numbering fails",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "Test Issue"},
+			wantStatus: fiber.StatusInternalServerError,
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(0), "", assert.AnError)
+				},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				},
+				project: func() {},
+			},
+		},
+		{
+			name:       "custom status",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "Test Issue", Status: "in_review"},
+			wantStatus: fiber.StatusCreated,
+			setupMocks: mocks{
+				repo: func() {
+					sqlMock.ExpectBegin()
+					mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.GetLastIssueBoardRankParams) bool {
+						return p.Status == "in_review"
+					})).Return("", nil)
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+					mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateIssueParams) bool {
+						return p.Status == "in_review"
+					})).Return(nil)
+					sqlMock.ExpectCommit()
+				},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "in_review").Return(db.TeamStatus{TeamID: "team-1", Key: "in_review"}, nil)
+				},
+				project: func() {},
+			},
+		},
+		{
+			name:       "status not in team",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "Test Issue", Status: "archived"},
+			wantStatus: fiber.StatusBadRequest,
+			setupMocks: mocks{
+				repo: func() {},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "archived").Return(db.TeamStatus{}, sql.ErrNoRows)
 				},
+				project: func() {},
+			},
+		},
+		{
+			name:       "default status lookup fails",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "Test Issue"},
+			wantStatus: fiber.StatusInternalServerError,
+			setupMocks: mocks{
+				repo: func() {},
 				team: func() {
 					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("", assert.AnError)
 				},
 				project: func() {},
 			},
@@ -109,7 +238,7 @@ func TestCreateIssue(t *testing.T) {
 				repo: func() {},
 				team: func() {
 					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(false, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(workspaceMemberRoles, nil)
 				},
 				project: func() {},
 			},
@@ -134,7 +263,7 @@ func TestCreateIssue(t *testing.T) {
 				repo: func() {},
 				team: func() {
 					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(false, errors.New("mock error"))
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(noRoles, errors.New("mock error"))
 				},
 				project: func() {},
 			},
@@ -147,10 +276,83 @@ func TestCreateIssue(t *testing.T) {
 				repo: func() {},
 				team: func() {
 					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				},
+				project: func() {
+					mockProjRepo.On("GetProjectByID", mock.Anything, "proj-x").Return(db.Project{}, sql.ErrNoRows)
+				},
+			},
+		},
+		{
+			name:       "project member creates issue in project",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "In Project", ProjectID: ptr("proj-1")},
+			wantStatus: fiber.StatusCreated,
+			setupMocks: mocks{
+				repo: func() {
+					sqlMock.ExpectBegin()
+					mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+					mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateIssueParams) bool {
+						return p.ProjectID.String == "proj-1"
+					})).Return(nil)
+					sqlMock.ExpectCommit()
+				},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				},
+				project: func() {
+					mockProjRepo.On("GetProjectByID", mock.Anything, "proj-1").Return(db.Project{ID: "proj-1", WorkspaceID: "ws-1", TeamID: "team-1", CreatedBy: "user-999"}, nil)
+					mockProjRepo.On("IsProjectMember", mock.Anything, "proj-1", "user-123").Return(true, nil)
+				},
+			},
+		},
+		{
+			name:       "not a member of the project",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "In Project", ProjectID: ptr("proj-1")},
+			wantStatus: fiber.StatusForbidden,
+			setupMocks: mocks{
+				repo: func() {},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				},
+				project: func() {
+					mockProjRepo.On("GetProjectByID", mock.Anything, "proj-1").Return(db.Project{ID: "proj-1", WorkspaceID: "ws-1", TeamID: "team-1", CreatedBy: "user-999"}, nil)
+					mockProjRepo.On("IsProjectMember", mock.Anything, "proj-1", "user-123").Return(false, nil)
+				},
+			},
+		},
+		{
+			name:       "project of another team",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "In Project", ProjectID: ptr("proj-2")},
+			wantStatus: fiber.StatusBadRequest,
+			setupMocks: mocks{
+				repo: func() {},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(db.Team{ID: "team-1", Name: "Core", WorkspaceID: "ws-1"}, nil)
+				},
+				project: func() {
+					mockProjRepo.On("GetProjectByID", mock.Anything, "proj-2").Return(db.Project{ID: "proj-2", Name: "Mobile", WorkspaceID: "ws-1", TeamID: "team-2"}, nil)
+				},
+			},
+		},
+		{
+			name:       "project of another workspace",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "In Project", ProjectID: ptr("proj-3")},
+			wantStatus: fiber.StatusBadRequest,
+			setupMocks: mocks{
+				repo: func() {},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(db.Team{ID: "team-1", Name: "Core", WorkspaceID: "ws-1"}, nil)
 				},
 				project: func() {
-					mockProjRepo.On("IsProjectExists", mock.Anything, "proj-x").Return(false, nil)
+					mockProjRepo.On("GetProjectByID", mock.Anything, "proj-3").Return(db.Project{ID: "proj-3", Name: "Elsewhere", WorkspaceID: "ws-2", TeamID: "team-9"}, nil)
 				},
 			},
 		},
@@ -160,11 +362,16 @@ func TestCreateIssue(t *testing.T) {
 			wantStatus: fiber.StatusCreated,
 			setupMocks: mocks{
 				repo: func() {
-					mockRepo.On("CreateIssue", mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(nil)
+					sqlMock.ExpectBegin()
+					mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+					mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(nil)
+					sqlMock.ExpectCommit()
 				},
 				team: func() {
 					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-x").Return(false, nil)
 				},
 				project: func() {},
@@ -176,12 +383,17 @@ func TestCreateIssue(t *testing.T) {
 			wantStatus: fiber.StatusCreated,
 			setupMocks: mocks{
 				repo: func() {
-					mockRepo.On("CreateIssue", mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(nil)
+					sqlMock.ExpectBegin()
+					mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+					mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(nil)
+					sqlMock.ExpectCommit()
 					mockRepo.On("AddAssigneeToIssue", mock.Anything, mock.Anything).Return(assert.AnError)
 				},
 				team: func() {
 					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-y").Return(true, nil)
 				},
 				project: func() {},
@@ -203,16 +415,59 @@ func TestCreateIssue(t *testing.T) {
 			wantStatus: fiber.StatusCreated,
 			setupMocks: mocks{
 				repo: func() {
-					mockRepo.On("CreateIssue", mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(nil)
+					sqlMock.ExpectBegin()
+					mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+					mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(nil)
+					sqlMock.ExpectCommit()
 				},
 				team: func() {
 					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-z").Return(false, errors.New("mock error"))
 				},
 				project: func() {},
 			},
 		},
+		{
+			name:       "with labels",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "Labelled", Labels: &[]string{"l1", "l2"}},
+			wantStatus: fiber.StatusCreated,
+			setupMocks: mocks{
+				repo: func() {
+					sqlMock.ExpectBegin()
+					mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+					mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.AnythingOfType("db.CreateIssueParams")).Return(nil)
+					sqlMock.ExpectCommit()
+					mockLabelRepo.On("AddLabelToIssue", mock.Anything, mock.Anything, "l1").Return(nil).Once()
+					mockLabelRepo.On("AddLabelToIssue", mock.Anything, mock.Anything, "l2").Return(nil).Once()
+				},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+					mockLabelRepo.On("ListTeamLabels", mock.Anything, "team-1").Return([]db.Label{{ID: "l1"}, {ID: "l2"}}, nil)
+				},
+				project: func() {},
+			},
+		},
+		{
+			name:       "label the team cannot use",
+			body:       &models.IssueCreate{TeamID: "team-1", Title: "Labelled", Labels: &[]string{"l1", "other-team"}},
+			wantStatus: fiber.StatusBadRequest,
+			setupMocks: mocks{
+				repo: func() {},
+				team: func() {
+					mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+					mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+					mockLabelRepo.On("ListTeamLabels", mock.Anything, "team-1").Return([]db.Label{{ID: "l1"}}, nil)
+				},
+				project: func() {},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -229,14 +484,25 @@ func TestCreateIssue(t *testing.T) {
 			req.Header.Set("Content-Type", "application/json")
 			resp, _ := app.Test(req)
 			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
 
 			mockRepo.ExpectedCalls = nil
 			mockTeamRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
 			mockProjRepo.ExpectedCalls = nil
+			mockLabelRepo.ExpectedCalls = nil
 		})
 	}
 }
 
+// assigneeRule requires an assignee on issues moving to doing.
+var assigneeRule = []db.TeamTransitionRule{{
+	ID:          "rule-1",
+	TeamID:      "team-1",
+	ToStatus:    sql.NullString{String: "doing", Valid: true},
+	Requirement: "assignee",
+}}
+
 func TestUpdateIssue(t *testing.T) {
 	app := fiber.New()
 	mockDB, sqlMock, err := sqlmock.New()
@@ -246,11 +512,15 @@ func TestUpdateIssue(t *testing.T) {
 	mockTeamRepo := new(mocks.MockTeamRepository)
 	mockProjRepo := new(mocks.MockProjectRepo)
 
+	mockLabelRepo := new(mocks.MockLabelRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
 	handler := routes.IssueHandler{
 		DB:          mockDB,
 		Repo:        mockRepo,
 		TeamRepo:    mockTeamRepo,
 		ProjectRepo: mockProjRepo,
+		LabelRepo:   mockLabelRepo,
+		Authz:       authz.NewAuthorizer(mockRoleRepo),
 	}
 
 	app.Use(withUserID("user-123"))
@@ -281,13 +551,19 @@ func TestUpdateIssue(t *testing.T) {
 						Return(db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-123"}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
 				},
 				query: func() {
+					sqlMock.ExpectBegin()
 					sqlMock.ExpectExec("UPDATE issues SET title = .*").
 						WithArgs("Updated Title", "issue-1").
 						WillReturnResult(sqlmock.NewResult(1, 1))
+					mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+						return e.IssueID == "issue-1" && e.ActorID == "user-123" && e.Field == "title" &&
+							e.NewValue.String == "Updated Title"
+					})).Return(nil)
+					sqlMock.ExpectCommit()
 				},
 			},
 		},
@@ -302,13 +578,15 @@ func TestUpdateIssue(t *testing.T) {
 						Return(db.Issue{ID: "issue-err", TeamID: "team-X", OwnerID: "user-123"}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-X", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-X", "user-123").
+						Return(teamMemberRoles, nil)
 				},
 				query: func() {
+					sqlMock.ExpectBegin()
 					sqlMock.ExpectExec("UPDATE issues SET title = .*").
 						WithArgs("Failed", "issue-err").
 						WillReturnError(errors.New("query failed"))
+					sqlMock.ExpectRollback()
 				},
 			},
 		},
@@ -363,8 +641,8 @@ func TestUpdateIssue(t *testing.T) {
 						Return(db.Issue{ID: "issue-3", TeamID: "team-1", OwnerID: "someone-else"}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").
-						Return(false, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(workspaceMemberRoles, nil)
 				},
 				query: func() {},
 			},
@@ -380,8 +658,8 @@ func TestUpdateIssue(t *testing.T) {
 						Return(db.Issue{ID: "issue-4", TeamID: "team-err", OwnerID: "owner-1"}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-err", "user-123").
-						Return(false, errors.New("mock error"))
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-err", "user-123").
+						Return(noRoles, errors.New("mock error"))
 				},
 				query: func() {},
 			},
@@ -395,18 +673,26 @@ func TestUpdateIssue(t *testing.T) {
 				repo: func() {
 					mockRepo.On("GetIssueByID", mock.Anything, "issue-add-ok").
 						Return(db.Issue{ID: "issue-add-ok", TeamID: "team-1", OwnerID: "user-123"}, nil)
-					mockRepo.On("AddAssigneeToIssue", mock.Anything, db.AddAssigneeToIssueParams{
-						IssueID: "issue-add-ok",
-						UserID:  "user-x",
-					}).Return(nil)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-add-ok").
+						Return([]db.User{}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-x").
 						Return(true, nil)
 				},
-				query: func() {},
+				query: func() {
+					sqlMock.ExpectBegin()
+					mockRepo.On("AddAssigneeToIssueTx", mock.Anything, mock.Anything, db.AddAssigneeToIssueParams{
+						IssueID: "issue-add-ok",
+						UserID:  "user-x",
+					}).Return(nil).Once()
+					mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+						return e.IssueID == "issue-add-ok" && e.Field == "assignees"
+					})).Return(nil)
+					sqlMock.ExpectCommit()
+				},
 			},
 		},
 		{
@@ -418,10 +704,12 @@ func TestUpdateIssue(t *testing.T) {
 				repo: func() {
 					mockRepo.On("GetIssueByID", mock.Anything, "issue-add-check-error").
 						Return(db.Issue{ID: "issue-add-check-error", TeamID: "team-a", OwnerID: "user-123"}, nil)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-add-check-error").
+						Return([]db.User{}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-a", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-a", "user-123").
+						Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-a", "user-x").
 						Return(false, errors.New("check error"))
 				},
@@ -432,21 +720,26 @@ func TestUpdateIssue(t *testing.T) {
 			name:       "RemoveAssignee: error removing",
 			issueID:    "remove-error",
 			req:        &models.UpdateIssueRequest{RemoveAssignee: &[]string{"user-x"}},
-			wantStatus: fiber.StatusOK,
+			wantStatus: fiber.StatusInternalServerError,
 			setupMocks: mocks{
 				repo: func() {
 					mockRepo.On("GetIssueByID", mock.Anything, "remove-error").
 						Return(db.Issue{ID: "remove-error", TeamID: "team-b", OwnerID: "user-123"}, nil)
-					mockRepo.On("RemoveAssigneeFromIssue", mock.Anything, mock.Anything).
-						Return(assert.AnError)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "remove-error").
+						Return([]db.User{{ID: "user-x"}}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-b", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-b", "user-123").
+						Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-b", "user-x").
 						Return(true, nil)
 				},
-				query: func() {},
+				query: func() {
+					sqlMock.ExpectBegin()
+					mockRepo.On("RemoveAssigneeFromIssueTx", mock.Anything, mock.Anything, mock.Anything).
+						Return(assert.AnError).Once()
+					sqlMock.ExpectRollback()
+				},
 			},
 		},
 		{
@@ -458,10 +751,12 @@ func TestUpdateIssue(t *testing.T) {
 				repo: func() {
 					mockRepo.On("GetIssueByID", mock.Anything, "issue-add-notmember").
 						Return(db.Issue{ID: "issue-add-notmember", TeamID: "team-z", OwnerID: "user-123"}, nil)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-add-notmember").
+						Return([]db.User{}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-z", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-z", "user-123").
+						Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-z", "user-x").
 						Return(false, nil)
 				},
@@ -472,21 +767,26 @@ func TestUpdateIssue(t *testing.T) {
 			name:       "AddAssignee: error while adding",
 			issueID:    "issue-add-fail",
 			req:        &models.UpdateIssueRequest{AddAssignee: &[]string{"user-x"}},
-			wantStatus: fiber.StatusOK,
+			wantStatus: fiber.StatusInternalServerError,
 			setupMocks: mocks{
 				repo: func() {
 					mockRepo.On("GetIssueByID", mock.Anything, "issue-add-fail").
 						Return(db.Issue{ID: "issue-add-fail", TeamID: "team-z", OwnerID: "user-123"}, nil)
-					mockRepo.On("AddAssigneeToIssue", mock.Anything, mock.Anything).
-						Return(assert.AnError)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-add-fail").
+						Return([]db.User{}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-z", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-z", "user-123").
+						Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-z", "user-x").
 						Return(true, nil)
 				},
-				query: func() {},
+				query: func() {
+					sqlMock.ExpectBegin()
+					mockRepo.On("AddAssigneeToIssueTx", mock.Anything, mock.Anything, mock.Anything).
+						Return(assert.AnError).Once()
+					sqlMock.ExpectRollback()
+				},
 			},
 		},
 		{
@@ -498,10 +798,12 @@ func TestUpdateIssue(t *testing.T) {
 				repo: func() {
 					mockRepo.On("GetIssueByID", mock.Anything, "issue-remove-notmember").
 						Return(db.Issue{ID: "issue-remove-notmember", TeamID: "team-y", OwnerID: "user-123"}, nil)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-remove-notmember").
+						Return([]db.User{}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-y", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-y", "user-123").
+						Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-y", "user-y").
 						Return(false, nil)
 				},
@@ -512,21 +814,26 @@ func TestUpdateIssue(t *testing.T) {
 			name:       "RemoveAssignee: error while removing",
 			issueID:    "issue-remove-fail",
 			req:        &models.UpdateIssueRequest{RemoveAssignee: &[]string{"user-y"}},
-			wantStatus: fiber.StatusOK,
+			wantStatus: fiber.StatusInternalServerError,
 			setupMocks: mocks{
 				repo: func() {
 					mockRepo.On("GetIssueByID", mock.Anything, "issue-remove-fail").
 						Return(db.Issue{ID: "issue-remove-fail", TeamID: "team-y", OwnerID: "user-123"}, nil)
-					mockRepo.On("RemoveAssigneeFromIssue", mock.Anything, mock.Anything).
-						Return(assert.AnError)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-remove-fail").
+						Return([]db.User{{ID: "user-y"}}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-y", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-y", "user-123").
+						Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-y", "user-y").
 						Return(true, nil)
 				},
-				query: func() {},
+				query: func() {
+					sqlMock.ExpectBegin()
+					mockRepo.On("RemoveAssigneeFromIssueTx", mock.Anything, mock.Anything, mock.Anything).
+						Return(assert.AnError).Once()
+					sqlMock.ExpectRollback()
+				},
 			},
 		},
 		{
@@ -538,121 +845,452 @@ func TestUpdateIssue(t *testing.T) {
 				repo: func() {
 					mockRepo.On("GetIssueByID", mock.Anything, "issue-remove-ok").
 						Return(db.Issue{ID: "issue-remove-ok", TeamID: "team-x", OwnerID: "user-123"}, nil)
-					mockRepo.On("RemoveAssigneeFromIssue", mock.Anything, mock.Anything).
-						Return(nil)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-remove-ok").
+						Return([]db.User{{ID: "user-a"}}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-x", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-x", "user-123").
+						Return(teamMemberRoles, nil)
 					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-x", "user-a").
 						Return(true, nil)
 				},
-				query: func() {},
+				query: func() {
+					sqlMock.ExpectBegin()
+					mockRepo.On("RemoveAssigneeFromIssueTx", mock.Anything, mock.Anything, db.RemoveAssigneeFromIssueParams{
+						IssueID: "issue-remove-ok",
+						UserID:  "user-a",
+					}).Return(nil).Once()
+					mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+						return e.IssueID == "issue-remove-ok" && e.Field == "assignees"
+					})).Return(nil)
+					sqlMock.ExpectCommit()
+				},
 			},
 		},
 		{
-			name:       "RemoveAssignee: error checking membership",
-			issueID:    "issue-remove-check-error",
-			req:        &models.UpdateIssueRequest{RemoveAssignee: &[]string{"user-z"}},
-			wantStatus: fiber.StatusOK,
+			name:       "failed to fetch assignees",
+			issueID:    "issue-assignees-error",
+			req:        &models.UpdateIssueRequest{AddAssignee: &[]string{"user-x"}},
+			wantStatus: fiber.StatusInternalServerError,
 			setupMocks: mocks{
 				repo: func() {
-					mockRepo.On("GetIssueByID", mock.Anything, "issue-remove-check-error").
-						Return(db.Issue{ID: "issue-remove-check-error", TeamID: "team-z", OwnerID: "user-123"}, nil)
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-assignees-error").
+						Return(db.Issue{ID: "issue-assignees-error", TeamID: "team-1", OwnerID: "user-123"}, nil)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-assignees-error").
+						Return([]db.User(nil), errors.New("db error"))
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-z", "user-123").
-						Return(true, nil)
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-z", "user-z").
-						Return(false, errors.New("mock error"))
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
 				},
 				query: func() {},
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.req != nil {
-				tt.rawBody = mustJSON(tt.req)
-			}
-
-			tt.setupMocks.repo()
-			tt.setupMocks.team()
-			tt.setupMocks.query()
-
-			req := httptest.NewRequest(http.MethodPut, "/issues/"+tt.issueID, bytes.NewReader(tt.rawBody))
-			req.Header.Set("Content-Type", "application/json")
-			resp, err := app.Test(req)
-
-			require.NoError(t, err)
-			assert.Equal(t, tt.wantStatus, resp.StatusCode)
-			require.NoError(t, sqlMock.ExpectationsWereMet())
-
-			mockRepo.ExpectedCalls = nil
-			mockTeamRepo.ExpectedCalls = nil
-			mockProjRepo.ExpectedCalls = nil
-		})
-	}
-}
-
-func TestDeleteIssue(t *testing.T) {
-	app := fiber.New()
-	mockRepo := new(mocks.MockIssueRepo)
-	mockTeamRepo := new(mocks.MockTeamRepository)
-	handler := routes.IssueHandler{
-		DB:       nil,
-		Repo:     mockRepo,
-		TeamRepo: mockTeamRepo,
-	}
-
-	app.Use(withUserID("user-123"))
-	app.Delete("/issues/:id", handler.DeleteIssue)
-
-	type mocks struct {
-		repo func()
-		team func()
-	}
-
-	tests := []struct {
-		name       string
-		issueID    string
-		setupMocks mocks
-		wantStatus int
-	}{
 		{
-			name:    "Success",
-			issueID: "success-id",
+			name:       "unchanged fields record nothing",
+			issueID:    "issue-same",
+			req:        &models.UpdateIssueRequest{Status: ptr("todo")},
+			wantStatus: fiber.StatusOK,
 			setupMocks: mocks{
 				repo: func() {
-					mockRepo.On("GetIssueByID", mock.Anything, "success-id").
-						Return(db.Issue{ID: "success-id", TeamID: "team-1", OwnerID: "user-123"}, nil)
-					mockRepo.On("DeleteIssue", mock.Anything, "success-id").
-						Return(nil)
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-same").
+						Return(db.Issue{ID: "issue-same", TeamID: "team-1", OwnerID: "user-123", Status: "todo"}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "todo").Return(db.TeamStatus{TeamID: "team-1", Key: "todo"}, nil)
+				},
+				query: func() {
+					sqlMock.ExpectBegin()
+					sqlMock.ExpectExec("UPDATE issues SET status = .*").
+						WithArgs("todo", "issue-same").
+						WillReturnResult(sqlmock.NewResult(1, 1))
+					sqlMock.ExpectCommit()
 				},
 			},
-			wantStatus: fiber.StatusOK,
 		},
 		{
-			name:       "Missing ID",
-			issueID:    "undefined",
-			setupMocks: mocks{repo: func() {}, team: func() {}},
+			name:       "status not in team",
+			issueID:    "issue-status",
+			req:        &models.UpdateIssueRequest{Status: ptr("archived")},
 			wantStatus: fiber.StatusBadRequest,
-		},
-		{
-			name:    "🔍 Issue not found",
-			issueID: "not-found-id",
 			setupMocks: mocks{
 				repo: func() {
-					mockRepo.On("GetIssueByID", mock.Anything, "not-found-id").
-						Return(db.Issue{}, sql.ErrNoRows)
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-status").
+						Return(db.Issue{ID: "issue-status", TeamID: "team-1", OwnerID: "user-123", Status: "todo"}, nil)
 				},
-				team: func() {},
-			},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "archived").Return(db.TeamStatus{}, sql.ErrNoRows)
+				},
+				query: func() {},
+			},
+		},
+		{
+			name:       "new team lacks current status",
+			issueID:    "issue-move-team",
+			req:        &models.UpdateIssueRequest{TeamID: ptr("team-2")},
+			wantStatus: fiber.StatusBadRequest,
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-move-team").
+						Return(db.Issue{ID: "issue-move-team", TeamID: "team-1", OwnerID: "user-123", Status: "in_review"}, nil)
+				},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-2", "in_review").Return(db.TeamStatus{}, sql.ErrNoRows)
+				},
+				query: func() {},
+			},
+		},
+		{
+			name:       "transition rule not met",
+			issueID:    "issue-rule",
+			req:        &models.UpdateIssueRequest{Status: ptr("doing")},
+			wantStatus: fiber.StatusUnprocessableEntity,
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-rule").
+						Return(db.Issue{ID: "issue-rule", TeamID: "team-1", OwnerID: "user-123", Status: "todo"}, nil)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-rule").Return([]db.User{}, nil)
+				},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "doing").
+						Return(db.TeamStatus{TeamID: "team-1", Key: "doing", Category: "started"}, nil)
+					mockTeamRepo.On("ListTransitionRules", mock.Anything, "team-1").Return(assigneeRule, nil)
+				},
+				query: func() {},
+			},
+		},
+		{
+			name:       "assignee added by the same update meets the rule",
+			issueID:    "issue-rule-ok",
+			req:        &models.UpdateIssueRequest{Status: ptr("doing"), AddAssignee: &[]string{"user-x"}},
+			wantStatus: fiber.StatusOK,
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-rule-ok").
+						Return(db.Issue{ID: "issue-rule-ok", TeamID: "team-1", OwnerID: "user-123", Status: "todo"}, nil)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-rule-ok").Return([]db.User{}, nil)
+				},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "doing").
+						Return(db.TeamStatus{TeamID: "team-1", Key: "doing", Category: "started"}, nil)
+					mockTeamRepo.On("ListTransitionRules", mock.Anything, "team-1").Return(assigneeRule, nil)
+					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-x").Return(true, nil)
+				},
+				query: func() {
+					sqlMock.ExpectBegin()
+					sqlMock.ExpectExec("UPDATE issues SET status = .*").
+						WithArgs("doing", "issue-rule-ok").
+						WillReturnResult(sqlmock.NewResult(1, 1))
+					mockRepo.On("AddAssigneeToIssueTx", mock.Anything, mock.Anything, db.AddAssigneeToIssueParams{
+						IssueID: "issue-rule-ok",
+						UserID:  "user-x",
+					}).Return(nil).Once()
+					mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+					sqlMock.ExpectCommit()
+				},
+			},
+		},
+		{
+			name:       "RemoveAssignee: error checking membership",
+			issueID:    "issue-remove-check-error",
+			req:        &models.UpdateIssueRequest{RemoveAssignee: &[]string{"user-z"}},
+			wantStatus: fiber.StatusOK,
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-remove-check-error").
+						Return(db.Issue{ID: "issue-remove-check-error", TeamID: "team-z", OwnerID: "user-123"}, nil)
+					mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-remove-check-error").
+						Return([]db.User{}, nil)
+				},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-z", "user-123").
+						Return(teamMemberRoles, nil)
+					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-z", "user-z").
+						Return(false, errors.New("mock error"))
+				},
+				query: func() {},
+			},
+		},
+		{
+			name:       "labels added and removed are recorded",
+			issueID:    "issue-labels",
+			req:        &models.UpdateIssueRequest{AddLabel: &[]string{"l1", "l2"}, RemoveLabel: &[]string{"l2", "l3"}},
+			wantStatus: fiber.StatusOK,
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-labels").
+						Return(db.Issue{ID: "issue-labels", TeamID: "team-1", OwnerID: "user-123"}, nil)
+					mockLabelRepo.On("ListLabelsByIssueID", mock.Anything, "issue-labels").Return([]db.Label{{ID: "l2"}}, nil)
+				},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+					mockLabelRepo.On("ListTeamLabels", mock.Anything, "team-1").Return([]db.Label{{ID: "l1"}, {ID: "l2"}}, nil)
+				},
+				query: func() {
+					sqlMock.ExpectBegin()
+					mockLabelRepo.On("AddLabelToIssueTx", mock.Anything, mock.Anything, "issue-labels", "l1").Return(nil).Once()
+					mockLabelRepo.On("RemoveLabelFromIssueTx", mock.Anything, mock.Anything, "issue-labels", "l2").Return(nil).Once()
+					mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+						return e.Field == "labels" && e.NewValue.String == "l1" && !e.OldValue.Valid
+					})).Return(nil).Once()
+					mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+						return e.Field == "labels" && e.OldValue.String == "l2" && !e.NewValue.Valid
+					})).Return(nil).Once()
+					sqlMock.ExpectCommit()
+				},
+			},
+		},
+		{
+			name:       "label of the team the issue moves to",
+			issueID:    "issue-label-team",
+			req:        &models.UpdateIssueRequest{TeamID: ptr("team-2"), AddLabel: &[]string{"l1"}},
+			wantStatus: fiber.StatusBadRequest,
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-label-team").
+						Return(db.Issue{ID: "issue-label-team", TeamID: "team-1", OwnerID: "user-123", Status: "todo"}, nil)
+				},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-2", "todo").
+						Return(db.TeamStatus{TeamID: "team-2", Key: "todo"}, nil)
+					mockLabelRepo.On("ListTeamLabels", mock.Anything, "team-2").Return([]db.Label{}, nil)
+				},
+				query: func() {},
+			},
+		},
+		{
+			name:       "issue moved by identifier gets a number in the new team",
+			issueID:    "ENG-3",
+			req:        &models.UpdateIssueRequest{TeamID: ptr("team-2")},
+			wantStatus: fiber.StatusOK,
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("ListIssueIDsByIdentifier", mock.Anything, "ENG-3", "user-123").Return([]string{"issue-renumber"}, nil)
+					mockRepo.On("GetIssueByID", mock.Anything, "issue-renumber").
+						Return(db.Issue{ID: "issue-renumber", TeamID: "team-1", OwnerID: "user-123", Status: "todo", Number: 3, Identifier: "ENG-3"}, nil)
+					mockRepo.On("NextIssueNumber", mock.Anything, "team-2").Return(int64(12), "OPS-12", nil)
+				},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+					mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-2", "todo").
+						Return(db.TeamStatus{TeamID: "team-2", Key: "todo"}, nil)
+				},
+				query: func() {
+					sqlMock.ExpectBegin()
+					sqlMock.ExpectExec(`UPDATE issues SET team_id = \?, number = \?, identifier = \? WHERE id = \?`).
+						WithArgs("team-2", int64(12), "OPS-12", "issue-renumber").
+						WillReturnResult(sqlmock.NewResult(1, 1))
+					mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+						return e.Field == "team_id" && e.NewValue.String == "team-2"
+					})).Return(nil).Once()
+					mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+						return e.Field == "identifier" && e.OldValue.String == "ENG-3" && e.NewValue.String == "OPS-12"
+					})).Return(nil).Once()
+					sqlMock.ExpectCommit()
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.req != nil {
+				tt.rawBody = mustJSON(tt.req)
+			}
+
+			tt.setupMocks.repo()
+			tt.setupMocks.team()
+			tt.setupMocks.query()
+
+			req := httptest.NewRequest(http.MethodPut, "/issues/"+tt.issueID, bytes.NewReader(tt.rawBody))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+
+			mockRepo.ExpectedCalls = nil
+			mockTeamRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+			mockProjRepo.ExpectedCalls = nil
+			mockLabelRepo.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestUpdateIssueTransitionViolations(t *testing.T) {
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{Repo: mockRepo, TeamRepo: mockTeamRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Put("/issues/:id", handler.UpdateIssue)
+
+	mockRepo.On("GetIssueByID", mock.Anything, "issue-1").
+		Return(db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-123", Status: "doing"}, nil)
+	mockRepo.On("ListAssigneesByIssueID", mock.Anything, "issue-1").Return([]db.User{{ID: "user-456"}}, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "done").
+		Return(db.TeamStatus{TeamID: "team-1", Key: "done", Category: "completed"}, nil)
+	mockTeamRepo.On("ListTransitionRules", mock.Anything, "team-1").Return([]db.TeamTransitionRule{
+		{ID: "rule-1", TeamID: "team-1", ToCategory: sql.NullString{String: "completed", Valid: true}, Requirement: "end_date"},
+		{ID: "rule-2", TeamID: "team-1", ToStatus: sql.NullString{String: "done", Valid: true}, Requirement: "actor_is_assignee"},
+		{ID: "rule-3", TeamID: "team-1", ToStatus: sql.NullString{String: "todo", Valid: true}, Requirement: "forbidden"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/issues/issue-1", bytes.NewReader([]byte(`{"status":"done"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	var body struct {
+		Error      string `json:"error"`
+		From       string `json:"from"`
+		To         string `json:"to"`
+		Violations []struct {
+			RuleID      string `json:"rule_id"`
+			Requirement string `json:"requirement"`
+		} `json:"violations"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "transition not allowed", body.Error)
+	assert.Equal(t, "doing", body.From)
+	assert.Equal(t, "done", body.To)
+	require.Len(t, body.Violations, 2)
+	assert.Equal(t, "rule-1", body.Violations[0].RuleID)
+	assert.Equal(t, "end_date", body.Violations[0].Requirement)
+	assert.Equal(t, "rule-2", body.Violations[1].RuleID)
+	assert.Equal(t, "actor_is_assignee", body.Violations[1].Requirement)
+}
+
+func TestDeleteIssue(t *testing.T) {
+	app := fiber.New()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{
+		DB:       nil,
+		Repo:     mockRepo,
+		TeamRepo: mockTeamRepo,
+		Authz:    authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app.Use(withUserID("user-123"))
+	app.Delete("/issues/:id", handler.DeleteIssue)
+
+	type mocks struct {
+		repo func()
+		team func()
+	}
+
+	tests := []struct {
+		name       string
+		issueID    string
+		setupMocks mocks
+		wantStatus int
+	}{
+		{
+			name:    "Success",
+			issueID: "success-id",
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("GetIssueByID", mock.Anything, "success-id").
+						Return(db.Issue{ID: "success-id", TeamID: "team-1", OwnerID: "user-123"}, nil)
+					mockRepo.On("DeleteIssue", mock.Anything, "success-id").
+						Return(nil)
+				},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+				},
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "Missing ID",
+			issueID:    "undefined",
+			setupMocks: mocks{repo: func() {}, team: func() {}},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:    "by identifier",
+			issueID: "ENG-7",
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("ListIssueIDsByIdentifier", mock.Anything, "ENG-7", "user-123").Return([]string{"success-id"}, nil)
+					mockRepo.On("GetIssueByID", mock.Anything, "success-id").
+						Return(db.Issue{ID: "success-id", TeamID: "team-1", OwnerID: "user-123", Identifier: "ENG-7"}, nil)
+					mockRepo.On("DeleteIssue", mock.Anything, "success-id").
+						Return(nil)
+				},
+				team: func() {
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+						Return(teamMemberRoles, nil)
+				},
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:    "unknown identifier",
+			issueID: "ENG-404",
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("ListIssueIDsByIdentifier", mock.Anything, "ENG-404", "user-123").Return([]string{}, nil)
+				},
+				team: func() {},
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name:    "identifier in two workspaces",
+			issueID: "ENG-1",
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("ListIssueIDsByIdentifier", mock.Anything, "ENG-1", "user-123").Return([]string{"a", "b"}, nil)
+				},
+				team: func() {},
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:    "identifier lookup fails",
+			issueID: "ENG-2",
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("ListIssueIDsByIdentifier", mock.Anything, "ENG-2", "user-123").Return(nil, errors.New("db error"))
+				},
+				team: func() {},
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+		{
+			name:    "🔍 Issue not found",
+			issueID: "not-found-id",
+			setupMocks: mocks{
+				repo: func() {
+					mockRepo.On("GetIssueByID", mock.Anything, "not-found-id").
+						Return(db.Issue{}, sql.ErrNoRows)
+				},
+				team: func() {},
+			},
 			wantStatus: fiber.StatusNotFound,
 		},
 		{
@@ -676,8 +1314,8 @@ func TestDeleteIssue(t *testing.T) {
 						Return(db.Issue{ID: "unauth-id", TeamID: "team-2", OwnerID: "someone-else"}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-2", "user-123").
-						Return(false, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-2", "user-123").
+						Return(workspaceMemberRoles, nil)
 				},
 			},
 			wantStatus: fiber.StatusForbidden,
@@ -691,8 +1329,8 @@ func TestDeleteIssue(t *testing.T) {
 						Return(db.Issue{ID: "team-error-id", TeamID: "team-err", OwnerID: "user-123"}, nil)
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-err", "user-123").
-						Return(false, errors.New("check error"))
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-err", "user-123").
+						Return(noRoles, errors.New("check error"))
 				},
 			},
 			wantStatus: fiber.StatusInternalServerError,
@@ -708,8 +1346,8 @@ func TestDeleteIssue(t *testing.T) {
 						Return(errors.New("delete error"))
 				},
 				team: func() {
-					mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-x", "user-123").
-						Return(true, nil)
+					mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-x", "user-123").
+						Return(teamMemberRoles, nil)
 				},
 			},
 			wantStatus: fiber.StatusInternalServerError,
@@ -729,44 +1367,103 @@ func TestDeleteIssue(t *testing.T) {
 
 			mockRepo.ExpectedCalls = nil
 			mockTeamRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
 		})
 	}
 }
 
-func TestGetIssuesByUserID(t *testing.T) {
+func TestListIssues(t *testing.T) {
 	app := fiber.New()
 	mockRepo := new(mocks.MockIssueRepo)
-	handler := routes.IssueHandler{
-		DB:          nil,
-		Repo:        mockRepo,
-		TeamRepo:    nil,
-		ProjectRepo: nil,
-	}
+	handler := routes.IssueHandler{Repo: mockRepo}
 
 	app.Use(withUserID("user-123"))
-	app.Get("/issues/user", handler.GetIssuesByUserID)
+	app.Get("/issues", handler.ListIssues)
+
+	endTo := time.Date(2024, 6, 30, 23, 59, 59, int(999*time.Millisecond), time.UTC)
 
 	tests := []struct {
 		name       string
+		query      string
 		setupMocks func()
 		wantStatus int
+		wantCursor string
 	}{
 		{
-			name: "success",
+			name:  "success without filters",
+			query: "",
+			setupMocks: func() {
+				mockRepo.On("ListIssues", mock.Anything, "user-123", mock.MatchedBy(func(f models.IssueFilter) bool {
+					return len(f.Statuses) == 0 && len(f.Sort) == 0 && f.Limit == 50 && f.Cursor == ""
+				})).Return([]db.Issue{
+					{ID: "i1", Title: "Test 1"},
+					{ID: "i2", Title: "Test 2"},
+				}, "next", nil)
+			},
+			wantStatus: fiber.StatusOK,
+			wantCursor: "next",
+		},
+		{
+			name:  "filters are parsed from the query string",
+			query: "?status=todo,doing&assignee=me,u2,none&owner=me&team=t1&q=%20login%20&sort=-priority,title&end_to=2024-06-30&limit=10&cursor=abc",
+			setupMocks: func() {
+				mockRepo.On("ListIssues", mock.Anything, "user-123", mock.MatchedBy(func(f models.IssueFilter) bool {
+					return assert.ObjectsAreEqual([]string{"todo", "doing"}, f.Statuses) &&
+						assert.ObjectsAreEqual([]string{"user-123", "u2"}, f.AssigneeIDs) &&
+						f.Unassigned &&
+						assert.ObjectsAreEqual([]string{"user-123"}, f.OwnerIDs) &&
+						assert.ObjectsAreEqual([]string{"t1"}, f.TeamIDs) &&
+						f.Text == "login" &&
+						assert.ObjectsAreEqual([]models.IssueSort{{Field: "priority", Desc: true}, {Field: "title"}}, f.Sort) &&
+						f.EndTo != nil && f.EndTo.Equal(endTo) &&
+						f.Limit == 10 && f.Cursor == "abc"
+				})).Return([]db.Issue{}, "", nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:  "overdue issues",
+			query: "?overdue=true&assignee=me",
 			setupMocks: func() {
-				mockRepo.On("GetIssueByUserID", mock.Anything, "user-123").
-					Return([]db.Issue{
-						{ID: "i1", Title: "Test 1"},
-						{ID: "i2", Title: "Test 2"},
-					}, nil)
+				mockRepo.On("ListIssues", mock.Anything, "user-123", mock.MatchedBy(func(f models.IssueFilter) bool {
+					return f.OverdueAt != nil && time.Since(*f.OverdueAt) < time.Minute
+				})).Return([]db.Issue{}, "", nil)
 			},
 			wantStatus: fiber.StatusOK,
 		},
 		{
-			name: "repo error",
+			name:       "invalid overdue",
+			query:      "?overdue=yes",
+			setupMocks: func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "invalid date",
+			query:      "?start_from=yesterday",
+			setupMocks: func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "limit too large",
+			query:      "?limit=1000",
+			setupMocks: func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:  "invalid sort or cursor",
+			query: "?sort=owner_id",
+			setupMocks: func() {
+				mockRepo.On("ListIssues", mock.Anything, "user-123", mock.Anything).
+					Return(nil, "", fmt.Errorf("%w: unknown sort field", repositories.ErrInvalidIssueFilter))
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:  "repo error",
+			query: "",
 			setupMocks: func() {
-				mockRepo.On("GetIssueByUserID", mock.Anything, "user-123").
-					Return([]db.Issue{}, errors.New("unexpected error"))
+				mockRepo.On("ListIssues", mock.Anything, "user-123", mock.Anything).
+					Return(nil, "", errors.New("unexpected error"))
 			},
 			wantStatus: fiber.StatusInternalServerError,
 		},
@@ -778,11 +1475,724 @@ func TestGetIssuesByUserID(t *testing.T) {
 
 			tt.setupMocks()
 
-			req := httptest.NewRequest(http.MethodGet, "/issues/user", nil)
-			req.Header.Set("Content-Type", "application/json")
+			req := httptest.NewRequest(http.MethodGet, "/issues"+tt.query, nil)
 			resp, err := app.Test(req)
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusOK {
+				var body struct {
+					Issues     []db.Issue `json:"issues"`
+					NextCursor string     `json:"next_cursor"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.wantCursor, body.NextCursor)
+			}
 		})
 	}
 }
+
+func TestGetIssueHistory(t *testing.T) {
+	app := fiber.New()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{
+		Repo:     mockRepo,
+		TeamRepo: mockTeamRepo,
+		Authz:    authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app.Use(withUserID("user-123"))
+	app.Get("/issues/:id/history", handler.GetIssueHistory)
+
+	tests := []struct {
+		name       string
+		url        string
+		setupMocks func()
+		wantStatus int
+	}{
+		{
+			name: "success",
+			url:  "/issues/issue-1/history?limit=10&offset=5",
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-1").
+					Return(db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-123"}, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				mockRepo.On("ListIssueEvents", mock.Anything, "issue-1", int64(10), int64(5)).
+					Return([]db.IssueEvent{{ID: "e1", IssueID: "issue-1", Field: "title"}}, nil)
+				mockRepo.On("CountIssueEvents", mock.Anything, "issue-1").Return(int64(6), nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "limit too large",
+			url:        "/issues/issue-1/history?limit=500",
+			setupMocks: func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "not a team member",
+			url:  "/issues/issue-2/history",
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-2").
+					Return(db.Issue{ID: "issue-2", TeamID: "team-2", OwnerID: "someone"}, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-2", "user-123").Return(workspaceMemberRoles, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name: "list fails",
+			url:  "/issues/issue-3/history",
+			setupMocks: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "issue-3").
+					Return(db.Issue{ID: "issue-3", TeamID: "team-1", OwnerID: "user-123"}, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				mockRepo.On("ListIssueEvents", mock.Anything, "issue-3", int64(50), int64(0)).
+					Return(nil, errors.New("db error"))
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks()
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.url, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			mockRepo.ExpectedCalls = nil
+			mockTeamRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestMoveIssue(t *testing.T) {
+	issue := db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-123", Status: "todo", Rank: "5"}
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMocks func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock)
+		wantStatus int
+		wantRank   string
+	}{
+		{
+			name: "between two cards of another column",
+			body: `{"status":"doing","after_id":"a","before_id":"b"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				repo.On("GetIssueBoardRankTx", mock.Anything, mock.Anything, db.GetIssueBoardRankParams{ID: "a"}).
+					Return(db.GetIssueBoardRankRow{ID: "a", TeamID: "team-1", Status: "doing", Rank: "a"}, nil)
+				repo.On("GetIssueBoardRankTx", mock.Anything, mock.Anything, db.GetIssueBoardRankParams{ID: "b"}).
+					Return(db.GetIssueBoardRankRow{ID: "b", TeamID: "team-1", Status: "doing", Rank: "b"}, nil)
+				repo.On("MoveIssueTx", mock.Anything, mock.Anything, "", "issue-1", "doing", "ai").Return(nil)
+				repo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.Field == "status" && e.OldValue.String == "todo" && e.NewValue.String == "doing"
+				})).Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+			wantRank:   "ai",
+		},
+		{
+			name: "to the bottom of a view's column",
+			body: `{"view_id":"view-1"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {
+				repo.On("GetTeamIDByViewID", mock.Anything, "view-1").Return("team-1", nil)
+				sqlMock.ExpectBegin()
+				repo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, db.GetLastIssueBoardRankParams{
+					ViewID: "view-1", TeamID: "team-1", Status: "todo", ID: "issue-1",
+				}).Return("i", nil)
+				repo.On("MoveIssueTx", mock.Anything, mock.Anything, "view-1", "issue-1", "todo", "r").Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+			wantRank:   "r",
+		},
+		{
+			name:       "status not in team",
+			body:       `{"status":"archived"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "transition rule not met",
+			body:       `{"status":"done"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusUnprocessableEntity,
+		},
+		{
+			name:       "next to itself",
+			body:       `{"after_id":"issue-1"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "next to itself by identifier",
+			body: `{"after_id":"ENG-1"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {
+				repo.On("ListIssueIDsByIdentifier", mock.Anything, "ENG-1", "user-123").Return([]string{"issue-1"}, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "view of another team",
+			body: `{"view_id":"view-2"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {
+				repo.On("GetTeamIDByViewID", mock.Anything, "view-2").Return("team-2", nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "view not found",
+			body: `{"view_id":"view-3"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {
+				repo.On("GetTeamIDByViewID", mock.Anything, "view-3").Return("", sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name: "neighbour left the column",
+			body: `{"after_id":"a"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				repo.On("GetIssueBoardRankTx", mock.Anything, mock.Anything, db.GetIssueBoardRankParams{ID: "a"}).
+					Return(db.GetIssueBoardRankRow{ID: "a", TeamID: "team-1", Status: "done", Rank: "a"}, nil)
+				sqlMock.ExpectRollback()
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name: "neighbours out of order",
+			body: `{"after_id":"a","before_id":"b"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				repo.On("GetIssueBoardRankTx", mock.Anything, mock.Anything, db.GetIssueBoardRankParams{ID: "a"}).
+					Return(db.GetIssueBoardRankRow{ID: "a", TeamID: "team-1", Status: "todo", Rank: "c"}, nil)
+				repo.On("GetIssueBoardRankTx", mock.Anything, mock.Anything, db.GetIssueBoardRankParams{ID: "b"}).
+					Return(db.GetIssueBoardRankRow{ID: "b", TeamID: "team-1", Status: "todo", Rank: "b"}, nil)
+				sqlMock.ExpectRollback()
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name: "write fails",
+			body: `{"before_id":"b"}`,
+			setupMocks: func(repo *mocks.MockIssueRepo, sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				repo.On("GetIssueBoardRankTx", mock.Anything, mock.Anything, db.GetIssueBoardRankParams{ID: "b"}).
+					Return(db.GetIssueBoardRankRow{ID: "b", TeamID: "team-1", Status: "todo", Rank: "b"}, nil)
+				repo.On("MoveIssueTx", mock.Anything, mock.Anything, "", "issue-1", "todo", "5").Return(errors.New("db error"))
+				sqlMock.ExpectRollback()
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			mockRepo := new(mocks.MockIssueRepo)
+			mockTeamRepo := new(mocks.MockTeamRepository)
+			mockRoleRepo := new(mocks.MockRoleRepo)
+			handler := routes.IssueHandler{DB: mockDB, Repo: mockRepo, TeamRepo: mockTeamRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+			app := fiber.New()
+			app.Use(withUserID("user-123"))
+			app.Post("/issues/:id/move", handler.MoveIssue)
+
+			mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(issue, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+			mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "doing").Return(db.TeamStatus{TeamID: "team-1", Key: "doing"}, nil)
+			mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "archived").Return(db.TeamStatus{}, sql.ErrNoRows)
+			mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "done").
+				Return(db.TeamStatus{TeamID: "team-1", Key: "done", Category: "completed"}, nil)
+			mockTeamRepo.On("ListTransitionRules", mock.Anything, "team-1").Return([]db.TeamTransitionRule{
+				{ID: "rule-1", TeamID: "team-1", ToCategory: sql.NullString{String: "completed", Valid: true}, Requirement: "end_date"},
+			}, nil)
+			tt.setupMocks(mockRepo, sqlMock)
+
+			req := httptest.NewRequest(http.MethodPost, "/issues/issue-1/move", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+
+			if tt.wantRank != "" {
+				var body struct {
+					Issue models.IssueMoved `json:"issue"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.wantRank, body.Issue.Rank)
+			}
+		})
+	}
+}
+
+func TestMoveIssueBroadcast(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{DB: mockDB, Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/issues/:id/move", handler.MoveIssue)
+
+	board := &ws.MockConn{}
+	ws.SetConnectionForTest(ws.RoomKey("team", "team-board"), "user-456", board)
+	defer ws.UnregisterFromRoom("user-456", "team", "team-board")
+
+	mockRepo.On("GetIssueByID", mock.Anything, "issue-9").Return(db.Issue{ID: "issue-9", TeamID: "team-board", Status: "todo", Rank: "i"}, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-board", "user-123").Return(teamMemberRoles, nil)
+	sqlMock.ExpectBegin()
+	mockRepo.On("GetIssueBoardRankTx", mock.Anything, mock.Anything, db.GetIssueBoardRankParams{ID: "top"}).
+		Return(db.GetIssueBoardRankRow{ID: "top", TeamID: "team-board", Status: "todo", Rank: "a"}, nil)
+	mockRepo.On("MoveIssueTx", mock.Anything, mock.Anything, "", "issue-9", "todo", "5").Return(nil)
+	sqlMock.ExpectCommit()
+
+	req := httptest.NewRequest(http.MethodPost, "/issues/issue-9/move", bytes.NewReader([]byte(`{"before_id":"top"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	require.Len(t, board.Messages, 1)
+	msg := board.Messages[0].(map[string]interface{})
+	assert.Equal(t, "issue_moved", msg["type"])
+	assert.Equal(t, models.IssueMoved{IssueID: "issue-9", Status: "todo", Rank: "5", BeforeID: "top"}, msg["data"])
+}
+
+func TestListIssueChildren(t *testing.T) {
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Get("/issues/:id/children", handler.ListIssueChildren)
+
+	t.Run("children with progress", func(t *testing.T) {
+		mockRepo.On("GetIssueByID", mock.Anything, "parent").
+			Return(db.Issue{ID: "parent", TeamID: "team-1", OwnerID: "user-123"}, nil).Once()
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil).Once()
+		mockRepo.On("ListChildIssues", mock.Anything, "parent").Return([]db.Issue{
+			{ID: "child-1", ParentID: sql.NullString{String: "parent", Valid: true}},
+			{ID: "child-2", ParentID: sql.NullString{String: "parent", Valid: true}},
+		}, nil).Once()
+		mockRepo.On("GetSubIssueProgress", mock.Anything, "parent").
+			Return(db.GetSubIssueProgressRow{Total: 5, Completed: 3}, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/issues/parent/children", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Children []db.Issue              `json:"children"`
+			Progress models.SubIssueProgress `json:"progress"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Len(t, body.Children, 2)
+		assert.Equal(t, models.SubIssueProgress{Completed: 3, Total: 5}, body.Progress)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		mockRepo.On("GetIssueByID", mock.Anything, "other").
+			Return(db.Issue{ID: "other", TeamID: "team-2", OwnerID: "someone"}, nil).Once()
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-2", "user-123").Return(noRoles, nil).Once()
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/issues/other/children", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("issue not found", func(t *testing.T) {
+		mockRepo.On("GetIssueByID", mock.Anything, "missing").Return(db.Issue{}, sql.ErrNoRows).Once()
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/issues/missing/children", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestCreateSubIssue(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{DB: mockDB, Repo: mockRepo, TeamRepo: mockTeamRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/issues", handler.CreateIssue)
+
+	mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+	mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+
+	t.Run("parent by identifier", func(t *testing.T) {
+		mockRepo.On("ListIssueIDsByIdentifier", mock.Anything, "ENG-1", "user-123").Return([]string{"parent"}, nil).Once()
+		mockRepo.On("GetIssueByID", mock.Anything, "parent").
+			Return(db.Issue{ID: "parent", TeamID: "team-1", OwnerID: "user-123"}, nil).Once()
+		mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(2), "ENG-2", nil).Once()
+		sqlMock.ExpectBegin()
+		mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", nil).Once()
+		mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateIssueParams) bool {
+			return p.ParentID == sql.NullString{String: "parent", Valid: true}
+		})).Return(nil).Once()
+		sqlMock.ExpectCommit()
+
+		body := mustJSON(models.IssueCreate{Title: "Child", TeamID: "team-1", ParentID: ptr("ENG-1")})
+		req := httptest.NewRequest(http.MethodPost, "/issues", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		require.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		mockRepo.On("GetIssueByID", mock.Anything, "missing").Return(db.Issue{}, sql.ErrNoRows).Once()
+
+		body := mustJSON(models.IssueCreate{Title: "Child", TeamID: "team-1", ParentID: ptr("missing")})
+		req := httptest.NewRequest(http.MethodPost, "/issues", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		mockRepo.AssertNumberOfCalls(t, "CreateIssueTx", 1)
+	})
+}
+
+func TestUpdateIssueParent(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockRelationRepo := new(mocks.MockRelationRepo)
+	handler := routes.IssueHandler{DB: mockDB, Repo: mockRepo, TeamRepo: mockTeamRepo, RelationRepo: mockRelationRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Patch("/issues/:id", handler.UpdateIssue)
+
+	patch := func(id, body string) int {
+		req := httptest.NewRequest(http.MethodPatch, "/issues/"+id, bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name:       "own parent",
+			body:       `{"parent_id":"issue-1"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "parent is a sub-issue of the issue",
+			body: `{"parent_id":"grandchild"}`,
+			setup: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "grandchild").
+					Return(db.Issue{ID: "grandchild", TeamID: "team-1", OwnerID: "user-123"}, nil)
+				mockRepo.On("ListIssueAncestorIDs", mock.Anything, "grandchild").Return([]string{"child", "issue-1"}, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "set parent",
+			body: `{"parent_id":"epic"}`,
+			setup: func() {
+				mockRepo.On("GetIssueByID", mock.Anything, "epic").
+					Return(db.Issue{ID: "epic", TeamID: "team-1", OwnerID: "user-123"}, nil)
+				mockRepo.On("ListIssueAncestorIDs", mock.Anything, "epic").Return([]string{}, nil)
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(`UPDATE issues SET parent_id = \? WHERE id = \?`).
+					WithArgs(sql.NullString{String: "epic", Valid: true}, "issue-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.Field == "parent_id" && e.NewValue.String == "epic"
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "detach",
+			body: `{"parent_id":""}`,
+			setup: func() {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(`UPDATE issues SET parent_id = \? WHERE id = \?`).
+					WithArgs(sql.NullString{}, "issue-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.Field == "parent_id" && e.OldValue.String == "old-parent" && !e.NewValue.Valid
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "cascade status to open sub-issues",
+			body: `{"status":"done","cascade_status":true}`,
+			setup: func() {
+				mockTeamRepo.On("GetTeamStatus", mock.Anything, "team-1", "done").
+					Return(db.TeamStatus{TeamID: "team-1", Key: "done", Category: "completed"}, nil)
+				mockTeamRepo.On("ListTransitionRules", mock.Anything, "team-1").Return([]db.TeamTransitionRule{
+					{ID: "rule-1", TeamID: "team-1", FromStatus: sql.NullString{String: "review", Valid: true}, ToStatus: sql.NullString{String: "done", Valid: true}, Requirement: "forbidden"},
+				}, nil)
+				mockTeamRepo.On("ListTeamStatuses", mock.Anything, "team-1").Return([]db.TeamStatus{
+					{Key: "todo", Category: "unstarted"},
+					{Key: "review", Category: "started"},
+					{Key: "done", Category: "completed"},
+					{Key: "wontfix", Category: "cancelled"},
+				}, nil)
+				mockRepo.On("ListIssueDescendants", mock.Anything, "issue-1").Return([]db.Issue{
+					{ID: "open", TeamID: "team-1", Status: "todo"},
+					{ID: "blocked-by-rule", TeamID: "team-1", Status: "review"},
+					{ID: "cancelled", TeamID: "team-1", Status: "wontfix"},
+					{ID: "other-team", TeamID: "team-2", Status: "todo"},
+				}, nil)
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(`UPDATE issues SET status = \? WHERE id = \?`).
+					WithArgs("done", "issue-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.IssueID == "issue-1" && e.Field == "status"
+				})).Return(nil).Once()
+				mockRepo.On("SetIssueStatusTx", mock.Anything, mock.Anything, "open", "done").Return(nil).Once()
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.IssueID == "open" && e.Field == "status" && e.OldValue.String == "todo" && e.NewValue.String == "done"
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+				mockRelationRepo.On("ListOpenBlockedIssues", mock.Anything, "issue-1").Return([]db.ListOpenBlockedIssuesRow{}, nil).Once()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(db.Issue{
+				ID: "issue-1", TeamID: "team-1", OwnerID: "user-123", Status: "todo",
+				ParentID: sql.NullString{String: "old-parent", Valid: true},
+			}, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+			tt.setup()
+
+			assert.Equal(t, tt.wantStatus, patch("issue-1", tt.body))
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+
+			mockRepo.ExpectedCalls = nil
+			mockTeamRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+
+	// only the open sub-issue the rules let through moved
+	mockRepo.AssertNumberOfCalls(t, "SetIssueStatusTx", 1)
+}
+
+func TestDeleteIssueChildren(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{DB: mockDB, Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Delete("/issues/:id", handler.DeleteIssue)
+
+	del := func(url string) int {
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, url, nil))
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	setup := func(child db.Issue) {
+		mockRepo.ExpectedCalls = nil
+		mockRoleRepo.ExpectedCalls = nil
+		mockRepo.On("GetIssueByID", mock.Anything, "parent").
+			Return(db.Issue{ID: "parent", TeamID: "team-1", OwnerID: "user-123"}, nil)
+		mockRepo.On("ListIssueDescendants", mock.Anything, "parent").Return([]db.Issue{child}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-2", "user-123").Return(workspaceMemberRoles, nil)
+	}
+
+	t.Run("invalid option", func(t *testing.T) {
+		assert.Equal(t, fiber.StatusBadRequest, del("/issues/parent?children=orphan"))
+	})
+
+	t.Run("delete sub-issues too", func(t *testing.T) {
+		setup(db.Issue{ID: "child", TeamID: "team-1", OwnerID: "user-123"})
+		sqlMock.ExpectBegin()
+		mockRepo.On("DeleteIssuesTx", mock.Anything, mock.Anything, []string{"child", "parent"}).Return(nil).Once()
+		sqlMock.ExpectCommit()
+
+		assert.Equal(t, fiber.StatusOK, del("/issues/parent?children=delete"))
+		require.NoError(t, sqlMock.ExpectationsWereMet())
+		mockRepo.AssertNumberOfCalls(t, "DeleteIssue", 0)
+	})
+
+	t.Run("sub-issue the user cannot delete", func(t *testing.T) {
+		setup(db.Issue{ID: "child", TeamID: "team-2", OwnerID: "someone"})
+
+		assert.Equal(t, fiber.StatusForbidden, del("/issues/parent?children=delete"))
+		mockRepo.AssertNumberOfCalls(t, "DeleteIssuesTx", 1)
+	})
+}
+
+func TestUpdateIssueProject(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockProjectRepo := new(mocks.MockProjectRepo)
+	handler := routes.IssueHandler{DB: mockDB, Repo: mockRepo, TeamRepo: mockTeamRepo, ProjectRepo: mockProjectRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Patch("/issues/:id", handler.UpdateIssue)
+
+	core := db.Project{ID: "proj-1", Name: "Core", WorkspaceID: "ws-1", TeamID: "team-1", CreatedBy: "user-999"}
+	mobile := db.Project{ID: "proj-2", Name: "Mobile", WorkspaceID: "ws-1", TeamID: "team-2", CreatedBy: "user-999"}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name: "moved to a project of its team",
+			body: `{"project_id":"proj-1"}`,
+			setup: func() {
+				mockProjectRepo.On("GetProjectByID", mock.Anything, "proj-1").Return(core, nil)
+				mockProjectRepo.On("IsProjectMember", mock.Anything, "proj-1", "user-123").Return(true, nil)
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(`UPDATE issues SET project_id = \? WHERE id = \?`).
+					WithArgs(sql.NullString{String: "proj-1", Valid: true}, "issue-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "taken out of its project",
+			body: `{"project_id":""}`,
+			setup: func() {
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(`UPDATE issues SET project_id = \? WHERE id = \?`).
+					WithArgs(sql.NullString{}, "issue-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.Field == "project_id" && e.OldValue.String == "proj-0" && !e.NewValue.Valid
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "project of another team",
+			body: `{"project_id":"proj-2"}`,
+			setup: func() {
+				mockProjectRepo.On("GetProjectByID", mock.Anything, "proj-2").Return(mobile, nil)
+				mockTeamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(db.Team{ID: "team-1", Name: "Platform", WorkspaceID: "ws-1"}, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+			wantError:  "project Mobile belongs to another team than Platform",
+		},
+		{
+			name: "not a member of the project",
+			body: `{"project_id":"proj-1"}`,
+			setup: func() {
+				mockProjectRepo.On("GetProjectByID", mock.Anything, "proj-1").Return(core, nil)
+				mockProjectRepo.On("IsProjectMember", mock.Anything, "proj-1", "user-123").Return(false, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+			wantError:  "you are not a member of project Core",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(db.Issue{
+				ID: "issue-1", TeamID: "team-1", OwnerID: "user-123", Status: "todo",
+				ProjectID: sql.NullString{String: "proj-0", Valid: true},
+			}, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPatch, "/issues/issue-1", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantError != "" {
+				var body map[string]string
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.wantError, body["error"])
+			}
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+
+			mockRepo.ExpectedCalls = nil
+			mockTeamRepo.ExpectedCalls = nil
+			mockProjectRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+}
+
+func TestUpdateIssueTeamKeepsProject(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockProjectRepo := new(mocks.MockProjectRepo)
+	handler := routes.IssueHandler{DB: mockDB, Repo: mockRepo, TeamRepo: mockTeamRepo, ProjectRepo: mockProjectRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Patch("/issues/:id", handler.UpdateIssue)
+
+	// moving to team-2 would leave the issue in a project of team-1
+	mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(db.Issue{
+		ID: "issue-1", TeamID: "team-1", OwnerID: "user-123", Status: "todo",
+		ProjectID: sql.NullString{String: "proj-1", Valid: true},
+	}, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	mockProjectRepo.On("GetProjectByID", mock.Anything, "proj-1").Return(db.Project{ID: "proj-1", Name: "Core", WorkspaceID: "ws-1", TeamID: "team-1"}, nil)
+	mockTeamRepo.On("GetTeamByID", mock.Anything, "team-2").Return(db.Team{ID: "team-2", Name: "Ops", WorkspaceID: "ws-1"}, nil)
+
+	req := httptest.NewRequest(http.MethodPatch, "/issues/issue-1", bytes.NewReader([]byte(`{"team_id":"team-2"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	mockProjectRepo.AssertNotCalled(t, "IsProjectMember", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,407 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/repositories"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+const defaultLabelColor = "#bec2c8"
+
+type LabelHandler struct {
+	Repo     repositories.LabelRepository
+	TeamRepo repositories.TeamRepository
+	Authz    *authz.Authorizer
+}
+
+func NewLabelHandler(repo repositories.LabelRepository, teamRepo repositories.TeamRepository, roleRepo repositories.RoleRepository) *LabelHandler {
+	return &LabelHandler{
+		Repo:     repo,
+		TeamRepo: teamRepo,
+		Authz:    authz.NewAuthorizer(roleRepo),
+	}
+}
+
+func toLabel(l db.Label) models.Label {
+	return models.Label{
+		ID:          l.ID,
+		WorkspaceID: l.WorkspaceID,
+		TeamID:      l.TeamID.String,
+		Name:        l.Name,
+		Color:       l.Color,
+		Description: l.Description.String,
+		CreatedAt:   l.CreatedAt,
+	}
+}
+
+func toLabels(rows []db.Label) []models.Label {
+	labels := make([]models.Label, 0, len(rows))
+	for _, l := range rows {
+		labels = append(labels, toLabel(l))
+	}
+	return labels
+}
+
+// broadcastLabel tells the clients of the label's workspace, or of its team
+// for a team label, that the label changed.
+func broadcastLabel(l db.Label, event string, payload interface{}) {
+	if l.TeamID.Valid {
+		ws.BroadcastToRoom("team", l.TeamID.String, event, payload)
+		return
+	}
+	ws.BroadcastToRoom("workspace", l.WorkspaceID, event, payload)
+}
+
+// labelNameTaken reports whether another label of the same scope as l, out of
+// labels, is already called name. Case is ignored, as in the unique index.
+func labelNameTaken(labels []db.Label, l db.Label, name string) bool {
+	for _, other := range labels {
+		if other.ID != l.ID && other.TeamID == l.TeamID && strings.EqualFold(other.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// listScopeLabels returns the labels of the scope of l.
+func (h *LabelHandler) listScopeLabels(ctx context.Context, l db.Label) ([]db.Label, error) {
+	if l.TeamID.Valid {
+		return h.Repo.ListTeamLabels(ctx, l.TeamID.String)
+	}
+	return h.Repo.ListWorkspaceLabels(ctx, l.WorkspaceID)
+}
+
+// loadTeam returns the team of the :id param. It writes the error response
+// and returns false when the team does not exist.
+func (h *LabelHandler) loadTeam(c *fiber.Ctx) (db.Team, bool) {
+	team, err := h.TeamRepo.GetTeamByID(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
+			return team, false
+		}
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check team"})
+		return team, false
+	}
+	return team, true
+}
+
+// loadLabel returns the label of the :id param. It writes the error response
+// and returns false when the label does not exist.
+func (h *LabelHandler) loadLabel(c *fiber.Ctx) (db.Label, bool) {
+	label, err := h.Repo.GetLabelByID(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "label not found"})
+			return label, false
+		}
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch label"})
+		return label, false
+	}
+	return label, true
+}
+
+// ListWorkspaceLabels returns the labels shared by every team of the workspace.
+func (h *LabelHandler) ListWorkspaceLabels(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspaceid")
+	if !authorize(c, h.Authz, authz.WorkspaceView, authz.ForWorkspace(workspaceID), "you are not a member of this workspace") {
+		return nil
+	}
+
+	labels, err := h.Repo.ListWorkspaceLabels(c.Context(), workspaceID)
+	if err != nil {
+		log.Printf("Failed to list labels of workspace %s: %v", workspaceID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list labels"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"labels": toLabels(labels)})
+}
+
+// ListTeamLabels returns the labels the team can use: its own and those of its
+// workspace.
+func (h *LabelHandler) ListTeamLabels(c *fiber.Ctx) error {
+	team, ok := h.loadTeam(c)
+	if !ok {
+		return nil
+	}
+	if !authorize(c, h.Authz, authz.TeamView, authz.ForTeam(team.ID), "you are not a member of this team") {
+		return nil
+	}
+
+	labels, err := h.Repo.ListTeamLabels(c.Context(), team.ID)
+	if err != nil {
+		log.Printf("Failed to list labels of team %s: %v", team.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list labels"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"labels": toLabels(labels)})
+}
+
+func (h *LabelHandler) CreateWorkspaceLabel(c *fiber.Ctx) error {
+	return h.createLabel(c, db.Label{WorkspaceID: c.Params("workspaceid")})
+}
+
+func (h *LabelHandler) CreateTeamLabel(c *fiber.Ctx) error {
+	team, ok := h.loadTeam(c)
+	if !ok {
+		return nil
+	}
+	return h.createLabel(c, db.Label{WorkspaceID: team.WorkspaceID, TeamID: sql.NullString{String: team.ID, Valid: true}})
+}
+
+// createLabel creates a label in the scope of label, which has its
+// WorkspaceID and TeamID set.
+func (h *LabelHandler) createLabel(c *fiber.Ctx, label db.Label) error {
+	var req models.CreateLabelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	if !authorize(c, h.Authz, authz.LabelCreate, authz.ForLabel(label), "no permission to create labels here") {
+		return nil
+	}
+
+	ctx := c.Context()
+	existing, err := h.listScopeLabels(ctx, label)
+	if err != nil {
+		log.Printf("Failed to list labels: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create label"})
+	}
+	if labelNameTaken(existing, label, req.Name) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("label %s already exists", req.Name)})
+	}
+
+	label.ID = uuid.New().String()
+	label.Name = req.Name
+	label.Color = req.Color
+	if label.Color == "" {
+		label.Color = defaultLabelColor
+	}
+	label.Description = sql.NullString{String: req.Description, Valid: req.Description != ""}
+	label.CreatedAt = time.Now().UTC()
+
+	if err := h.Repo.CreateLabel(ctx, db.CreateLabelParams(label)); err != nil {
+		log.Printf("Failed to create label %s: %v", req.Name, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create label"})
+	}
+
+	broadcastLabel(label, "label_created", toLabel(label))
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "label created successfully",
+		"label":   toLabel(label),
+	})
+}
+
+func (h *LabelHandler) UpdateLabel(c *fiber.Ctx) error {
+	var req models.UpdateLabelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	label, ok := h.loadLabel(c)
+	if !ok {
+		return nil
+	}
+	if !authorize(c, h.Authz, authz.LabelUpdate, authz.ForLabel(label), "no permission to update this label") {
+		return nil
+	}
+
+	ctx := c.Context()
+	if req.Name != nil && *req.Name != label.Name {
+		existing, err := h.listScopeLabels(ctx, label)
+		if err != nil {
+			log.Printf("Failed to list labels: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update label"})
+		}
+		if labelNameTaken(existing, label, *req.Name) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("label %s already exists", *req.Name)})
+		}
+		label.Name = *req.Name
+	}
+	if req.Color != nil {
+		label.Color = *req.Color
+	}
+	if req.Description != nil {
+		label.Description = ToNullString(req.Description)
+	}
+
+	if err := h.Repo.UpdateLabel(ctx, db.UpdateLabelParams{
+		Name:        label.Name,
+		Color:       label.Color,
+		Description: label.Description,
+		ID:          label.ID,
+	}); err != nil {
+		log.Printf("Failed to update label %s: %v", label.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update label"})
+	}
+
+	broadcastLabel(label, "label_updated", toLabel(label))
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "label updated successfully",
+		"label":   toLabel(label),
+	})
+}
+
+// DeleteLabel deletes the label and removes it from every issue and project.
+func (h *LabelHandler) DeleteLabel(c *fiber.Ctx) error {
+	label, ok := h.loadLabel(c)
+	if !ok {
+		return nil
+	}
+	if !authorize(c, h.Authz, authz.LabelDelete, authz.ForLabel(label), "no permission to delete this label") {
+		return nil
+	}
+
+	if err := h.Repo.DeleteLabel(c.Context(), label.ID); err != nil {
+		log.Printf("Failed to delete label %s: %v", label.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete label"})
+	}
+
+	broadcastLabel(label, "label_deleted", fiber.Map{"id": label.ID})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "label deleted successfully"})
+}
+
+// MergeLabel replaces the label with the label of req.Into on every issue and
+// project, then deletes it. Into must be usable wherever the label was: a
+// workspace label of the same workspace, or a label of the same team.
+func (h *LabelHandler) MergeLabel(c *fiber.Ctx) error {
+	var req models.MergeLabelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	source, ok := h.loadLabel(c)
+	if !ok {
+		return nil
+	}
+	if req.Into == source.ID {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot merge a label into itself"})
+	}
+	if !authorize(c, h.Authz, authz.LabelDelete, authz.ForLabel(source), "no permission to merge this label") {
+		return nil
+	}
+
+	ctx := c.Context()
+	target, err := h.Repo.GetLabelByID(ctx, req.Into)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "target label not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch label"})
+	}
+	if target.WorkspaceID != source.WorkspaceID || (target.TeamID.Valid && target.TeamID != source.TeamID) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "target label is not available everywhere the label is used"})
+	}
+
+	if err := h.Repo.MergeLabel(ctx, source.ID, target.ID); err != nil {
+		log.Printf("Failed to merge label %s into %s: %v", source.ID, target.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to merge label"})
+	}
+
+	broadcastLabel(source, "label_merged", fiber.Map{"id": source.ID, "into": target.ID})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "label merged successfully",
+		"label":   toLabel(target),
+	})
+}
+
+// checkLabels checks that the team can use every label of ids. On failure the
+// error response is already written.
+func checkLabels(c *fiber.Ctx, repo repositories.LabelRepository, teamID string, ids []string) bool {
+	return check(c, usableLabels(c.Context(), repo, teamID, ids))
+}
+
+// usableLabels checks that the team can use every label of ids. It fails with
+// 400 naming the first label it cannot use.
+func usableLabels(ctx context.Context, repo repositories.LabelRepository, teamID string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	labels, err := repo.ListTeamLabels(ctx, teamID)
+	if err != nil {
+		log.Printf("Failed to list labels of team %s: %v", teamID, err)
+		return &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to check labels"}
+	}
+	usable := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		usable[l.ID] = true
+	}
+	for _, id := range ids {
+		if !usable[id] {
+			return &StatusError{Status: fiber.StatusBadRequest, Message: fmt.Sprintf("label %s cannot be used in the team", id)}
+		}
+	}
+	return nil
+}
+
+// ListIssueLabels returns the labels of the issue.
+func (h *IssueHandler) ListIssueLabels(c *fiber.Ctx) error {
+	issueID, ok := h.resolveIssueID(c, c.Params("id"))
+	if !ok {
+		return nil
+	}
+	issue, err := h.Repo.GetIssueByID(c.Context(), issueID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "issue not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch issue"})
+	}
+	if !authorize(c, h.Authz, authz.IssueView, authz.ForIssue(issue), "you are not authorized to view this issue") {
+		return nil
+	}
+
+	labels, err := h.LabelRepo.ListLabelsByIssueID(c.Context(), issueID)
+	if err != nil {
+		log.Printf("Failed to list labels of issue %s: %v", issueID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list labels"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"labels": toLabels(labels)})
+}
+
+// ListProjectLabels returns the labels of the project.
+func (h *ProjectHandler) ListProjectLabels(c *fiber.Ctx) error {
+	projectID := c.Params("id")
+	project, err := h.Repo.GetProjectByID(c.Context(), projectID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project not found"})
+	}
+	if !authorize(c, h.Authz, authz.ProjectView, authz.ForProject(project), "you are not a member of the team") {
+		return nil
+	}
+
+	labels, err := h.LabelRepo.ListLabelsByProjectID(c.Context(), projectID)
+	if err != nil {
+		log.Printf("Failed to list labels of project %s: %v", projectID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list labels"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"labels": toLabels(labels)})
+}
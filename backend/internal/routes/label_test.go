@@ -0,0 +1,384 @@
+package routes_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nack098/nakumanager/internal/db"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+)
+
+var (
+	bugLabel  = db.Label{ID: "l-bug", WorkspaceID: "ws-1", Name: "Bug", Color: "#ff0000"}
+	teamLabel = db.Label{ID: "l-api", WorkspaceID: "ws-1", TeamID: sql.NullString{String: "team-1", Valid: true}, Name: "API", Color: "#00ff00"}
+)
+
+func newLabelApp(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) *fiber.App {
+	handler := routes.NewLabelHandler(repo, teamRepo, roleRepo)
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Get("/workspace/:workspaceid/labels", handler.ListWorkspaceLabels)
+	app.Post("/workspace/:workspaceid/labels", handler.CreateWorkspaceLabel)
+	app.Get("/teams/:id/labels", handler.ListTeamLabels)
+	app.Post("/teams/:id/labels", handler.CreateTeamLabel)
+	app.Patch("/labels/:id", handler.UpdateLabel)
+	app.Delete("/labels/:id", handler.DeleteLabel)
+	app.Post("/labels/:id/merge", handler.MergeLabel)
+	return app
+}
+
+func TestListLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		setupMocks func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo)
+		wantStatus int
+		wantLabels int
+	}{
+		{
+			name: "workspace labels",
+			path: "/workspace/ws-1/labels",
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("member", nil)
+				repo.On("ListWorkspaceLabels", mock.Anything, "ws-1").Return([]db.Label{bugLabel}, nil)
+			},
+			wantStatus: fiber.StatusOK,
+			wantLabels: 1,
+		},
+		{
+			name: "workspace labels outside the workspace",
+			path: "/workspace/ws-1/labels",
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("", sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name: "team labels include the workspace's",
+			path: "/teams/team-1/labels",
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				teamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(db.Team{ID: "team-1", WorkspaceID: "ws-1"}, nil)
+				roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				repo.On("ListTeamLabels", mock.Anything, "team-1").Return([]db.Label{teamLabel, bugLabel}, nil)
+			},
+			wantStatus: fiber.StatusOK,
+			wantLabels: 2,
+		},
+		{
+			name: "team not found",
+			path: "/teams/team-x/labels",
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				teamRepo.On("GetTeamByID", mock.Anything, "team-x").Return(db.Team{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockLabelRepo)
+			teamRepo := new(mocks.MockTeamRepository)
+			roleRepo := new(mocks.MockRoleRepo)
+			tt.setupMocks(repo, teamRepo, roleRepo)
+			app := newLabelApp(repo, teamRepo, roleRepo)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusOK {
+				var body struct {
+					Labels []map[string]any `json:"labels"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Len(t, body.Labels, tt.wantLabels)
+			}
+		})
+	}
+}
+
+func TestCreateLabel(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		setupMocks func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo)
+		wantStatus int
+	}{
+		{
+			name: "workspace label with the default color",
+			path: "/workspace/ws-1/labels",
+			body: `{"name":" Feature ","description":"New functionality"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("member", nil)
+				repo.On("ListWorkspaceLabels", mock.Anything, "ws-1").Return([]db.Label{bugLabel}, nil)
+				repo.On("CreateLabel", mock.Anything, mock.MatchedBy(func(p db.CreateLabelParams) bool {
+					return p.ID != "" && p.WorkspaceID == "ws-1" && !p.TeamID.Valid && p.Name == "Feature" &&
+						p.Color == "#bec2c8" && p.Description.String == "New functionality"
+				})).Return(nil)
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "name taken, ignoring case",
+			path: "/workspace/ws-1/labels",
+			body: `{"name":"bug"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("member", nil)
+				repo.On("ListWorkspaceLabels", mock.Anything, "ws-1").Return([]db.Label{bugLabel}, nil)
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name: "guests cannot create labels",
+			path: "/workspace/ws-1/labels",
+			body: `{"name":"Feature"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("guest", nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:       "invalid color",
+			path:       "/workspace/ws-1/labels",
+			body:       `{"name":"Feature","color":"red"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "team label may share a workspace label's name",
+			path: "/teams/team-1/labels",
+			body: `{"name":"Bug","color":"#123456"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				teamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(db.Team{ID: "team-1", WorkspaceID: "ws-1"}, nil)
+				roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				repo.On("ListTeamLabels", mock.Anything, "team-1").Return([]db.Label{bugLabel, teamLabel}, nil)
+				repo.On("CreateLabel", mock.Anything, mock.MatchedBy(func(p db.CreateLabelParams) bool {
+					return p.WorkspaceID == "ws-1" && p.TeamID.String == "team-1" && p.Color == "#123456"
+				})).Return(nil)
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "team label by someone outside the team",
+			path: "/teams/team-1/labels",
+			body: `{"name":"Infra"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, teamRepo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				teamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(db.Team{ID: "team-1", WorkspaceID: "ws-1"}, nil)
+				roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(workspaceMemberRoles, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockLabelRepo)
+			teamRepo := new(mocks.MockTeamRepository)
+			roleRepo := new(mocks.MockRoleRepo)
+			tt.setupMocks(repo, teamRepo, roleRepo)
+			app := newLabelApp(repo, teamRepo, roleRepo)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateLabel(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setupMocks func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo)
+		wantStatus int
+	}{
+		{
+			name: "rename and recolor",
+			body: `{"name":"Defect","color":"#aa0000"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo) {
+				repo.On("GetLabelByID", mock.Anything, "l-bug").Return(bugLabel, nil)
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("member", nil)
+				repo.On("ListWorkspaceLabels", mock.Anything, "ws-1").Return([]db.Label{bugLabel}, nil)
+				repo.On("UpdateLabel", mock.Anything, db.UpdateLabelParams{ID: "l-bug", Name: "Defect", Color: "#aa0000"}).Return(nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "rename to a taken name",
+			body: `{"name":"feature"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo) {
+				repo.On("GetLabelByID", mock.Anything, "l-bug").Return(bugLabel, nil)
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("member", nil)
+				repo.On("ListWorkspaceLabels", mock.Anything, "ws-1").Return([]db.Label{bugLabel, {ID: "l-feat", WorkspaceID: "ws-1", Name: "Feature"}}, nil)
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name: "label not found",
+			body: `{"color":"#aa0000"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo) {
+				repo.On("GetLabelByID", mock.Anything, "l-bug").Return(db.Label{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockLabelRepo)
+			roleRepo := new(mocks.MockRoleRepo)
+			tt.setupMocks(repo, roleRepo)
+			app := newLabelApp(repo, new(mocks.MockTeamRepository), roleRepo)
+
+			req := httptest.NewRequest(http.MethodPatch, "/labels/l-bug", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteLabel(t *testing.T) {
+	tests := []struct {
+		name       string
+		label      db.Label
+		setupRoles func(roleRepo *mocks.MockRoleRepo)
+		wantStatus int
+	}{
+		{
+			name:  "workspace admin deletes a workspace label",
+			label: bugLabel,
+			setupRoles: func(roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("admin", nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:  "member cannot delete a workspace label",
+			label: bugLabel,
+			setupRoles: func(roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("member", nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:  "team lead deletes a team label",
+			label: teamLabel,
+			setupRoles: func(roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamLeadRoles, nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockLabelRepo)
+			roleRepo := new(mocks.MockRoleRepo)
+			repo.On("GetLabelByID", mock.Anything, tt.label.ID).Return(tt.label, nil)
+			tt.setupRoles(roleRepo)
+			if tt.wantStatus == fiber.StatusOK {
+				repo.On("DeleteLabel", mock.Anything, tt.label.ID).Return(nil)
+			}
+			app := newLabelApp(repo, new(mocks.MockTeamRepository), roleRepo)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/labels/"+tt.label.ID, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMergeLabel(t *testing.T) {
+	otherTeamLabel := db.Label{ID: "l-web", WorkspaceID: "ws-1", TeamID: sql.NullString{String: "team-2", Valid: true}, Name: "Web"}
+
+	tests := []struct {
+		name       string
+		source     db.Label
+		body       string
+		setupMocks func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo)
+		wantStatus int
+	}{
+		{
+			name:   "team label into a workspace label",
+			source: teamLabel,
+			body:   `{"into":"l-bug"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamLeadRoles, nil)
+				repo.On("GetLabelByID", mock.Anything, "l-bug").Return(bugLabel, nil)
+				repo.On("MergeLabel", mock.Anything, "l-api", "l-bug").Return(nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "into itself",
+			source:     teamLabel,
+			body:       `{"into":"l-api"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:   "workspace label into a team label",
+			source: bugLabel,
+			body:   `{"into":"l-api"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-123").Return("owner", nil)
+				repo.On("GetLabelByID", mock.Anything, "l-api").Return(teamLabel, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:   "into another team's label",
+			source: teamLabel,
+			body:   `{"into":"l-web"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamLeadRoles, nil)
+				repo.On("GetLabelByID", mock.Anything, "l-web").Return(otherTeamLabel, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:   "team member cannot merge",
+			source: teamLabel,
+			body:   `{"into":"l-bug"}`,
+			setupMocks: func(repo *mocks.MockLabelRepo, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockLabelRepo)
+			roleRepo := new(mocks.MockRoleRepo)
+			repo.On("GetLabelByID", mock.Anything, tt.source.ID).Return(tt.source, nil)
+			tt.setupMocks(repo, roleRepo)
+			app := newLabelApp(repo, new(mocks.MockTeamRepository), roleRepo)
+
+			req := httptest.NewRequest(http.MethodPost, "/labels/"+tt.source.ID+"/merge", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			repo.AssertExpectations(t)
+		})
+	}
+}
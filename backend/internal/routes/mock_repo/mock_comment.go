@@ -0,0 +1,64 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/nack098/nakumanager/internal/db"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCommentRepo struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepo) CreateComment(ctx context.Context, data db.CreateCommentParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepo) GetCommentByID(ctx context.Context, id string) (db.IssueComment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.IssueComment), args.Error(1)
+}
+
+func (m *MockCommentRepo) ListCommentsByIssueID(ctx context.Context, issueID string) ([]db.IssueComment, error) {
+	args := m.Called(ctx, issueID)
+	if data := args.Get(0); data != nil {
+		return data.([]db.IssueComment), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCommentRepo) UpdateComment(ctx context.Context, id, body, editedBy string) error {
+	args := m.Called(ctx, id, body, editedBy)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepo) DeleteComment(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepo) ListCommentEdits(ctx context.Context, commentID string) ([]db.IssueCommentEdit, error) {
+	args := m.Called(ctx, commentID)
+	if data := args.Get(0); data != nil {
+		return data.([]db.IssueCommentEdit), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCommentRepo) SetMentions(ctx context.Context, commentID string, usernames []string) ([]db.ListMentionsByCommentIDRow, error) {
+	args := m.Called(ctx, commentID, usernames)
+	if data := args.Get(0); data != nil {
+		return data.([]db.ListMentionsByCommentIDRow), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCommentRepo) ListMentions(ctx context.Context, commentID string) ([]db.ListMentionsByCommentIDRow, error) {
+	args := m.Called(ctx, commentID)
+	if data := args.Get(0); data != nil {
+		return data.([]db.ListMentionsByCommentIDRow), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
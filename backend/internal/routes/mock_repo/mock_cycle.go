@@ -0,0 +1,89 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/nack098/nakumanager/internal/db"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCycleRepo struct {
+	mock.Mock
+}
+
+func (m *MockCycleRepo) CreateCycle(ctx context.Context, data db.CreateCycleParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockCycleRepo) CreateCycleTx(ctx context.Context, tx *sql.Tx, data db.CreateCycleParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockCycleRepo) GetCycleByID(ctx context.Context, id string) (db.Cycle, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Cycle), args.Error(1)
+}
+
+func (m *MockCycleRepo) ListTeamCycles(ctx context.Context, teamID string) ([]db.Cycle, error) {
+	args := m.Called(ctx, teamID)
+	if cycles, ok := args.Get(0).([]db.Cycle); ok {
+		return cycles, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCycleRepo) GetNextCycleNumber(ctx context.Context, teamID string) (int64, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCycleRepo) GetNextCycleNumberTx(ctx context.Context, tx *sql.Tx, teamID string) (int64, error) {
+	args := m.Called(ctx, tx, teamID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCycleRepo) GetNextOpenCycleTx(ctx context.Context, tx *sql.Tx, cycle db.Cycle) (db.Cycle, error) {
+	args := m.Called(ctx, tx, cycle)
+	return args.Get(0).(db.Cycle), args.Error(1)
+}
+
+func (m *MockCycleRepo) UpdateCycle(ctx context.Context, data db.UpdateCycleParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockCycleRepo) DeleteCycle(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCycleRepo) CloseCycleTx(ctx context.Context, tx *sql.Tx, data db.CloseCycleParams) (bool, error) {
+	args := m.Called(ctx, tx, data)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCycleRepo) GetCycleStats(ctx context.Context, cycleID string) (db.GetCycleStatsRow, error) {
+	args := m.Called(ctx, cycleID)
+	return args.Get(0).(db.GetCycleStatsRow), args.Error(1)
+}
+
+func (m *MockCycleRepo) GetCycleStatsTx(ctx context.Context, tx *sql.Tx, cycleID string) (db.GetCycleStatsRow, error) {
+	args := m.Called(ctx, tx, cycleID)
+	return args.Get(0).(db.GetCycleStatsRow), args.Error(1)
+}
+
+func (m *MockCycleRepo) ListUnfinishedCycleIssueIDsTx(ctx context.Context, tx *sql.Tx, cycleID string) ([]string, error) {
+	args := m.Called(ctx, tx, cycleID)
+	if ids, ok := args.Get(0).([]string); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCycleRepo) SetIssueCycleTx(ctx context.Context, tx *sql.Tx, data db.SetIssueCycleParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
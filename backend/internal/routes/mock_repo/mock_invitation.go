@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"context"
+
+	db "github.com/nack098/nakumanager/internal/db"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInvitationRepo struct {
+	mock.Mock
+}
+
+func (m *MockInvitationRepo) CreateInvitation(ctx context.Context, data db.CreateInvitationParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepo) GetInvitationByID(ctx context.Context, id string) (db.WorkspaceInvitation, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.WorkspaceInvitation), args.Error(1)
+}
+
+func (m *MockInvitationRepo) ListInvitationsByWorkspaceID(ctx context.Context, workspaceID string) ([]db.WorkspaceInvitation, error) {
+	args := m.Called(ctx, workspaceID)
+	if invitations, ok := args.Get(0).([]db.WorkspaceInvitation); ok {
+		return invitations, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInvitationRepo) ListPendingInvitationsByEmail(ctx context.Context, email string) ([]db.ListPendingInvitationsByEmailRow, error) {
+	args := m.Called(ctx, email)
+	if invitations, ok := args.Get(0).([]db.ListPendingInvitationsByEmailRow); ok {
+		return invitations, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockInvitationRepo) RevokeInvitation(ctx context.Context, id, workspaceID string) (bool, error) {
+	args := m.Called(ctx, id, workspaceID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInvitationRepo) AcceptInvitation(ctx context.Context, invitation db.WorkspaceInvitation, userID string) (bool, error) {
+	args := m.Called(ctx, invitation, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInvitationRepo) DeclineInvitation(ctx context.Context, invitation db.WorkspaceInvitation) (bool, error) {
+	args := m.Called(ctx, invitation)
+	return args.Bool(0), args.Error(1)
+}
@@ -2,8 +2,10 @@ package mock
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -21,6 +23,11 @@ func (m *MockIssueRepo) CreateIssue(ctx context.Context, data db.CreateIssuePara
 	return args.Error(0)
 }
 
+func (m *MockIssueRepo) CreateIssueTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
 func (m *MockIssueRepo) DeleteIssue(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -53,3 +60,100 @@ func (m *MockIssueRepo) GetIssueByUserID(ctx context.Context, userID string) ([]
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockIssueRepo) ListIssues(ctx context.Context, userID string, filter models.IssueFilter) ([]db.Issue, string, error) {
+	args := m.Called(ctx, userID, filter)
+	if data := args.Get(0); data != nil {
+		return data.([]db.Issue), args.String(1), args.Error(2)
+	}
+	return nil, args.String(1), args.Error(2)
+}
+
+func (m *MockIssueRepo) AddAssigneeToIssueTx(ctx context.Context, tx *sql.Tx, data db.AddAssigneeToIssueParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockIssueRepo) RemoveAssigneeFromIssueTx(ctx context.Context, tx *sql.Tx, data db.RemoveAssigneeFromIssueParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockIssueRepo) CreateIssueEventTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueEventParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockIssueRepo) ListIssueEvents(ctx context.Context, issueID string, limit, offset int64) ([]db.IssueEvent, error) {
+	args := m.Called(ctx, issueID, limit, offset)
+	if data := args.Get(0); data != nil {
+		return data.([]db.IssueEvent), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIssueRepo) CountIssueEvents(ctx context.Context, issueID string) (int64, error) {
+	args := m.Called(ctx, issueID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockIssueRepo) GetTeamIDByViewID(ctx context.Context, viewID string) (string, error) {
+	args := m.Called(ctx, viewID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockIssueRepo) GetIssueBoardRankTx(ctx context.Context, tx *sql.Tx, data db.GetIssueBoardRankParams) (db.GetIssueBoardRankRow, error) {
+	args := m.Called(ctx, tx, data)
+	return args.Get(0).(db.GetIssueBoardRankRow), args.Error(1)
+}
+
+func (m *MockIssueRepo) GetLastIssueBoardRankTx(ctx context.Context, tx *sql.Tx, data db.GetLastIssueBoardRankParams) (string, error) {
+	args := m.Called(ctx, tx, data)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockIssueRepo) MoveIssueTx(ctx context.Context, tx *sql.Tx, viewID, issueID, status, rank string) error {
+	args := m.Called(ctx, tx, viewID, issueID, status, rank)
+	return args.Error(0)
+}
+
+func (m *MockIssueRepo) NextIssueNumber(ctx context.Context, teamID string) (int64, string, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).(int64), args.String(1), args.Error(2)
+}
+
+func (m *MockIssueRepo) ListIssueIDsByIdentifier(ctx context.Context, identifier, userID string) ([]string, error) {
+	args := m.Called(ctx, identifier, userID)
+	ids, _ := args.Get(0).([]string)
+	return ids, args.Error(1)
+}
+
+func (m *MockIssueRepo) ListChildIssues(ctx context.Context, parentID string) ([]db.Issue, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).([]db.Issue), args.Error(1)
+}
+
+func (m *MockIssueRepo) ListIssueDescendants(ctx context.Context, issueID string) ([]db.Issue, error) {
+	args := m.Called(ctx, issueID)
+	return args.Get(0).([]db.Issue), args.Error(1)
+}
+
+func (m *MockIssueRepo) ListIssueAncestorIDs(ctx context.Context, issueID string) ([]string, error) {
+	args := m.Called(ctx, issueID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockIssueRepo) GetSubIssueProgress(ctx context.Context, parentID string) (db.GetSubIssueProgressRow, error) {
+	args := m.Called(ctx, parentID)
+	return args.Get(0).(db.GetSubIssueProgressRow), args.Error(1)
+}
+
+func (m *MockIssueRepo) SetIssueStatusTx(ctx context.Context, tx *sql.Tx, issueID, status string) error {
+	args := m.Called(ctx, tx, issueID, status)
+	return args.Error(0)
+}
+
+func (m *MockIssueRepo) DeleteIssuesTx(ctx context.Context, tx *sql.Tx, ids []string) error {
+	args := m.Called(ctx, tx, ids)
+	return args.Error(0)
+}
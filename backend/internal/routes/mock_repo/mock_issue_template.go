@@ -0,0 +1,67 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/nack098/nakumanager/internal/db"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIssueTemplateRepo struct {
+	mock.Mock
+}
+
+func (m *MockIssueTemplateRepo) CreateIssueTemplateTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueTemplateParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockIssueTemplateRepo) GetIssueTemplateByID(ctx context.Context, id string) (db.IssueTemplate, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.IssueTemplate), args.Error(1)
+}
+
+func (m *MockIssueTemplateRepo) ListTeamIssueTemplates(ctx context.Context, teamID string) ([]db.IssueTemplate, error) {
+	args := m.Called(ctx, teamID)
+	if templates, ok := args.Get(0).([]db.IssueTemplate); ok {
+		return templates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIssueTemplateRepo) UpdateIssueTemplateTx(ctx context.Context, tx *sql.Tx, data db.UpdateIssueTemplateParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockIssueTemplateRepo) DeleteIssueTemplate(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockIssueTemplateRepo) SetIssueTemplateLabelsTx(ctx context.Context, tx *sql.Tx, templateID string, labelIDs []string) error {
+	args := m.Called(ctx, tx, templateID, labelIDs)
+	return args.Error(0)
+}
+
+func (m *MockIssueTemplateRepo) SetIssueTemplateAssigneesTx(ctx context.Context, tx *sql.Tx, templateID string, userIDs []string) error {
+	args := m.Called(ctx, tx, templateID, userIDs)
+	return args.Error(0)
+}
+
+func (m *MockIssueTemplateRepo) ListIssueTemplateLabelIDs(ctx context.Context, templateID string) ([]string, error) {
+	args := m.Called(ctx, templateID)
+	if ids, ok := args.Get(0).([]string); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockIssueTemplateRepo) ListIssueTemplateAssigneeIDs(ctx context.Context, templateID string) ([]string, error) {
+	args := m.Called(ctx, templateID)
+	if ids, ok := args.Get(0).([]string); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,100 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/nack098/nakumanager/internal/db"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLabelRepo struct {
+	mock.Mock
+}
+
+func (m *MockLabelRepo) CreateLabel(ctx context.Context, data db.CreateLabelParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepo) GetLabelByID(ctx context.Context, id string) (db.Label, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Label), args.Error(1)
+}
+
+func (m *MockLabelRepo) ListWorkspaceLabels(ctx context.Context, workspaceID string) ([]db.Label, error) {
+	args := m.Called(ctx, workspaceID)
+	if labels, ok := args.Get(0).([]db.Label); ok {
+		return labels, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLabelRepo) ListTeamLabels(ctx context.Context, teamID string) ([]db.Label, error) {
+	args := m.Called(ctx, teamID)
+	if labels, ok := args.Get(0).([]db.Label); ok {
+		return labels, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLabelRepo) UpdateLabel(ctx context.Context, data db.UpdateLabelParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepo) DeleteLabel(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepo) MergeLabel(ctx context.Context, sourceID, targetID string) error {
+	args := m.Called(ctx, sourceID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepo) ListLabelsByIssueID(ctx context.Context, issueID string) ([]db.Label, error) {
+	args := m.Called(ctx, issueID)
+	if labels, ok := args.Get(0).([]db.Label); ok {
+		return labels, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLabelRepo) ListLabelsByProjectID(ctx context.Context, projectID string) ([]db.Label, error) {
+	args := m.Called(ctx, projectID)
+	if labels, ok := args.Get(0).([]db.Label); ok {
+		return labels, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockLabelRepo) AddLabelToIssue(ctx context.Context, issueID, labelID string) error {
+	args := m.Called(ctx, issueID, labelID)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepo) RemoveLabelFromIssue(ctx context.Context, issueID, labelID string) error {
+	args := m.Called(ctx, issueID, labelID)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepo) AddLabelToIssueTx(ctx context.Context, tx *sql.Tx, issueID, labelID string) error {
+	args := m.Called(ctx, tx, issueID, labelID)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepo) RemoveLabelFromIssueTx(ctx context.Context, tx *sql.Tx, issueID, labelID string) error {
+	args := m.Called(ctx, tx, issueID, labelID)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepo) AddLabelToProject(ctx context.Context, projectID, labelID string) error {
+	args := m.Called(ctx, projectID, labelID)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepo) RemoveLabelFromProject(ctx context.Context, projectID, labelID string) error {
+	args := m.Called(ctx, projectID, labelID)
+	return args.Error(0)
+}
@@ -0,0 +1,50 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	db "github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationRepo struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepo) ListDueIssueRecipients(ctx context.Context, until time.Time) ([]models.DueIssueRecipient, error) {
+	args := m.Called(ctx, until)
+	if due, ok := args.Get(0).([]models.DueIssueRecipient); ok {
+		return due, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationRepo) CreateNotification(ctx context.Context, data db.CreateNotificationParams) (bool, error) {
+	args := m.Called(ctx, data)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepo) ListUserNotifications(ctx context.Context, userID string, limit, offset int64) ([]db.ListUserNotificationsRow, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if notifications, ok := args.Get(0).([]db.ListUserNotificationsRow); ok {
+		return notifications, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNotificationRepo) CountUnreadNotifications(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkNotificationRead(ctx context.Context, id, userID string, at time.Time) (bool, error) {
+	args := m.Called(ctx, id, userID, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkAllNotificationsRead(ctx context.Context, userID string, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
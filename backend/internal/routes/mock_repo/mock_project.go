@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/nack098/nakumanager/internal/db"
 	models "github.com/nack098/nakumanager/internal/models"
@@ -30,9 +31,9 @@ func (m *MockProjectRepo) GetProjectByID(ctx context.Context, id string) (db.Pro
 	return db.Project{}, args.Error(1)
 }
 
-func (m *MockProjectRepo) GetProjectsByUserID(ctx context.Context, userID string) ([]db.Project, error) {
+func (m *MockProjectRepo) GetProjectsByUserID(ctx context.Context, userID string) ([]db.GetProjectsByUserIDRow, error) {
 	args := m.Called(ctx, userID)
-	if projects, ok := args.Get(0).([]db.Project); ok {
+	if projects, ok := args.Get(0).([]db.GetProjectsByUserIDRow); ok {
 		return projects, args.Error(1)
 	}
 	return nil, args.Error(1)
@@ -43,6 +44,11 @@ func (m *MockProjectRepo) IsProjectExists(ctx context.Context, projectID string)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockProjectRepo) IsProjectMember(ctx context.Context, projectID, userID string) (bool, error) {
+	args := m.Called(ctx, projectID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockProjectRepo) GetOwnerByProjectID(ctx context.Context, projectID string) (string, error) {
 	args := m.Called(ctx, projectID)
 	return args.String(0), args.Error(1)
@@ -62,3 +68,77 @@ func (m *MockProjectRepo) RemoveMemberFromProject(ctx context.Context, projectID
 	args := m.Called(ctx, projectID, userID)
 	return args.Error(0)
 }
+
+func (m *MockProjectRepo) CreateMilestoneTx(ctx context.Context, tx *sql.Tx, data db.CreateProjectMilestoneParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) GetMilestoneByID(ctx context.Context, id string) (db.ProjectMilestone, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.ProjectMilestone), args.Error(1)
+}
+
+func (m *MockProjectRepo) ListMilestones(ctx context.Context, projectID string) ([]db.ProjectMilestone, error) {
+	args := m.Called(ctx, projectID)
+	if milestones, ok := args.Get(0).([]db.ProjectMilestone); ok {
+		return milestones, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProjectRepo) UpdateMilestoneTx(ctx context.Context, tx *sql.Tx, data db.UpdateProjectMilestoneParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) SetMilestoneOrderTx(ctx context.Context, tx *sql.Tx, ids []string) error {
+	args := m.Called(ctx, tx, ids)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) DeleteMilestone(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) ListMilestoneProgress(ctx context.Context, projectID string) ([]db.ListMilestoneProgressRow, error) {
+	args := m.Called(ctx, projectID)
+	if rows, ok := args.Get(0).([]db.ListMilestoneProgressRow); ok {
+		return rows, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProjectRepo) GetProjectProgress(ctx context.Context, projectID string) (db.GetProjectProgressRow, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).(db.GetProjectProgressRow), args.Error(1)
+}
+
+func (m *MockProjectRepo) CreateProjectUpdate(ctx context.Context, data db.CreateProjectUpdateParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) GetProjectUpdateByID(ctx context.Context, id string) (db.ProjectUpdate, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.ProjectUpdate), args.Error(1)
+}
+
+func (m *MockProjectRepo) ListProjectUpdates(ctx context.Context, projectID string) ([]db.ProjectUpdate, error) {
+	args := m.Called(ctx, projectID)
+	if updates, ok := args.Get(0).([]db.ProjectUpdate); ok {
+		return updates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProjectRepo) EditProjectUpdate(ctx context.Context, data db.EditProjectUpdateParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) DeleteProjectUpdate(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -0,0 +1,72 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	db "github.com/nack098/nakumanager/internal/db"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRecurringIssueRepo struct {
+	mock.Mock
+}
+
+func (m *MockRecurringIssueRepo) CreateRecurringIssue(ctx context.Context, data db.CreateRecurringIssueParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockRecurringIssueRepo) GetRecurringIssueByID(ctx context.Context, id string) (db.RecurringIssue, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.RecurringIssue), args.Error(1)
+}
+
+func (m *MockRecurringIssueRepo) ListTeamRecurringIssues(ctx context.Context, teamID string) ([]db.RecurringIssue, error) {
+	args := m.Called(ctx, teamID)
+	if recurring, ok := args.Get(0).([]db.RecurringIssue); ok {
+		return recurring, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRecurringIssueRepo) UpdateRecurringIssue(ctx context.Context, data db.UpdateRecurringIssueParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockRecurringIssueRepo) DeleteRecurringIssue(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRecurringIssueRepo) ListDueRecurringIssues(ctx context.Context, now time.Time) ([]db.RecurringIssue, error) {
+	args := m.Called(ctx, now)
+	if recurring, ok := args.Get(0).([]db.RecurringIssue); ok {
+		return recurring, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRecurringIssueRepo) AdvanceRecurringIssue(ctx context.Context, data db.AdvanceRecurringIssueParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockRecurringIssueRepo) ClaimRecurringIssueRun(ctx context.Context, recurringID string, scheduledFor time.Time) (bool, error) {
+	args := m.Called(ctx, recurringID, scheduledFor)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRecurringIssueRepo) FinishRecurringIssueRun(ctx context.Context, data db.FinishRecurringIssueRunParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockRecurringIssueRepo) ListRecurringIssueRuns(ctx context.Context, recurringID string, limit int64) ([]db.RecurringIssueRun, error) {
+	args := m.Called(ctx, recurringID, limit)
+	if runs, ok := args.Get(0).([]db.RecurringIssueRun); ok {
+		return runs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
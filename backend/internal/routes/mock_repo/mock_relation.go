@@ -0,0 +1,49 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+
+	db "github.com/nack098/nakumanager/internal/db"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRelationRepo struct {
+	mock.Mock
+}
+
+func (m *MockRelationRepo) CreateIssueRelation(ctx context.Context, data db.CreateIssueRelationParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockRelationRepo) CreateIssueRelationTx(ctx context.Context, tx *sql.Tx, data db.CreateIssueRelationParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockRelationRepo) GetIssueRelation(ctx context.Context, id string) (db.IssueRelation, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.IssueRelation), args.Error(1)
+}
+
+func (m *MockRelationRepo) DeleteIssueRelation(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRelationRepo) ListIssueRelations(ctx context.Context, issueID string) ([]db.ListIssueRelationsRow, error) {
+	args := m.Called(ctx, issueID)
+	if rows, ok := args.Get(0).([]db.ListIssueRelationsRow); ok {
+		return rows, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRelationRepo) ListOpenBlockedIssues(ctx context.Context, issueID string) ([]db.ListOpenBlockedIssuesRow, error) {
+	args := m.Called(ctx, issueID)
+	if rows, ok := args.Get(0).([]db.ListOpenBlockedIssuesRow); ok {
+		return rows, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
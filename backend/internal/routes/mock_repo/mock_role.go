@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	db "github.com/nack098/nakumanager/internal/db"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRoleRepo struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepo) GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (string, error) {
+	args := m.Called(ctx, workspaceID, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRoleRepo) GetTeamRoles(ctx context.Context, teamID, userID string) (db.GetTeamMemberRolesRow, error) {
+	args := m.Called(ctx, teamID, userID)
+	return args.Get(0).(db.GetTeamMemberRolesRow), args.Error(1)
+}
+
+func (m *MockRoleRepo) SetWorkspaceRole(ctx context.Context, workspaceID, userID, role string) error {
+	args := m.Called(ctx, workspaceID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepo) SetTeamLead(ctx context.Context, teamID, userID string) error {
+	args := m.Called(ctx, teamID, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,20 @@
+package mock
+
+import (
+	"context"
+
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSearchRepo struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepo) Search(ctx context.Context, userID, query string, limit, offset int) ([]models.SearchResult, error) {
+	args := m.Called(ctx, userID, query, limit, offset)
+	if data := args.Get(0); data != nil {
+		return data.([]models.SearchResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/nack098/nakumanager/internal/db"
 	models "github.com/nack098/nakumanager/internal/models"
@@ -71,3 +72,68 @@ func (m *MockTeamRepository) SetLeaderToTeam(ctx context.Context, data db.SetLea
 	args := m.Called(ctx, data)
 	return args.Error(0)
 }
+
+func (m *MockTeamRepository) ListTeamStatuses(ctx context.Context, teamID string) ([]db.TeamStatus, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).([]db.TeamStatus), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetTeamStatus(ctx context.Context, teamID, key string) (db.TeamStatus, error) {
+	args := m.Called(ctx, teamID, key)
+	return args.Get(0).(db.TeamStatus), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetDefaultTeamStatus(ctx context.Context, teamID string) (string, error) {
+	args := m.Called(ctx, teamID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTeamRepository) CountIssuesByTeamStatus(ctx context.Context, teamID, key string) (int64, error) {
+	args := m.Called(ctx, teamID, key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTeamRepository) CreateTeamStatusTx(ctx context.Context, tx *sql.Tx, data db.CreateTeamStatusParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) UpdateTeamStatusTx(ctx context.Context, tx *sql.Tx, data db.UpdateTeamStatusParams) error {
+	args := m.Called(ctx, tx, data)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) DeleteTeamStatusTx(ctx context.Context, tx *sql.Tx, teamID, key, moveTo string) error {
+	args := m.Called(ctx, tx, teamID, key, moveTo)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) SetTeamStatusOrderTx(ctx context.Context, tx *sql.Tx, teamID string, keys []string) error {
+	args := m.Called(ctx, tx, teamID, keys)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) ListTransitionRules(ctx context.Context, teamID string) ([]db.TeamTransitionRule, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).([]db.TeamTransitionRule), args.Error(1)
+}
+
+func (m *MockTeamRepository) CreateTransitionRule(ctx context.Context, data db.CreateTransitionRuleParams) error {
+	args := m.Called(ctx, data)
+	return args.Error(0)
+}
+
+func (m *MockTeamRepository) DeleteTransitionRule(ctx context.Context, teamID, id string) (int64, error) {
+	args := m.Called(ctx, teamID, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTeamRepository) ListTeamKeys(ctx context.Context, workspaceID string) ([]string, error) {
+	args := m.Called(ctx, workspaceID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTeamRepository) SetTeamKeyTx(ctx context.Context, tx *sql.Tx, teamID, key string) error {
+	args := m.Called(ctx, tx, teamID, key)
+	return args.Error(0)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/nack098/nakumanager/internal/db"
+	"github.com/nack098/nakumanager/internal/sqlbuilder"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -17,16 +18,6 @@ func (m *MockViewRepo) AddGroupByToView(ctx context.Context, data db.AddGroupByT
 	return args.Error(0)
 }
 
-func (m *MockViewRepo) AddIssueToView(ctx context.Context, data db.AddIssueToViewParams) error {
-	args := m.Called(ctx, data)
-	return args.Error(0)
-}
-
-func (m *MockViewRepo) AddIssueToViewTx(ctx context.Context, tx *sql.Tx, params db.AddIssueToViewParams) error {
-	args := m.Called(ctx, tx, params)
-	return args.Error(0)
-}
-
 func (m *MockViewRepo) CreateView(ctx context.Context, data db.CreateViewParams) error {
 	args := m.Called(ctx, data)
 	return args.Error(0)
@@ -56,11 +47,6 @@ func (m *MockViewRepo) RemoveGroupByFromView(ctx context.Context, id string) err
 	return args.Error(0)
 }
 
-func (m *MockViewRepo) RemoveIssueFromView(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
-	return args.Error(0)
-}
-
 func (m *MockViewRepo) ListViewByTeamID(ctx context.Context, teamID string) ([]db.View, error) {
 	args := m.Called(ctx, teamID)
 
@@ -84,6 +70,56 @@ func (m *MockViewRepo) UpdateViewTeamID(ctx context.Context, id string, teamID s
 	return args.Error(0)
 }
 
+func (m *MockViewRepo) UpdateViewFilter(ctx context.Context, id string, filter sql.NullString) error {
+	args := m.Called(ctx, id, filter)
+	return args.Error(0)
+}
+
+func (m *MockViewRepo) ListViewIssues(ctx context.Context, viewID, teamID string, filter sqlbuilder.Expr) ([]db.Issue, error) {
+	args := m.Called(ctx, viewID, teamID, filter)
+	if data := args.Get(0); data != nil {
+		return data.([]db.Issue), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockViewRepo) ListViewIssueAssignees(ctx context.Context, teamID string, filter sqlbuilder.Expr) (map[string][]string, error) {
+	args := m.Called(ctx, teamID, filter)
+	if data := args.Get(0); data != nil {
+		return data.(map[string][]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockViewRepo) ListViewIssueLabels(ctx context.Context, teamID string, filter sqlbuilder.Expr) (map[string][]string, error) {
+	args := m.Called(ctx, teamID, filter)
+	if data := args.Get(0); data != nil {
+		return data.(map[string][]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockViewRepo) ListViewGroupBys(ctx context.Context, id string) ([]db.ViewGroupBy, error) {
+	args := m.Called(ctx, id)
+	if data := args.Get(0); data != nil {
+		return data.([]db.ViewGroupBy), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockViewRepo) ListGroupValues(ctx context.Context, teamID string, field string) ([]string, error) {
+	args := m.Called(ctx, teamID, field)
+	if data := args.Get(0); data != nil {
+		return data.([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockViewRepo) GetTeamIssueVersion(ctx context.Context, teamID string) (int64, error) {
+	args := m.Called(ctx, teamID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockViewRepo) GetTeamIDByViewID(ctx context.Context, id string) (string, error) {
 	args := m.Called(ctx, id)
 	return args.String(0), args.Error(1)
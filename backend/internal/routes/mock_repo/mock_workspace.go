@@ -26,8 +26,8 @@ func (m *MockWorkspaceRepo) DeleteWorkspace(ctx context.Context, id string) erro
 	return args.Error(0)
 }
 
-func (m *MockWorkspaceRepo) AddMemberToWorkspace(ctx context.Context, workspaceID, userID string) error {
-	args := m.Called(ctx, workspaceID, userID)
+func (m *MockWorkspaceRepo) AddMemberToWorkspace(ctx context.Context, workspaceID, userID, role string) error {
+	args := m.Called(ctx, workspaceID, userID, role)
 	return args.Error(0)
 }
 
@@ -0,0 +1,95 @@
+package routes
+
+import (
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/repositories"
+)
+
+// NotificationHandler serves the notifications of the current user. They are
+// created by the background jobs, see jobs.DueReminders.
+type NotificationHandler struct {
+	Repo repositories.NotificationRepository
+}
+
+func NewNotificationHandler(repo repositories.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{Repo: repo}
+}
+
+func toNotification(n db.ListUserNotificationsRow) models.Notification {
+	notification := models.Notification{
+		ID:         n.ID,
+		Type:       n.Type,
+		IssueID:    n.IssueID,
+		Identifier: n.Identifier,
+		Title:      n.Title,
+		TeamID:     n.TeamID,
+		DueAt:      n.DueAt,
+		CreatedAt:  n.CreatedAt,
+	}
+	if n.ReadAt.Valid {
+		notification.ReadAt = &n.ReadAt.Time
+	}
+	return notification
+}
+
+// ListNotifications returns the user's notifications, newest first, and how
+// many of them are unread.
+func (h *NotificationHandler) ListNotifications(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	limit, offset, err := parsePagination(c, 50, 100)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	rows, err := h.Repo.ListUserNotifications(c.Context(), userID, int64(limit), int64(offset))
+	if err != nil {
+		log.Printf("Failed to list notifications of user %s: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list notifications"})
+	}
+	unread, err := h.Repo.CountUnreadNotifications(c.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to count unread notifications of user %s: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list notifications"})
+	}
+
+	notifications := make([]models.Notification, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, toNotification(row))
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"notifications": notifications,
+		"unread":        unread,
+		"limit":         limit,
+		"offset":        offset,
+	})
+}
+
+func (h *NotificationHandler) MarkNotificationRead(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	id := c.Params("id")
+
+	found, err := h.Repo.MarkNotificationRead(c.Context(), id, userID, time.Now())
+	if err != nil {
+		log.Printf("Failed to mark notification %s as read: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to mark notification as read"})
+	}
+	// การแจ้งเตือนของคนอื่นตอบเหมือนไม่มีอยู่
+	if !found {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "notification not found"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *NotificationHandler) MarkAllNotificationsRead(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if err := h.Repo.MarkAllNotificationsRead(c.Context(), userID, time.Now()); err != nil {
+		log.Printf("Failed to mark notifications of user %s as read: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to mark notifications as read"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,115 @@
+package routes_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListNotifications(t *testing.T) {
+	mockRepo := new(mocks.MockNotificationRepo)
+	handler := routes.NewNotificationHandler(mockRepo)
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Get("/notifications", handler.ListNotifications)
+
+	dueAt := time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC)
+	mockRepo.On("ListUserNotifications", mock.Anything, "user-123", int64(20), int64(0)).Return([]db.ListUserNotificationsRow{
+		{ID: "n2", IssueID: "issue-1", Type: models.NotificationOverdue, DueAt: dueAt, Identifier: "ENG-1", Title: "Ship it", TeamID: "team-1"},
+		{ID: "n1", IssueID: "issue-1", Type: models.NotificationDueSoon, DueAt: dueAt, Identifier: "ENG-1", Title: "Ship it", TeamID: "team-1",
+			ReadAt: sql.NullTime{Time: dueAt.Add(-time.Hour), Valid: true}},
+	}, nil)
+	mockRepo.On("CountUnreadNotifications", mock.Anything, "user-123").Return(int64(1), nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/notifications?limit=20", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Notifications []models.Notification `json:"notifications"`
+		Unread        int64                 `json:"unread"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Notifications, 2)
+	assert.Nil(t, body.Notifications[0].ReadAt)
+	assert.NotNil(t, body.Notifications[1].ReadAt)
+	assert.Equal(t, "ENG-1", body.Notifications[0].Identifier)
+	assert.Equal(t, int64(1), body.Unread)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/notifications?limit=500", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	mockRepo := new(mocks.MockNotificationRepo)
+	handler := routes.NewNotificationHandler(mockRepo)
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/notifications/read", handler.MarkAllNotificationsRead)
+	app.Post("/notifications/:id/read", handler.MarkNotificationRead)
+
+	tests := []struct {
+		name       string
+		url        string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "own notification",
+			url:  "/notifications/n1/read",
+			setup: func() {
+				mockRepo.On("MarkNotificationRead", mock.Anything, "n1", "user-123", mock.Anything).Return(true, nil)
+			},
+			wantStatus: fiber.StatusNoContent,
+		},
+		{
+			name: "someone else's notification",
+			url:  "/notifications/n9/read",
+			setup: func() {
+				mockRepo.On("MarkNotificationRead", mock.Anything, "n9", "user-123", mock.Anything).Return(false, nil)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name: "all notifications",
+			url:  "/notifications/read",
+			setup: func() {
+				mockRepo.On("MarkAllNotificationsRead", mock.Anything, "user-123", mock.Anything).Return(nil)
+			},
+			wantStatus: fiber.StatusNoContent,
+		},
+		{
+			name: "repo error",
+			url:  "/notifications/read",
+			setup: func() {
+				mockRepo.On("MarkAllNotificationsRead", mock.Anything, "user-123", mock.Anything).Return(errors.New("unexpected error"))
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			tt.setup()
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodPost, tt.url, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+func toProgress(total, completed int64) models.Progress {
+	p := models.Progress{Total: total, Completed: completed}
+	if total > 0 {
+		p.Percent = completed * 100 / total
+	}
+	return p
+}
+
+func toMilestone(m db.ProjectMilestone) models.Milestone {
+	milestone := models.Milestone{
+		ID:        m.ID,
+		ProjectID: m.ProjectID,
+		Name:      m.Name,
+		Position:  m.Position,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.TargetDate.Valid {
+		milestone.TargetDate = &m.TargetDate.Time
+	}
+	return milestone
+}
+
+// loadProjectFor fetches the project of the request and checks that the user
+// may act on it. It writes the error response and returns false otherwise.
+func (h *ProjectHandler) loadProjectFor(c *fiber.Ctx, action authz.Action, msg string) (db.Project, bool) {
+	projectID := c.Params("id")
+	project, err := h.Repo.GetProjectByID(c.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project not found"})
+			return project, false
+		}
+		log.Printf("Failed to get project %s: %v", projectID, err)
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch project"})
+		return project, false
+	}
+	return project, authorize(c, h.Authz, action, authz.ForProject(project), msg)
+}
+
+// loadMilestone fetches the milestone of the request, which must belong to
+// the project.
+func (h *ProjectHandler) loadMilestone(c *fiber.Ctx, projectID string) (db.ProjectMilestone, bool) {
+	milestone, err := h.Repo.GetMilestoneByID(c.Context(), c.Params("milestoneId"))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Failed to get milestone %s: %v", c.Params("milestoneId"), err)
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch milestone"})
+		return milestone, false
+	}
+	if err != nil || milestone.ProjectID != projectID {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "milestone not found"})
+		return milestone, false
+	}
+	return milestone, true
+}
+
+// milestonesWithProgress returns the milestones of the project in order with
+// the progress of each, and the progress of the whole project.
+func (h *ProjectHandler) milestonesWithProgress(ctx context.Context, projectID string) ([]models.Milestone, models.Progress, error) {
+	rows, err := h.Repo.ListMilestones(ctx, projectID)
+	if err != nil {
+		return nil, models.Progress{}, err
+	}
+	counts, err := h.Repo.ListMilestoneProgress(ctx, projectID)
+	if err != nil {
+		return nil, models.Progress{}, err
+	}
+	total, err := h.Repo.GetProjectProgress(ctx, projectID)
+	if err != nil {
+		return nil, models.Progress{}, err
+	}
+
+	byMilestone := make(map[string]db.ListMilestoneProgressRow, len(counts))
+	for _, row := range counts {
+		byMilestone[row.MilestoneID.String] = row
+	}
+	milestones := make([]models.Milestone, 0, len(rows))
+	for _, row := range rows {
+		milestone := toMilestone(row)
+		count := byMilestone[row.ID]
+		milestone.Progress = toProgress(count.Total, count.Completed)
+		milestones = append(milestones, milestone)
+	}
+	return milestones, toProgress(total.Total, total.Completed), nil
+}
+
+func (h *ProjectHandler) broadcastMilestones(c *fiber.Ctx, projectID string) {
+	milestones, progress, err := h.milestonesWithProgress(c.Context(), projectID)
+	if err != nil {
+		log.Printf("Failed to list milestones of project %s: %v", projectID, err)
+		return
+	}
+	ws.BroadcastToRoom("project", projectID, "project_milestones_updated", fiber.Map{
+		"milestones": milestones,
+		"progress":   progress,
+	})
+}
+
+// milestoneIDs returns the ids of milestones in order.
+func milestoneIDs(milestones []db.ProjectMilestone) []string {
+	ids := make([]string, 0, len(milestones))
+	for _, m := range milestones {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+// ListProjectMilestones returns the milestones of the project in order with
+// their progress, and the progress of the project as a whole.
+func (h *ProjectHandler) ListProjectMilestones(c *fiber.Ctx) error {
+	project, ok := h.loadProjectFor(c, authz.ProjectView, "you are not authorized to view this project")
+	if !ok {
+		return nil
+	}
+
+	milestones, progress, err := h.milestonesWithProgress(c.Context(), project.ID)
+	if err != nil {
+		log.Printf("Failed to list milestones of project %s: %v", project.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list milestones"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"milestones": milestones,
+		"progress":   progress,
+	})
+}
+
+// CreateProjectMilestone adds a milestone after the others of the project
+// unless Position is given.
+func (h *ProjectHandler) CreateProjectMilestone(c *fiber.Ctx) error {
+	var req models.CreateMilestoneRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	project, ok := h.loadProjectFor(c, authz.ProjectUpdate, "you are not authorized to update this project")
+	if !ok {
+		return nil
+	}
+
+	ctx := c.Context()
+	existing, err := h.Repo.ListMilestones(ctx, project.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list milestones"})
+	}
+
+	position := len(existing)
+	if req.Position != nil && *req.Position < position {
+		position = *req.Position
+	}
+	milestone := db.CreateProjectMilestoneParams{
+		ID:        uuid.NewString(),
+		ProjectID: project.ID,
+		Name:      req.Name,
+		Position:  int64(position),
+		CreatedAt: time.Now(),
+	}
+	if req.TargetDate != nil {
+		milestone.TargetDate = sql.NullTime{Time: *req.TargetDate, Valid: true}
+	}
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create milestone"})
+	}
+	defer tx.Rollback()
+
+	if err := h.Repo.CreateMilestoneTx(ctx, tx, milestone); err != nil {
+		log.Printf("Failed to create milestone of project %s: %v", project.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create milestone"})
+	}
+	if position < len(existing) {
+		ids := moveToPosition(milestoneIDs(existing), milestone.ID, position)
+		if err := h.Repo.SetMilestoneOrderTx(ctx, tx, ids); err != nil {
+			log.Printf("Failed to reorder milestones of project %s: %v", project.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create milestone"})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit milestone of project %s: %v", project.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create milestone"})
+	}
+
+	h.broadcastMilestones(c, project.ID)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":   "milestone created successfully",
+		"milestone": toMilestone(db.ProjectMilestone(milestone)),
+	})
+}
+
+// UpdateProjectMilestone renames, reschedules or moves a milestone.
+func (h *ProjectHandler) UpdateProjectMilestone(c *fiber.Ctx) error {
+	var req models.UpdateMilestoneRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	project, ok := h.loadProjectFor(c, authz.ProjectUpdate, "you are not authorized to update this project")
+	if !ok {
+		return nil
+	}
+	milestone, ok := h.loadMilestone(c, project.ID)
+	if !ok {
+		return nil
+	}
+
+	if req.Name != nil {
+		milestone.Name = *req.Name
+	}
+	if req.TargetDate != nil {
+		milestone.TargetDate = sql.NullTime{Time: *req.TargetDate, Valid: true}
+	}
+
+	ctx := c.Context()
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update milestone"})
+	}
+	defer tx.Rollback()
+
+	if err := h.Repo.UpdateMilestoneTx(ctx, tx, db.UpdateProjectMilestoneParams{
+		Name:       milestone.Name,
+		TargetDate: milestone.TargetDate,
+		ID:         milestone.ID,
+	}); err != nil {
+		log.Printf("Failed to update milestone %s: %v", milestone.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update milestone"})
+	}
+	if req.Position != nil {
+		existing, err := h.Repo.ListMilestones(ctx, project.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list milestones"})
+		}
+		ids := moveToPosition(milestoneIDs(existing), milestone.ID, *req.Position)
+		if err := h.Repo.SetMilestoneOrderTx(ctx, tx, ids); err != nil {
+			log.Printf("Failed to reorder milestones of project %s: %v", project.ID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update milestone"})
+		}
+		for i, id := range ids {
+			if id == milestone.ID {
+				milestone.Position = int64(i)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit milestone %s: %v", milestone.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update milestone"})
+	}
+
+	h.broadcastMilestones(c, project.ID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":   "milestone updated successfully",
+		"milestone": toMilestone(milestone),
+	})
+}
+
+// DeleteProjectMilestone deletes a milestone. Its issues stay in the project
+// without a milestone.
+func (h *ProjectHandler) DeleteProjectMilestone(c *fiber.Ctx) error {
+	project, ok := h.loadProjectFor(c, authz.ProjectUpdate, "you are not authorized to update this project")
+	if !ok {
+		return nil
+	}
+	milestone, ok := h.loadMilestone(c, project.ID)
+	if !ok {
+		return nil
+	}
+
+	if err := h.Repo.DeleteMilestone(c.Context(), milestone.ID); err != nil {
+		log.Printf("Failed to delete milestone %s: %v", milestone.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete milestone"})
+	}
+
+	h.broadcastMilestones(c, project.ID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "milestone deleted successfully"})
+}
+
+// checkMilestone checks that the issues of the project can be linked to the
+// milestone milestoneID. On failure the error response is already written.
+func (h *IssueHandler) checkMilestone(c *fiber.Ctx, projectID, milestoneID string) bool {
+	return check(c, h.projectMilestone(c.Context(), projectID, milestoneID))
+}
+
+// projectMilestone checks that the issues of the project can be linked to
+// the milestone milestoneID, which must be one of the project's milestones.
+func (h *IssueHandler) projectMilestone(ctx context.Context, projectID, milestoneID string) error {
+	if projectID == "" {
+		return &StatusError{Status: fiber.StatusBadRequest, Message: "only issues in a project can have a milestone"}
+	}
+	milestone, err := h.ProjectRepo.GetMilestoneByID(ctx, milestoneID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &StatusError{Status: fiber.StatusBadRequest, Message: "milestone not found"}
+		}
+		log.Printf("Failed to get milestone %s: %v", milestoneID, err)
+		return &StatusError{Status: fiber.StatusInternalServerError, Message: "failed to check milestone"}
+	}
+	if milestone.ProjectID != projectID {
+		return &StatusError{Status: fiber.StatusBadRequest, Message: fmt.Sprintf("milestone %s belongs to another project", milestone.Name)}
+	}
+	return nil
+}
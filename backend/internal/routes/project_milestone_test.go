@@ -0,0 +1,348 @@
+package routes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var milestoneProject = db.Project{ID: "project-1", Name: "Launch", WorkspaceID: "ws-1", TeamID: "team-1", CreatedBy: "user-999"}
+
+func TestListProjectMilestones(t *testing.T) {
+	mockRepo := new(mocks.MockProjectRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Get("/projects/:id/milestones", handler.ListProjectMilestones)
+
+	mockRepo.On("GetProjectByID", mock.Anything, "project-1").Return(milestoneProject, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	mockRepo.On("ListMilestones", mock.Anything, "project-1").Return([]db.ProjectMilestone{
+		{ID: "ms-1", ProjectID: "project-1", Name: "Alpha", Position: 0},
+		{ID: "ms-2", ProjectID: "project-1", Name: "Beta", Position: 1},
+	}, nil)
+	mockRepo.On("ListMilestoneProgress", mock.Anything, "project-1").Return([]db.ListMilestoneProgressRow{
+		{MilestoneID: sql.NullString{String: "ms-1", Valid: true}, Total: 4, Completed: 3},
+		{MilestoneID: sql.NullString{}, Total: 2, Completed: 0},
+	}, nil)
+	mockRepo.On("GetProjectProgress", mock.Anything, "project-1").Return(db.GetProjectProgressRow{Total: 6, Completed: 3}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/projects/project-1/milestones", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Milestones []models.Milestone `json:"milestones"`
+		Progress   models.Progress    `json:"progress"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Milestones, 2)
+	assert.Equal(t, models.Progress{Total: 4, Completed: 3, Percent: 75}, body.Milestones[0].Progress)
+	assert.Equal(t, models.Progress{}, body.Milestones[1].Progress)
+	assert.Equal(t, models.Progress{Total: 6, Completed: 3, Percent: 50}, body.Progress)
+}
+
+func TestCreateProjectMilestone(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockProjectRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.ProjectHandler{DB: mockDB, Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/projects/:id/milestones", handler.CreateProjectMilestone)
+
+	existing := []db.ProjectMilestone{
+		{ID: "ms-1", ProjectID: "project-1", Name: "Alpha", Position: 0},
+		{ID: "ms-2", ProjectID: "project-1", Name: "Beta", Position: 1},
+	}
+	project := func(roles db.GetTeamMemberRolesRow) {
+		mockRepo.On("GetProjectByID", mock.Anything, "project-1").Return(milestoneProject, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(roles, nil)
+	}
+	broadcast := func() {
+		mockRepo.On("ListMilestoneProgress", mock.Anything, "project-1").Return([]db.ListMilestoneProgressRow{}, nil)
+		mockRepo.On("GetProjectProgress", mock.Anything, "project-1").Return(db.GetProjectProgressRow{}, nil)
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		setup        func()
+		wantStatus   int
+		wantPosition int64
+	}{
+		{
+			name: "appended after the others",
+			body: `{"name":"Release candidate","target_date":"2026-03-01T00:00:00Z"}`,
+			setup: func() {
+				project(teamLeadRoles)
+				mockRepo.On("ListMilestones", mock.Anything, "project-1").Return(existing, nil)
+				sqlMock.ExpectBegin()
+				mockRepo.On("CreateMilestoneTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateProjectMilestoneParams) bool {
+					return p.Name == "Release candidate" && p.Position == 2 && p.TargetDate.Valid
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+				broadcast()
+			},
+			wantStatus:   fiber.StatusCreated,
+			wantPosition: 2,
+		},
+		{
+			name: "inserted at position",
+			body: `{"name":"Kickoff","position":0}`,
+			setup: func() {
+				project(teamLeadRoles)
+				mockRepo.On("ListMilestones", mock.Anything, "project-1").Return(existing, nil)
+				sqlMock.ExpectBegin()
+				mockRepo.On("CreateMilestoneTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateProjectMilestoneParams) bool {
+					return p.Name == "Kickoff" && p.Position == 0
+				})).Return(nil).Once()
+				mockRepo.On("SetMilestoneOrderTx", mock.Anything, mock.Anything, mock.MatchedBy(func(ids []string) bool {
+					return len(ids) == 3 && ids[1] == "ms-1" && ids[2] == "ms-2"
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+				broadcast()
+			},
+			wantStatus:   fiber.StatusCreated,
+			wantPosition: 0,
+		},
+		{
+			name:       "missing name",
+			body:       `{"name":"  "}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "member of the team who does not lead the project",
+			body: `{"name":"Beta"}`,
+			setup: func() {
+				project(teamMemberRoles)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name: "unknown project",
+			body: `{"name":"Beta"}`,
+			setup: func() {
+				mockRepo.On("GetProjectByID", mock.Anything, "project-1").Return(db.Project{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPost, "/projects/project-1/milestones", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+
+			if tt.wantStatus == fiber.StatusCreated {
+				var body struct {
+					Milestone models.Milestone `json:"milestone"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.wantPosition, body.Milestone.Position)
+			}
+
+			mockRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRepo.AssertNumberOfCalls(t, "CreateMilestoneTx", 2)
+	mockRepo.AssertNumberOfCalls(t, "SetMilestoneOrderTx", 1)
+}
+
+func TestUpdateProjectMilestone(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockProjectRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.ProjectHandler{DB: mockDB, Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Patch("/projects/:id/milestones/:milestoneId", handler.UpdateProjectMilestone)
+
+	milestones := []db.ProjectMilestone{
+		{ID: "ms-1", ProjectID: "project-1", Name: "Alpha", Position: 0},
+		{ID: "ms-2", ProjectID: "project-1", Name: "Beta", Position: 1},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "renamed and moved first",
+			path: "/projects/project-1/milestones/ms-2",
+			body: `{"name":"Beta 2","position":0}`,
+			setup: func() {
+				mockRepo.On("GetMilestoneByID", mock.Anything, "ms-2").Return(milestones[1], nil)
+				sqlMock.ExpectBegin()
+				mockRepo.On("UpdateMilestoneTx", mock.Anything, mock.Anything, db.UpdateProjectMilestoneParams{Name: "Beta 2", ID: "ms-2"}).Return(nil).Once()
+				mockRepo.On("ListMilestones", mock.Anything, "project-1").Return(milestones, nil)
+				mockRepo.On("SetMilestoneOrderTx", mock.Anything, mock.Anything, []string{"ms-2", "ms-1"}).Return(nil).Once()
+				sqlMock.ExpectCommit()
+				mockRepo.On("ListMilestoneProgress", mock.Anything, "project-1").Return([]db.ListMilestoneProgressRow{}, nil)
+				mockRepo.On("GetProjectProgress", mock.Anything, "project-1").Return(db.GetProjectProgressRow{}, nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "milestone of another project",
+			path: "/projects/project-1/milestones/ms-x",
+			body: `{"name":"Gamma"}`,
+			setup: func() {
+				mockRepo.On("GetMilestoneByID", mock.Anything, "ms-x").Return(db.ProjectMilestone{ID: "ms-x", ProjectID: "project-2"}, nil)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name: "unknown milestone",
+			path: "/projects/project-1/milestones/missing",
+			body: `{"name":"Gamma"}`,
+			setup: func() {
+				mockRepo.On("GetMilestoneByID", mock.Anything, "missing").Return(db.ProjectMilestone{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.On("GetProjectByID", mock.Anything, "project-1").Return(milestoneProject, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamLeadRoles, nil)
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPatch, tt.path, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+
+			mockRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRepo.AssertNumberOfCalls(t, "SetMilestoneOrderTx", 1)
+}
+
+func TestUpdateIssueMilestone(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	mockProjectRepo := new(mocks.MockProjectRepo)
+	handler := routes.IssueHandler{DB: mockDB, Repo: mockRepo, TeamRepo: mockTeamRepo, ProjectRepo: mockProjectRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Patch("/issues/:id", handler.UpdateIssue)
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "linked to a milestone of its project",
+			body: `{"milestone_id":"ms-2"}`,
+			setup: func() {
+				mockProjectRepo.On("GetMilestoneByID", mock.Anything, "ms-2").Return(db.ProjectMilestone{ID: "ms-2", ProjectID: "project-1"}, nil)
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(`UPDATE issues SET milestone_id = \? WHERE id = \?`).
+					WithArgs(sql.NullString{String: "ms-2", Valid: true}, "issue-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e db.CreateIssueEventParams) bool {
+					return e.Field == "milestone_id" && e.OldValue.String == "ms-1" && e.NewValue.String == "ms-2"
+				})).Return(nil).Once()
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "unlinked when moved to another project",
+			body: `{"project_id":"project-2"}`,
+			setup: func() {
+				mockProjectRepo.On("GetProjectByID", mock.Anything, "project-2").Return(db.Project{ID: "project-2", TeamID: "team-1"}, nil)
+				mockProjectRepo.On("IsProjectMember", mock.Anything, "project-2", "user-123").Return(true, nil)
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(`UPDATE issues SET project_id = \?, milestone_id = \? WHERE id = \?`).
+					WithArgs(sql.NullString{String: "project-2", Valid: true}, sql.NullString{}, "issue-1").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mockRepo.On("CreateIssueEventTx", mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "milestone of another project",
+			body: `{"milestone_id":"ms-x"}`,
+			setup: func() {
+				mockProjectRepo.On("GetMilestoneByID", mock.Anything, "ms-x").Return(db.ProjectMilestone{ID: "ms-x", ProjectID: "project-2", Name: "Other"}, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "unknown milestone",
+			body: `{"milestone_id":"missing"}`,
+			setup: func() {
+				mockProjectRepo.On("GetMilestoneByID", mock.Anything, "missing").Return(db.ProjectMilestone{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(db.Issue{
+				ID: "issue-1", TeamID: "team-1", OwnerID: "user-123", Status: "todo",
+				ProjectID:   sql.NullString{String: "project-1", Valid: true},
+				MilestoneID: sql.NullString{String: "ms-1", Valid: true},
+			}, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPatch, "/issues/issue-1", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.NoError(t, sqlMock.ExpectationsWereMet())
+
+			mockRepo.ExpectedCalls = nil
+			mockProjectRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRepo.AssertNumberOfCalls(t, "CreateIssueEventTx", 3)
+}
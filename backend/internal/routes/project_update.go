@@ -0,0 +1,179 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+// loadProjectUpdateFor fetches an update posted on the project and checks that
+// the user may perform the action on it. On failure the error response is
+// already written.
+func (h *ProjectHandler) loadProjectUpdateFor(c *fiber.Ctx, project db.Project, action authz.Action) (db.ProjectUpdate, bool) {
+	updateID := c.Params("updateId")
+	update, err := h.Repo.GetProjectUpdateByID(c.Context(), updateID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Failed to get project update %s: %v", updateID, err)
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch update"})
+		return update, false
+	}
+	if err != nil || update.ProjectID != project.ID {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "update not found"})
+		return update, false
+	}
+	if !authorize(c, h.Authz, action, authz.ForProjectUpdate(project, update), "you can only modify your own updates") {
+		return update, false
+	}
+	return update, true
+}
+
+// ListProjectUpdates returns the status updates posted on the project, newest
+// first.
+func (h *ProjectHandler) ListProjectUpdates(c *fiber.Ctx) error {
+	project, ok := h.loadProjectFor(c, authz.ProjectView, "you are not authorized to view this project")
+	if !ok {
+		return nil
+	}
+
+	updates, err := h.Repo.ListProjectUpdates(c.Context(), project.ID)
+	if err != nil {
+		log.Printf("Failed to list updates of project %s: %v", project.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch updates"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"updates": updates})
+}
+
+func (h *ProjectHandler) GetProjectUpdate(c *fiber.Ctx) error {
+	project, ok := h.loadProjectFor(c, authz.ProjectView, "you are not authorized to view this project")
+	if !ok {
+		return nil
+	}
+	update, ok := h.loadProjectUpdateFor(c, project, authz.ProjectView)
+	if !ok {
+		return nil
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"update": update})
+}
+
+// CreateProjectUpdate posts a status update on the project. Only those who
+// may update the project, such as its leader, can post.
+func (h *ProjectHandler) CreateProjectUpdate(c *fiber.Ctx) error {
+	var req models.CreateProjectUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	project, ok := h.loadProjectFor(c, authz.ProjectUpdate, "only the project leader can post updates")
+	if !ok {
+		return nil
+	}
+
+	update := db.ProjectUpdate{
+		ID:        uuid.NewString(),
+		ProjectID: project.ID,
+		AuthorID:  c.Locals("userID").(string),
+		Health:    req.Health,
+		Body:      req.Body,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := h.Repo.CreateProjectUpdate(c.Context(), db.CreateProjectUpdateParams{
+		ID:        update.ID,
+		ProjectID: update.ProjectID,
+		AuthorID:  update.AuthorID,
+		Health:    update.Health,
+		Body:      update.Body,
+		CreatedAt: update.CreatedAt,
+	}); err != nil {
+		log.Printf("Failed to post update on project %s: %v", project.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to post update"})
+	}
+
+	ws.BroadcastToRoom("project", project.ID, "project_update_created", update)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "update posted successfully",
+		"update":  update,
+	})
+}
+
+// EditProjectUpdate changes the health or body of an update. Only its author
+// can edit it.
+func (h *ProjectHandler) EditProjectUpdate(c *fiber.Ctx) error {
+	var req models.EditProjectUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Body != nil {
+		body := strings.TrimSpace(*req.Body)
+		req.Body = &body
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	project, ok := h.loadProjectFor(c, authz.ProjectView, "you are not authorized to view this project")
+	if !ok {
+		return nil
+	}
+	update, ok := h.loadProjectUpdateFor(c, project, authz.ProjectUpdateEdit)
+	if !ok {
+		return nil
+	}
+
+	if req.Health != nil {
+		update.Health = *req.Health
+	}
+	if req.Body != nil {
+		update.Body = *req.Body
+	}
+	update.UpdatedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	if err := h.Repo.EditProjectUpdate(c.Context(), db.EditProjectUpdateParams{
+		Health:    update.Health,
+		Body:      update.Body,
+		UpdatedAt: update.UpdatedAt,
+		ID:        update.ID,
+	}); err != nil {
+		log.Printf("Failed to edit project update %s: %v", update.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to edit update"})
+	}
+
+	ws.BroadcastToRoom("project", project.ID, "project_update_updated", update)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "update edited successfully",
+		"update":  update,
+	})
+}
+
+func (h *ProjectHandler) DeleteProjectUpdate(c *fiber.Ctx) error {
+	project, ok := h.loadProjectFor(c, authz.ProjectView, "you are not authorized to view this project")
+	if !ok {
+		return nil
+	}
+	update, ok := h.loadProjectUpdateFor(c, project, authz.ProjectUpdateDelete)
+	if !ok {
+		return nil
+	}
+
+	if err := h.Repo.DeleteProjectUpdate(c.Context(), update.ID); err != nil {
+		log.Printf("Failed to delete project update %s: %v", update.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete update"})
+	}
+
+	ws.BroadcastToRoom("project", project.ID, "project_update_deleted", fiber.Map{"update_id": update.ID})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "update deleted successfully"})
+}
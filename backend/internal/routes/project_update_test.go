@@ -0,0 +1,209 @@
+package routes_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateProjectUpdate(t *testing.T) {
+	mockRepo := new(mocks.MockProjectRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/projects/:id/updates", handler.CreateProjectUpdate)
+
+	led := milestoneProject
+	led.LeaderID = "user-123"
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "leader posts an update",
+			body: `{"health":"at_risk","body":"  Waiting on **review**.  "}`,
+			setup: func() {
+				mockRepo.On("GetProjectByID", mock.Anything, "project-1").Return(led, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				mockRepo.On("CreateProjectUpdate", mock.Anything, mock.MatchedBy(func(p db.CreateProjectUpdateParams) bool {
+					return p.ProjectID == "project-1" && p.AuthorID == "user-123" && p.Health == "at_risk" && p.Body == "Waiting on **review**."
+				})).Return(nil).Once()
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "team member who does not lead the project",
+			body: `{"health":"on_track","body":"All good"}`,
+			setup: func() {
+				mockRepo.On("GetProjectByID", mock.Anything, "project-1").Return(milestoneProject, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:       "unknown health",
+			body:       `{"health":"fine","body":"All good"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "empty body",
+			body:       `{"health":"on_track","body":"   "}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPost, "/projects/project-1/updates", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusCreated {
+				var body struct {
+					Update db.ProjectUpdate `json:"update"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, "at_risk", body.Update.Health)
+				assert.NotEmpty(t, body.Update.ID)
+			}
+
+			mockRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRepo.AssertNumberOfCalls(t, "CreateProjectUpdate", 1)
+}
+
+func TestEditAndDeleteProjectUpdate(t *testing.T) {
+	mockRepo := new(mocks.MockProjectRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Patch("/projects/:id/updates/:updateId", handler.EditProjectUpdate)
+	app.Delete("/projects/:id/updates/:updateId", handler.DeleteProjectUpdate)
+
+	own := db.ProjectUpdate{ID: "update-1", ProjectID: "project-1", AuthorID: "user-123", Health: "on_track", Body: "Started"}
+	others := db.ProjectUpdate{ID: "update-2", ProjectID: "project-1", AuthorID: "user-999", Health: "on_track", Body: "Kickoff"}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		roles      db.GetTeamMemberRolesRow
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name:   "author edits the health",
+			method: http.MethodPatch,
+			path:   "/projects/project-1/updates/update-1",
+			body:   `{"health":"off_track"}`,
+			roles:  teamMemberRoles,
+			setup: func() {
+				mockRepo.On("GetProjectUpdateByID", mock.Anything, "update-1").Return(own, nil)
+				mockRepo.On("EditProjectUpdate", mock.Anything, mock.MatchedBy(func(p db.EditProjectUpdateParams) bool {
+					return p.ID == "update-1" && p.Health == "off_track" && p.Body == "Started" && p.UpdatedAt.Valid
+				})).Return(nil).Once()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:   "lead cannot edit others update",
+			method: http.MethodPatch,
+			path:   "/projects/project-1/updates/update-2",
+			body:   `{"body":"Rewritten"}`,
+			roles:  teamLeadRoles,
+			setup: func() {
+				mockRepo.On("GetProjectUpdateByID", mock.Anything, "update-2").Return(others, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:   "update of another project",
+			method: http.MethodPatch,
+			path:   "/projects/project-1/updates/update-x",
+			body:   `{"body":"Rewritten"}`,
+			roles:  teamMemberRoles,
+			setup: func() {
+				mockRepo.On("GetProjectUpdateByID", mock.Anything, "update-x").Return(db.ProjectUpdate{ID: "update-x", ProjectID: "project-2"}, nil)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name:   "lead deletes others update",
+			method: http.MethodDelete,
+			path:   "/projects/project-1/updates/update-2",
+			roles:  teamLeadRoles,
+			setup: func() {
+				mockRepo.On("GetProjectUpdateByID", mock.Anything, "update-2").Return(others, nil)
+				mockRepo.On("DeleteProjectUpdate", mock.Anything, "update-2").Return(nil).Once()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:   "member cannot delete others update",
+			method: http.MethodDelete,
+			path:   "/projects/project-1/updates/update-2",
+			roles:  teamMemberRoles,
+			setup: func() {
+				mockRepo.On("GetProjectUpdateByID", mock.Anything, "update-2").Return(others, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:   "unknown update",
+			method: http.MethodDelete,
+			path:   "/projects/project-1/updates/missing",
+			roles:  teamLeadRoles,
+			setup: func() {
+				mockRepo.On("GetProjectUpdateByID", mock.Anything, "missing").Return(db.ProjectUpdate{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.On("GetProjectByID", mock.Anything, "project-1").Return(milestoneProject, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(tt.roles, nil)
+			tt.setup()
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			mockRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRepo.AssertNumberOfCalls(t, "EditProjectUpdate", 1)
+	mockRepo.AssertNumberOfCalls(t, "DeleteProjectUpdate", 1)
+}
@@ -2,26 +2,32 @@ package routes
 
 import (
 	"database/sql"
+	"log"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
 	models "github.com/nack098/nakumanager/internal/models"
 	"github.com/nack098/nakumanager/internal/repositories"
 	"github.com/nack098/nakumanager/internal/ws"
 )
 
 type ProjectHandler struct {
-	DB       *sql.DB
-	Repo     repositories.ProjectRepository
-	TeamRepo repositories.TeamRepository
+	DB        *sql.DB
+	Repo      repositories.ProjectRepository
+	TeamRepo  repositories.TeamRepository
+	LabelRepo repositories.LabelRepository
+	Authz     *authz.Authorizer
 }
 
-func NewProjectHandler(db *sql.DB, repo repositories.ProjectRepository, teamRepo repositories.TeamRepository) *ProjectHandler {
+func NewProjectHandler(db *sql.DB, repo repositories.ProjectRepository, teamRepo repositories.TeamRepository, labelRepo repositories.LabelRepository, roleRepo repositories.RoleRepository) *ProjectHandler {
 	return &ProjectHandler{
-		DB:       db,
-		Repo:     repo,
-		TeamRepo: teamRepo,
+		DB:        db,
+		Repo:      repo,
+		TeamRepo:  teamRepo,
+		LabelRepo: labelRepo,
+		Authz:     authz.NewAuthorizer(roleRepo),
 	}
 }
 
@@ -45,13 +51,13 @@ func (h *ProjectHandler) CreateProject(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "team does not belong to the specified workspace"})
 	}
 
-	// ตรวจสอบสมาชิกทีม
-	isMember, err := h.TeamRepo.IsMemberInTeam(c.Context(), body.TeamID, userID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check team membership"})
+	// ตรวจสอบสิทธิ์ในทีม
+	if !authorize(c, h.Authz, authz.ProjectCreate, authz.ForTeam(body.TeamID), "you are not a member of the team") {
+		return nil
 	}
-	if !isMember {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "you are not a member of the team"})
+
+	if !checkLabels(c, h.LabelRepo, body.TeamID, body.Labels) {
+		return nil
 	}
 
 	projectID := uuid.NewString()
@@ -66,6 +72,12 @@ func (h *ProjectHandler) CreateProject(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	for _, labelID := range body.Labels {
+		if err := h.LabelRepo.AddLabelToProject(c.Context(), projectID, labelID); err != nil {
+			log.Printf("Failed to add label %s: %v", labelID, err)
+		}
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Project created successfully"})
 }
 
@@ -80,27 +92,19 @@ func (h *ProjectHandler) UpdateProject(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
 	}
 
-	userID := c.Locals("userID").(string)
-
-	// ตรวจสอบงาสคนที่แก้ไขคือ Owner ไม่ก็ Leader
-	owner, err := h.Repo.GetOwnerByProjectID(c.Context(), projectID)
+	// ตรวจสอบว่า project มีอยู่จริง
+	project, err := h.Repo.GetProjectByID(c.Context(), projectID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project not found"})
 	}
-	if owner != userID {
-		leader, err := h.Repo.GetLeaderByProjectID(c.Context(), projectID)
-		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "project not found"})
-		}
-		if leader != userID {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "you are not authorized to update this project"})
-		}
+
+	// ผู้แก้ไขต้องเป็น Owner, Leader, หัวหน้าทีม หรือ admin ของ workspace
+	if !authorize(c, h.Authz, authz.ProjectUpdate, authz.ForProject(project), "you are not authorized to update this project") {
+		return nil
 	}
 
-	// ตรวจสอบว่า project มีอยู่จริง
-	_, err = h.Repo.GetProjectByID(c.Context(), projectID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch project"})
+	if body.AddLabel != nil && !checkLabels(c, h.LabelRepo, project.TeamID, *body.AddLabel) {
+		return nil
 	}
 
 	body.ID = projectID
@@ -118,8 +122,26 @@ func (h *ProjectHandler) UpdateProject(c *fiber.Ctx) error {
 		}
 	}
 
-	if _, err := h.DB.ExecContext(c.Context(), query, args...); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update project"})
+	if body.AddLabel != nil {
+		for _, labelID := range *body.AddLabel {
+			if err := h.LabelRepo.AddLabelToProject(c.Context(), projectID, labelID); err != nil {
+				log.Printf("Failed to add label %s: %v", labelID, err)
+			}
+		}
+	}
+
+	if body.RemoveLabel != nil {
+		for _, labelID := range *body.RemoveLabel {
+			if err := h.LabelRepo.RemoveLabelFromProject(c.Context(), projectID, labelID); err != nil {
+				log.Printf("Failed to remove label %s: %v", labelID, err)
+			}
+		}
+	}
+
+	if query != "" {
+		if _, err := h.DB.ExecContext(c.Context(), query, args...); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update project"})
+		}
 	}
 
 	ws.BroadcastToRoom("project", projectID, "project_updated", body)
@@ -146,18 +168,15 @@ func (h *ProjectHandler) DeleteProject(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "project_id is required"})
 	}
 
-	// ตรวจสอบสิทธิ์
-	userID := c.Locals("userID").(string)
-
 	// ตรวจสอบว่า project มีอยู่
 	project, err := h.Repo.GetProjectByID(c.Context(), projectID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch project"})
 	}
 
-	// ตรวจสอบว่าผู้ใช้เป็น Owner หรือ Leader
-	if project.CreatedBy != userID && project.LeaderID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "only project creator or leader can delete project"})
+	// ตรวจสอบว่าผู้ใช้เป็น Owner, Leader หรือผู้ดูแลทีม/workspace
+	if !authorize(c, h.Authz, authz.ProjectDelete, authz.ForProject(project), "only project creator or leader can delete project") {
+		return nil
 	}
 
 	// ลบ
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/nack098/nakumanager/internal/authz"
 	"github.com/nack098/nakumanager/internal/db"
 	models "github.com/nack098/nakumanager/internal/models"
 	"github.com/nack098/nakumanager/internal/routes"
@@ -27,12 +28,16 @@ func TestNewProjectHandler(t *testing.T) {
 	db := &sql.DB{}
 	projectRepo := new(mocks.MockProjectRepo)
 	teamRepo := new(mocks.MockTeamRepository)
+	labelRepo := new(mocks.MockLabelRepo)
+	roleRepo := new(mocks.MockRoleRepo)
 
-	handler := routes.NewProjectHandler(db, projectRepo, teamRepo)
+	handler := routes.NewProjectHandler(db, projectRepo, teamRepo, labelRepo, roleRepo)
 
 	assert.Equal(t, db, handler.DB)
 	assert.Equal(t, projectRepo, handler.Repo)
 	assert.Equal(t, teamRepo, handler.TeamRepo)
+	assert.Equal(t, labelRepo, handler.LabelRepo)
+	assert.NotNil(t, handler.Authz)
 }
 
 func TestCreateProject(t *testing.T) {
@@ -40,7 +45,8 @@ func TestCreateProject(t *testing.T) {
 
 	mockRepo := new(mocks.MockProjectRepo)
 	mockTeamRepo := new(mocks.MockTeamRepository)
-	handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo}
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 	app.Post("/projects", func(c *fiber.Ctx) error {
 		c.Locals("userID", "user-123")
@@ -57,7 +63,7 @@ func TestCreateProject(t *testing.T) {
 
 		team := db.Team{ID: "team-1", WorkspaceID: "workspace-1"}
 		mockTeamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(team, nil)
-		mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(true, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 		mockRepo.On("CreateProject", mock.Anything, mock.Anything).Return(nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/projects", bytes.NewReader(jsonBody))
@@ -72,7 +78,8 @@ func TestCreateProject(t *testing.T) {
 
 		mockRepo := new(mocks.MockProjectRepo)
 		mockTeamRepo := new(mocks.MockTeamRepository)
-		handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Post("/projects", func(c *fiber.Ctx) error {
 			c.Locals("userID", "user-123")
@@ -87,7 +94,7 @@ func TestCreateProject(t *testing.T) {
 		jsonBody, _ := json.Marshal(body)
 
 		mockTeamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(db.Team{ID: "team-1", WorkspaceID: "workspace-1"}, nil)
-		mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").Return(true, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 		mockRepo.On("CreateProject", mock.Anything, mock.Anything).Return(errors.New("failed to create project"))
 
 		req := httptest.NewRequest(http.MethodPost, "/projects", bytes.NewReader(jsonBody))
@@ -177,9 +184,11 @@ func TestCreateProject(t *testing.T) {
 	t.Run("failed to check team membership", func(t *testing.T) {
 		mockTeamRepo := new(mocks.MockTeamRepository)
 		mockRepo := new(mocks.MockProjectRepo)
+		mockRoleRepo := new(mocks.MockRoleRepo)
 		handler := &routes.ProjectHandler{
 			Repo:     mockRepo,
 			TeamRepo: mockTeamRepo,
+			Authz:    authz.NewAuthorizer(mockRoleRepo),
 		}
 
 		app := fiber.New()
@@ -197,8 +206,8 @@ func TestCreateProject(t *testing.T) {
 		mockTeamRepo.On("GetTeamByID", mock.Anything, "team-1").
 			Return(db.Team{ID: "team-1", WorkspaceID: "workspace-1"}, nil)
 
-		mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").
-			Return(false, errors.New("failed to check team membership"))
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+			Return(noRoles, errors.New("failed to check team membership"))
 
 		req := httptest.NewRequest(http.MethodPost, "/projects", bytes.NewReader(jsonBody))
 		req.Header.Set("Content-Type", "application/json")
@@ -210,9 +219,11 @@ func TestCreateProject(t *testing.T) {
 	t.Run("User not a member of team", func(t *testing.T) {
 		mockTeamRepo := new(mocks.MockTeamRepository)
 		mockRepo := new(mocks.MockProjectRepo)
+		mockRoleRepo := new(mocks.MockRoleRepo)
 		handler := &routes.ProjectHandler{
 			Repo:     mockRepo,
 			TeamRepo: mockTeamRepo,
+			Authz:    authz.NewAuthorizer(mockRoleRepo),
 		}
 
 		app := fiber.New()
@@ -230,8 +241,8 @@ func TestCreateProject(t *testing.T) {
 		mockTeamRepo.On("GetTeamByID", mock.Anything, "team-1").
 			Return(db.Team{ID: "team-1", WorkspaceID: "workspace-1"}, nil)
 
-		mockTeamRepo.On("IsMemberInTeam", mock.Anything, "team-1", "user-123").
-			Return(false, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+			Return(workspaceMemberRoles, nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/projects", bytes.NewReader(jsonBody))
 		req.Header.Set("Content-Type", "application/json")
@@ -245,7 +256,8 @@ func TestCreateProject(t *testing.T) {
 
 		mockRepo := new(mocks.MockProjectRepo)
 		mockTeamRepo := new(mocks.MockTeamRepository)
-		handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Post("/projects", func(c *fiber.Ctx) error {
 			c.Locals("userID", "user-123")
@@ -255,8 +267,8 @@ func TestCreateProject(t *testing.T) {
 		mockTeamRepo.On("GetTeamByID", mock.AnythingOfType("*fasthttp.RequestCtx"), "team-1").
 			Return(db.Team{ID: "team-1", WorkspaceID: "workspace-1"}, nil)
 
-		mockTeamRepo.On("IsMemberInTeam", mock.AnythingOfType("*fasthttp.RequestCtx"), "team-1", "user-123").
-			Return(true, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.AnythingOfType("*fasthttp.RequestCtx"), "team-1", "user-123").
+			Return(teamMemberRoles, nil)
 
 		leaderID := ""
 		body := models.CreateProject{
@@ -281,6 +293,53 @@ func TestCreateProject(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("labels", func(t *testing.T) {
+		teamLabels := []db.Label{{ID: "l1", WorkspaceID: "workspace-1"}, {ID: "l2", WorkspaceID: "workspace-1", TeamID: sql.NullString{String: "team-1", Valid: true}}}
+
+		tests := []struct {
+			name       string
+			labels     []string
+			wantStatus int
+		}{
+			{"labels of the team are added", []string{"l1", "l2"}, fiber.StatusCreated},
+			{"label the team cannot use", []string{"l1", "other"}, fiber.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := new(mocks.MockProjectRepo)
+				mockTeamRepo := new(mocks.MockTeamRepository)
+				mockLabelRepo := new(mocks.MockLabelRepo)
+				mockRoleRepo := new(mocks.MockRoleRepo)
+				handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo, LabelRepo: mockLabelRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+				app := fiber.New()
+				app.Use(withUserID("user-123"))
+				app.Post("/projects", handler.CreateProject)
+
+				mockTeamRepo.On("GetTeamByID", mock.Anything, "team-1").Return(db.Team{ID: "team-1", WorkspaceID: "workspace-1"}, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				mockLabelRepo.On("ListTeamLabels", mock.Anything, "team-1").Return(teamLabels, nil)
+				if tt.wantStatus == fiber.StatusCreated {
+					mockRepo.On("CreateProject", mock.Anything, mock.Anything).Return(nil)
+					for _, id := range tt.labels {
+						mockLabelRepo.On("AddLabelToProject", mock.Anything, mock.Anything, id).Return(nil).Once()
+					}
+				}
+
+				jsonBody, _ := json.Marshal(models.CreateProject{TeamID: "team-1", WorkspaceID: "workspace-1", Name: "Labelled", Labels: tt.labels})
+				req := httptest.NewRequest(http.MethodPost, "/projects", bytes.NewReader(jsonBody))
+				req.Header.Set("Content-Type", "application/json")
+				resp, err := app.Test(req)
+				require.NoError(t, err)
+
+				assert.Equal(t, tt.wantStatus, resp.StatusCode)
+				mockRepo.AssertExpectations(t)
+				mockLabelRepo.AssertExpectations(t)
+			})
+		}
+	})
+
 }
 
 func TestGetProjectByUserID(t *testing.T) {
@@ -327,13 +386,15 @@ func TestDeleteProject(t *testing.T) {
 
 		mockRepo := new(mocks.MockProjectRepo)
 		mockTeamRepo := new(mocks.MockTeamRepository)
-		handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Use(withUserID("user-123"))
 		app.Delete("/projects/:id", handler.DeleteProject)
 
 		mockRepo.On("GetProjectByID", mock.Anything, "project-123").
-			Return(db.Project{ID: "project-123", CreatedBy: "user-123"}, nil)
+			Return(db.Project{ID: "project-123", TeamID: "team-1", CreatedBy: "user-123"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 
 		mockRepo.On("DeleteProject", mock.Anything, "project-123").
 			Return(nil)
@@ -354,13 +415,15 @@ func TestDeleteProject(t *testing.T) {
 		app := fiber.New()
 
 		mockRepo := new(mocks.MockProjectRepo)
-		handler := &routes.ProjectHandler{Repo: mockRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Use(withUserID("user-123"))
 		app.Delete("/projects/:id", handler.DeleteProject)
 
 		mockRepo.On("GetProjectByID", mock.Anything, "project-err").
-			Return(db.Project{ID: "project-err", CreatedBy: "user-123"}, nil)
+			Return(db.Project{ID: "project-err", TeamID: "team-1", CreatedBy: "user-123"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 
 		mockRepo.On("DeleteProject", mock.Anything, "project-err").
 			Return(errors.New("delete failed"))
@@ -402,7 +465,8 @@ func TestDeleteProject(t *testing.T) {
 		app := fiber.New()
 
 		mockRepo := new(mocks.MockProjectRepo)
-		handler := &routes.ProjectHandler{Repo: mockRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Use(withUserID("user-123"))
 		app.Delete("/projects/:id", handler.DeleteProject)
@@ -427,13 +491,15 @@ func TestDeleteProject(t *testing.T) {
 
 		mockRepo := new(mocks.MockProjectRepo)
 		mockTeamRepo := new(mocks.MockTeamRepository)
-		handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, TeamRepo: mockTeamRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Use(withUserID("user-123"))
 		app.Delete("/projects/:id", handler.DeleteProject)
 
 		mockRepo.On("GetProjectByID", mock.Anything, "project-123").
-			Return(db.Project{ID: "project-123", CreatedBy: "user-456"}, nil)
+			Return(db.Project{ID: "project-123", TeamID: "team-1", CreatedBy: "user-456"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/projects/project-123", nil)
 		req.Header.Set("Content-Type", "application/json")
@@ -447,6 +513,30 @@ func TestDeleteProject(t *testing.T) {
 		assert.Equal(t, "only project creator or leader can delete project", response["error"])
 	})
 
+	t.Run("Team Lead Can Delete", func(t *testing.T) {
+		app := fiber.New()
+
+		mockRepo := new(mocks.MockProjectRepo)
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+
+		app.Use(withUserID("user-123"))
+		app.Delete("/projects/:id", handler.DeleteProject)
+
+		mockRepo.On("GetProjectByID", mock.Anything, "project-123").
+			Return(db.Project{ID: "project-123", TeamID: "team-1", CreatedBy: "user-456"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+			Return(teamLeadRoles, nil)
+		mockRepo.On("DeleteProject", mock.Anything, "project-123").Return(nil)
+
+		req := httptest.NewRequest(http.MethodDelete, "/projects/project-123", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockRepo.AssertExpectations(t)
+	})
+
 }
 
 func TestUpdateProject(t *testing.T) {
@@ -455,18 +545,19 @@ func TestUpdateProject(t *testing.T) {
 
 		mockRepo := new(mocks.MockProjectRepo)
 
-		db, sqlMock, err := sqlmock.New()
+		mockDb, sqlMock, err := sqlmock.New()
 		assert.NoError(t, err)
-		defer db.Close()
+		defer mockDb.Close()
 
-		handler := &routes.ProjectHandler{Repo: mockRepo, DB: db}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, DB: mockDb, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Use(withUserID("user-123"))
 		app.Put("/projects/:id", handler.UpdateProject)
 
-		mockRepo.On("GetOwnerByProjectID", mock.Anything, "project-123").Return("user-123", nil)
 		mockRepo.On("GetProjectByID", mock.Anything, "project-123").
-			Return(&models.CreateProject{ID: "project-123", Name: "Old Name"}, nil)
+			Return(db.Project{ID: "project-123", TeamID: "team-1", Name: "Old Name", CreatedBy: "user-123"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 
 		sqlMock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -527,70 +618,73 @@ func TestUpdateProject(t *testing.T) {
 		assert.Equal(t, "invalid request body", response["error"])
 	})
 
-	t.Run("Get Project Owner Failed", func(t *testing.T) {
+	t.Run("Guest Cannot Update", func(t *testing.T) {
 		app := fiber.New()
 
 		mockRepo := new(mocks.MockProjectRepo)
-		handler := &routes.ProjectHandler{Repo: mockRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Use(withUserID("user-123"))
 		app.Patch("/projects/:id", handler.UpdateProject)
 
-		mockRepo.On("GetOwnerByProjectID", mock.Anything, "project-123").Return("", errors.New("failed to get project owner"))
+		mockRepo.On("GetProjectByID", mock.Anything, "project-123").
+			Return(db.Project{ID: "project-123", TeamID: "team-1", CreatedBy: "user-123"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+			Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-1", WorkspaceRole: "guest", TeamRole: "member"}, nil)
 
 		req := httptest.NewRequest(http.MethodPatch, "/projects/project-123", bytes.NewReader([]byte(`{}`)))
 		req.Header.Set("Content-Type", "application/json")
 		resp, err := app.Test(req)
 
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
 
 		var response map[string]interface{}
 		_ = json.NewDecoder(resp.Body).Decode(&response)
-		assert.Equal(t, "project not found", response["error"])
+		assert.Equal(t, "you are not authorized to update this project", response["error"])
 	})
 
-	t.Run("Get Leader Failed", func(t *testing.T) {
+	t.Run("Check Roles Failed", func(t *testing.T) {
 		app := fiber.New()
 
 		mockRepo := new(mocks.MockProjectRepo)
-		handler := &routes.ProjectHandler{Repo: mockRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Use(withUserID("user-123"))
 		app.Patch("/projects/:id", handler.UpdateProject)
 
-		mockRepo.On("GetOwnerByProjectID", mock.Anything, "project-123").
-			Return("user-456", nil)
-
-		mockRepo.On("GetLeaderByProjectID", mock.Anything, "project-123").
-			Return("", errors.New("leader fetch failed"))
+		mockRepo.On("GetProjectByID", mock.Anything, "project-123").
+			Return(db.Project{ID: "project-123", TeamID: "team-1", CreatedBy: "user-456"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").
+			Return(noRoles, errors.New("roles fetch failed"))
 
 		req := httptest.NewRequest(http.MethodPatch, "/projects/project-123", bytes.NewReader([]byte(`{}`)))
 		req.Header.Set("Content-Type", "application/json")
 		resp, err := app.Test(req)
 
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 
 		var response map[string]interface{}
 		_ = json.NewDecoder(resp.Body).Decode(&response)
-		assert.Equal(t, "project not found", response["error"])
+		assert.Equal(t, "failed to check permissions", response["error"])
 	})
 
 	t.Run("User is neither Owner nor Leader", func(t *testing.T) {
 		app := fiber.New()
 
 		mockRepo := new(mocks.MockProjectRepo)
-		handler := &routes.ProjectHandler{Repo: mockRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Use(withUserID("user-123"))
 		app.Patch("/projects/:id", handler.UpdateProject)
 
-		mockRepo.On("GetOwnerByProjectID", mock.Anything, "project-123").
-			Return("user-456", nil)
-
-		mockRepo.On("GetLeaderByProjectID", mock.Anything, "project-123").
-			Return("user-789", nil)
+		mockRepo.On("GetProjectByID", mock.Anything, "project-123").
+			Return(db.Project{ID: "project-123", TeamID: "team-1", CreatedBy: "user-456", LeaderID: "user-789"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 
 		req := httptest.NewRequest(http.MethodPatch, "/projects/project-123", bytes.NewReader([]byte(`{}`)))
 		req.Header.Set("Content-Type", "application/json")
@@ -604,32 +698,30 @@ func TestUpdateProject(t *testing.T) {
 		assert.Equal(t, "you are not authorized to update this project", response["error"])
 	})
 
-	t.Run("GetProjectByID failed", func(t *testing.T) {
+	t.Run("Project Not Found", func(t *testing.T) {
 		app := fiber.New()
 
 		mockRepo := new(mocks.MockProjectRepo)
-		handler := &routes.ProjectHandler{Repo: mockRepo}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
 
 		app.Use(withUserID("user-123"))
 		app.Patch("/projects/:id", handler.UpdateProject)
 
-		mockRepo.On("GetOwnerByProjectID", mock.Anything, "project-123").
-			Return("user-123", nil)
-
 		mockRepo.On("GetProjectByID", mock.Anything, "project-123").
-			Return(db.Project{}, errors.New("db error"))
+			Return(db.Project{}, sql.ErrNoRows)
 
 		req := httptest.NewRequest(http.MethodPatch, "/projects/project-123", bytes.NewBufferString(`{}`))
 		req.Header.Set("Content-Type", "application/json")
 
 		resp, err := app.Test(req)
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
 
 		var response map[string]interface{}
 		err = json.NewDecoder(resp.Body).Decode(&response)
 		assert.NoError(t, err)
-		assert.Equal(t, "failed to fetch project", response["error"])
+		assert.Equal(t, "project not found", response["error"])
 
 		mockRepo.AssertExpectations(t)
 	})
@@ -642,14 +734,14 @@ func TestUpdateProject(t *testing.T) {
 		assert.NoError(t, err)
 		defer mockDb.Close()
 
-		handler := &routes.ProjectHandler{Repo: mockRepo, DB: mockDb}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, DB: mockDb, Authz: authz.NewAuthorizer(mockRoleRepo)}
 		app.Use(withUserID("user-123"))
 		app.Patch("/projects/:id", handler.UpdateProject)
 
-		mockRepo.On("GetOwnerByProjectID", mock.Anything, "project-123").
-			Return("user-123", nil)
 		mockRepo.On("GetProjectByID", mock.Anything, "project-123").
-			Return(db.Project{ID: "project-123"}, nil)
+			Return(db.Project{ID: "project-123", TeamID: "team-1", LeaderID: "user-123"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 
 		mockRepo.On("AddMemberToProject", mock.Anything, "project-123", "userA").Return(nil).Once()
 		mockRepo.On("AddMemberToProject", mock.Anything, "project-123", "userB").Return(nil).Once()
@@ -693,18 +785,20 @@ func TestUpdateProject(t *testing.T) {
 		require.NoError(t, err)
 		defer mockDb.Close()
 
-		handler := &routes.ProjectHandler{Repo: mockRepo, DB: mockDb}
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, DB: mockDb, Authz: authz.NewAuthorizer(mockRoleRepo)}
 		app.Use(withUserID("user-123"))
 		app.Patch("/projects/:id", handler.UpdateProject)
 
-		mockRepo.On("GetOwnerByProjectID", mock.Anything, "project-123").Return("user-123", nil)
-		mockRepo.On("GetProjectByID", mock.Anything, "project-123").Return(db.Project{ID: "project-123"}, nil)
+		mockRepo.On("GetProjectByID", mock.Anything, "project-123").Return(db.Project{ID: "project-123", TeamID: "team-1", CreatedBy: "user-123"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
 
 		emptySlice := []string{}
+		name := "Renamed"
 		bodyStruct := models.EditProject{
 			AddMember:    &emptySlice,
 			RemoveMember: &emptySlice,
-			Name:         nil,
+			Name:         &name,
 		}
 		jsonBody, err := json.Marshal(bodyStruct)
 		require.NoError(t, err)
@@ -727,4 +821,62 @@ func TestUpdateProject(t *testing.T) {
 		assert.NoError(t, sqlMock.ExpectationsWereMet())
 	})
 
+	t.Run("labels only", func(t *testing.T) {
+		app := fiber.New()
+
+		mockRepo := new(mocks.MockProjectRepo)
+		mockLabelRepo := new(mocks.MockLabelRepo)
+		mockDb, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer mockDb.Close()
+
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, LabelRepo: mockLabelRepo, DB: mockDb, Authz: authz.NewAuthorizer(mockRoleRepo)}
+		app.Use(withUserID("user-123"))
+		app.Patch("/projects/:id", handler.UpdateProject)
+
+		mockRepo.On("GetProjectByID", mock.Anything, "project-123").Return(db.Project{ID: "project-123", TeamID: "team-1", CreatedBy: "user-123"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+		mockLabelRepo.On("ListTeamLabels", mock.Anything, "team-1").Return([]db.Label{{ID: "l1"}}, nil)
+		mockLabelRepo.On("AddLabelToProject", mock.Anything, "project-123", "l1").Return(nil).Once()
+		mockLabelRepo.On("RemoveLabelFromProject", mock.Anything, "project-123", "l2").Return(nil).Once()
+
+		req := httptest.NewRequest(http.MethodPatch, "/projects/project-123", strings.NewReader(`{"add_label":["l1"],"remove_label":["l2"]}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		mockLabelRepo.AssertExpectations(t)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("label of another team", func(t *testing.T) {
+		app := fiber.New()
+
+		mockRepo := new(mocks.MockProjectRepo)
+		mockLabelRepo := new(mocks.MockLabelRepo)
+		mockRoleRepo := new(mocks.MockRoleRepo)
+		handler := &routes.ProjectHandler{Repo: mockRepo, LabelRepo: mockLabelRepo, Authz: authz.NewAuthorizer(mockRoleRepo)}
+		app.Use(withUserID("user-123"))
+		app.Patch("/projects/:id", handler.UpdateProject)
+
+		mockRepo.On("GetProjectByID", mock.Anything, "project-123").Return(db.Project{ID: "project-123", TeamID: "team-1", CreatedBy: "user-123"}, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+		mockLabelRepo.On("ListTeamLabels", mock.Anything, "team-1").Return([]db.Label{{ID: "l1"}}, nil)
+
+		req := httptest.NewRequest(http.MethodPatch, "/projects/project-123", strings.NewReader(`{"add_label":["l9"]}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		var response map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, "label l9 cannot be used in the team", response["error"])
+		mockLabelRepo.AssertNotCalled(t, "AddLabelToProject", mock.Anything, mock.Anything, mock.Anything)
+	})
+
 }
@@ -0,0 +1,366 @@
+package routes
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/recurrence"
+	"github.com/nack098/nakumanager/internal/repositories"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+// recentRuns is how many occurrences GetRecurringIssue shows.
+const recentRuns = 10
+
+type RecurringIssueHandler struct {
+	Repo         repositories.RecurringIssueRepository
+	TeamRepo     repositories.TeamRepository
+	ProjectRepo  repositories.ProjectRepository
+	TemplateRepo repositories.IssueTemplateRepository
+	Authz        *authz.Authorizer
+}
+
+func NewRecurringIssueHandler(repo repositories.RecurringIssueRepository, teamRepo repositories.TeamRepository, projectRepo repositories.ProjectRepository, templateRepo repositories.IssueTemplateRepository, roleRepo repositories.RoleRepository) *RecurringIssueHandler {
+	return &RecurringIssueHandler{
+		Repo:         repo,
+		TeamRepo:     teamRepo,
+		ProjectRepo:  projectRepo,
+		TemplateRepo: templateRepo,
+		Authz:        authz.NewAuthorizer(roleRepo),
+	}
+}
+
+func toRecurringIssue(r db.RecurringIssue) models.RecurringIssue {
+	recurring := models.RecurringIssue{
+		ID:         r.ID,
+		TeamID:     r.TeamID,
+		TemplateID: r.TemplateID.String,
+		Title:      r.Title,
+		Content:    r.Content.String,
+		Priority:   r.Priority.String,
+		ProjectID:  r.ProjectID.String,
+		Rule:       r.Rule,
+		StartsAt:   r.StartsAt,
+		Paused:     r.Paused,
+		CreatedBy:  r.CreatedBy,
+		CreatedAt:  r.CreatedAt,
+	}
+	if r.NextRunAt.Valid {
+		recurring.NextRunAt = &r.NextRunAt.Time
+	}
+	if r.LastRunAt.Valid {
+		recurring.LastRunAt = &r.LastRunAt.Time
+	}
+	if r.UpdatedAt.Valid {
+		recurring.UpdatedAt = &r.UpdatedAt.Time
+	}
+	return recurring
+}
+
+// nextRun returns the first occurrence of the rule after now. It is not set
+// when the rule has none left. On an invalid rule the error response is
+// already written.
+func nextRun(c *fiber.Ctx, rule string, start, now time.Time) (sql.NullTime, bool) {
+	r, err := recurrence.Parse(rule, start)
+	if err != nil {
+		c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("invalid rule: %v", err)})
+		return sql.NullTime{}, false
+	}
+	next, ok := r.Next(now)
+	return sql.NullTime{Time: next, Valid: ok}, true
+}
+
+// loadRecurringIssue returns the recurring issue of the :id param if the user
+// may act on it. On failure the error response is already written.
+func (h *RecurringIssueHandler) loadRecurringIssue(c *fiber.Ctx, action authz.Action) (db.RecurringIssue, bool) {
+	recurring, err := h.Repo.GetRecurringIssueByID(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "recurring issue not found"})
+			return recurring, false
+		}
+		log.Printf("Failed to get recurring issue %s: %v", c.Params("id"), err)
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch recurring issue"})
+		return recurring, false
+	}
+	if !authorize(c, h.Authz, action, authz.ForRecurringIssue(recurring), "you are not authorized to access this recurring issue") {
+		return recurring, false
+	}
+	return recurring, true
+}
+
+// checkRecurringFields checks that the template and the project can be used
+// in issues of the team. On failure the error response is already written.
+func (h *RecurringIssueHandler) checkRecurringFields(c *fiber.Ctx, teamID, templateID, projectID string) bool {
+	if templateID != "" {
+		template, err := h.TemplateRepo.GetIssueTemplateByID(c.Context(), templateID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "template not found"})
+				return false
+			}
+			log.Printf("Failed to get template %s: %v", templateID, err)
+			c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check template"})
+			return false
+		}
+		if template.TeamID != teamID {
+			c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "template belongs to another team"})
+			return false
+		}
+	}
+	if projectID != "" {
+		if _, ok := checkProjectTeam(c, h.ProjectRepo, h.TeamRepo, projectID, teamID); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ListTeamRecurringIssues returns the recurring issues of the team.
+func (h *RecurringIssueHandler) ListTeamRecurringIssues(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+	if !authorize(c, h.Authz, authz.TeamView, authz.ForTeam(teamID), "you are not a member of this team") {
+		return nil
+	}
+
+	rows, err := h.Repo.ListTeamRecurringIssues(c.Context(), teamID)
+	if err != nil {
+		log.Printf("Failed to list recurring issues of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list recurring issues"})
+	}
+	recurring := make([]models.RecurringIssue, 0, len(rows))
+	for _, row := range rows {
+		recurring = append(recurring, toRecurringIssue(row))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"recurring_issues": recurring})
+}
+
+// CreateRecurringIssue adds a recurring issue to the team. Its issues are
+// created on behalf of the user who adds it.
+func (h *RecurringIssueHandler) CreateRecurringIssue(c *fiber.Ctx) error {
+	var req models.CreateRecurringIssueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+	if req.Title == "" && req.TemplateID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "title is required without a template"})
+	}
+
+	ctx := c.Context()
+	teamID := c.Params("id")
+	teamExists, err := h.TeamRepo.IsTeamExists(ctx, teamID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check team"})
+	}
+	if !teamExists {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
+	}
+	if !authorize(c, h.Authz, authz.RecurringIssueCreate, authz.ForTeam(teamID), "you are not allowed to add recurring issues to this team") {
+		return nil
+	}
+	if !h.checkRecurringFields(c, teamID, req.TemplateID, req.ProjectID) {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	start := now
+	if req.StartsAt != nil {
+		start = req.StartsAt.UTC()
+	}
+	next, ok := nextRun(c, req.Rule, start, now)
+	if !ok {
+		return nil
+	}
+	if !next.Valid {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "rule has no occurrences left"})
+	}
+
+	row := db.CreateRecurringIssueParams{
+		ID:         uuid.New().String(),
+		TeamID:     teamID,
+		TemplateID: sql.NullString{String: req.TemplateID, Valid: req.TemplateID != ""},
+		Title:      req.Title,
+		Content:    sql.NullString{String: req.Content, Valid: req.Content != ""},
+		Priority:   sql.NullString{String: req.Priority, Valid: req.Priority != ""},
+		ProjectID:  sql.NullString{String: req.ProjectID, Valid: req.ProjectID != ""},
+		Rule:       req.Rule,
+		StartsAt:   start,
+		NextRunAt:  next,
+		CreatedBy:  c.Locals("userID").(string),
+		CreatedAt:  now,
+	}
+	if err := h.Repo.CreateRecurringIssue(ctx, row); err != nil {
+		log.Printf("Failed to create recurring issue of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create recurring issue"})
+	}
+
+	recurring := toRecurringIssue(db.RecurringIssue{
+		ID:         row.ID,
+		TeamID:     row.TeamID,
+		TemplateID: row.TemplateID,
+		Title:      row.Title,
+		Content:    row.Content,
+		Priority:   row.Priority,
+		ProjectID:  row.ProjectID,
+		Rule:       row.Rule,
+		StartsAt:   row.StartsAt,
+		NextRunAt:  row.NextRunAt,
+		CreatedBy:  row.CreatedBy,
+		CreatedAt:  row.CreatedAt,
+	})
+	ws.BroadcastToRoom("team", teamID, "recurring_issue_created", recurring)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":         "recurring issue created successfully",
+		"recurring_issue": recurring,
+	})
+}
+
+// GetRecurringIssue returns the recurring issue with its latest occurrences.
+func (h *RecurringIssueHandler) GetRecurringIssue(c *fiber.Ctx) error {
+	row, ok := h.loadRecurringIssue(c, authz.TeamView)
+	if !ok {
+		return nil
+	}
+	runs, err := h.Repo.ListRecurringIssueRuns(c.Context(), row.ID, recentRuns)
+	if err != nil {
+		log.Printf("Failed to list runs of recurring issue %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch recurring issue"})
+	}
+
+	recurring := toRecurringIssue(row)
+	recurring.Runs = make([]models.RecurringIssueRun, 0, len(runs))
+	for _, r := range runs {
+		recurring.Runs = append(recurring.Runs, models.RecurringIssueRun{
+			ScheduledFor: r.ScheduledFor,
+			IssueID:      r.IssueID.String,
+			Error:        r.Error.String,
+		})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"recurring_issue": recurring})
+}
+
+// UpdateRecurringIssue changes the fields that are set in the request. A new
+// rule or start, or resuming a paused recurring issue, schedules it again from
+// now, so occurrences missed while it was paused are skipped.
+func (h *RecurringIssueHandler) UpdateRecurringIssue(c *fiber.Ctx) error {
+	var req models.UpdateRecurringIssueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+	if req.Priority != nil && *req.Priority != "" {
+		if err := validate.Var(*req.Priority, "oneof=low medium high"); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "priority must be low, medium or high"})
+		}
+	}
+
+	row, ok := h.loadRecurringIssue(c, authz.RecurringIssueUpdate)
+	if !ok {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	params := db.UpdateRecurringIssueParams{
+		TemplateID: row.TemplateID,
+		Title:      row.Title,
+		Content:    row.Content,
+		Priority:   row.Priority,
+		ProjectID:  row.ProjectID,
+		Rule:       row.Rule,
+		StartsAt:   row.StartsAt,
+		NextRunAt:  row.NextRunAt,
+		Paused:     row.Paused,
+		UpdatedAt:  sql.NullTime{Time: now, Valid: true},
+		ID:         row.ID,
+	}
+	if req.TemplateID != nil {
+		params.TemplateID = sql.NullString{String: *req.TemplateID, Valid: *req.TemplateID != ""}
+	}
+	if req.Title != nil {
+		params.Title = *req.Title
+	}
+	if req.Content != nil {
+		params.Content = sql.NullString{String: *req.Content, Valid: *req.Content != ""}
+	}
+	if req.Priority != nil {
+		params.Priority = sql.NullString{String: *req.Priority, Valid: *req.Priority != ""}
+	}
+	if req.ProjectID != nil {
+		params.ProjectID = sql.NullString{String: *req.ProjectID, Valid: *req.ProjectID != ""}
+	}
+	if req.Rule != nil {
+		params.Rule = *req.Rule
+	}
+	if req.StartsAt != nil {
+		params.StartsAt = req.StartsAt.UTC()
+	}
+	if req.Paused != nil {
+		params.Paused = *req.Paused
+	}
+	if params.Title == "" && !params.TemplateID.Valid {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "title is required without a template"})
+	}
+
+	templateID, projectID := "", ""
+	if req.TemplateID != nil {
+		templateID = *req.TemplateID
+	}
+	if req.ProjectID != nil {
+		projectID = *req.ProjectID
+	}
+	if !h.checkRecurringFields(c, row.TeamID, templateID, projectID) {
+		return nil
+	}
+
+	resumed := row.Paused && !params.Paused
+	if req.Rule != nil || req.StartsAt != nil || resumed {
+		if params.NextRunAt, ok = nextRun(c, params.Rule, params.StartsAt, now); !ok {
+			return nil
+		}
+	}
+
+	if err := h.Repo.UpdateRecurringIssue(c.Context(), params); err != nil {
+		log.Printf("Failed to update recurring issue %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update recurring issue"})
+	}
+
+	row.TemplateID, row.Title, row.Content, row.Priority, row.ProjectID = params.TemplateID, params.Title, params.Content, params.Priority, params.ProjectID
+	row.Rule, row.StartsAt, row.NextRunAt, row.Paused, row.UpdatedAt = params.Rule, params.StartsAt, params.NextRunAt, params.Paused, params.UpdatedAt
+	recurring := toRecurringIssue(row)
+	ws.BroadcastToRoom("team", row.TeamID, "recurring_issue_updated", recurring)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":         "recurring issue updated successfully",
+		"recurring_issue": recurring,
+	})
+}
+
+// DeleteRecurringIssue stops the recurring issue. Issues it created are kept.
+func (h *RecurringIssueHandler) DeleteRecurringIssue(c *fiber.Ctx) error {
+	row, ok := h.loadRecurringIssue(c, authz.RecurringIssueDelete)
+	if !ok {
+		return nil
+	}
+	if err := h.Repo.DeleteRecurringIssue(c.Context(), row.ID); err != nil {
+		log.Printf("Failed to delete recurring issue %s: %v", row.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete recurring issue"})
+	}
+	ws.BroadcastToRoom("team", row.TeamID, "recurring_issue_deleted", fiber.Map{"id": row.ID})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "recurring issue deleted successfully"})
+}
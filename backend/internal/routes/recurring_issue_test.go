@@ -0,0 +1,256 @@
+package routes_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRecurringIssue(t *testing.T) {
+	mockRecurringRepo := new(mocks.MockRecurringIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockTemplateRepo := new(mocks.MockIssueTemplateRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.RecurringIssueHandler{
+		Repo:         mockRecurringRepo,
+		TeamRepo:     mockTeamRepo,
+		TemplateRepo: mockTemplateRepo,
+		Authz:        authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Post("/teams/:id/recurring-issues", handler.CreateRecurringIssue)
+
+	team := func(roles db.GetTeamMemberRolesRow) {
+		mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+		mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(roles, nil)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setup      func()
+		wantStatus int
+	}{
+		{
+			name: "scheduled from its start",
+			body: `{"title":"Access audit","rule":"FREQ=MONTHLY;BYMONTHDAY=1;BYHOUR=9;BYMINUTE=0","starts_at":"2030-03-15T00:00:00Z","template_id":"tpl-1"}`,
+			setup: func() {
+				team(teamMemberRoles)
+				mockTemplateRepo.On("GetIssueTemplateByID", mock.Anything, "tpl-1").Return(bugTemplate, nil)
+				mockRecurringRepo.On("CreateRecurringIssue", mock.Anything, mock.MatchedBy(func(p db.CreateRecurringIssueParams) bool {
+					return p.TeamID == "team-1" && p.TemplateID.String == "tpl-1" && p.CreatedBy == "user-123" &&
+						p.NextRunAt.Time.Equal(time.Date(2030, 4, 1, 9, 0, 0, 0, time.UTC))
+				})).Return(nil).Once()
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "invalid rule",
+			body: `{"title":"Access audit","rule":"every monday"}`,
+			setup: func() {
+				team(teamMemberRoles)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "rule already ended",
+			body: `{"title":"Access audit","rule":"FREQ=DAILY;UNTIL=20200101"}`,
+			setup: func() {
+				team(teamMemberRoles)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "no title and no template",
+			body:       `{"rule":"@weekly"}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "template of another team",
+			body: `{"rule":"@weekly","template_id":"tpl-2"}`,
+			setup: func() {
+				team(teamMemberRoles)
+				mockTemplateRepo.On("GetIssueTemplateByID", mock.Anything, "tpl-2").Return(db.IssueTemplate{ID: "tpl-2", TeamID: "team-2"}, nil)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "not a member of the team",
+			body: `{"title":"Access audit","rule":"@weekly"}`,
+			setup: func() {
+				team(workspaceMemberRoles)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPost, "/teams/team-1/recurring-issues", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			mockRecurringRepo.ExpectedCalls = nil
+			mockTeamRepo.ExpectedCalls = nil
+			mockTemplateRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRecurringRepo.AssertNumberOfCalls(t, "CreateRecurringIssue", 1)
+}
+
+func TestUpdateRecurringIssue(t *testing.T) {
+	mockRecurringRepo := new(mocks.MockRecurringIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.RecurringIssueHandler{
+		Repo:  mockRecurringRepo,
+		Authz: authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Patch("/recurring-issues/:id", handler.UpdateRecurringIssue)
+
+	paused := db.RecurringIssue{
+		ID:        "rec-1",
+		TeamID:    "team-1",
+		Title:     "Dependency review",
+		Rule:      "0 9 * * MON",
+		StartsAt:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		NextRunAt: sql.NullTime{Time: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), Valid: true},
+		Paused:    true,
+		CreatedBy: "user-123",
+	}
+	other := paused
+	other.CreatedBy = "user-456"
+
+	tests := []struct {
+		name       string
+		recurring  db.RecurringIssue
+		body       string
+		setup      func()
+		wantStatus int
+		wantPaused bool
+	}{
+		{
+			name:      "resuming skips missed occurrences",
+			recurring: paused,
+			body:      `{"paused":false}`,
+			setup: func() {
+				mockRecurringRepo.On("UpdateRecurringIssue", mock.Anything, mock.MatchedBy(func(p db.UpdateRecurringIssueParams) bool {
+					return !p.Paused && p.NextRunAt.Time.After(time.Now()) && p.NextRunAt.Time.Weekday() == time.Monday
+				})).Return(nil).Once()
+			},
+			wantStatus: fiber.StatusOK,
+			wantPaused: false,
+		},
+		{
+			name:      "renaming keeps the schedule",
+			recurring: paused,
+			body:      `{"title":"Weekly review"}`,
+			setup: func() {
+				mockRecurringRepo.On("UpdateRecurringIssue", mock.Anything, mock.MatchedBy(func(p db.UpdateRecurringIssueParams) bool {
+					return p.Title == "Weekly review" && p.Paused && p.NextRunAt == paused.NextRunAt
+				})).Return(nil).Once()
+			},
+			wantStatus: fiber.StatusOK,
+			wantPaused: true,
+		},
+		{
+			name:       "another member's recurring issue",
+			recurring:  other,
+			body:       `{"paused":false}`,
+			setup:      func() {},
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRecurringRepo.On("GetRecurringIssueByID", mock.Anything, "rec-1").Return(tt.recurring, nil)
+			mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+			tt.setup()
+
+			req := httptest.NewRequest(http.MethodPatch, "/recurring-issues/rec-1", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusOK {
+				var body struct {
+					Recurring models.RecurringIssue `json:"recurring_issue"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.wantPaused, body.Recurring.Paused)
+			}
+
+			mockRecurringRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+	mockRecurringRepo.AssertNumberOfCalls(t, "UpdateRecurringIssue", 2)
+}
+
+func TestCreateIssueAs(t *testing.T) {
+	mockDB, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer mockDB.Close()
+	mockRepo := new(mocks.MockIssueRepo)
+	mockTeamRepo := new(mocks.MockTeamRepository)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.IssueHandler{
+		DB:       mockDB,
+		Repo:     mockRepo,
+		TeamRepo: mockTeamRepo,
+		Authz:    authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	mockTeamRepo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+	mockTeamRepo.On("GetDefaultTeamStatus", mock.Anything, "team-1").Return("todo", nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-999").Return(noRoles, nil)
+	mockRepo.On("GetLastIssueBoardRankTx", mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	mockRepo.On("NextIssueNumber", mock.Anything, "team-1").Return(int64(7), "ENG-7", nil)
+	sqlMock.ExpectBegin()
+	mockRepo.On("CreateIssueTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateIssueParams) bool {
+		return p.Title == "Dependency review" && p.OwnerID == "user-123" && p.Status == "todo"
+	})).Return(nil).Once()
+	sqlMock.ExpectCommit()
+
+	issueID, identifier, err := handler.CreateIssueAs(context.Background(), "user-123", models.IssueCreate{TeamID: "team-1", Title: "Dependency review"}, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, issueID)
+	assert.Equal(t, "ENG-7", identifier)
+
+	_, _, err = handler.CreateIssueAs(context.Background(), "user-999", models.IssueCreate{TeamID: "team-1", Title: "Dependency review"}, "")
+	var statusErr *routes.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, fiber.StatusForbidden, statusErr.Status)
+
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+	mockRepo.AssertNumberOfCalls(t, "CreateIssueTx", 1)
+}
@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/repositories"
+)
+
+type SearchHandler struct {
+	Repo repositories.SearchRepository
+}
+
+func NewSearchHandler(repo repositories.SearchRepository) *SearchHandler {
+	return &SearchHandler{Repo: repo}
+}
+
+// Search looks up issues and projects by text. Only what the user is allowed to
+// view is returned; matches in titles and snippets are wrapped in <mark> tags.
+func (h *SearchHandler) Search(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "query parameter q is required"})
+	}
+
+	limit, offset, err := parsePagination(c, 20, 50)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	results, err := h.Repo.Search(c.Context(), userID, query, limit, offset)
+	if err != nil {
+		log.Printf("Failed to search %q: %v", query, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to search"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"results": results,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
@@ -0,0 +1,94 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/routes"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	mockRepo := new(mocks.MockSearchRepo)
+	handler := routes.NewSearchHandler(mockRepo)
+
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Get("/search", handler.Search)
+
+	tests := []struct {
+		name        string
+		query       string
+		setupMocks  func()
+		wantStatus  int
+		wantResults int
+	}{
+		{
+			name:  "success",
+			query: "?q=%20login%20bug%20",
+			setupMocks: func() {
+				mockRepo.On("Search", mock.Anything, "user-123", "login bug", 20, 0).Return([]models.SearchResult{
+					{Type: "issue", ID: "issue-1", Title: "<mark>Login</mark> <mark>bug</mark>"},
+					{Type: "project", ID: "project-1", Title: "<mark>Login</mark> revamp"},
+				}, nil)
+			},
+			wantStatus:  fiber.StatusOK,
+			wantResults: 2,
+		},
+		{
+			name:  "custom page",
+			query: "?q=login&limit=5&offset=10",
+			setupMocks: func() {
+				mockRepo.On("Search", mock.Anything, "user-123", "login", 5, 10).Return([]models.SearchResult{}, nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "missing query",
+			query:      "?q=%20",
+			setupMocks: func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "limit too large",
+			query:      "?q=login&limit=100",
+			setupMocks: func() {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:  "repo error",
+			query: "?q=login",
+			setupMocks: func() {
+				mockRepo.On("Search", mock.Anything, "user-123", "login", 20, 0).Return(nil, errors.New("db error"))
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.ExpectedCalls = nil
+			tt.setupMocks()
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/search"+tt.query, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusOK {
+				var body struct {
+					Results []models.SearchResult `json:"results"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Len(t, body.Results, tt.wantResults)
+			}
+		})
+	}
+}
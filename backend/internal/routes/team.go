@@ -1,11 +1,18 @@
 package routes
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/go-playground/validator"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
 	"github.com/nack098/nakumanager/internal/db"
 	models "github.com/nack098/nakumanager/internal/models"
 	"github.com/nack098/nakumanager/internal/repositories"
@@ -15,15 +22,67 @@ import (
 type TeamHandler struct {
 	Repo          repositories.TeamRepository
 	WorkspaceRepo repositories.WorkspaceRepository
+	RoleRepo      repositories.RoleRepository
+	Authz         *authz.Authorizer
+	// DB ใช้เปิด transaction ตอนแก้สถานะและ key ของทีม
+	DB *sql.DB
 }
 
-func NewTeamHandler(repo repositories.TeamRepository, workspaceRepo repositories.WorkspaceRepository) *TeamHandler {
+func NewTeamHandler(db *sql.DB, repo repositories.TeamRepository, workspaceRepo repositories.WorkspaceRepository, roleRepo repositories.RoleRepository) *TeamHandler {
 	return &TeamHandler{
+		DB:            db,
 		Repo:          repo,
 		WorkspaceRepo: workspaceRepo,
+		RoleRepo:      roleRepo,
+		Authz:         authz.NewAuthorizer(roleRepo),
 	}
 }
 
+// teamKeyPattern is the format of team keys: a letter followed by up to nine
+// letters or digits, all uppercase.
+var teamKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{0,9}$`)
+
+const errInvalidTeamKey = "key must be a letter followed by up to nine letters or digits"
+
+// deriveTeamKey makes a key from the first three letters or digits of the team
+// name, or TEAM when the name doesn't start with a Latin letter. A number is
+// added when a team of the workspace already has the key.
+func deriveTeamKey(name string, taken []string) string {
+	key := ""
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			key += string(r)
+		}
+		if len(key) == 3 {
+			break
+		}
+	}
+	if !teamKeyPattern.MatchString(key) {
+		key = "TEAM"
+	}
+
+	candidate := key
+	for n := 2; slices.Contains(taken, candidate); n++ {
+		candidate = fmt.Sprintf("%s%d", key, n)
+	}
+	return candidate
+}
+
+// setTeamKey changes the key of the team together with the identifiers of its
+// issues.
+func (h *TeamHandler) setTeamKey(ctx context.Context, teamID, key string) error {
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := h.Repo.SetTeamKeyTx(ctx, tx, teamID, key); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // CreateTeam
 func (h *TeamHandler) CreateTeam(c *fiber.Ctx) error {
 	userID := c.Locals("userID").(string)
@@ -34,14 +93,14 @@ func (h *TeamHandler) CreateTeam(c *fiber.Ctx) error {
 	}
 
 	//Check if workspace exists
-	workspace, err := h.WorkspaceRepo.GetWorkspaceByID(c.Context(), request.WorkspaceID)
+	_, err := h.WorkspaceRepo.GetWorkspaceByID(c.Context(), request.WorkspaceID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "workspace not found"})
 	}
 
-	//Check if user is owner of workspace
-	if workspace.OwnerID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "no permission in this workspace"})
+	//Check if user is owner or admin of workspace
+	if !authorize(c, h.Authz, authz.TeamCreate, authz.ForWorkspace(request.WorkspaceID), "no permission in this workspace") {
+		return nil
 	}
 
 	request.ID = uuid.New().String()
@@ -57,6 +116,20 @@ func (h *TeamHandler) CreateTeam(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": errMessages})
 	}
 
+	//Check team key
+	keys, err := h.Repo.ListTeamKeys(c.Context(), request.WorkspaceID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check team keys"})
+	}
+	request.Key = strings.ToUpper(strings.TrimSpace(request.Key))
+	if request.Key == "" {
+		request.Key = deriveTeamKey(request.Name, keys)
+	} else if !teamKeyPattern.MatchString(request.Key) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": errInvalidTeamKey})
+	} else if slices.Contains(keys, request.Key) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("team key %s is already used in the workspace", request.Key)})
+	}
+
 	//Create team
 	err = h.Repo.CreateTeam(c.Context(), request)
 	if err != nil {
@@ -74,6 +147,7 @@ func (h *TeamHandler) CreateTeam(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"message": "team created successfully",
+		"key":     request.Key,
 	})
 }
 
@@ -99,28 +173,20 @@ func (h *TeamHandler) GetTeamsByUserID(c *fiber.Ctx) error {
 
 // DeleteTeam
 func (h *TeamHandler) DeleteTeam(c *fiber.Ctx) error {
-	userID := c.Locals("userID").(string)
-
 	//Parse request
 	teamID := c.Params("id")
 	if teamID == "" || teamID == "empty" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "team ID is required"})
 	}
 
-	//Check if user is owner
-	owner, err := h.Repo.GetOwnerByTeamID(c.Context(), teamID)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
-	}
-
-	//Check if user is leader
-	leader, err := h.Repo.GetLeaderByTeamID(c.Context(), teamID)
+	_, err := h.Repo.GetTeamByID(c.Context(), teamID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
 	}
 
-	if owner != userID && leader != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "no permission to remove member from this team"})
+	//Check if user is workspace admin or team lead
+	if !authorize(c, h.Authz, authz.TeamDelete, authz.ForTeam(teamID), "no permission to delete this team") {
+		return nil
 	}
 
 	err = h.Repo.DeleteTeam(c.Context(), teamID)
@@ -134,8 +200,6 @@ func (h *TeamHandler) DeleteTeam(c *fiber.Ctx) error {
 }
 
 func (h *TeamHandler) UpdateTeam(c *fiber.Ctx) error {
-	userID := c.Locals("userID").(string)
-
 	teamID := strings.TrimSpace(c.Params("id"))
 	if teamID == "" || teamID == "empty" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "team ID is required"})
@@ -157,18 +221,47 @@ func (h *TeamHandler) UpdateTeam(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
 	}
 
-	// Get owner and leader
-	owner, err := h.Repo.GetOwnerByTeamID(ctx, teamID)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
+	if !authorize(c, h.Authz, authz.TeamUpdate, authz.ForTeam(teamID), "no permission to update this team") {
+		return nil
 	}
-	leader, err := h.Repo.GetLeaderByTeamID(ctx, teamID)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
+
+	// สมาชิกใหม่ของทีมต้องอยู่ใน workspace ของทีมแล้ว ตรวจก่อนแก้อย่างอื่น
+	if req.AddMembers != nil {
+		for _, memberID := range *req.AddMembers {
+			roles, err := h.RoleRepo.GetTeamRoles(ctx, teamID, memberID)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check member"})
+			}
+			if roles.WorkspaceRole == "" {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("user %s is not a member of the workspace", memberID)})
+			}
+		}
 	}
 
-	if owner != userID && leader != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "no permission to remove member from this team"})
+	// Change key
+	if req.Key != nil {
+		key := strings.ToUpper(strings.TrimSpace(*req.Key))
+		if !teamKeyPattern.MatchString(key) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": errInvalidTeamKey})
+		}
+		team, err := h.Repo.GetTeamByID(ctx, teamID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch team"})
+		}
+		if key != team.Key {
+			keys, err := h.Repo.ListTeamKeys(ctx, team.WorkspaceID)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check team keys"})
+			}
+			if slices.Contains(keys, key) {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("team key %s is already used in the workspace", key)})
+			}
+			if err := h.setTeamKey(ctx, teamID, key); err != nil {
+				log.Printf("Failed to change key of team %s: %v", teamID, err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to change team key"})
+			}
+		}
+		req.Key = &key
 	}
 
 	// Rename team
@@ -215,6 +308,9 @@ func (h *TeamHandler) UpdateTeam(c *fiber.Ctx) error {
 		if err := h.Repo.SetLeaderToTeam(ctx, db.SetLeaderToTeamParams{ID: teamID, LeaderID: *req.NewLeaderID}); err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to set team leader"})
 		}
+		if err := h.RoleRepo.SetTeamLead(ctx, teamID, *req.NewLeaderID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to set team leader"})
+		}
 	}
 
 	ws.BroadcastToRoom("team", teamID, "team_updated", req)
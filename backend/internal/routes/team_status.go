@@ -0,0 +1,323 @@
+package routes
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+// statusKeyPattern keeps keys usable in URLs and in the comma separated
+// ?status= filter of the issue listing.
+var statusKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// loadTeamFor checks that the team exists and that the user may act on it.
+// It writes the error response and returns false otherwise.
+func (h *TeamHandler) loadTeamFor(c *fiber.Ctx, teamID string, action authz.Action, msg string) bool {
+	exists, err := h.Repo.IsTeamExists(c.Context(), teamID)
+	if err != nil {
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check team"})
+		return false
+	}
+	if !exists {
+		c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "team not found"})
+		return false
+	}
+	return authorize(c, h.Authz, action, authz.ForTeam(teamID), msg)
+}
+
+// reorderStatuses returns the keys of statuses with key moved to position, or
+// appended when it is not among them yet.
+func reorderStatuses(statuses []db.TeamStatus, key string, position int) []string {
+	keys := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		keys = append(keys, s.Key)
+	}
+	return moveToPosition(keys, key, position)
+}
+
+// moveToPosition returns keys with key moved to position, or inserted there
+// when it is not among them yet. Positions past the end put it last.
+func moveToPosition(keys []string, key string, position int) []string {
+	moved := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		if k != key {
+			moved = append(moved, k)
+		}
+	}
+	if position > len(moved) {
+		position = len(moved)
+	}
+	moved = append(moved, "")
+	copy(moved[position+1:], moved[position:])
+	moved[position] = key
+	return moved
+}
+
+func (h *TeamHandler) broadcastStatuses(c *fiber.Ctx, teamID string) {
+	statuses, err := h.Repo.ListTeamStatuses(c.Context(), teamID)
+	if err != nil {
+		log.Printf("Failed to list statuses of team %s: %v", teamID, err)
+		return
+	}
+	ws.BroadcastToRoom("team", teamID, "team_statuses_updated", fiber.Map{"statuses": statuses})
+}
+
+// ListTeamStatuses returns the workflow statuses of the team in board order.
+func (h *TeamHandler) ListTeamStatuses(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+	if !h.loadTeamFor(c, teamID, authz.TeamView, "you are not a member of this team") {
+		return nil
+	}
+
+	statuses, err := h.Repo.ListTeamStatuses(c.Context(), teamID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list statuses"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"statuses": statuses})
+}
+
+func (h *TeamHandler) CreateTeamStatus(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+
+	var req models.CreateTeamStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	req.Key = strings.TrimSpace(req.Key)
+	req.Name = strings.TrimSpace(req.Name)
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+	if !statusKeyPattern.MatchString(req.Key) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "key may only contain lowercase letters, digits, - and _",
+		})
+	}
+
+	if !h.loadTeamFor(c, teamID, authz.TeamUpdate, "no permission to update this team") {
+		return nil
+	}
+
+	ctx := c.Context()
+	statuses, err := h.Repo.ListTeamStatuses(ctx, teamID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list statuses"})
+	}
+	for _, s := range statuses {
+		if s.Key == req.Key {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": fmt.Sprintf("status %s already exists", req.Key)})
+		}
+	}
+
+	position := len(statuses)
+	if req.Position != nil && *req.Position < position {
+		position = *req.Position
+	}
+	status := db.TeamStatus{
+		TeamID:   teamID,
+		Key:      req.Key,
+		Name:     req.Name,
+		Category: req.Category,
+		Position: int64(position),
+		Color:    req.Color,
+	}
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create status"})
+	}
+	defer tx.Rollback()
+
+	if err := h.Repo.CreateTeamStatusTx(ctx, tx, db.CreateTeamStatusParams(status)); err != nil {
+		log.Printf("Failed to create status %s: %v", req.Key, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create status"})
+	}
+	if position < len(statuses) {
+		if err := h.Repo.SetTeamStatusOrderTx(ctx, tx, teamID, reorderStatuses(statuses, req.Key, position)); err != nil {
+			log.Printf("Failed to reorder statuses of team %s: %v", teamID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create status"})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit status %s: %v", req.Key, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create status"})
+	}
+
+	h.broadcastStatuses(c, teamID)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "status created successfully",
+		"status":  status,
+	})
+}
+
+func (h *TeamHandler) UpdateTeamStatus(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+	key := c.Params("key")
+
+	var req models.UpdateTeamStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+
+	if !h.loadTeamFor(c, teamID, authz.TeamUpdate, "no permission to update this team") {
+		return nil
+	}
+
+	ctx := c.Context()
+	statuses, err := h.Repo.ListTeamStatuses(ctx, teamID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list statuses"})
+	}
+	var status *db.TeamStatus
+	for i := range statuses {
+		if statuses[i].Key == key {
+			status = &statuses[i]
+		}
+	}
+	if status == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "status not found"})
+	}
+
+	updated := *status
+	if req.Name != nil {
+		updated.Name = *req.Name
+	}
+	if req.Category != nil {
+		updated.Category = *req.Category
+	}
+	if req.Color != nil {
+		updated.Color = *req.Color
+	}
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update status"})
+	}
+	defer tx.Rollback()
+
+	if err := h.Repo.UpdateTeamStatusTx(ctx, tx, db.UpdateTeamStatusParams{
+		Name:     updated.Name,
+		Category: updated.Category,
+		Color:    updated.Color,
+		TeamID:   teamID,
+		Key:      key,
+	}); err != nil {
+		log.Printf("Failed to update status %s: %v", key, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update status"})
+	}
+	if req.Position != nil {
+		keys := reorderStatuses(statuses, key, *req.Position)
+		if err := h.Repo.SetTeamStatusOrderTx(ctx, tx, teamID, keys); err != nil {
+			log.Printf("Failed to reorder statuses of team %s: %v", teamID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update status"})
+		}
+		for i, k := range keys {
+			if k == key {
+				updated.Position = int64(i)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit status %s: %v", key, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update status"})
+	}
+
+	h.broadcastStatuses(c, teamID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "status updated successfully",
+		"status":  updated,
+	})
+}
+
+// DeleteTeamStatus deletes a status. Issues still in it are moved to the
+// status given by ?move_to=, which is required while the status is in use.
+func (h *TeamHandler) DeleteTeamStatus(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+	key := c.Params("key")
+	moveTo := c.Query("move_to")
+
+	if !h.loadTeamFor(c, teamID, authz.TeamUpdate, "no permission to update this team") {
+		return nil
+	}
+
+	ctx := c.Context()
+	statuses, err := h.Repo.ListTeamStatuses(ctx, teamID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list statuses"})
+	}
+	found, targetFound := false, false
+	remaining := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		if s.Key == key {
+			found = true
+			continue
+		}
+		if s.Key == moveTo {
+			targetFound = true
+		}
+		remaining = append(remaining, s.Key)
+	}
+	if !found {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "status not found"})
+	}
+	if len(remaining) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "a team must keep at least one status"})
+	}
+	if moveTo != "" && !targetFound {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("move_to must be another status of this team, got %s", moveTo)})
+	}
+
+	if moveTo == "" {
+		count, err := h.Repo.CountIssuesByTeamStatus(ctx, teamID, key)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to count issues"})
+		}
+		if count > 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": fmt.Sprintf("%d issues are in status %s, choose where to move them with move_to", count, key),
+			})
+		}
+	}
+
+	tx, err := h.DB.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Failed to begin transaction: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete status"})
+	}
+	defer tx.Rollback()
+
+	if err := h.Repo.DeleteTeamStatusTx(ctx, tx, teamID, key, moveTo); err != nil {
+		log.Printf("Failed to delete status %s: %v", key, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete status"})
+	}
+	if err := h.Repo.SetTeamStatusOrderTx(ctx, tx, teamID, remaining); err != nil {
+		log.Printf("Failed to reorder statuses of team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete status"})
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit deleting status %s: %v", key, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete status"})
+	}
+
+	h.broadcastStatuses(c, teamID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "status deleted successfully"})
+}
@@ -2,6 +2,7 @@ package routes_test
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -9,23 +10,31 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
 	"github.com/nack098/nakumanager/internal/routes"
 	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
 )
 
 func TestNewTeamHandler(t *testing.T) {
+	db := &sql.DB{}
 	mockTeamRepo := new(mocks.MockTeamRepository)
 	mockWorkspaceRepo := new(mocks.MockWorkspaceRepo)
-	handler := routes.NewTeamHandler(mockTeamRepo, mockWorkspaceRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := routes.NewTeamHandler(db, mockTeamRepo, mockWorkspaceRepo, mockRoleRepo)
 
 	assert.NotNil(t, handler)
+	assert.Equal(t, db, handler.DB)
 	assert.Equal(t, mockTeamRepo, handler.Repo)
 	assert.Equal(t, mockWorkspaceRepo, handler.WorkspaceRepo)
+	assert.Equal(t, mockRoleRepo, handler.RoleRepo)
+	assert.NotNil(t, handler.Authz)
 
 }
 
@@ -33,7 +42,8 @@ func TestCreateTeam(t *testing.T) {
 	t.Run("Create Team Successfully", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/teams", handler.CreateTeam)
@@ -43,8 +53,12 @@ func TestCreateTeam(t *testing.T) {
 
 		workSpaceRepo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
-		repo.On("CreateTeam", mock.Anything, mock.Anything).Return(nil)
+		repo.On("ListTeamKeys", mock.Anything, "ws-123").Return([]string{"TES"}, nil)
+		repo.On("CreateTeam", mock.Anything, mock.MatchedBy(func(team models.CreateTeam) bool {
+			return team.Key == "TES2"
+		})).Return(nil)
 		repo.On("AddMemberToTeam", mock.Anything, mock.Anything).Return(nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/teams", bytes.NewReader(body))
@@ -58,7 +72,8 @@ func TestCreateTeam(t *testing.T) {
 	t.Run("Fail to create team", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/teams", handler.CreateTeam)
@@ -68,7 +83,9 @@ func TestCreateTeam(t *testing.T) {
 
 		workSpaceRepo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
+		repo.On("ListTeamKeys", mock.Anything, "ws-123").Return([]string{}, nil)
 		repo.On("CreateTeam", mock.Anything, mock.Anything).Return(errors.New("failed to add member to team"))
 
 		req := httptest.NewRequest(http.MethodPost, "/teams", bytes.NewReader(body))
@@ -82,7 +99,8 @@ func TestCreateTeam(t *testing.T) {
 	t.Run("Fail to add member to team", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/teams", handler.CreateTeam)
@@ -92,7 +110,9 @@ func TestCreateTeam(t *testing.T) {
 
 		workSpaceRepo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
+		repo.On("ListTeamKeys", mock.Anything, "ws-123").Return([]string{}, nil)
 		repo.On("CreateTeam", mock.Anything, mock.Anything).Return(nil)
 		repo.On("AddMemberToTeam", mock.Anything, mock.Anything).Return(errors.New("failed to add member to team"))
 
@@ -107,7 +127,8 @@ func TestCreateTeam(t *testing.T) {
 	t.Run("Invalid Request Body", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/teams", handler.CreateTeam)
@@ -130,7 +151,8 @@ func TestCreateTeam(t *testing.T) {
 	t.Run("Workspace Not Found", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/teams", handler.CreateTeam)
@@ -152,7 +174,8 @@ func TestCreateTeam(t *testing.T) {
 	t.Run("User is not workspace owner", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/teams", handler.CreateTeam)
@@ -162,6 +185,7 @@ func TestCreateTeam(t *testing.T) {
 
 		workSpaceRepo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-456"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("member", nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/teams", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
@@ -174,7 +198,8 @@ func TestCreateTeam(t *testing.T) {
 	t.Run("Create team validation fails", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/teams", handler.CreateTeam)
@@ -184,6 +209,7 @@ func TestCreateTeam(t *testing.T) {
 
 		workSpaceRepo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/teams", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
@@ -193,13 +219,56 @@ func TestCreateTeam(t *testing.T) {
 		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 	})
 
+	t.Run("team key", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			key        string
+			wantStatus int
+			wantKey    string
+		}{
+			{"given key is uppercased", " eng ", fiber.StatusCreated, "ENG"},
+			{"key in use", "ops", fiber.StatusConflict, ""},
+			{"invalid key", "1ENG", fiber.StatusBadRequest, ""},
+			{"key too long", "ENGINEERING", fiber.StatusBadRequest, ""},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				repo := new(mocks.MockTeamRepository)
+				workSpaceRepo := new(mocks.MockWorkspaceRepo)
+				roleRepo := new(mocks.MockRoleRepo)
+				handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
+				app := fiber.New()
+				app.Use(withUserID("user-123"))
+				app.Post("/teams", handler.CreateTeam)
+
+				workSpaceRepo.On("GetWorkspaceByID", mock.Anything, "ws-123").Return(db.Workspace{ID: "ws-123"}, nil)
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("admin", nil)
+				repo.On("ListTeamKeys", mock.Anything, "ws-123").Return([]string{"OPS"}, nil)
+				repo.On("CreateTeam", mock.Anything, mock.MatchedBy(func(team models.CreateTeam) bool {
+					return team.Key == tt.wantKey
+				})).Return(nil)
+				repo.On("AddMemberToTeam", mock.Anything, mock.Anything).Return(nil)
+
+				body, _ := json.Marshal(map[string]string{"name": "Engineering", "workspace_id": "ws-123", "key": tt.key})
+				req := httptest.NewRequest(http.MethodPost, "/teams", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				resp, err := app.Test(req, -1)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantStatus, resp.StatusCode)
+				if tt.wantStatus != fiber.StatusCreated {
+					repo.AssertNotCalled(t, "CreateTeam", mock.Anything, mock.Anything)
+				}
+			})
+		}
+	})
 }
 
 func TestGetTeamsByUserID(t *testing.T) {
 	t.Run("Get Teams Successfully", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Get("/teams", handler.GetTeamsByUserID)
@@ -216,7 +285,8 @@ func TestGetTeamsByUserID(t *testing.T) {
 	t.Run("Get Teams Failed", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Get("/teams", handler.GetTeamsByUserID)
@@ -233,7 +303,8 @@ func TestGetTeamsByUserID(t *testing.T) {
 	t.Run("Team is not exists", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Get("/teams", handler.GetTeamsByUserID)
@@ -252,13 +323,14 @@ func TestDeleteTeam(t *testing.T) {
 	t.Run("Delete Team Successfully", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/teams/:id", handler.DeleteTeam)
 
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("user-123", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-123").Return("user-456", nil)
+		repo.On("GetTeamByID", mock.Anything, "team-123").Return(db.Team{ID: "team-123", WorkspaceID: "ws-123"}, nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-123", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "owner", TeamRole: "member"}, nil)
 		repo.On("DeleteTeam", mock.Anything, "team-123").Return(nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/teams/team-123", nil)
@@ -271,13 +343,14 @@ func TestDeleteTeam(t *testing.T) {
 	t.Run("Delete Team Failed", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/teams/:id", handler.DeleteTeam)
 
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("user-123", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-123").Return("user-456", nil)
+		repo.On("GetTeamByID", mock.Anything, "team-123").Return(db.Team{ID: "team-123", WorkspaceID: "ws-123"}, nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-123", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "owner", TeamRole: "member"}, nil)
 		repo.On("DeleteTeam", mock.Anything, "team-123").Return(errors.New("failed to delete team"))
 
 		req := httptest.NewRequest(http.MethodDelete, "/teams/team-123", nil)
@@ -289,7 +362,8 @@ func TestDeleteTeam(t *testing.T) {
 	t.Run("TeamID is not provided", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/teams/:id", handler.DeleteTeam)
@@ -300,15 +374,16 @@ func TestDeleteTeam(t *testing.T) {
 		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 	})
 
-	t.Run("Check Team Owner Failed", func(t *testing.T) {
+	t.Run("Team Not Found", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/teams/:id", handler.DeleteTeam)
 
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("", errors.New("failed to check team owner"))
+		repo.On("GetTeamByID", mock.Anything, "team-123").Return(db.Team{}, errors.New("team not found"))
 
 		req := httptest.NewRequest(http.MethodDelete, "/teams/team-123", nil)
 		resp, err := app.Test(req, -1)
@@ -316,34 +391,36 @@ func TestDeleteTeam(t *testing.T) {
 		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
 	})
 
-	t.Run("Check Team Leader Failed", func(t *testing.T) {
+	t.Run("Check Roles Failed", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/teams/:id", handler.DeleteTeam)
 
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("user-123", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-123").Return("", errors.New("failed to check team leader"))
+		repo.On("GetTeamByID", mock.Anything, "team-123").Return(db.Team{ID: "team-123", WorkspaceID: "ws-123"}, nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-123", "user-123").Return(db.GetTeamMemberRolesRow{}, errors.New("failed to check roles"))
 
 		req := httptest.NewRequest(http.MethodDelete, "/teams/team-123", nil)
 		resp, err := app.Test(req, -1)
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 	})
 
-	t.Run("No Permission to Remove Member", func(t *testing.T) {
+	t.Run("No Permission to Delete Team", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/teams/:id", handler.DeleteTeam)
 
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("user-999", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-123").Return("user-888", nil)
+		repo.On("GetTeamByID", mock.Anything, "team-123").Return(db.Team{ID: "team-123", WorkspaceID: "ws-123"}, nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-123", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "member", TeamRole: "member"}, nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/teams/team-123", nil)
 		resp, err := app.Test(req, -1)
@@ -357,7 +434,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("Update Team Success", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -374,14 +452,14 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-xyz").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-xyz").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-xyz").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 		repo.On("RenameTeam", mock.Anything, db.RenameTeamParams{ID: "team-xyz", Name: "Updated Team Name"}).Return(nil)
 		repo.On("IsMemberInTeam", mock.Anything, "team-xyz", "user-new").Return(false, nil)
 		repo.On("AddMemberToTeam", mock.Anything, db.AddMemberToTeamParams{TeamID: "team-xyz", UserID: "user-new"}).Return(nil)
 		repo.On("RemoveMemberFromTeam", mock.Anything, db.RemoveMemberFromTeamParams{TeamID: "team-xyz", UserID: "user-old"}).Return(nil)
 		repo.On("IsMemberInTeam", mock.Anything, "team-xyz", "user-new").Return(true, nil)
 		repo.On("SetLeaderToTeam", mock.Anything, db.SetLeaderToTeamParams{ID: "team-xyz", LeaderID: "user-new"}).Return(nil)
+		roleRepo.On("SetTeamLead", mock.Anything, "team-xyz", "user-new").Return(nil)
 
 		resp, err := app.Test(req, -1)
 		assert.NoError(t, err)
@@ -391,7 +469,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("UpdateTeam Failed", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -405,8 +484,7 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-xyz").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-xyz").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-xyz").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 		repo.On("RenameTeam", mock.Anything, db.RenameTeamParams{ID: "team-xyz", Name: "Updated Team Name"}).Return(errors.New("failed to rename team"))
 
 		resp, err := app.Test(req, -1)
@@ -419,7 +497,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("TeamID is not provided", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Patch("/teams/:id", handler.UpdateTeam)
@@ -433,7 +512,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("Bad Body Request", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Patch("/teams/:id", handler.UpdateTeam)
@@ -445,17 +525,18 @@ func TestUpdateTeam(t *testing.T) {
 
 	})
 
-	t.Run("Check Team Owner Failed", func(t *testing.T) {
+	t.Run("Check Roles Failed", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Patch("/teams/:id", handler.UpdateTeam)
 
 		repo.On("IsTeamExists", mock.Anything, "team-123").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("", errors.New("failed to check team owner"))
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-123", "user-123").Return(db.GetTeamMemberRolesRow{}, errors.New("failed to check roles"))
 
 		reqBody := `{}`
 		req := httptest.NewRequest(http.MethodPatch, "/teams/team-123", strings.NewReader(reqBody))
@@ -463,22 +544,22 @@ func TestUpdateTeam(t *testing.T) {
 
 		resp, err := app.Test(req, -1)
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 
 		repo.AssertExpectations(t)
 	})
 
-	t.Run("Check Team Leader Failed", func(t *testing.T) {
+	t.Run("Team Lead Can Update", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Patch("/teams/:id", handler.UpdateTeam)
 
 		repo.On("IsTeamExists", mock.Anything, "team-123").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("user-123", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-123").Return("", errors.New("failed to check team leader"))
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-123", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "member", TeamRole: "lead"}, nil)
 
 		reqBody := `{}`
 		req := httptest.NewRequest(http.MethodPatch, "/teams/team-123", strings.NewReader(reqBody))
@@ -486,21 +567,21 @@ func TestUpdateTeam(t *testing.T) {
 
 		resp, err := app.Test(req, -1)
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 	})
 
-	t.Run("No Permission to Remove Member", func(t *testing.T) {
+	t.Run("No Permission to Update Team", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Patch("/teams/:id", handler.UpdateTeam)
 
 		repo.On("IsTeamExists", mock.Anything, "team-123").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("user-999", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-123").Return("user-888", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-123", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "member", TeamRole: "member"}, nil)
 
 		reqBody := `{}`
 		req := httptest.NewRequest(http.MethodPatch, "/teams/team-123", strings.NewReader(reqBody))
@@ -514,7 +595,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("Check Team Exists Failed", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
@@ -536,7 +618,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("Team Does Not Exist", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
@@ -558,7 +641,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("AddMemberToTeam - Success", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -569,8 +653,7 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-xyz").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-xyz").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-xyz").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 		repo.On("IsMemberInTeam", mock.Anything, "team-xyz", "user-new").Return(false, nil)
 		repo.On("AddMemberToTeam", mock.Anything, db.AddMemberToTeamParams{
 			TeamID: "team-xyz", UserID: "user-new",
@@ -585,7 +668,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("AddMember - Already Exists (Should Skip)", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -596,8 +680,7 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-xyz").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-xyz").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-xyz").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 		repo.On("IsMemberInTeam", mock.Anything, "team-xyz", "user-existing").Return(true, nil)
 
 		repo.On("AddMemberToTeam", mock.Anything, mock.Anything).Maybe() 
@@ -609,10 +692,36 @@ func TestUpdateTeam(t *testing.T) {
 		repo.AssertNotCalled(t, "AddMemberToTeam", mock.Anything, mock.Anything)
 	})
 
+	t.Run("AddMembers - Not In Workspace", func(t *testing.T) {
+		repo := new(mocks.MockTeamRepository)
+		workSpaceRepo := new(mocks.MockWorkspaceRepo)
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
+
+		app := fiber.New()
+		app.Use(withUserID("user-owner"))
+		app.Patch("/teams/:id", handler.UpdateTeam)
+
+		reqBody := `{"add_members": ["user-outsider"]}`
+		req := httptest.NewRequest(http.MethodPatch, "/teams/team-xyz", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		repo.On("IsTeamExists", mock.Anything, "team-xyz").Return(true, nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", "user-owner").Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", "user-outsider").Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123"}, nil)
+
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+		repo.AssertNumberOfCalls(t, "AddMemberToTeam", 0)
+	})
+
 	t.Run("AddMemberToTeam - Insert Failed", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -623,8 +732,7 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-xyz").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-xyz").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-xyz").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 		repo.On("IsMemberInTeam", mock.Anything, "team-xyz", "user-new").Return(false, nil)
 		repo.On("AddMemberToTeam", mock.Anything, db.AddMemberToTeamParams{
 			TeamID: "team-xyz", UserID: "user-new",
@@ -639,7 +747,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("AddMembers - IsMemberInTeam Error", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -650,8 +759,7 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-123").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-123").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-123", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 
 		repo.On("IsMemberInTeam", mock.Anything, "team-123", "user-new").Return(false, errors.New("DB error"))
 
@@ -663,7 +771,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("RemoveMembers - Success", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
@@ -674,8 +783,7 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-abc").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-abc").Return("user-123", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-abc").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-abc", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 
 		repo.On("RemoveMemberFromTeam", mock.Anything, db.RemoveMemberFromTeamParams{
 			TeamID: "team-abc", UserID: "user-old",
@@ -690,7 +798,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("RemoveMembers - RemoveMember Error", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
@@ -701,8 +810,7 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-abc").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-abc").Return("user-123", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-abc").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-abc", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 
 		repo.On("RemoveMemberFromTeam", mock.Anything, db.RemoveMemberFromTeamParams{
 			TeamID: "team-abc", UserID: "user-old",
@@ -717,7 +825,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("Set New Leader Success", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -728,24 +837,26 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-xyz").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-xyz").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-xyz").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 		repo.On("IsMemberInTeam", mock.Anything, "team-xyz", "user-new").Return(true, nil)
 		repo.On("SetLeaderToTeam", mock.Anything, db.SetLeaderToTeamParams{
 			ID:       "team-xyz",
 			LeaderID: "user-new",
 		}).Return(nil)
+		roleRepo.On("SetTeamLead", mock.Anything, "team-xyz", "user-new").Return(nil)
 
 		resp, err := app.Test(req, -1)
 		assert.NoError(t, err)
 		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 		repo.AssertExpectations(t)
+		roleRepo.AssertExpectations(t)
 	})
 
 	t.Run("Set New Leader - Check Member Error", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -755,8 +866,7 @@ func TestUpdateTeam(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPatch, "/teams/team-xyz", strings.NewReader(reqBody))
 		req.Header.Set("Content-Type", "application/json")
 		repo.On("IsTeamExists", mock.Anything, "team-xyz").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-xyz").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-xyz").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 		repo.On("IsMemberInTeam", mock.Anything, "team-xyz", "user-new").
 			Return(false, errors.New("db error"))
 
@@ -768,7 +878,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("Set New Leader - Not a Member", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -778,8 +889,7 @@ func TestUpdateTeam(t *testing.T) {
 		req := httptest.NewRequest(http.MethodPatch, "/teams/team-xyz", strings.NewReader(reqBody))
 		req.Header.Set("Content-Type", "application/json")
 		repo.On("IsTeamExists", mock.Anything, "team-xyz").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-xyz").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-xyz").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-xyz", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 		repo.On("IsMemberInTeam", mock.Anything, "team-xyz", "user-new").
 			Return(false, nil)
 
@@ -791,7 +901,8 @@ func TestUpdateTeam(t *testing.T) {
 	t.Run("SetLeaderToTeam - Failed to Save", func(t *testing.T) {
 		repo := new(mocks.MockTeamRepository)
 		workSpaceRepo := new(mocks.MockWorkspaceRepo)
-		handler := routes.TeamHandler{Repo: repo, WorkspaceRepo: workSpaceRepo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.NewTeamHandler(nil, repo, workSpaceRepo, roleRepo)
 
 		app := fiber.New()
 		app.Use(withUserID("user-owner"))
@@ -802,8 +913,7 @@ func TestUpdateTeam(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("IsTeamExists", mock.Anything, "team-123").Return(true, nil)
-		repo.On("GetOwnerByTeamID", mock.Anything, "team-123").Return("user-owner", nil)
-		repo.On("GetLeaderByTeamID", mock.Anything, "team-123").Return("user-leader", nil)
+		roleRepo.On("GetTeamRoles", mock.Anything, "team-123", mock.Anything).Return(db.GetTeamMemberRolesRow{WorkspaceID: "ws-123", WorkspaceRole: "admin", TeamRole: "member"}, nil)
 		repo.On("IsMemberInTeam", mock.Anything, "team-123", "user-new").Return(true, nil)
 
 		repo.On("SetLeaderToTeam", mock.Anything, db.SetLeaderToTeamParams{
@@ -817,3 +927,616 @@ func TestUpdateTeam(t *testing.T) {
 		repo.AssertExpectations(t)
 	})
 }
+
+func TestUpdateTeamKey(t *testing.T) {
+	team := db.Team{ID: "team-1", WorkspaceID: "ws-1", Key: "ENG"}
+
+	tests := []struct {
+		name       string
+		key        string
+		setupMocks func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock)
+		wantStatus int
+	}{
+		{
+			name: "issues get the new key",
+			key:  "core",
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("GetTeamByID", mock.Anything, "team-1").Return(team, nil)
+				repo.On("ListTeamKeys", mock.Anything, "ws-1").Return([]string{"ENG", "OPS"}, nil)
+				sqlMock.ExpectBegin()
+				repo.On("SetTeamKeyTx", mock.Anything, mock.Anything, "team-1", "CORE").Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "same key",
+			key:  "ENG",
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("GetTeamByID", mock.Anything, "team-1").Return(team, nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "key of another team",
+			key:  "OPS",
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("GetTeamByID", mock.Anything, "team-1").Return(team, nil)
+				repo.On("ListTeamKeys", mock.Anything, "ws-1").Return([]string{"ENG", "OPS"}, nil)
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:       "invalid key",
+			key:        "E-1",
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "update fails",
+			key:  "CORE",
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("GetTeamByID", mock.Anything, "team-1").Return(team, nil)
+				repo.On("ListTeamKeys", mock.Anything, "ws-1").Return([]string{"ENG"}, nil)
+				sqlMock.ExpectBegin()
+				repo.On("SetTeamKeyTx", mock.Anything, mock.Anything, "team-1", "CORE").Return(errors.New("mock DB error"))
+				sqlMock.ExpectRollback()
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			repo := new(mocks.MockTeamRepository)
+			roleRepo := new(mocks.MockRoleRepo)
+			handler := routes.NewTeamHandler(mockDB, repo, new(mocks.MockWorkspaceRepo), roleRepo)
+			app := fiber.New()
+			app.Use(withUserID("user-123"))
+			app.Patch("/teams/:id", handler.UpdateTeam)
+
+			repo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+			roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamLeadRoles, nil)
+			tt.setupMocks(repo, sqlMock)
+
+			body, _ := json.Marshal(map[string]string{"key": tt.key})
+			req := httptest.NewRequest(http.MethodPatch, "/teams/team-1", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+var defaultTeamStatuses = []db.TeamStatus{
+	{TeamID: "team-1", Key: "todo", Name: "Todo", Category: "unstarted", Position: 0, Color: "#bec2c8"},
+	{TeamID: "team-1", Key: "doing", Name: "Doing", Category: "started", Position: 1, Color: "#f2c94c"},
+	{TeamID: "team-1", Key: "done", Name: "Done", Category: "completed", Position: 2, Color: "#5e6ad2"},
+}
+
+func TestListTeamStatuses(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(repo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo)
+		wantStatus int
+	}{
+		{
+			name: "member lists statuses",
+			setupMocks: func(repo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				repo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+				roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+				repo.On("ListTeamStatuses", mock.Anything, "team-1").Return(defaultTeamStatuses, nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name: "not a member",
+			setupMocks: func(repo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				repo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+				roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(noRoles, nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name: "team not found",
+			setupMocks: func(repo *mocks.MockTeamRepository, roleRepo *mocks.MockRoleRepo) {
+				repo.On("IsTeamExists", mock.Anything, "team-1").Return(false, nil)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockTeamRepository)
+			roleRepo := new(mocks.MockRoleRepo)
+			handler := routes.NewTeamHandler(nil, repo, new(mocks.MockWorkspaceRepo), roleRepo)
+			app := fiber.New()
+			app.Use(withUserID("user-123"))
+			app.Get("/teams/:id/statuses", handler.ListTeamStatuses)
+			tt.setupMocks(repo, roleRepo)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/teams/team-1/statuses", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantStatus == fiber.StatusOK {
+				var body struct {
+					Statuses []db.TeamStatus `json:"statuses"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, defaultTeamStatuses, body.Statuses)
+			}
+		})
+	}
+}
+
+func TestCreateTeamStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setupMocks func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock)
+		wantStatus int
+	}{
+		{
+			name: "appended after the others",
+			body: `{"key":"cancelled","name":"Cancelled","category":"cancelled","color":"#95a2b3"}`,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("ListTeamStatuses", mock.Anything, "team-1").Return(defaultTeamStatuses, nil)
+				sqlMock.ExpectBegin()
+				repo.On("CreateTeamStatusTx", mock.Anything, mock.Anything, db.CreateTeamStatusParams{
+					TeamID: "team-1", Key: "cancelled", Name: "Cancelled", Category: "cancelled", Position: 3, Color: "#95a2b3",
+				}).Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "inserted at a position",
+			body: `{"key":"in_review","name":"In Review","category":"started","color":"#f2994a","position":2}`,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("ListTeamStatuses", mock.Anything, "team-1").Return(defaultTeamStatuses, nil)
+				sqlMock.ExpectBegin()
+				repo.On("CreateTeamStatusTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p db.CreateTeamStatusParams) bool {
+					return p.Key == "in_review" && p.Position == 2
+				})).Return(nil)
+				repo.On("SetTeamStatusOrderTx", mock.Anything, mock.Anything, "team-1", []string{"todo", "doing", "in_review", "done"}).Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name: "key already used",
+			body: `{"key":"doing","name":"Doing","category":"started","color":"#f2c94c"}`,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("ListTeamStatuses", mock.Anything, "team-1").Return(defaultTeamStatuses, nil)
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:       "invalid key",
+			body:       `{"key":"In Review","name":"In Review","category":"started","color":"#f2994a"}`,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "invalid category",
+			body:       `{"key":"blocked","name":"Blocked","category":"paused","color":"#eb5757"}`,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "invalid color",
+			body:       `{"key":"blocked","name":"Blocked","category":"started","color":"red"}`,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name: "write fails",
+			body: `{"key":"blocked","name":"Blocked","category":"started","color":"#eb5757"}`,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("ListTeamStatuses", mock.Anything, "team-1").Return(defaultTeamStatuses, nil)
+				sqlMock.ExpectBegin()
+				repo.On("CreateTeamStatusTx", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db error"))
+				sqlMock.ExpectRollback()
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			repo := new(mocks.MockTeamRepository)
+			roleRepo := new(mocks.MockRoleRepo)
+			handler := routes.NewTeamHandler(mockDB, repo, new(mocks.MockWorkspaceRepo), roleRepo)
+			app := fiber.New()
+			app.Use(withUserID("user-123"))
+			app.Post("/teams/:id/statuses", handler.CreateTeamStatus)
+
+			repo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+			roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamLeadRoles, nil)
+			tt.setupMocks(repo, sqlMock)
+
+			req := httptest.NewRequest(http.MethodPost, "/teams/team-1/statuses", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateTeamStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		key        string
+		body       string
+		roles      db.GetTeamMemberRolesRow
+		setupMocks func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock)
+		wantStatus int
+		want       db.TeamStatus
+	}{
+		{
+			name:  "rename and move to the front",
+			key:   "done",
+			body:  `{"name":"Shipped","position":0}`,
+			roles: teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				repo.On("UpdateTeamStatusTx", mock.Anything, mock.Anything, db.UpdateTeamStatusParams{
+					Name: "Shipped", Category: "completed", Color: "#5e6ad2", TeamID: "team-1", Key: "done",
+				}).Return(nil)
+				repo.On("SetTeamStatusOrderTx", mock.Anything, mock.Anything, "team-1", []string{"done", "todo", "doing"}).Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+			want:       db.TeamStatus{TeamID: "team-1", Key: "done", Name: "Shipped", Category: "completed", Position: 0, Color: "#5e6ad2"},
+		},
+		{
+			name:  "change category only",
+			key:   "doing",
+			body:  `{"category":"unstarted"}`,
+			roles: teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				repo.On("UpdateTeamStatusTx", mock.Anything, mock.Anything, db.UpdateTeamStatusParams{
+					Name: "Doing", Category: "unstarted", Color: "#f2c94c", TeamID: "team-1", Key: "doing",
+				}).Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+			want:       db.TeamStatus{TeamID: "team-1", Key: "doing", Name: "Doing", Category: "unstarted", Position: 1, Color: "#f2c94c"},
+		},
+		{
+			name:       "status not found",
+			key:        "blocked",
+			body:       `{"name":"Blocked"}`,
+			roles:      teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name:       "members cannot edit statuses",
+			key:        "done",
+			body:       `{"name":"Shipped"}`,
+			roles:      teamMemberRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:       "empty name",
+			key:        "done",
+			body:       `{"name":"  "}`,
+			roles:      teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			repo := new(mocks.MockTeamRepository)
+			roleRepo := new(mocks.MockRoleRepo)
+			handler := routes.NewTeamHandler(mockDB, repo, new(mocks.MockWorkspaceRepo), roleRepo)
+			app := fiber.New()
+			app.Use(withUserID("user-123"))
+			app.Patch("/teams/:id/statuses/:key", handler.UpdateTeamStatus)
+
+			repo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+			roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(tt.roles, nil)
+			repo.On("ListTeamStatuses", mock.Anything, "team-1").Return(defaultTeamStatuses, nil)
+			tt.setupMocks(repo, sqlMock)
+
+			req := httptest.NewRequest(http.MethodPatch, "/teams/team-1/statuses/"+tt.key, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+
+			if tt.wantStatus == fiber.StatusOK {
+				var body struct {
+					Status db.TeamStatus `json:"status"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				assert.Equal(t, tt.want, body.Status)
+			}
+		})
+	}
+}
+
+func TestDeleteTeamStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		statuses   []db.TeamStatus
+		setupMocks func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock)
+		wantStatus int
+	}{
+		{
+			name:     "unused status",
+			url:      "/teams/team-1/statuses/doing",
+			statuses: defaultTeamStatuses,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("CountIssuesByTeamStatus", mock.Anything, "team-1", "doing").Return(int64(0), nil)
+				sqlMock.ExpectBegin()
+				repo.On("DeleteTeamStatusTx", mock.Anything, mock.Anything, "team-1", "doing", "").Return(nil)
+				repo.On("SetTeamStatusOrderTx", mock.Anything, mock.Anything, "team-1", []string{"todo", "done"}).Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:     "in use without move_to",
+			url:      "/teams/team-1/statuses/doing",
+			statuses: defaultTeamStatuses,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				repo.On("CountIssuesByTeamStatus", mock.Anything, "team-1", "doing").Return(int64(4), nil)
+			},
+			wantStatus: fiber.StatusConflict,
+		},
+		{
+			name:     "issues moved to another status",
+			url:      "/teams/team-1/statuses/doing?move_to=todo",
+			statuses: defaultTeamStatuses,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {
+				sqlMock.ExpectBegin()
+				repo.On("DeleteTeamStatusTx", mock.Anything, mock.Anything, "team-1", "doing", "todo").Return(nil)
+				repo.On("SetTeamStatusOrderTx", mock.Anything, mock.Anything, "team-1", []string{"todo", "done"}).Return(nil)
+				sqlMock.ExpectCommit()
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:       "move_to is the deleted status",
+			url:        "/teams/team-1/statuses/doing?move_to=doing",
+			statuses:   defaultTeamStatuses,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "move_to of another team",
+			url:        "/teams/team-1/statuses/doing?move_to=archived",
+			statuses:   defaultTeamStatuses,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "last status",
+			url:        "/teams/team-1/statuses/todo",
+			statuses:   defaultTeamStatuses[:1],
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "status not found",
+			url:        "/teams/team-1/statuses/blocked",
+			statuses:   defaultTeamStatuses,
+			setupMocks: func(repo *mocks.MockTeamRepository, sqlMock sqlmock.Sqlmock) {},
+			wantStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, sqlMock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer mockDB.Close()
+
+			repo := new(mocks.MockTeamRepository)
+			roleRepo := new(mocks.MockRoleRepo)
+			handler := routes.NewTeamHandler(mockDB, repo, new(mocks.MockWorkspaceRepo), roleRepo)
+			app := fiber.New()
+			app.Use(withUserID("user-123"))
+			app.Delete("/teams/:id/statuses/:key", handler.DeleteTeamStatus)
+
+			repo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+			roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamLeadRoles, nil)
+			repo.On("ListTeamStatuses", mock.Anything, "team-1").Return(tt.statuses, nil)
+			tt.setupMocks(repo, sqlMock)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, tt.url, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListTransitionRules(t *testing.T) {
+	repo := new(mocks.MockTeamRepository)
+	roleRepo := new(mocks.MockRoleRepo)
+	handler := routes.NewTeamHandler(nil, repo, new(mocks.MockWorkspaceRepo), roleRepo)
+	app := fiber.New()
+	app.Use(withUserID("user-123"))
+	app.Get("/teams/:id/transition-rules", handler.ListTransitionRules)
+
+	repo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+	roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamMemberRoles, nil)
+	repo.On("ListTransitionRules", mock.Anything, "team-1").Return([]db.TeamTransitionRule{{
+		ID:          "rule-1",
+		TeamID:      "team-1",
+		FromStatus:  sql.NullString{String: "doing", Valid: true},
+		ToCategory:  sql.NullString{String: "completed", Valid: true},
+		Requirement: "end_date",
+	}}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/teams/team-1/transition-rules", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body map[string][]map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body["rules"], 1)
+	rule := body["rules"][0]
+	assert.Equal(t, "doing", rule["from_status"])
+	assert.Equal(t, "completed", rule["to_category"])
+	assert.NotContains(t, rule, "to_status")
+}
+
+func TestCreateTransitionRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		roles      db.GetTeamMemberRolesRow
+		setupMocks func(repo *mocks.MockTeamRepository)
+		wantStatus int
+	}{
+		{
+			name:  "rule on a status",
+			body:  `{"from_status":"todo","to_status":"doing","requirement":"assignee"}`,
+			roles: teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository) {
+				repo.On("GetTeamStatus", mock.Anything, "team-1", "todo").Return(db.TeamStatus{Key: "todo"}, nil)
+				repo.On("GetTeamStatus", mock.Anything, "team-1", "doing").Return(db.TeamStatus{Key: "doing"}, nil)
+				repo.On("CreateTransitionRule", mock.Anything, mock.MatchedBy(func(p db.CreateTransitionRuleParams) bool {
+					return p.ID != "" && p.TeamID == "team-1" && p.FromStatus.String == "todo" &&
+						p.ToStatus.String == "doing" && !p.ToCategory.Valid && p.Requirement == "assignee"
+				})).Return(nil)
+				repo.On("ListTransitionRules", mock.Anything, "team-1").Return([]db.TeamTransitionRule{}, nil)
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name:  "rule on a category",
+			body:  `{"to_category":"completed","requirement":"actor_is_assignee"}`,
+			roles: teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository) {
+				repo.On("CreateTransitionRule", mock.Anything, mock.MatchedBy(func(p db.CreateTransitionRuleParams) bool {
+					return !p.FromStatus.Valid && !p.ToStatus.Valid && p.ToCategory.String == "completed"
+				})).Return(nil)
+				repo.On("ListTransitionRules", mock.Anything, "team-1").Return([]db.TeamTransitionRule{}, nil)
+			},
+			wantStatus: fiber.StatusCreated,
+		},
+		{
+			name:       "no target",
+			body:       `{"requirement":"assignee"}`,
+			roles:      teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "both targets",
+			body:       `{"to_status":"done","to_category":"completed","requirement":"end_date"}`,
+			roles:      teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "unknown requirement",
+			body:       `{"to_status":"done","requirement":"approval"}`,
+			roles:      teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:  "status not in team",
+			body:  `{"to_status":"archived","requirement":"forbidden"}`,
+			roles: teamLeadRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository) {
+				repo.On("GetTeamStatus", mock.Anything, "team-1", "archived").Return(db.TeamStatus{}, sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "member cannot configure rules",
+			body:       `{"to_status":"done","requirement":"end_date"}`,
+			roles:      teamMemberRoles,
+			setupMocks: func(repo *mocks.MockTeamRepository) {},
+			wantStatus: fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockTeamRepository)
+			roleRepo := new(mocks.MockRoleRepo)
+			handler := routes.NewTeamHandler(nil, repo, new(mocks.MockWorkspaceRepo), roleRepo)
+			app := fiber.New()
+			app.Use(withUserID("user-123"))
+			app.Post("/teams/:id/transition-rules", handler.CreateTransitionRule)
+
+			repo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil).Maybe()
+			roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(tt.roles, nil)
+			tt.setupMocks(repo)
+
+			req := httptest.NewRequest(http.MethodPost, "/teams/team-1/transition-rules", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteTransitionRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		rows       int64
+		err        error
+		wantStatus int
+	}{
+		{name: "deleted", rows: 1, wantStatus: fiber.StatusOK},
+		{name: "not found", rows: 0, wantStatus: fiber.StatusNotFound},
+		{name: "delete fails", err: errors.New("db error"), wantStatus: fiber.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockTeamRepository)
+			roleRepo := new(mocks.MockRoleRepo)
+			handler := routes.NewTeamHandler(nil, repo, new(mocks.MockWorkspaceRepo), roleRepo)
+			app := fiber.New()
+			app.Use(withUserID("user-123"))
+			app.Delete("/teams/:id/transition-rules/:ruleid", handler.DeleteTransitionRule)
+
+			repo.On("IsTeamExists", mock.Anything, "team-1").Return(true, nil)
+			roleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-123").Return(teamLeadRoles, nil)
+			repo.On("DeleteTransitionRule", mock.Anything, "team-1", "rule-1").Return(tt.rows, tt.err)
+			repo.On("ListTransitionRules", mock.Anything, "team-1").Return([]db.TeamTransitionRule{}, nil).Maybe()
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/teams/team-1/transition-rules/rule-1", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
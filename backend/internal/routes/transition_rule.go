@@ -0,0 +1,242 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/workflow"
+	"github.com/nack098/nakumanager/internal/ws"
+)
+
+func toTransitionRule(r db.TeamTransitionRule) models.TransitionRule {
+	return models.TransitionRule{
+		ID:          r.ID,
+		TeamID:      r.TeamID,
+		FromStatus:  r.FromStatus.String,
+		ToStatus:    r.ToStatus.String,
+		ToCategory:  r.ToCategory.String,
+		Requirement: r.Requirement,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+func (h *TeamHandler) listTransitionRules(ctx context.Context, teamID string) ([]models.TransitionRule, error) {
+	rows, err := h.Repo.ListTransitionRules(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]models.TransitionRule, 0, len(rows))
+	for _, r := range rows {
+		rules = append(rules, toTransitionRule(r))
+	}
+	return rules, nil
+}
+
+func (h *TeamHandler) broadcastTransitionRules(c *fiber.Ctx, teamID string) {
+	rules, err := h.listTransitionRules(c.Context(), teamID)
+	if err != nil {
+		log.Printf("Failed to list transition rules of team %s: %v", teamID, err)
+		return
+	}
+	ws.BroadcastToRoom("team", teamID, "team_transition_rules_updated", fiber.Map{"rules": rules})
+}
+
+func (h *TeamHandler) ListTransitionRules(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+	if !h.loadTeamFor(c, teamID, authz.TeamView, "you are not a member of this team") {
+		return nil
+	}
+
+	rules, err := h.listTransitionRules(c.Context(), teamID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list transition rules"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"rules": rules})
+}
+
+// CreateTransitionRule adds a rule for moves into to_status, or into any
+// status of to_category, optionally only from from_status.
+func (h *TeamHandler) CreateTransitionRule(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+
+	var req models.CreateTransitionRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	req.FromStatus = strings.TrimSpace(req.FromStatus)
+	req.ToStatus = strings.TrimSpace(req.ToStatus)
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed", "detail": err.Error()})
+	}
+	if req.ToStatus != "" && req.ToCategory != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "set either to_status or to_category, not both"})
+	}
+
+	if !h.loadTeamFor(c, teamID, authz.TeamUpdate, "no permission to update this team") {
+		return nil
+	}
+
+	ctx := c.Context()
+	for _, key := range []string{req.FromStatus, req.ToStatus} {
+		if key == "" {
+			continue
+		}
+		if _, err := h.Repo.GetTeamStatus(ctx, teamID, key); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": fmt.Sprintf("status %s does not exist in the team", key)})
+			}
+			log.Printf("Failed to get status %s of team %s: %v", key, teamID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check status"})
+		}
+	}
+
+	params := db.CreateTransitionRuleParams{
+		ID:          uuid.New().String(),
+		TeamID:      teamID,
+		FromStatus:  sql.NullString{String: req.FromStatus, Valid: req.FromStatus != ""},
+		ToStatus:    sql.NullString{String: req.ToStatus, Valid: req.ToStatus != ""},
+		ToCategory:  sql.NullString{String: req.ToCategory, Valid: req.ToCategory != ""},
+		Requirement: req.Requirement,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := h.Repo.CreateTransitionRule(ctx, params); err != nil {
+		log.Printf("Failed to create transition rule for team %s: %v", teamID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create transition rule"})
+	}
+
+	h.broadcastTransitionRules(c, teamID)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "transition rule created successfully",
+		"rule":    toTransitionRule(db.TeamTransitionRule(params)),
+	})
+}
+
+func (h *TeamHandler) DeleteTransitionRule(c *fiber.Ctx) error {
+	teamID := c.Params("id")
+	ruleID := c.Params("ruleid")
+
+	if !h.loadTeamFor(c, teamID, authz.TeamUpdate, "no permission to update this team") {
+		return nil
+	}
+
+	n, err := h.Repo.DeleteTransitionRule(c.Context(), teamID, ruleID)
+	if err != nil {
+		log.Printf("Failed to delete transition rule %s: %v", ruleID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to delete transition rule"})
+	}
+	if n == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "transition rule not found"})
+	}
+
+	h.broadcastTransitionRules(c, teamID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "transition rule deleted successfully"})
+}
+
+// checkTransition checks moving issue into the status to against the rules of
+// its team. add and remove are the assignee changes made by the same update
+// and hasEndDate tells whether the issue will have an end date. It writes a
+// 422 response listing the broken rules and returns false when the move is
+// not allowed.
+func (h *IssueHandler) checkTransition(c *fiber.Ctx, issue db.Issue, to db.TeamStatus, userID string, add, remove *[]string, hasEndDate bool) bool {
+	ctx := c.Context()
+	rows, err := h.TeamRepo.ListTransitionRules(ctx, to.TeamID)
+	if err != nil {
+		log.Printf("Failed to list transition rules of team %s: %v", to.TeamID, err)
+		c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check transition rules"})
+		return false
+	}
+	rules := make([]models.TransitionRule, 0, len(rows))
+	needAssignees := false
+	for _, r := range rows {
+		rule := toTransitionRule(r)
+		if !workflow.Applies(rule, issue.Status, to.Key, to.Category) {
+			continue
+		}
+		rules = append(rules, rule)
+		if rule.Requirement == workflow.RequireAssignee || rule.Requirement == workflow.RequireActorAssignee {
+			needAssignees = true
+		}
+	}
+	if len(rules) == 0 {
+		return true
+	}
+
+	t := workflow.Transition{
+		From:       issue.Status,
+		To:         to.Key,
+		ToCategory: to.Category,
+		ActorID:    userID,
+		HasEndDate: hasEndDate,
+	}
+	if needAssignees {
+		t.Assignees, err = h.assigneesAfter(ctx, issue, add, remove)
+		if err != nil {
+			log.Printf("Failed to get assignees of issue %s: %v", issue.ID, err)
+			c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to fetch assignees"})
+			return false
+		}
+	}
+
+	if violations := workflow.Check(rules, t); len(violations) > 0 {
+		c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":      "transition not allowed",
+			"from":       issue.Status,
+			"to":         to.Key,
+			"violations": violations,
+		})
+		return false
+	}
+	return true
+}
+
+// assigneesAfter returns the assignees of issue once add and remove are
+// applied. Users outside the team are skipped, as UpdateIssue does.
+func (h *IssueHandler) assigneesAfter(ctx context.Context, issue db.Issue, add, remove *[]string) ([]string, error) {
+	users, err := h.Repo.ListAssigneesByIssueID(ctx, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(users))
+	for _, u := range users {
+		set[u.ID] = true
+	}
+	for _, change := range []struct {
+		ids *[]string
+		add bool
+	}{{add, true}, {remove, false}} {
+		if change.ids == nil {
+			continue
+		}
+		for _, id := range *change.ids {
+			member, err := h.TeamRepo.IsMemberInTeam(ctx, issue.TeamID, id)
+			if err != nil {
+				return nil, err
+			}
+			if !member {
+				continue
+			}
+			if change.add {
+				set[id] = true
+			} else {
+				delete(set, id)
+			}
+		}
+	}
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
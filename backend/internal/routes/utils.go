@@ -2,16 +2,21 @@ package routes
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator"
+	"github.com/gofiber/fiber/v2"
 	models "github.com/nack098/nakumanager/internal/models"
 )
 
 var validate = validator.New()
 
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]+)`)
+
 func ToNullString(s *string) sql.NullString {
 	if s != nil && strings.TrimSpace(*s) != "" {
 		return sql.NullString{String: *s, Valid: true}
@@ -33,6 +38,36 @@ func nullStringToString(ns sql.NullString) string {
 	return ""
 }
 
+// parsePagination reads the limit and offset query parameters, falling back to
+// defaultLimit when limit is not given.
+func parsePagination(c *fiber.Ctx, defaultLimit, maxLimit int) (int, int, error) {
+	limit := c.QueryInt("limit", defaultLimit)
+	offset := c.QueryInt("offset", 0)
+	if limit < 1 || limit > maxLimit {
+		return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	if offset < 0 {
+		return 0, 0, errors.New("offset must not be negative")
+	}
+	return limit, offset, nil
+}
+
+// ParseMentions returns the distinct usernames mentioned as @username in text,
+// in order of first appearance.
+func ParseMentions(text string) []string {
+	seen := map[string]bool{}
+	usernames := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
+
 func buildUpdateQuery(p models.EditProject) (string, []interface{}) {
 	query := "UPDATE projects SET "
 	args := []interface{}{}
@@ -58,22 +93,25 @@ func buildUpdateQuery(p models.EditProject) (string, []interface{}) {
 		sets = append(sets, "end_date = ?")
 		args = append(args, *p.EndDate)
 	}
-	if p.Label != nil {
-		sets = append(sets, "label = ?")
-		args = append(args, *p.Label)
-	}
 	if p.LeaderID != nil {
 		sets = append(sets, "leader_id = ?")
 		args = append(args, *p.LeaderID)
 	}
 
+	if len(sets) == 0 {
+		return "", nil
+	}
+
 	query += strings.Join(sets, ", ") + " WHERE id = ?"
 	args = append(args, p.ID)
 
 	return query, args
 }
 
-func buildUpdateIssueQuery(i models.UpdateIssueRequest) (string, []interface{}) {
+// buildUpdateIssueQuery returns the update of the fields set in the request.
+// renumber is the new number of an issue moving to another team, or nil. now
+// is when the issue joins the cycle it is put in.
+func buildUpdateIssueQuery(i models.UpdateIssueRequest, renumber *issueNumber, now time.Time) (string, []interface{}) {
 	query := "UPDATE issues SET "
 	args := []interface{}{}
 	sets := []string{}
@@ -96,12 +134,16 @@ func buildUpdateIssueQuery(i models.UpdateIssueRequest) (string, []interface{})
 	}
 	if i.ProjectID != nil {
 		sets = append(sets, "project_id = ?")
-		args = append(args, *i.ProjectID)
+		args = append(args, ToNullString(i.ProjectID))
 	}
 	if i.TeamID != nil {
 		sets = append(sets, "team_id = ?")
 		args = append(args, *i.TeamID)
 	}
+	if renumber != nil {
+		sets = append(sets, "number = ?", "identifier = ?")
+		args = append(args, renumber.Number, renumber.Identifier)
+	}
 	if i.StartDate != nil {
 		sets = append(sets, "start_date = ?")
 		args = append(args, *i.StartDate)
@@ -110,14 +152,23 @@ func buildUpdateIssueQuery(i models.UpdateIssueRequest) (string, []interface{})
 		sets = append(sets, "end_date = ?")
 		args = append(args, *i.EndDate)
 	}
-	if i.Label != nil {
-		sets = append(sets, "label = ?")
-		args = append(args, *i.Label)
-	}
 	if i.OwnerID != nil {
 		sets = append(sets, "owner_id = ?")
 		args = append(args, *i.OwnerID)
 	}
+	if i.ParentID != nil {
+		sets = append(sets, "parent_id = ?")
+		args = append(args, ToNullString(i.ParentID))
+	}
+	if i.CycleID != nil {
+		sets = append(sets, "cycle_id = ?", "cycle_added_at = ?")
+		cycleID := ToNullString(i.CycleID)
+		args = append(args, cycleID, sql.NullTime{Time: now, Valid: cycleID.Valid})
+	}
+	if i.MilestoneID != nil {
+		sets = append(sets, "milestone_id = ?")
+		args = append(args, ToNullString(i.MilestoneID))
+	}
 
 	if len(sets) == 0 {
 		return "", nil
@@ -128,30 +179,3 @@ func buildUpdateIssueQuery(i models.UpdateIssueRequest) (string, []interface{})
 
 	return query, args
 }
-
-func BuildGroupByQuery(table string, groupBys []string) (string, error) {
-	validCols := map[string]bool{
-		"status": true, "priority": true, "project_id": true,
-		"label": true, "assignee": true, "team_id": true, "end_date": true,
-	}
-	if len(groupBys) == 0 {
-		return "", fmt.Errorf("no group_by fields provided")
-	}
-	for _, col := range groupBys {
-		if !validCols[col] {
-			return "", fmt.Errorf("invalid group_by column: %s", col)
-		}
-	}
-
-	var conditions []string
-	for _, col := range groupBys {
-		conditions = append(conditions, fmt.Sprintf("%s IS NOT NULL", col))
-	}
-
-	query := fmt.Sprintf(`
-		SELECT id
-		FROM %s
-		WHERE team_id = ? AND %s
-	`, table, strings.Join(conditions, " AND "))
-	return query, nil
-}
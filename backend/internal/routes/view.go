@@ -1,28 +1,131 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
+	"time"
 
 	models "github.com/nack098/nakumanager/internal/models"
 	"github.com/nack098/nakumanager/internal/ws"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
 	"github.com/nack098/nakumanager/internal/db"
 	"github.com/nack098/nakumanager/internal/repositories"
+	"github.com/nack098/nakumanager/internal/viewcache"
+	"github.com/nack098/nakumanager/internal/viewfilter"
+	"github.com/nack098/nakumanager/internal/viewgroup"
 )
 
 type ViewHandler struct {
-	DB   *sql.DB
-	Repo repositories.ViewRepository
+	DB    *sql.DB
+	Repo  repositories.ViewRepository
+	Authz *authz.Authorizer
+	// Cache เป็น nil ได้ หมายถึงคำนวณ view ใหม่ทุกครั้ง
+	Cache *viewcache.Cache
 }
 
-func NewViewHandler(db *sql.DB, repo repositories.ViewRepository) *ViewHandler {
+func NewViewHandler(db *sql.DB, repo repositories.ViewRepository, roleRepo repositories.RoleRepository) *ViewHandler {
 	return &ViewHandler{
-		DB:   db,
-		Repo: repo}
+		DB:    db,
+		Repo:  repo,
+		Authz: authz.NewAuthorizer(roleRepo)}
+}
+
+func toView(v db.View) models.View {
+	filter, err := viewfilter.Parse(v.Filter.String)
+	if err != nil {
+		log.Printf("View %s has an invalid filter: %v", v.ID, err)
+	}
+	return models.View{
+		ID:        v.ID,
+		Name:      v.Name,
+		CreatedBy: v.CreatedBy,
+		TeamID:    v.TeamID,
+		Filter:    filter,
+	}
+}
+
+func toViews(views []db.View) []models.View {
+	out := make([]models.View, len(views))
+	for i, v := range views {
+		out[i] = toView(v)
+	}
+	return out
+}
+
+// encodeViewFilter stores a filter as JSON, and an empty filter as NULL.
+func encodeViewFilter(f *models.ViewFilter) sql.NullString {
+	if f == nil || (len(f.Conditions) == 0 && len(f.Groups) == 0) {
+		return sql.NullString{}
+	}
+	b, _ := json.Marshal(f)
+	return sql.NullString{String: string(b), Valid: true}
+}
+
+var validGroupBys = map[string]bool{
+	"status": true, "priority": true, "project_id": true,
+	"label": true, "assignee": true, "team_id": true, "end_date": true,
+}
+
+var validGroupOrders = map[string]bool{
+	viewgroup.OrderNatural: true, viewgroup.OrderValue: true, viewgroup.OrderCount: true,
+}
+
+// viewGroupOptions reads the grouping of a request, given either as group_bys
+// or as groups, and fills in the default order.
+func viewGroupOptions(groupBys []string, groups []models.ViewGroupOption) ([]models.ViewGroupOption, error) {
+	if groupBys != nil && groups != nil {
+		return nil, fmt.Errorf("use either group_bys or groups, not both")
+	}
+	if groups == nil {
+		for _, g := range groupBys {
+			groups = append(groups, models.ViewGroupOption{Field: g})
+		}
+	}
+
+	seen := map[string]bool{}
+	out := make([]models.ViewGroupOption, len(groups))
+	for i, g := range groups {
+		if !validGroupBys[g.Field] {
+			return nil, fmt.Errorf("Invalid group_by value: %s", g.Field)
+		}
+		if seen[g.Field] {
+			return nil, fmt.Errorf("Duplicate group_by value: %s", g.Field)
+		}
+		seen[g.Field] = true
+		if g.Order == "" {
+			g.Order = viewgroup.OrderNatural
+		}
+		if !validGroupOrders[g.Order] {
+			return nil, fmt.Errorf("Invalid group order: %s", g.Order)
+		}
+		out[i] = g
+	}
+	return out, nil
+}
+
+func addGroupByParams(viewID string, position int, g models.ViewGroupOption) db.AddGroupByToViewParams {
+	return db.AddGroupByToViewParams{
+		ViewID:     viewID,
+		GroupBy:    g.Field,
+		Position:   int64(position),
+		GroupOrder: g.Order,
+		Descending: g.Desc,
+		ShowEmpty:  g.ShowEmpty,
+	}
+}
+
+func toViewGroupOptions(groupBys []db.ViewGroupBy) []models.ViewGroupOption {
+	out := make([]models.ViewGroupOption, len(groupBys))
+	for i, g := range groupBys {
+		out[i] = models.ViewGroupOption{Field: g.GroupBy, Order: g.GroupOrder, Desc: g.Descending, ShowEmpty: g.ShowEmpty}
+	}
+	return out
 }
 
 func (h *ViewHandler) CreateView(c *fiber.Ctx) error {
@@ -33,9 +136,20 @@ func (h *ViewHandler) CreateView(c *fiber.Ctx) error {
 	if req.Name == "" || req.TeamID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name and team_id are required"})
 	}
+	if err := viewfilter.Validate(req.Filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid filter: " + err.Error()})
+	}
+	groups, err := viewGroupOptions(req.GroupBys, req.Groups)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
 
 	userID := c.Locals("userID").(string)
 
+	if !authorize(c, h.Authz, authz.ViewCreate, authz.ForTeam(req.TeamID), "you are not a member of the team") {
+		return nil
+	}
+
 	req.ID = uuid.New().String()
 	ctx := c.Context()
 
@@ -44,61 +158,17 @@ func (h *ViewHandler) CreateView(c *fiber.Ctx) error {
 		Name:      req.Name,
 		CreatedBy: userID,
 		TeamID:    req.TeamID,
+		Filter:    encodeViewFilter(req.Filter),
 	}); err != nil {
 		log.Printf("Failed to create view: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create view"})
 	}
 
-	validGroupBys := map[string]bool{
-		"status": true, "priority": true, "project_id": true,
-		"label": true, "assignee": true, "team_id": true, "end_date": true,
-	}
-	for _, g := range req.GroupBys {
-		if !validGroupBys[g] {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": fmt.Sprintf("Invalid group_by value: %s", g),
-			})
-		}
-		if err := h.Repo.AddGroupByToView(ctx, db.AddGroupByToViewParams{
-			ViewID:  req.ID,
-			GroupBy: g,
-		}); err != nil {
-			log.Printf("Failed to add group_by %s: %v", g, err)
+	for i, g := range groups {
+		if err := h.Repo.AddGroupByToView(ctx, addGroupByParams(req.ID, i, g)); err != nil {
+			log.Printf("Failed to add group_by %s: %v", g.Field, err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": fmt.Sprintf("Failed to add group_by: %s", g),
-			})
-		}
-	}
-
-	query, _ := BuildGroupByQuery("issues", req.GroupBys)
-
-	rows, err := h.DB.QueryContext(ctx, query, req.TeamID)
-	if err != nil {
-		log.Printf("Failed to query grouped issues: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to group issues"})
-	}
-	defer rows.Close()
-
-	issueSet := make(map[string]struct{})
-	for rows.Next() {
-		var issueID string
-		if err := rows.Scan(&issueID); err == nil {
-			issueSet[issueID] = struct{}{}
-		}
-	}
-	if err := rows.Err(); err != nil {
-		log.Printf("Row error: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read grouped issues"})
-	}
-
-	for issueID := range issueSet {
-		if err := h.Repo.AddIssueToView(ctx, db.AddIssueToViewParams{
-			ViewID:  req.ID,
-			IssueID: issueID,
-		}); err != nil {
-			log.Printf("Failed to add issue %s to view: %v", issueID, err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": fmt.Sprintf("Failed to add issue %s to view", issueID),
+				"error": fmt.Sprintf("Failed to add group_by: %s", g.Field),
 			})
 		}
 	}
@@ -125,7 +195,7 @@ func (h *ViewHandler) GetViewsByGroupBy(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get views by group_by"})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Success", "views": views})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Success", "views": toViews(views)})
 }
 
 func (h *ViewHandler) GetViewByTeamID(c *fiber.Ctx) error {
@@ -137,6 +207,10 @@ func (h *ViewHandler) GetViewByTeamID(c *fiber.Ctx) error {
 		})
 	}
 
+	if !authorize(c, h.Authz, authz.ViewView, authz.ForTeam(teamID), "you are not a member of the team") {
+		return nil
+	}
+
 	views, err := h.Repo.ListViewByTeamID(c.Context(), teamID)
 	if err != nil {
 		log.Printf("Failed to get views by user ID: %v", err)
@@ -145,7 +219,7 @@ func (h *ViewHandler) GetViewByTeamID(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(views)
+	return c.Status(fiber.StatusOK).JSON(toViews(views))
 }
 
 func (h *ViewHandler) DeleteView(c *fiber.Ctx) error {
@@ -172,6 +246,10 @@ func (h *ViewHandler) DeleteView(c *fiber.Ctx) error {
 		})
 	}
 
+	if !authorize(c, h.Authz, authz.ViewDelete, authz.ForView(view[0]), "you are not authorized to delete this view") {
+		return nil
+	}
+
 	err = h.Repo.DeleteView(c.Context(), viewID)
 	if err != nil {
 		log.Printf("Failed to delete view: %v", err)
@@ -179,6 +257,7 @@ func (h *ViewHandler) DeleteView(c *fiber.Ctx) error {
 			"error": "Failed to delete view",
 		})
 	}
+	h.Cache.Invalidate(viewID)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": "View deleted successfully",
@@ -197,11 +276,13 @@ func (h *ViewHandler) UpdateView(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
 	}
-
-	teamID, err := h.Repo.GetTeamIDByViewID(c.Context(), viewID)
+	if err := viewfilter.Validate(req.Filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid filter: " + err.Error()})
+	}
+	updateGroups := req.GroupBys != nil || req.Groups != nil
+	groups, err := viewGroupOptions(req.GroupBys, req.Groups)
 	if err != nil {
-		log.Printf("Failed to get team_id: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get team_id"})
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	userID := c.Locals("userID").(string)
@@ -218,6 +299,16 @@ func (h *ViewHandler) UpdateView(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "View not found"})
 	}
 
+	if !authorize(c, h.Authz, authz.ViewUpdate, authz.ForView(view[0]), "you are not authorized to update this view") {
+		return nil
+	}
+	// ย้าย view ไปทีมอื่นได้เฉพาะทีมที่สร้าง view ได้
+	if req.TeamID != "" && req.TeamID != view[0].TeamID {
+		if !authorize(c, h.Authz, authz.ViewCreate, authz.ForTeam(req.TeamID), "you are not a member of the team") {
+			return nil
+		}
+	}
+
 	ctx := c.Context()
 	tx, err := h.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -253,77 +344,159 @@ func (h *ViewHandler) UpdateView(c *fiber.Ctx) error {
 		}
 	}
 
-	if req.GroupBys != nil {
-		log.Printf("Updating group_bys to: %v", req.GroupBys)
+	if req.Filter != nil {
+		log.Printf("Updating view filter")
+		err = h.Repo.UpdateViewFilter(ctx, viewID, encodeViewFilter(req.Filter))
+		if err != nil {
+			log.Printf("Failed to update view filter: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update view filter"})
+		}
+	}
+
+	if updateGroups {
+		log.Printf("Updating group_bys to: %v", groups)
 
 		if err = h.Repo.RemoveGroupByFromView(ctx, viewID); err != nil {
 			log.Printf("Failed to remove old group_bys: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to remove old group_bys"})
 		}
-		if err = h.Repo.RemoveIssueFromView(ctx, viewID); err != nil {
-			log.Printf("Failed to remove old issues: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to remove old issues"})
-		}
 
-		for _, gb := range req.GroupBys {
-			log.Printf("Adding group_by: %s", gb)
-			err = h.Repo.AddGroupByToView(ctx, db.AddGroupByToViewParams{
-				ViewID:  viewID,
-				GroupBy: gb,
-			})
+		for i, g := range groups {
+			log.Printf("Adding group_by: %s", g.Field)
+			err = h.Repo.AddGroupByToView(ctx, addGroupByParams(viewID, i, g))
 			if err != nil {
-				log.Printf("Failed to add group_by %s: %v", gb, err)
+				log.Printf("Failed to add group_by %s: %v", g.Field, err)
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to add group_by"})
 			}
 		}
+	}
 
-		query, _ := BuildGroupByQuery("issues", req.GroupBys)
+	h.Cache.Invalidate(viewID)
+	ws.BroadcastToRoom("view", viewID, "view_updated", req)
 
-		log.Printf("Using team_id: %s", teamID)
+	log.Println("View updated successfully")
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "View updated successfully"})
+}
 
-		rows, err := tx.QueryContext(ctx, query, teamID)
+// evaluateView runs the view's filter for the user, or reuses the cached result
+// while no issue of the team has been written since it was evaluated.
+func (h *ViewHandler) evaluateView(ctx context.Context, view db.View, userID string) (viewcache.Entry, error) {
+	key := viewcache.Key{ViewID: view.ID, UserID: userID}
+	var version int64
+	if h.Cache != nil {
+		v, err := h.Repo.GetTeamIssueVersion(ctx, view.TeamID)
 		if err != nil {
-			log.Printf("Failed to query grouped issues: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to group issues"})
-		}
-		defer rows.Close()
-
-		issueSet := make(map[string]struct{})
-		found := false
-		for rows.Next() {
-			var issueID string
-			if err := rows.Scan(&issueID); err == nil {
-				log.Printf("Found issue ID: %s", issueID)
-				issueSet[issueID] = struct{}{}
-				found = true
-			} else {
-				log.Printf("Row scan error: %v", err)
-			}
-		}
-		if !found {
-			log.Println("No issues found by group_by query")
+			return viewcache.Entry{}, err
 		}
-		if err := rows.Err(); err != nil {
-			log.Printf("Row error: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to read grouped issues"})
+		if entry, ok := h.Cache.Get(key, v); ok {
+			return entry, nil
 		}
+		version = v
+	}
+
+	filter, err := viewfilter.Parse(view.Filter.String)
+	if err != nil {
+		return viewcache.Entry{}, fmt.Errorf("invalid filter: %w", err)
+	}
+	where, err := viewfilter.Compile(filter, viewfilter.Env{UserID: userID, Now: time.Now().UTC()})
+	if err != nil {
+		return viewcache.Entry{}, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	issues, err := h.Repo.ListViewIssues(ctx, view.ID, view.TeamID, where)
+	if err != nil {
+		return viewcache.Entry{}, err
+	}
+	assignees, err := h.Repo.ListViewIssueAssignees(ctx, view.TeamID, where)
+	if err != nil {
+		return viewcache.Entry{}, err
+	}
+
+	labels, err := h.Repo.ListViewIssueLabels(ctx, view.TeamID, where)
+	if err != nil {
+		return viewcache.Entry{}, err
+	}
 
-		for issueID := range issueSet {
-			log.Printf("Adding issue %s to view %s", issueID, viewID)
-			if err := h.Repo.AddIssueToViewTx(ctx, tx, db.AddIssueToViewParams{
-				ViewID:  viewID,
-				IssueID: issueID,
-			}); err != nil {
-				log.Printf("Failed to add issue %s to view: %v", issueID, err)
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error": fmt.Sprintf("Failed to add issue %s to view", issueID),
-				})
+	entry := viewcache.Entry{Issues: issues, Assignees: assignees, Labels: labels}
+	h.Cache.Set(key, version, entry)
+	return entry, nil
+}
+
+// ListViewIssues returns the issues currently matching the view, for the
+// current user, grouped by the view's group_bys. Every group has its count and
+// its first page of at most limit issues; the next page of a group is read by
+// passing its next_cursor as cursor, which returns that group alone.
+func (h *ViewHandler) ListViewIssues(c *fiber.Ctx) error {
+	viewID := c.Params("id")
+	if viewID == "" || viewID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing view_id"})
+	}
+
+	ctx := c.Context()
+	userID := c.Locals("userID").(string)
+
+	views, err := h.Repo.GetViewByID(ctx, viewID)
+	if err != nil {
+		log.Printf("Failed to get view by ID: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get view"})
+	}
+	if len(views) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "View not found"})
+	}
+	view := views[0]
+
+	if !authorize(c, h.Authz, authz.ViewView, authz.ForView(view), "you are not a member of the team") {
+		return nil
+	}
+
+	limit := c.QueryInt("limit", defaultIssuePageSize)
+	if limit < 1 || limit > maxIssuePageSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("limit must be between 1 and %d", maxIssuePageSize),
+		})
+	}
+
+	groupBys, err := h.Repo.ListViewGroupBys(ctx, viewID)
+	if err != nil {
+		log.Printf("Failed to get group_bys of view %s: %v", viewID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get view issues"})
+	}
+
+	levels := make([]viewgroup.Level, len(groupBys))
+	for i, g := range groupBys {
+		levels[i] = viewgroup.Level{Field: g.GroupBy, Order: g.GroupOrder, Desc: g.Descending, ShowEmpty: g.ShowEmpty}
+		// ลำดับปกติและกลุ่มว่างต้องรู้ค่าทั้งหมดของ field ก่อน
+		if g.ShowEmpty || g.GroupOrder == viewgroup.OrderNatural {
+			levels[i].Domain, err = h.Repo.ListGroupValues(ctx, view.TeamID, g.GroupBy)
+			if err != nil {
+				log.Printf("Failed to get values of %s for view %s: %v", g.GroupBy, viewID, err)
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get view issues"})
 			}
 		}
 	}
 
-	ws.BroadcastToRoom("view", viewID, "view_updated", req)
+	entry, err := h.evaluateView(ctx, view, userID)
+	if err != nil {
+		log.Printf("Failed to evaluate view %s: %v", viewID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to get view issues"})
+	}
 
-	log.Println("View updated successfully")
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "View updated successfully"})
+	res := fiber.Map{
+		"view":      toView(view),
+		"group_bys": toViewGroupOptions(groupBys),
+		"total":     len(entry.Issues),
+	}
+	switch cursor := c.Query("cursor"); {
+	case cursor != "":
+		group, err := viewgroup.Page(entry.Issues, viewgroup.Related{Assignees: entry.Assignees, Labels: entry.Labels}, levels, cursor, limit)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		res["group"] = group
+	case len(levels) == 0:
+		res["issues"], res["next_cursor"] = viewgroup.FirstPage(entry.Issues, limit)
+	default:
+		res["groups"] = viewgroup.Build(entry.Issues, viewgroup.Related{Assignees: entry.Assignees, Labels: entry.Labels}, levels, limit)
+	}
+	return c.Status(fiber.StatusOK).JSON(res)
 }
@@ -1,12 +1,15 @@
 package routes
 
 import (
+	"database/sql"
+	"errors"
 	"log"
 	"strings"
 
 	"github.com/go-playground/validator"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/nack098/nakumanager/internal/authz"
 	models "github.com/nack098/nakumanager/internal/models"
 	"github.com/nack098/nakumanager/internal/repositories"
 	"github.com/nack098/nakumanager/internal/ws"
@@ -16,13 +19,17 @@ import (
 type WorkspaceHandler struct {
 	Repo     repositories.WorkspaceRepository
 	UserRepo repositories.UserRepository
+	RoleRepo repositories.RoleRepository
+	Authz    *authz.Authorizer
 }
 
 // Concrete NewWorkspaceHandler
-func NewWorkspaceHandler(workspaceRepo repositories.WorkspaceRepository, userRepo repositories.UserRepository) *WorkspaceHandler {
+func NewWorkspaceHandler(workspaceRepo repositories.WorkspaceRepository, userRepo repositories.UserRepository, roleRepo repositories.RoleRepository) *WorkspaceHandler {
 	return &WorkspaceHandler{
 		Repo:     workspaceRepo,
 		UserRepo: userRepo,
+		RoleRepo: roleRepo,
+		Authz:    authz.NewAuthorizer(roleRepo),
 	}
 }
 
@@ -40,7 +47,6 @@ func (h *WorkspaceHandler) CreateWorkspace(c *fiber.Ctx) error {
 
 	workspace.ID = uuid.New().String()
 	workspace.Name = strings.TrimSpace(workspace.Name)
-	workspace.Members = []string{userID}
 
 	if err := validate.Struct(workspace); err != nil {
 		validationErrors := err.(validator.ValidationErrors)
@@ -58,7 +64,7 @@ func (h *WorkspaceHandler) CreateWorkspace(c *fiber.Ctx) error {
 	}
 
 	//Add creator to workspace members
-	err = h.Repo.AddMemberToWorkspace(c.Context(), workspace.ID, userID)
+	err = h.Repo.AddMemberToWorkspace(c.Context(), workspace.ID, userID, string(authz.RoleOwner))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to add creator to workspace"})
 	}
@@ -87,18 +93,15 @@ func (h *WorkspaceHandler) DeleteWorkspace(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "workspace id is required"})
 	}
 
-	//Check if user is authenticated
-	userID := c.Locals("userID").(string)
-
 	//Get workspace
-	workspace, err := h.Repo.GetWorkspaceByID(c.Context(), workspaceID)
+	_, err := h.Repo.GetWorkspaceByID(c.Context(), workspaceID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "workspace not found"})
 	}
 
-	//Check if user is owner
-	if workspace.OwnerID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "you are not authorized to delete this workspace"})
+	//Only the owner can delete the workspace
+	if !authorize(c, h.Authz, authz.WorkspaceDelete, authz.ForWorkspace(workspaceID), "you are not authorized to delete this workspace") {
+		return nil
 	}
 
 	//Delete workspace
@@ -116,21 +119,23 @@ func (h *WorkspaceHandler) UpdateWorkspace(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "workspace id is required"})
 	}
 
-	userID := c.Locals("userID").(string)
-
 	workspace, err := h.Repo.GetWorkspaceByID(c.Context(), workspaceID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "workspace not found"})
 	}
 
-	if workspace.OwnerID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "you are not authorized to update this workspace"})
+	if !authorize(c, h.Authz, authz.WorkspaceUpdate, authz.ForWorkspace(workspaceID), "you are not authorized to update this workspace") {
+		return nil
 	}
 
 	var req models.UpdateWorkspaceRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
 	}
+	// สมาชิกใหม่ต้องได้รับคำเชิญและตอบรับเอง เพิ่มตรงจากที่นี่ไม่ได้
+	if req.AddMembers != nil && len(*req.AddMembers) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "members join a workspace by accepting an invitation"})
+	}
 
 	log.Println("Received update workspace request:", req)
 
@@ -141,26 +146,80 @@ func (h *WorkspaceHandler) UpdateWorkspace(c *fiber.Ctx) error {
 		}
 	}
 
-	// Add members
-	if req.AddMembers != nil {
-		for _, memberID := range *req.AddMembers {
-			if err := h.Repo.AddMemberToWorkspace(c.Context(), workspaceID, memberID); err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to add member to workspace"})
-			}
-		}
-	}
-
 	// Remove members
 	if req.RemoveMembers != nil {
 		for _, memberID := range *req.RemoveMembers {
+			if memberID == workspace.OwnerID {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "the workspace owner cannot be removed"})
+			}
+			role, err := h.RoleRepo.GetWorkspaceRole(c.Context(), workspaceID, memberID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check member role"})
+			}
+			if authz.Role(role) == authz.RoleAdmin && !authorize(c, h.Authz, authz.WorkspaceManageAdmins, authz.ForWorkspace(workspaceID), "only the owner can remove an admin") {
+				return nil
+			}
 			if err := h.Repo.RemoveMemberFromWorkspace(c.Context(), workspaceID, memberID); err != nil {
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to remove member from workspace"})
 			}
 		}
 	}
-	
 
 	ws.BroadcastToRoom("workspace", workspaceID, "workspace_updated", req)
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "workspace updated successfully"})
 }
+
+// UpdateMemberRole changes the role of a workspace member. Admins can manage
+// members and guests, only the owner can promote or demote admins.
+func (h *WorkspaceHandler) UpdateMemberRole(c *fiber.Ctx) error {
+	workspaceID := strings.TrimSpace(c.Params("workspaceid"))
+	if workspaceID == "" || workspaceID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "workspace id is required"})
+	}
+
+	memberID := strings.TrimSpace(c.Params("userid"))
+	if memberID == "" || memberID == "undefined" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "user id is required"})
+	}
+
+	var req models.UpdateMemberRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if err := validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "role must be one of admin, member, guest"})
+	}
+
+	ctx := c.Context()
+
+	if !authorize(c, h.Authz, authz.WorkspaceManageMembers, authz.ForWorkspace(workspaceID), "you are not authorized to manage members of this workspace") {
+		return nil
+	}
+
+	current, err := h.RoleRepo.GetWorkspaceRole(ctx, workspaceID, memberID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "member not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to check member role"})
+	}
+
+	if authz.Role(current) == authz.RoleOwner {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "the owner role cannot be changed"})
+	}
+
+	if authz.Role(current) == authz.RoleAdmin || authz.Role(req.Role) == authz.RoleAdmin {
+		if !authorize(c, h.Authz, authz.WorkspaceManageAdmins, authz.ForWorkspace(workspaceID), "only the owner can manage admins") {
+			return nil
+		}
+	}
+
+	if err := h.RoleRepo.SetWorkspaceRole(ctx, workspaceID, memberID, req.Role); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to update member role"})
+	}
+
+	ws.BroadcastToRoom("workspace", workspaceID, "workspace_member_role_updated", fiber.Map{"user_id": memberID, "role": req.Role})
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "member role updated successfully"})
+}
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
 	"github.com/nack098/nakumanager/internal/db"
 	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
 	"github.com/stretchr/testify/assert"
@@ -31,17 +32,22 @@ func TestNewWorkspaceHandler(t *testing.T) {
 	mockWorkspaceRepo := new(mocks.MockWorkspaceRepo)
 	mockUserRepo := new(mocks.MockUserRepo)
 
-	handler := routes.NewWorkspaceHandler(mockWorkspaceRepo, mockUserRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+
+	handler := routes.NewWorkspaceHandler(mockWorkspaceRepo, mockUserRepo, mockRoleRepo)
 
 	assert.NotNil(t, handler)
 	assert.Equal(t, mockWorkspaceRepo, handler.Repo)
 	assert.Equal(t, mockUserRepo, handler.UserRepo)
+	assert.Equal(t, mockRoleRepo, handler.RoleRepo)
+	assert.NotNil(t, handler.Authz)
 }
 
 func TestCreateWorkspace(t *testing.T) {
 	t.Run("Create Workspace Successfully", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/workspaces", handler.CreateWorkspace)
@@ -50,7 +56,7 @@ func TestCreateWorkspace(t *testing.T) {
 		body, _ := json.Marshal(payload)
 
 		repo.On("CreateWorkspace", mock.Anything, mock.Anything, "Test Workspace", "user-123").Return(nil)
-		repo.On("AddMemberToWorkspace", mock.Anything, mock.Anything, "user-123").Return(nil)
+		repo.On("AddMemberToWorkspace", mock.Anything, mock.Anything, "user-123", "owner").Return(nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/workspaces", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
@@ -62,7 +68,8 @@ func TestCreateWorkspace(t *testing.T) {
 
 	t.Run("Invalid request body", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/workspaces", handler.CreateWorkspace)
@@ -81,7 +88,8 @@ func TestCreateWorkspace(t *testing.T) {
 
 	t.Run("Validation errors", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		tests := []struct {
 			name     string
 			payload  string
@@ -117,7 +125,8 @@ func TestCreateWorkspace(t *testing.T) {
 
 	t.Run("Create Workspace Fail", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
@@ -141,7 +150,8 @@ func TestCreateWorkspace(t *testing.T) {
 
 	t.Run("Fail to add cretor to workspace", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Post("/workspaces", handler.CreateWorkspace)
@@ -150,7 +160,7 @@ func TestCreateWorkspace(t *testing.T) {
 		body, _ := json.Marshal(payload)
 
 		repo.On("CreateWorkspace", mock.Anything, mock.Anything, "Test Workspace", "user-123").Return(nil)
-		repo.On("AddMemberToWorkspace", mock.Anything, mock.Anything, "user-123").Return(errors.New("fail to add creator to workspace"))
+		repo.On("AddMemberToWorkspace", mock.Anything, mock.Anything, "user-123", "owner").Return(errors.New("fail to add creator to workspace"))
 
 		req := httptest.NewRequest(http.MethodPost, "/workspaces", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
@@ -164,7 +174,8 @@ func TestCreateWorkspace(t *testing.T) {
 func TestGetWorkspaceByUserID(t *testing.T) {
 	t.Run("Get workspace by user id successfully", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Get("/workspaces", handler.GetWorkspacesByUserID)
@@ -189,7 +200,8 @@ func TestGetWorkspaceByUserID(t *testing.T) {
 
 	t.Run("Fail to get workspace by user id", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Get("/workspaces", handler.GetWorkspacesByUserID)
@@ -208,12 +220,14 @@ func TestGetWorkspaceByUserID(t *testing.T) {
 func TestDeleteWorkspace(t *testing.T) {
 	t.Run("Delete workspace successfully", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/workspaces/:workspaceid", handler.DeleteWorkspace)
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 		repo.On("DeleteWorkspace", mock.Anything, "ws-123").Return(nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/workspaces/ws-123", nil)
@@ -224,7 +238,8 @@ func TestDeleteWorkspace(t *testing.T) {
 
 	t.Run("WorkSpace ID is not provided", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/workspaces/:workspaceid", handler.DeleteWorkspace)
@@ -237,7 +252,8 @@ func TestDeleteWorkspace(t *testing.T) {
 
 	t.Run("Not found WorkSpace", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/workspaces/:workspaceid", handler.DeleteWorkspace)
@@ -252,12 +268,14 @@ func TestDeleteWorkspace(t *testing.T) {
 
 	t.Run("User Request is not Owner of WorkSpace", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/workspaces/:workspaceid", handler.DeleteWorkspace)
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").Return(db.Workspace{ID: "ws-123", OwnerID: "user-456"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("member", nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/workspaces/ws-123", nil)
 		resp, err := app.Test(req, -1)
@@ -267,12 +285,14 @@ func TestDeleteWorkspace(t *testing.T) {
 
 	t.Run("Delete WorkSpace failed", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Delete("/workspaces/:workspaceid", handler.DeleteWorkspace)
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 		repo.On("DeleteWorkspace", mock.Anything, "ws-123").Return(errors.New("failed to delete workspace"))
 
 		req := httptest.NewRequest(http.MethodDelete, "/workspaces/ws-123", nil)
@@ -285,7 +305,8 @@ func TestDeleteWorkspace(t *testing.T) {
 func TestUpdateWorkSpace(t *testing.T) {
 	t.Run("Update name workspace successfully", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 
@@ -297,6 +318,7 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
 		repo.On("RenameWorkspace", mock.Anything, "ws-123", "New Workspace Name").
 			Return(nil)
@@ -314,7 +336,8 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 	t.Run("Update name workspace failed", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 
@@ -325,6 +348,7 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
 		repo.On("RenameWorkspace", mock.Anything, "ws-123", "New Workspace Name").
 			Return(errors.New("failed to rename workspace"))
@@ -341,9 +365,10 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 	})
 
-	t.Run("Add Member to Workspace successfully", func(t *testing.T) {
+	t.Run("Adding members directly is rejected", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 
@@ -354,53 +379,24 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
-
-		repo.On("AddMemberToWorkspace", mock.Anything, "ws-123", "user-456").Return(nil)
-		repo.On("AddMemberToWorkspace", mock.Anything, "ws-123", "user-789").Return(nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
 		req := httptest.NewRequest(http.MethodPut, "/workspaces/ws-123", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
 
 		resp, err := app.Test(req, -1)
 		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 
 		respBody, _ := io.ReadAll(resp.Body)
-		assert.Contains(t, string(respBody), `"message":"workspace updated successfully"`)
-	})
-
-	t.Run("Failed to Add Member to Workspace successfully", func(t *testing.T) {
-		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
-		app := fiber.New()
-		app.Use(withUserID("user-123"))
-
-		app.Put("/workspaces/:workspaceid", handler.UpdateWorkspace)
-		body := []byte(`{
-		"add_members": ["user-456", "user-789"]
-		}`)
-
-		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").
-			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
-
-		repo.On("AddMemberToWorkspace", mock.Anything, "ws-123", "user-456").Return(errors.New("failed to add member to workspace"))
-		repo.On("AddMemberToWorkspace", mock.Anything, "ws-123", "user-789").Return(errors.New("failed to add member to workspace"))
-
-		req := httptest.NewRequest(http.MethodPut, "/workspaces/ws-123", bytes.NewReader(body))
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := app.Test(req, -1)
-		assert.NoError(t, err)
-		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
-
-		respBody, _ := io.ReadAll(resp.Body)
-		assert.Contains(t, string(respBody), `"error":"failed to add member to workspace"`)
-
+		assert.Contains(t, string(respBody), `"error":"members join a workspace by accepting an invitation"`)
+		repo.AssertNumberOfCalls(t, "AddMemberToWorkspace", 0)
 	})
 
 	t.Run("Remove Member from Workspace successfully", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 
@@ -411,7 +407,10 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-456").Return("member", nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-789").Return("member", nil)
 		repo.On("RemoveMemberFromWorkspace", mock.Anything, "ws-123", "user-456").Return(nil)
 		repo.On("RemoveMemberFromWorkspace", mock.Anything, "ws-123", "user-789").Return(nil)
 
@@ -428,7 +427,8 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 	t.Run("Failed to Remove Member from Workspace successfully", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 
@@ -439,7 +439,10 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-456").Return("member", nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-789").Return("member", nil)
 		repo.On("RemoveMemberFromWorkspace", mock.Anything, "ws-123", "user-456").Return(errors.New("failed to remove member from workspace"))
 		repo.On("RemoveMemberFromWorkspace", mock.Anything, "ws-123", "user-789").Return(errors.New("failed to remove member from workspace"))
 
@@ -456,7 +459,8 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 	t.Run("WorkSpace ID not provided", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 
@@ -473,7 +477,8 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 	t.Run("Not Found WorkSpace", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 
@@ -491,7 +496,8 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 	t.Run("Request User Is Not Workspace Owner", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 
@@ -501,6 +507,7 @@ func TestUpdateWorkSpace(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").Return(db.Workspace{ID: "ws-123", OwnerID: "user-456"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("member", nil)
 
 		resp, err := app.Test(req, -1)
 		assert.NoError(t, err)
@@ -509,7 +516,8 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 	t.Run("Invalid JSON body", func(t *testing.T) {
 		repo := new(mocks.MockWorkspaceRepo)
-		handler := routes.WorkspaceHandler{Repo: repo}
+		roleRepo := new(mocks.MockRoleRepo)
+		handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
 		app := fiber.New()
 		app.Use(withUserID("user-123"))
 		app.Put("/workspaces/:workspaceid", handler.UpdateWorkspace)
@@ -520,6 +528,7 @@ func TestUpdateWorkSpace(t *testing.T) {
 
 		repo.On("GetWorkspaceByID", mock.Anything, "ws-123").
 			Return(db.Workspace{ID: "ws-123", OwnerID: "user-123"}, nil)
+		roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return("owner", nil)
 
 		req := httptest.NewRequest(http.MethodPut, "/workspaces/ws-123", bytes.NewReader(body))
 		req.Header.Set("Content-Type", "application/json")
@@ -533,3 +542,189 @@ func TestUpdateWorkSpace(t *testing.T) {
 	})
 
 }
+
+func TestUpdateWorkspace_RemoveMemberRoles(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		userRole   string
+		removeID   string
+		setupMocks func(repo *mocks.MockWorkspaceRepo, roleRepo *mocks.MockRoleRepo)
+		wantStatus int
+	}{
+		{
+			name:       "owner cannot be removed",
+			userID:     "user-admin",
+			userRole:   "admin",
+			removeID:   "user-owner",
+			setupMocks: func(repo *mocks.MockWorkspaceRepo, roleRepo *mocks.MockRoleRepo) {},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:     "admin cannot remove another admin",
+			userID:   "user-admin",
+			userRole: "admin",
+			removeID: "user-admin-2",
+			setupMocks: func(repo *mocks.MockWorkspaceRepo, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-admin-2").Return("admin", nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:     "owner can remove an admin",
+			userID:   "user-owner",
+			userRole: "owner",
+			removeID: "user-admin-2",
+			setupMocks: func(repo *mocks.MockWorkspaceRepo, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-admin-2").Return("admin", nil)
+				repo.On("RemoveMemberFromWorkspace", mock.Anything, "ws-123", "user-admin-2").Return(nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:     "admin can remove a guest",
+			userID:   "user-admin",
+			userRole: "admin",
+			removeID: "user-guest",
+			setupMocks: func(repo *mocks.MockWorkspaceRepo, roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-guest").Return("guest", nil)
+				repo.On("RemoveMemberFromWorkspace", mock.Anything, "ws-123", "user-guest").Return(nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockWorkspaceRepo)
+			roleRepo := new(mocks.MockRoleRepo)
+			handler := routes.WorkspaceHandler{Repo: repo, RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
+			app := fiber.New()
+			app.Use(withUserID(tt.userID))
+			app.Patch("/workspace/:workspaceid", handler.UpdateWorkspace)
+
+			repo.On("GetWorkspaceByID", mock.Anything, "ws-123").Return(db.Workspace{ID: "ws-123", OwnerID: "user-owner"}, nil)
+			roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", tt.userID).Return(tt.userRole, nil)
+			tt.setupMocks(repo, roleRepo)
+
+			body := []byte(`{"remove_members": ["` + tt.removeID + `"]}`)
+			req := httptest.NewRequest(http.MethodPatch, "/workspace/ws-123", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateMemberRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		userRole   string
+		body       string
+		setupMocks func(roleRepo *mocks.MockRoleRepo)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:     "admin changes member to guest",
+			userRole: "admin",
+			body:     `{"role": "guest"}`,
+			setupMocks: func(roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-456").Return("member", nil)
+				roleRepo.On("SetWorkspaceRole", mock.Anything, "ws-123", "user-456", "guest").Return(nil)
+			},
+			wantStatus: fiber.StatusOK,
+			wantBody:   "member role updated successfully",
+		},
+		{
+			name:     "owner promotes member to admin",
+			userRole: "owner",
+			body:     `{"role": "admin"}`,
+			setupMocks: func(roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-456").Return("member", nil)
+				roleRepo.On("SetWorkspaceRole", mock.Anything, "ws-123", "user-456", "admin").Return(nil)
+			},
+			wantStatus: fiber.StatusOK,
+		},
+		{
+			name:     "admin cannot promote to admin",
+			userRole: "admin",
+			body:     `{"role": "admin"}`,
+			setupMocks: func(roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-456").Return("member", nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+			wantBody:   "only the owner can manage admins",
+		},
+		{
+			name:     "owner role cannot be changed",
+			userRole: "owner",
+			body:     `{"role": "member"}`,
+			setupMocks: func(roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-456").Return("owner", nil)
+			},
+			wantStatus: fiber.StatusForbidden,
+			wantBody:   "the owner role cannot be changed",
+		},
+		{
+			name:       "owner is not assignable",
+			userRole:   "owner",
+			body:       `{"role": "owner"}`,
+			setupMocks: func(roleRepo *mocks.MockRoleRepo) {},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:       "member cannot manage roles",
+			userRole:   "member",
+			body:       `{"role": "guest"}`,
+			setupMocks: func(roleRepo *mocks.MockRoleRepo) {},
+			wantStatus: fiber.StatusForbidden,
+		},
+		{
+			name:     "target is not a member",
+			userRole: "admin",
+			body:     `{"role": "guest"}`,
+			setupMocks: func(roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-456").Return("", sql.ErrNoRows)
+			},
+			wantStatus: fiber.StatusNotFound,
+		},
+		{
+			name:     "update fails",
+			userRole: "admin",
+			body:     `{"role": "guest"}`,
+			setupMocks: func(roleRepo *mocks.MockRoleRepo) {
+				roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-456").Return("member", nil)
+				roleRepo.On("SetWorkspaceRole", mock.Anything, "ws-123", "user-456", "guest").Return(errors.New("db error"))
+			},
+			wantStatus: fiber.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roleRepo := new(mocks.MockRoleRepo)
+			handler := routes.WorkspaceHandler{RoleRepo: roleRepo, Authz: authz.NewAuthorizer(roleRepo)}
+			app := fiber.New()
+			app.Use(withUserID("user-123"))
+			app.Patch("/workspace/:workspaceid/members/:userid", handler.UpdateMemberRole)
+
+			roleRepo.On("GetWorkspaceRole", mock.Anything, "ws-123", "user-123").Return(tt.userRole, nil).Maybe()
+			tt.setupMocks(roleRepo)
+
+			req := httptest.NewRequest(http.MethodPatch, "/workspace/ws-123/members/user-456", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			res, _ := io.ReadAll(resp.Body)
+			assert.Contains(t, string(res), tt.wantBody)
+			roleRepo.AssertExpectations(t)
+		})
+	}
+}
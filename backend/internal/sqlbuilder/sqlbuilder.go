@@ -0,0 +1,203 @@
+// Package sqlbuilder composes SELECT statements from small expressions.
+//
+// Values are always bound through ? placeholders. Column names and table names
+// are written into the SQL as is, so they must come from constants in the
+// caller, never from user input.
+package sqlbuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expr is a SQL fragment together with the arguments for its placeholders.
+type Expr struct {
+	SQL  string
+	Args []interface{}
+}
+
+func (e Expr) IsZero() bool {
+	return e.SQL == ""
+}
+
+func Raw(sql string, args ...interface{}) Expr {
+	return Expr{SQL: sql, Args: args}
+}
+
+type Op string
+
+const (
+	OpEq  Op = "="
+	OpNe  Op = "!="
+	OpLt  Op = "<"
+	OpLte Op = "<="
+	OpGt  Op = ">"
+	OpGte Op = ">="
+)
+
+func Compare(col string, op Op, value interface{}) Expr {
+	switch op {
+	case OpEq, OpNe, OpLt, OpLte, OpGt, OpGte:
+	default:
+		panic(fmt.Sprintf("sqlbuilder: unsupported operator %q", op))
+	}
+	return Expr{SQL: col + " " + string(op) + " ?", Args: []interface{}{value}}
+}
+
+func Eq(col string, value interface{}) Expr {
+	return Compare(col, OpEq, value)
+}
+
+// In matches col against any of the values. An empty list matches nothing.
+func In[T any](col string, values []T) Expr {
+	if len(values) == 0 {
+		return Raw("0")
+	}
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return Expr{SQL: col + " IN (" + placeholders(len(values)) + ")", Args: args}
+}
+
+func IsNull(col string) Expr {
+	return Raw(col + " IS NULL")
+}
+
+func NotNull(col string) Expr {
+	return Raw(col + " IS NOT NULL")
+}
+
+// Contains matches col against a substring, treating % and _ in it literally.
+func Contains(col, substr string) Expr {
+	return Expr{SQL: col + ` LIKE ? ESCAPE '\'`, Args: []interface{}{"%" + EscapeLike(substr) + "%"}}
+}
+
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// And joins the non-empty expressions. It matches everything when there are none.
+func And(exprs ...Expr) Expr {
+	return join(" AND ", "1", exprs)
+}
+
+// Or joins the non-empty expressions. It matches nothing when there are none.
+func Or(exprs ...Expr) Expr {
+	return join(" OR ", "0", exprs)
+}
+
+func Not(e Expr) Expr {
+	return Expr{SQL: "NOT (" + e.SQL + ")", Args: e.Args}
+}
+
+func join(sep, empty string, exprs []Expr) Expr {
+	nonEmpty := []Expr{}
+	for _, e := range exprs {
+		if !e.IsZero() {
+			nonEmpty = append(nonEmpty, e)
+		}
+	}
+	switch len(nonEmpty) {
+	case 0:
+		return Raw(empty)
+	case 1:
+		return nonEmpty[0]
+	}
+
+	parts := make([]string, len(nonEmpty))
+	args := []interface{}{}
+	for i, e := range nonEmpty {
+		parts[i] = "(" + e.SQL + ")"
+		args = append(args, e.Args...)
+	}
+	return Expr{SQL: strings.Join(parts, sep), Args: args}
+}
+
+// Time normalises a DATETIME column to UTC text so that it compares correctly
+// with TimeArg no matter which offset it was stored with.
+func Time(col string) string {
+	return "strftime('%Y-%m-%dT%H:%M:%f', " + col + ")"
+}
+
+func TimeArg(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000")
+}
+
+type Order struct {
+	Expr string
+	Desc bool
+}
+
+func (o Order) String() string {
+	if o.Desc {
+		return o.Expr + " DESC"
+	}
+	return o.Expr + " ASC"
+}
+
+// Select is a SELECT statement. Joins are written after From, e.g.
+// Raw("LEFT JOIN ranks r ON r.id = i.id AND r.view_id = ?", viewID). A zero
+// Limit means no limit.
+type Select struct {
+	Columns []string
+	From    string
+	Joins   []Expr
+	Where   []Expr
+	GroupBy []string
+	OrderBy []Order
+	Limit   int
+	Offset  int
+}
+
+func (s Select) Build() (string, []interface{}) {
+	var b strings.Builder
+	args := []interface{}{}
+
+	b.WriteString("SELECT ")
+	b.WriteString(strings.Join(s.Columns, ", "))
+	b.WriteString(" FROM ")
+	b.WriteString(s.From)
+
+	for _, j := range s.Joins {
+		b.WriteString(" ")
+		b.WriteString(j.SQL)
+		args = append(args, j.Args...)
+	}
+
+	if len(s.Where) > 0 {
+		where := And(s.Where...)
+		b.WriteString(" WHERE ")
+		b.WriteString(where.SQL)
+		args = append(args, where.Args...)
+	}
+
+	if len(s.GroupBy) > 0 {
+		b.WriteString(" GROUP BY ")
+		b.WriteString(strings.Join(s.GroupBy, ", "))
+	}
+
+	if len(s.OrderBy) > 0 {
+		orders := make([]string, len(s.OrderBy))
+		for i, o := range s.OrderBy {
+			orders[i] = o.String()
+		}
+		b.WriteString(" ORDER BY ")
+		b.WriteString(strings.Join(orders, ", "))
+	}
+
+	if s.Limit > 0 {
+		b.WriteString(" LIMIT " + strconv.Itoa(s.Limit))
+		if s.Offset > 0 {
+			b.WriteString(" OFFSET " + strconv.Itoa(s.Offset))
+		}
+	}
+
+	return b.String(), args
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
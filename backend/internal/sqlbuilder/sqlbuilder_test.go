@@ -0,0 +1,73 @@
+package sqlbuilder_test
+
+import (
+	"testing"
+
+	"github.com/nack098/nakumanager/internal/sqlbuilder"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuild(t *testing.T) {
+	q, args := sqlbuilder.Select{
+		Columns: []string{"id", "title"},
+		From:    "issues",
+		Where: []sqlbuilder.Expr{
+			sqlbuilder.Eq("team_id", "t1"),
+			sqlbuilder.Or(
+				sqlbuilder.In("status", []string{"todo", "doing"}),
+				sqlbuilder.IsNull("priority"),
+			),
+			{},
+		},
+		OrderBy: []sqlbuilder.Order{{Expr: "title"}, {Expr: "id", Desc: true}},
+		Limit:   10,
+	}.Build()
+
+	assert.Equal(t, "SELECT id, title FROM issues WHERE (team_id = ?) AND ((status IN (?, ?)) OR (priority IS NULL)) ORDER BY title ASC, id DESC LIMIT 10", q)
+	assert.Equal(t, []interface{}{"t1", "todo", "doing"}, args)
+}
+
+func TestSelectBuildJoins(t *testing.T) {
+	q, args := sqlbuilder.Select{
+		Columns: []string{"i.id"},
+		From:    "issues i",
+		Joins:   []sqlbuilder.Expr{sqlbuilder.Raw("LEFT JOIN view_issue_ranks vr ON vr.issue_id = i.id AND vr.view_id = ?", "v1")},
+		Where:   []sqlbuilder.Expr{sqlbuilder.Eq("i.team_id", "t1")},
+	}.Build()
+
+	assert.Equal(t, "SELECT i.id FROM issues i LEFT JOIN view_issue_ranks vr ON vr.issue_id = i.id AND vr.view_id = ? WHERE i.team_id = ?", q)
+	assert.Equal(t, []interface{}{"v1", "t1"}, args)
+}
+
+func TestExpressions(t *testing.T) {
+	tests := []struct {
+		name string
+		expr sqlbuilder.Expr
+		sql  string
+		args []interface{}
+	}{
+		{"empty and matches everything", sqlbuilder.And(), "1", nil},
+		{"empty or matches nothing", sqlbuilder.Or(), "0", nil},
+		{"empty in matches nothing", sqlbuilder.In("id", []string{}), "0", nil},
+		{"single expression is not wrapped", sqlbuilder.And(sqlbuilder.Eq("id", 1)), "id = ?", []interface{}{1}},
+		{"not", sqlbuilder.Not(sqlbuilder.Eq("id", 1)), "NOT (id = ?)", []interface{}{1}},
+		{"contains escapes wildcards", sqlbuilder.Contains("title", `50%_off\`), `title LIKE ? ESCAPE '\'`, []interface{}{`%50\%\_off\\%`}},
+		{"compare", sqlbuilder.Compare("end_date", sqlbuilder.OpLt, "2024"), "end_date < ?", []interface{}{"2024"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.sql, tt.expr.SQL)
+			assert.Equal(t, len(tt.args), len(tt.expr.Args))
+			if len(tt.args) > 0 {
+				assert.Equal(t, tt.args, tt.expr.Args)
+			}
+		})
+	}
+}
+
+func TestCompareRejectsUnknownOperator(t *testing.T) {
+	assert.Panics(t, func() {
+		sqlbuilder.Compare("id", sqlbuilder.Op("; DROP TABLE issues"), 1)
+	})
+}
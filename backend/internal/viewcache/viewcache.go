@@ -0,0 +1,109 @@
+// Package viewcache keeps the evaluated issues of views in memory so that
+// opening a view again does not run its filter again.
+//
+// An entry is only used while the team's issue version is unchanged, so any
+// write to an issue of the team invalidates it. Entries also expire after a
+// TTL, because filters such as "due today" or "assigned to me" depend on the
+// time and on who is asking.
+package viewcache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+const maxEntries = 1000
+
+type Key struct {
+	ViewID string
+	UserID string
+}
+
+// Entry is the result of evaluating a view: its issues in order and their
+// assignees and labels keyed by issue ID.
+type Entry struct {
+	Issues    []db.Issue
+	Assignees map[string][]string
+	Labels    map[string][]string
+}
+
+type cached struct {
+	version int64
+	expires time.Time
+	entry   Entry
+}
+
+// Cache is safe for concurrent use. A nil Cache stores nothing, so caching can
+// be turned off by leaving it unset.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[Key]cached
+}
+
+func New(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, now: time.Now, entries: map[Key]cached{}}
+}
+
+// Get returns the entry stored for key if it was evaluated at the given team
+// issue version and has not expired.
+func (c *Cache) Get(key Key, version int64) (Entry, bool) {
+	if c == nil {
+		return Entry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return Entry{}, false
+	}
+	if e.version != version || !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return Entry{}, false
+	}
+	return e.entry, true
+}
+
+func (c *Cache) Set(key Key, version int64, entry Entry) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= maxEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	// ยังเต็มอยู่ก็ทิ้งรายการใดก็ได้หนึ่งรายการ
+	if len(c.entries) >= maxEntries {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[key] = cached{version: version, expires: now.Add(c.ttl), entry: entry}
+}
+
+// Invalidate drops the entries of a view whose filter or team has changed.
+func (c *Cache) Invalidate(viewID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k := range c.entries {
+		if k.ViewID == viewID {
+			delete(c.entries, k)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package viewcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2024, 6, 12, 10, 0, 0, 0, time.UTC)
+	c := New(time.Minute)
+	c.now = func() time.Time { return now }
+
+	key := Key{ViewID: "v1", UserID: "u1"}
+	entry := Entry{Issues: []db.Issue{{ID: "i1"}}}
+	c.Set(key, 3, entry)
+
+	got, ok := c.Get(key, 3)
+	assert.True(t, ok)
+	assert.Equal(t, entry, got)
+
+	_, ok = c.Get(Key{ViewID: "v1", UserID: "u2"}, 3)
+	assert.False(t, ok, "entries are per user")
+
+	_, ok = c.Get(key, 4)
+	assert.False(t, ok, "a newer team version invalidates the entry")
+
+	c.Set(key, 4, entry)
+	now = now.Add(time.Minute)
+	_, ok = c.Get(key, 4)
+	assert.False(t, ok, "entries expire after the TTL")
+
+	c.Set(key, 4, entry)
+	c.Set(Key{ViewID: "v2", UserID: "u1"}, 4, entry)
+	c.Invalidate("v1")
+	_, ok = c.Get(key, 4)
+	assert.False(t, ok)
+	_, ok = c.Get(Key{ViewID: "v2", UserID: "u1"}, 4)
+	assert.True(t, ok, "other views are kept")
+}
+
+func TestCacheIsBounded(t *testing.T) {
+	c := New(time.Minute)
+	for i := 0; i < maxEntries+10; i++ {
+		c.Set(Key{ViewID: "v", UserID: string(rune('a' + i))}, 1, Entry{})
+	}
+	assert.LessOrEqual(t, len(c.entries), maxEntries)
+}
+
+func TestNilCache(t *testing.T) {
+	var c *Cache
+	c.Set(Key{ViewID: "v1"}, 1, Entry{})
+	_, ok := c.Get(Key{ViewID: "v1"}, 1)
+	assert.False(t, ok)
+	c.Invalidate("v1")
+}
@@ -0,0 +1,339 @@
+// Package viewfilter turns the filter of a view into a SQL condition on the
+// issues table, aliased as i.
+package viewfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/sqlbuilder"
+)
+
+const (
+	maxDepth      = 4
+	maxConditions = 50
+	maxValues     = 100
+)
+
+// Env is what a filter is evaluated against. "me" stands for UserID, and
+// relative dates such as "today" are computed from Now in its location.
+type Env struct {
+	UserID string
+	Now    time.Time
+}
+
+type kind int
+
+const (
+	kindValue kind = iota
+	kindUser
+	kindAssignee
+	kindLabel
+	kindText
+	kindDate
+)
+
+type field struct {
+	col  string
+	kind kind
+	// from is the table linking an issue to the values in col, for fields an
+	// issue can have several values of.
+	from string
+}
+
+var fields = map[string]field{
+	"status":     {col: "i.status", kind: kindValue},
+	"priority":   {col: "i.priority", kind: kindValue},
+	"label":      {col: "il.label_id", kind: kindLabel, from: "issue_labels il"},
+	"project_id": {col: "i.project_id", kind: kindValue},
+	"owner_id":   {col: "i.owner_id", kind: kindUser},
+	"assignee":   {col: "ia.user_id", kind: kindAssignee, from: "issue_assignees ia"},
+	"title":      {col: "i.title", kind: kindText},
+	"content":    {col: "i.content", kind: kindText},
+	"start_date": {col: "i.start_date", kind: kindDate},
+	"end_date":   {col: "i.end_date", kind: kindDate},
+}
+
+var operators = map[kind]map[string]bool{
+	kindValue:    {"eq": true, "neq": true, "in": true, "not_in": true, "is_empty": true, "is_not_empty": true},
+	kindUser:     {"eq": true, "neq": true, "in": true, "not_in": true, "is_empty": true, "is_not_empty": true},
+	kindAssignee: {"eq": true, "neq": true, "in": true, "not_in": true, "is_empty": true, "is_not_empty": true},
+	kindLabel:    {"eq": true, "neq": true, "in": true, "not_in": true, "is_empty": true, "is_not_empty": true},
+	kindText:     {"contains": true, "not_contains": true, "is_empty": true, "is_not_empty": true},
+	kindDate:     {"eq": true, "before": true, "after": true, "on_or_before": true, "on_or_after": true, "is_empty": true, "is_not_empty": true},
+}
+
+// Parse reads a filter stored as JSON. An empty string is no filter.
+func Parse(raw string) (*models.ViewFilter, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var f models.ViewFilter
+	if err := json.Unmarshal([]byte(raw), &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Validate reports the first problem in the filter, if any.
+func Validate(f *models.ViewFilter) error {
+	_, err := Compile(f, Env{UserID: "me", Now: time.Now()})
+	return err
+}
+
+// Compile returns the condition matching the issues selected by the filter.
+// A nil or empty filter gives a zero Expr, which matches every issue.
+func Compile(f *models.ViewFilter, env Env) (sqlbuilder.Expr, error) {
+	if f == nil {
+		return sqlbuilder.Expr{}, nil
+	}
+	c := compiler{env: env}
+	return c.group(*f, "filter", 0)
+}
+
+type compiler struct {
+	env        Env
+	conditions int
+}
+
+func (c *compiler) group(f models.ViewFilter, path string, depth int) (sqlbuilder.Expr, error) {
+	if depth >= maxDepth {
+		return sqlbuilder.Expr{}, fmt.Errorf("%s: groups can be nested at most %d levels deep", path, maxDepth)
+	}
+
+	parts := []sqlbuilder.Expr{}
+	for i, cond := range f.Conditions {
+		c.conditions++
+		if c.conditions > maxConditions {
+			return sqlbuilder.Expr{}, fmt.Errorf("filter: at most %d conditions are allowed", maxConditions)
+		}
+		e, err := c.condition(cond)
+		if err != nil {
+			return sqlbuilder.Expr{}, fmt.Errorf("%s.conditions[%d]: %w", path, i, err)
+		}
+		parts = append(parts, e)
+	}
+	for i, g := range f.Groups {
+		e, err := c.group(g, fmt.Sprintf("%s.groups[%d]", path, i), depth+1)
+		if err != nil {
+			return sqlbuilder.Expr{}, err
+		}
+		parts = append(parts, e)
+	}
+
+	// กลุ่มว่างไม่กรองอะไร จึงเป็นจริงเสมอทั้งแบบ and และ or
+	anyEmpty := len(parts) == 0
+	allEmpty := true
+	for _, p := range parts {
+		if p.IsZero() {
+			anyEmpty = true
+		} else {
+			allEmpty = false
+		}
+	}
+
+	switch f.Op {
+	case "", "and":
+		if allEmpty {
+			return sqlbuilder.Expr{}, nil
+		}
+		return sqlbuilder.And(parts...), nil
+	case "or":
+		if anyEmpty {
+			return sqlbuilder.Expr{}, nil
+		}
+		return sqlbuilder.Or(parts...), nil
+	}
+	return sqlbuilder.Expr{}, fmt.Errorf("%s: op must be \"and\" or \"or\", got %q", path, f.Op)
+}
+
+func (c *compiler) condition(cond models.ViewCondition) (sqlbuilder.Expr, error) {
+	fd, ok := fields[cond.Field]
+	if !ok {
+		return sqlbuilder.Expr{}, fmt.Errorf("unknown field %q", cond.Field)
+	}
+	if !operators[fd.kind][cond.Operator] {
+		return sqlbuilder.Expr{}, fmt.Errorf("operator %q is not supported for field %q", cond.Operator, cond.Field)
+	}
+
+	switch cond.Operator {
+	case "is_empty", "is_not_empty":
+	case "in", "not_in":
+		if len(cond.Values) == 0 || len(cond.Values) > maxValues {
+			return sqlbuilder.Expr{}, fmt.Errorf("operator %q needs between 1 and %d values", cond.Operator, maxValues)
+		}
+	default:
+		if cond.Value == "" {
+			return sqlbuilder.Expr{}, fmt.Errorf("operator %q needs a value", cond.Operator)
+		}
+	}
+
+	switch fd.kind {
+	case kindValue:
+		return valueCondition(fd.col, cond.Operator, cond.Value, cond.Values), nil
+	case kindUser:
+		return valueCondition(fd.col, cond.Operator, c.user(cond.Value), c.users(cond.Values)), nil
+	case kindAssignee:
+		return relatedCondition(fd, cond.Operator, c.user(cond.Value), c.users(cond.Values)), nil
+	case kindLabel:
+		return relatedCondition(fd, cond.Operator, cond.Value, cond.Values), nil
+	case kindText:
+		return textCondition(fd.col, cond.Operator, cond.Value), nil
+	}
+	return c.dateCondition(fd.col, cond.Operator, cond.Value)
+}
+
+func (c *compiler) user(id string) string {
+	if id == "me" {
+		return c.env.UserID
+	}
+	return id
+}
+
+func (c *compiler) users(ids []string) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = c.user(id)
+	}
+	return out
+}
+
+func isEmpty(col string) sqlbuilder.Expr {
+	return sqlbuilder.Or(sqlbuilder.IsNull(col), sqlbuilder.Eq(col, ""))
+}
+
+// valueCondition treats a missing value as different from every value, so
+// "priority neq high" also matches issues without a priority.
+func valueCondition(col, op, value string, values []string) sqlbuilder.Expr {
+	switch op {
+	case "eq":
+		return sqlbuilder.Eq(col, value)
+	case "neq":
+		return sqlbuilder.Or(sqlbuilder.IsNull(col), sqlbuilder.Compare(col, sqlbuilder.OpNe, value))
+	case "in":
+		return sqlbuilder.In(col, values)
+	case "not_in":
+		return sqlbuilder.Or(sqlbuilder.IsNull(col), sqlbuilder.Not(sqlbuilder.In(col, values)))
+	case "is_empty":
+		return isEmpty(col)
+	}
+	return sqlbuilder.Not(isEmpty(col))
+}
+
+// relatedCondition matches on the values an issue has in the linking table of
+// fd, so "assignee eq u1" matches issues u1 is one of the assignees of.
+func relatedCondition(fd field, op, value string, values []string) sqlbuilder.Expr {
+	alias := strings.SplitN(fd.col, ".", 2)[0]
+	exists := "EXISTS (SELECT 1 FROM " + fd.from + " WHERE " + alias + ".issue_id = i.id"
+	switch op {
+	case "eq":
+		return sqlbuilder.Raw(exists+" AND "+fd.col+" = ?)", value)
+	case "neq":
+		return sqlbuilder.Raw("NOT "+exists+" AND "+fd.col+" = ?)", value)
+	case "in", "not_in":
+		in := sqlbuilder.In(fd.col, values)
+		e := sqlbuilder.Raw(exists+" AND "+in.SQL+")", in.Args...)
+		if op == "not_in" {
+			return sqlbuilder.Not(e)
+		}
+		return e
+	case "is_empty":
+		return sqlbuilder.Raw("NOT " + exists + ")")
+	}
+	return sqlbuilder.Raw(exists + ")")
+}
+
+func textCondition(col, op, value string) sqlbuilder.Expr {
+	switch op {
+	case "contains":
+		return sqlbuilder.Contains(col, value)
+	case "not_contains":
+		return sqlbuilder.Or(sqlbuilder.IsNull(col), sqlbuilder.Not(sqlbuilder.Contains(col, value)))
+	case "is_empty":
+		return isEmpty(col)
+	}
+	return sqlbuilder.Not(isEmpty(col))
+}
+
+func (c *compiler) dateCondition(col, op, value string) (sqlbuilder.Expr, error) {
+	switch op {
+	case "is_empty":
+		return sqlbuilder.IsNull(col), nil
+	case "is_not_empty":
+		return sqlbuilder.NotNull(col), nil
+	}
+
+	start, end, err := resolveDate(value, c.env.Now)
+	if err != nil {
+		return sqlbuilder.Expr{}, err
+	}
+
+	t := sqlbuilder.Time(col)
+	switch op {
+	case "eq":
+		return sqlbuilder.And(
+			sqlbuilder.Compare(t, sqlbuilder.OpGte, sqlbuilder.TimeArg(start)),
+			sqlbuilder.Compare(t, sqlbuilder.OpLte, sqlbuilder.TimeArg(end)),
+		), nil
+	case "before":
+		return sqlbuilder.Compare(t, sqlbuilder.OpLt, sqlbuilder.TimeArg(start)), nil
+	case "after":
+		return sqlbuilder.Compare(t, sqlbuilder.OpGt, sqlbuilder.TimeArg(end)), nil
+	case "on_or_before":
+		return sqlbuilder.Compare(t, sqlbuilder.OpLte, sqlbuilder.TimeArg(end)), nil
+	}
+	return sqlbuilder.Compare(t, sqlbuilder.OpGte, sqlbuilder.TimeArg(start)), nil
+}
+
+var relativeDays = regexp.MustCompile(`^([+-])(\d{1,4})d$`)
+
+// resolveDate returns the first and last instant of the day the value refers
+// to. The value is a date (2006-01-02), a relative day (today, yesterday,
+// tomorrow, start_of_week, end_of_week, start_of_month, end_of_month, +7d,
+// -3d) or an RFC 3339 timestamp, which refers to that exact instant.
+func resolveDate(value string, now time.Time) (time.Time, time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, t, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var day time.Time
+
+	switch value {
+	case "today":
+		day = today
+	case "yesterday":
+		day = today.AddDate(0, 0, -1)
+	case "tomorrow":
+		day = today.AddDate(0, 0, 1)
+	case "start_of_week", "end_of_week":
+		// สัปดาห์เริ่มวันจันทร์
+		day = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		if value == "end_of_week" {
+			day = day.AddDate(0, 0, 6)
+		}
+	case "start_of_month":
+		day = today.AddDate(0, 0, 1-today.Day())
+	case "end_of_month":
+		day = today.AddDate(0, 1, -today.Day())
+	default:
+		if m := relativeDays.FindStringSubmatch(value); m != nil {
+			n, _ := strconv.Atoi(m[2])
+			if m[1] == "-" {
+				n = -n
+			}
+			day = today.AddDate(0, 0, n)
+		} else if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+			day = t
+		} else {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+	}
+
+	return day, day.AddDate(0, 0, 1).Add(-time.Millisecond), nil
+}
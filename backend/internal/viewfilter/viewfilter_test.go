@@ -0,0 +1,163 @@
+package viewfilter
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 2024-06-12 เป็นวันพุธ
+var testEnv = Env{UserID: "u1", Now: time.Date(2024, 6, 12, 15, 30, 0, 0, time.UTC)}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *models.ViewFilter
+		sql    string
+		args   []interface{}
+	}{
+		{"nil filter matches everything", nil, "", nil},
+		{"empty filter matches everything", &models.ViewFilter{}, "", nil},
+		{
+			"eq",
+			&models.ViewFilter{Conditions: []models.ViewCondition{{Field: "status", Operator: "eq", Value: "todo"}}},
+			"i.status = ?", []interface{}{"todo"},
+		},
+		{
+			"neq also matches missing values",
+			&models.ViewFilter{Conditions: []models.ViewCondition{{Field: "priority", Operator: "neq", Value: "high"}}},
+			"(i.priority IS NULL) OR (i.priority != ?)", []interface{}{"high"},
+		},
+		{
+			"me is the current user",
+			&models.ViewFilter{Conditions: []models.ViewCondition{{Field: "owner_id", Operator: "in", Values: []string{"me", "u2"}}}},
+			"i.owner_id IN (?, ?)", []interface{}{"u1", "u2"},
+		},
+		{
+			"assignee",
+			&models.ViewFilter{Conditions: []models.ViewCondition{{Field: "assignee", Operator: "eq", Value: "me"}}},
+			"EXISTS (SELECT 1 FROM issue_assignees ia WHERE ia.issue_id = i.id AND ia.user_id = ?)", []interface{}{"u1"},
+		},
+		{
+			"nested or group",
+			&models.ViewFilter{
+				Conditions: []models.ViewCondition{{Field: "label", Operator: "eq", Value: "bug"}},
+				Groups: []models.ViewFilter{{Op: "or", Conditions: []models.ViewCondition{
+					{Field: "priority", Operator: "eq", Value: "high"},
+					{Field: "title", Operator: "contains", Value: "crash"},
+				}}},
+			},
+			`(EXISTS (SELECT 1 FROM issue_labels il WHERE il.issue_id = i.id AND il.label_id = ?)) AND ((i.priority = ?) OR (i.title LIKE ? ESCAPE '\'))`, []interface{}{"bug", "high", "%crash%"},
+		},
+		{
+			"or with an empty group matches everything",
+			&models.ViewFilter{Op: "or", Conditions: []models.ViewCondition{{Field: "label", Operator: "eq", Value: "bug"}}, Groups: []models.ViewFilter{{}}},
+			"", nil,
+		},
+		{
+			"date eq covers the whole day",
+			&models.ViewFilter{Conditions: []models.ViewCondition{{Field: "end_date", Operator: "eq", Value: "today"}}},
+			"(strftime('%Y-%m-%dT%H:%M:%f', i.end_date) >= ?) AND (strftime('%Y-%m-%dT%H:%M:%f', i.end_date) <= ?)",
+			[]interface{}{"2024-06-12T00:00:00.000", "2024-06-12T23:59:59.999"},
+		},
+		{
+			"date is_empty",
+			&models.ViewFilter{Conditions: []models.ViewCondition{{Field: "start_date", Operator: "is_empty"}}},
+			"i.start_date IS NULL", nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Compile(tt.filter, testEnv)
+			require.NoError(t, err)
+			assert.Equal(t, tt.sql, e.SQL)
+			assert.Equal(t, len(tt.args), len(e.Args))
+			if len(tt.args) > 0 {
+				assert.Equal(t, tt.args, e.Args)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	deep := models.ViewFilter{}
+	for i := 0; i < maxDepth; i++ {
+		deep = models.ViewFilter{Groups: []models.ViewFilter{deep}}
+	}
+
+	tests := []struct {
+		name   string
+		filter models.ViewFilter
+		errMsg string
+	}{
+		{"unknown field", models.ViewFilter{Conditions: []models.ViewCondition{{Field: "secret", Operator: "eq", Value: "x"}}}, `filter.conditions[0]: unknown field "secret"`},
+		{"operator not allowed for field", models.ViewFilter{Conditions: []models.ViewCondition{{Field: "title", Operator: "in", Values: []string{"x"}}}}, `operator "in" is not supported for field "title"`},
+		{"missing value", models.ViewFilter{Conditions: []models.ViewCondition{{Field: "status", Operator: "eq"}}}, `operator "eq" needs a value`},
+		{"missing values", models.ViewFilter{Conditions: []models.ViewCondition{{Field: "status", Operator: "in"}}}, `operator "in" needs between 1 and 100 values`},
+		{"bad op", models.ViewFilter{Op: "xor"}, `filter: op must be "and" or "or"`},
+		{"bad date in nested group", models.ViewFilter{Groups: []models.ViewFilter{{Conditions: []models.ViewCondition{{Field: "end_date", Operator: "before", Value: "someday"}}}}}, `filter.groups[0].conditions[0]: invalid date "someday"`},
+		{"too deep", deep, "groups can be nested at most 4 levels deep"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.filter)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	t.Run("too many conditions", func(t *testing.T) {
+		f := models.ViewFilter{}
+		for i := 0; i <= maxConditions; i++ {
+			f.Conditions = append(f.Conditions, models.ViewCondition{Field: "status", Operator: "eq", Value: "todo"})
+		}
+		err := Validate(&f)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at most 50 conditions")
+	})
+}
+
+func TestResolveDate(t *testing.T) {
+	tests := []struct {
+		value string
+		day   string
+	}{
+		{"today", "2024-06-12"},
+		{"yesterday", "2024-06-11"},
+		{"tomorrow", "2024-06-13"},
+		{"start_of_week", "2024-06-10"},
+		{"end_of_week", "2024-06-16"},
+		{"start_of_month", "2024-06-01"},
+		{"end_of_month", "2024-06-30"},
+		{"+7d", "2024-06-19"},
+		{"-12d", "2024-05-31"},
+		{"2024-02-29", "2024-02-29"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			start, end, err := resolveDate(tt.value, testEnv.Now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.day, start.Format("2006-01-02"))
+			assert.Equal(t, "00:00:00", start.Format("15:04:05"))
+			assert.Equal(t, tt.day+" 23:59:59.999", end.Format("2006-01-02 15:04:05.000"))
+		})
+	}
+
+	t.Run("timestamp is an exact instant", func(t *testing.T) {
+		start, end, err := resolveDate("2024-06-12T08:00:00+07:00", testEnv.Now)
+		require.NoError(t, err)
+		assert.True(t, start.Equal(end))
+		assert.Equal(t, "2024-06-12T01:00:00Z", start.UTC().Format(time.RFC3339))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := resolveDate("next tuesday", testEnv.Now)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,265 @@
+// Package viewgroup buckets the issues of a view by its group_by keys.
+package viewgroup
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/nack098/nakumanager/internal/db"
+)
+
+// How the groups of a level are ordered. Natural is the order of the level's
+// Domain, e.g. the statuses of the team in board order; values outside it come
+// after, sorted as text.
+const (
+	OrderNatural = "natural"
+	OrderValue   = "value"
+	OrderCount   = "count"
+)
+
+var ErrInvalidCursor = errors.New("invalid group cursor")
+
+// Level configures one level of grouping.
+type Level struct {
+	Field     string
+	Order     string
+	Desc      bool
+	ShowEmpty bool
+	// Domain is every known value of Field in its natural order, e.g. the
+	// members of the team. Empty groups can only be shown for these values.
+	Domain []string
+}
+
+// Group holds the issues whose Field equals Value. Groups nest in the order of
+// the levels; only the innermost groups hold issues, one page of them. Count
+// is the number of issues in the group on every page. An empty Value is the
+// group of issues without a value, e.g. unassigned issues, and always comes
+// last.
+type Group struct {
+	Field      string     `json:"field"`
+	Value      string     `json:"value"`
+	Count      int        `json:"count"`
+	Groups     []Group    `json:"groups,omitempty"`
+	Issues     []db.Issue `json:"issues,omitempty"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// Related holds the values an issue can have several of, by issue ID.
+type Related struct {
+	Assignees map[string][]string
+	Labels    map[string][]string
+}
+
+type node struct {
+	value    string
+	first    int
+	issues   []db.Issue
+	children []*node
+}
+
+// Build groups the issues by levels, keeping the order of the issues inside
+// each group. Each innermost group holds its first limit issues and a cursor
+// for the rest. An issue with several assignees or labels is in the group of
+// each.
+func Build(issues []db.Issue, related Related, levels []Level, limit int) []Group {
+	return toGroups(split(issues, related, levels), levels, nil, limit)
+}
+
+// Page returns the next page of the innermost group the cursor points to. A
+// group that has emptied since the cursor was made is returned without issues.
+func Page(issues []db.Issue, related Related, levels []Level, cursor string, limit int) (Group, error) {
+	c, err := decodeCursor(cursor)
+	if err != nil || len(c.Path) != len(levels) || c.Offset < 0 {
+		return Group{}, ErrInvalidCursor
+	}
+
+	var found []db.Issue
+	nodes := split(issues, related, levels)
+	for _, value := range c.Path {
+		var next *node
+		for _, n := range nodes {
+			if n.value == value {
+				next = n
+				break
+			}
+		}
+		if next == nil {
+			found = nil
+			break
+		}
+		found = next.issues
+		nodes = next.children
+	}
+	if len(levels) == 0 {
+		found = issues
+	}
+
+	g := Group{Count: len(found)}
+	if len(levels) > 0 {
+		g.Field = levels[len(levels)-1].Field
+		g.Value = c.Path[len(c.Path)-1]
+	}
+	g.Issues, g.NextCursor = page(found, c.Path, c.Offset, limit)
+	return g, nil
+}
+
+// FirstPage returns the first limit issues and a cursor for the rest, for a
+// view without groups.
+func FirstPage(issues []db.Issue, limit int) ([]db.Issue, string) {
+	return page(issues, []string{}, 0, limit)
+}
+
+func page(issues []db.Issue, path []string, offset, limit int) ([]db.Issue, string) {
+	if offset >= len(issues) {
+		return []db.Issue{}, ""
+	}
+	end := offset + limit
+	if end >= len(issues) {
+		return issues[offset:], ""
+	}
+	return issues[offset:end], encodeCursor(groupCursor{Path: path, Offset: end})
+}
+
+func split(issues []db.Issue, related Related, levels []Level) []*node {
+	if len(levels) == 0 {
+		return nil
+	}
+	l := levels[0]
+
+	nodes := []*node{}
+	index := map[string]*node{}
+	for _, issue := range issues {
+		for _, v := range values(issue, related, l.Field) {
+			n, ok := index[v]
+			if !ok {
+				n = &node{value: v, first: len(nodes)}
+				index[v] = n
+				nodes = append(nodes, n)
+			}
+			n.issues = append(n.issues, issue)
+		}
+	}
+	if l.ShowEmpty {
+		for _, v := range l.Domain {
+			if _, ok := index[v]; !ok {
+				n := &node{value: v, first: len(nodes)}
+				index[v] = n
+				nodes = append(nodes, n)
+			}
+		}
+	}
+
+	sortNodes(nodes, l)
+	for _, n := range nodes {
+		n.children = split(n.issues, related, levels[1:])
+	}
+	return nodes
+}
+
+func sortNodes(nodes []*node, l Level) {
+	rank := make(map[string]int, len(l.Domain))
+	for i, v := range l.Domain {
+		rank[v] = i
+	}
+
+	compare := func(a, b *node) int {
+		switch l.Order {
+		case OrderCount:
+			return len(a.issues) - len(b.issues)
+		case OrderValue:
+			return strings.Compare(strings.ToLower(a.value), strings.ToLower(b.value))
+		}
+		ra, aKnown := rank[a.value]
+		rb, bKnown := rank[b.value]
+		switch {
+		case aKnown && bKnown:
+			return ra - rb
+		case aKnown:
+			return -1
+		case bKnown:
+			return 1
+		}
+		return strings.Compare(a.value, b.value)
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		// กลุ่มที่ไม่มีค่าอยู่ท้ายเสมอ ไม่ว่าจะเรียงแบบใด
+		if (a.value == "") != (b.value == "") {
+			return b.value == ""
+		}
+		c := compare(a, b)
+		if l.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		return a.first < b.first
+	})
+}
+
+func toGroups(nodes []*node, levels []Level, path []string, limit int) []Group {
+	groups := make([]Group, len(nodes))
+	for i, n := range nodes {
+		p := append(append([]string{}, path...), n.value)
+		g := Group{Field: levels[0].Field, Value: n.value, Count: len(n.issues)}
+		if len(levels) > 1 {
+			g.Groups = toGroups(n.children, levels[1:], p, limit)
+		} else {
+			g.Issues, g.NextCursor = page(n.issues, p, 0, limit)
+		}
+		groups[i] = g
+	}
+	return groups
+}
+
+func values(issue db.Issue, related Related, key string) []string {
+	switch key {
+	case "status":
+		return []string{issue.Status}
+	case "priority":
+		return []string{issue.Priority.String}
+	case "project_id":
+		return []string{issue.ProjectID.String}
+	case "team_id":
+		return []string{issue.TeamID}
+	case "end_date":
+		if !issue.EndDate.Valid {
+			return []string{""}
+		}
+		return []string{issue.EndDate.Time.UTC().Format("2006-01-02")}
+	case "assignee":
+		if ids := related.Assignees[issue.ID]; len(ids) > 0 {
+			return ids
+		}
+	case "label":
+		if ids := related.Labels[issue.ID]; len(ids) > 0 {
+			return ids
+		}
+	}
+	return []string{""}
+}
+
+type groupCursor struct {
+	Path   []string `json:"p"`
+	Offset int      `json:"o"`
+}
+
+func encodeCursor(c groupCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (groupCursor, error) {
+	var c groupCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(b, &c)
+	return c, err
+}
@@ -0,0 +1,186 @@
+package viewgroup_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/nack098/nakumanager/internal/db"
+	"github.com/nack098/nakumanager/internal/viewgroup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ids(issues []db.Issue) []string {
+	out := []string{}
+	for _, i := range issues {
+		out = append(out, i.ID)
+	}
+	return out
+}
+
+var statuses = []string{"todo", "doing", "done"}
+
+func by(fields ...string) []viewgroup.Level {
+	levels := make([]viewgroup.Level, len(fields))
+	for i, f := range fields {
+		levels[i] = viewgroup.Level{Field: f, Order: viewgroup.OrderNatural}
+		if f == "status" {
+			levels[i].Domain = statuses
+		}
+	}
+	return levels
+}
+
+func values(groups []viewgroup.Group) []string {
+	out := []string{}
+	for _, g := range groups {
+		out = append(out, g.Value)
+	}
+	return out
+}
+
+func TestBuild(t *testing.T) {
+	due := sql.NullTime{Time: time.Date(2024, 6, 12, 23, 0, 0, 0, time.FixedZone("ICT", 7*3600)), Valid: true}
+	issues := []db.Issue{
+		{ID: "i1", Status: "todo", Priority: sql.NullString{String: "high", Valid: true}, EndDate: due},
+		{ID: "i2", Status: "done"},
+		{ID: "i3", Status: "todo", EndDate: due},
+	}
+	assignees := map[string][]string{"i1": {"u1", "u2"}, "i3": {"u2"}}
+
+	t.Run("no keys", func(t *testing.T) {
+		assert.Empty(t, viewgroup.Build(issues, viewgroup.Related{Assignees: assignees}, nil, 10))
+	})
+
+	t.Run("missing values form their own group", func(t *testing.T) {
+		groups := viewgroup.Build(issues, viewgroup.Related{Assignees: assignees}, by("priority"), 10)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "high", groups[0].Value)
+		assert.Equal(t, []string{"i1"}, ids(groups[0].Issues))
+		assert.Equal(t, "", groups[1].Value)
+		assert.Equal(t, []string{"i2", "i3"}, ids(groups[1].Issues))
+	})
+
+	t.Run("end_date is grouped by UTC day", func(t *testing.T) {
+		groups := viewgroup.Build(issues, viewgroup.Related{Assignees: assignees}, by("end_date"), 10)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "2024-06-12", groups[0].Value)
+		assert.Equal(t, []string{"i1", "i3"}, ids(groups[0].Issues))
+	})
+
+	t.Run("nested with several assignees", func(t *testing.T) {
+		groups := viewgroup.Build(issues, viewgroup.Related{Assignees: assignees}, by("status", "assignee"), 10)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "todo", groups[0].Value)
+		assert.Equal(t, 2, groups[0].Count)
+		assert.Nil(t, groups[0].Issues)
+		require.Len(t, groups[0].Groups, 2)
+		assert.Equal(t, "u1", groups[0].Groups[0].Value)
+		assert.Equal(t, []string{"i1"}, ids(groups[0].Groups[0].Issues))
+		assert.Equal(t, "u2", groups[0].Groups[1].Value)
+		assert.Equal(t, []string{"i1", "i3"}, ids(groups[0].Groups[1].Issues))
+		assert.Equal(t, "done", groups[1].Value)
+		assert.Equal(t, "", groups[1].Groups[0].Value)
+	})
+
+	t.Run("several labels", func(t *testing.T) {
+		labels := map[string][]string{"i1": {"bug", "ui"}, "i2": {"ui"}}
+		groups := viewgroup.Build(issues, viewgroup.Related{Labels: labels}, by("label"), 10)
+		assert.Equal(t, []string{"bug", "ui", ""}, values(groups))
+		assert.Equal(t, []string{"i1", "i2"}, ids(groups[1].Issues))
+		assert.Equal(t, []string{"i3"}, ids(groups[2].Issues))
+	})
+}
+
+func TestBuildOrder(t *testing.T) {
+	issues := []db.Issue{
+		{ID: "i1", Status: "done"},
+		{ID: "i2", Status: "todo"},
+		{ID: "i3", Status: "done"},
+		{ID: "i4", Status: "Blocked"},
+		{ID: "i5", Status: ""},
+		{ID: "i6", Status: "archived"},
+	}
+
+	tests := []struct {
+		name  string
+		level viewgroup.Level
+		want  []string
+	}{
+		{"natural follows the domain, unknown values after", viewgroup.Level{Order: viewgroup.OrderNatural, Domain: statuses}, []string{"todo", "done", "Blocked", "archived", ""}},
+		{"natural descending", viewgroup.Level{Order: viewgroup.OrderNatural, Domain: statuses, Desc: true}, []string{"archived", "Blocked", "done", "todo", ""}},
+		{"value ignores case", viewgroup.Level{Order: viewgroup.OrderValue}, []string{"archived", "Blocked", "done", "todo", ""}},
+		{"count, ties by first appearance", viewgroup.Level{Order: viewgroup.OrderCount, Desc: true}, []string{"done", "todo", "Blocked", "archived", ""}},
+		{"show empty", viewgroup.Level{Order: viewgroup.OrderNatural, Domain: statuses, ShowEmpty: true}, []string{"todo", "doing", "done", "Blocked", "archived", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.level.Field = "status"
+			groups := viewgroup.Build(issues, viewgroup.Related{}, []viewgroup.Level{tt.level}, 10)
+			assert.Equal(t, tt.want, values(groups))
+		})
+	}
+
+	t.Run("empty groups have a zero count", func(t *testing.T) {
+		groups := viewgroup.Build(issues, viewgroup.Related{}, []viewgroup.Level{{Field: "status", Domain: statuses, ShowEmpty: true}}, 10)
+		assert.Equal(t, "doing", groups[1].Value)
+		assert.Equal(t, 0, groups[1].Count)
+		assert.Empty(t, groups[1].Issues)
+	})
+}
+
+func TestPage(t *testing.T) {
+	issues := []db.Issue{}
+	for _, id := range []string{"i1", "i2", "i3", "i4", "i5"} {
+		issues = append(issues, db.Issue{ID: id, Status: "todo"})
+	}
+	issues = append(issues, db.Issue{ID: "i6", Status: "done"})
+	assignees := map[string][]string{"i1": {"u1"}, "i2": {"u1"}, "i3": {"u1"}, "i6": {"u1"}}
+	levels := by("status", "assignee")
+
+	groups := viewgroup.Build(issues, viewgroup.Related{Assignees: assignees}, levels, 2)
+	u1 := groups[0].Groups[0]
+	assert.Equal(t, 3, u1.Count)
+	assert.Equal(t, []string{"i1", "i2"}, ids(u1.Issues))
+	require.NotEmpty(t, u1.NextCursor)
+	unassigned := groups[0].Groups[1]
+	assert.Equal(t, []string{"i4", "i5"}, ids(unassigned.Issues))
+	assert.Empty(t, unassigned.NextCursor)
+	assert.Empty(t, groups[1].Groups[0].NextCursor)
+
+	next, err := viewgroup.Page(issues, viewgroup.Related{Assignees: assignees}, levels, u1.NextCursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "assignee", next.Field)
+	assert.Equal(t, "u1", next.Value)
+	assert.Equal(t, 3, next.Count)
+	assert.Equal(t, []string{"i3"}, ids(next.Issues))
+	assert.Empty(t, next.NextCursor)
+
+	t.Run("group emptied since", func(t *testing.T) {
+		g, err := viewgroup.Page(issues[3:], viewgroup.Related{}, levels, u1.NextCursor, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 0, g.Count)
+		assert.Empty(t, g.Issues)
+	})
+
+	t.Run("cursor of another grouping", func(t *testing.T) {
+		_, err := viewgroup.Page(issues, viewgroup.Related{Assignees: assignees}, by("status"), u1.NextCursor, 2)
+		assert.ErrorIs(t, err, viewgroup.ErrInvalidCursor)
+	})
+
+	t.Run("garbage cursor", func(t *testing.T) {
+		_, err := viewgroup.Page(issues, viewgroup.Related{Assignees: assignees}, levels, "%%%", 2)
+		assert.ErrorIs(t, err, viewgroup.ErrInvalidCursor)
+	})
+
+	t.Run("ungrouped", func(t *testing.T) {
+		first, cursor := viewgroup.FirstPage(issues, 4)
+		assert.Equal(t, []string{"i1", "i2", "i3", "i4"}, ids(first))
+		rest, err := viewgroup.Page(issues, viewgroup.Related{Assignees: assignees}, nil, cursor, 4)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"i5", "i6"}, ids(rest.Issues))
+		assert.Equal(t, 6, rest.Count)
+	})
+}
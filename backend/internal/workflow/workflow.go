@@ -0,0 +1,91 @@
+// Package workflow checks status transitions of issues against the
+// transition rules of their team.
+package workflow
+
+import (
+	"fmt"
+
+	models "github.com/nack098/nakumanager/internal/models"
+)
+
+// Requirements a rule can put on a transition.
+const (
+	RequireAssignee      = "assignee"
+	RequireEndDate       = "end_date"
+	RequireActorAssignee = "actor_is_assignee"
+	Forbidden            = "forbidden"
+)
+
+// Transition is an issue moving from one status of its team to another.
+// Assignees and HasEndDate describe the issue as it will be after the update
+// that moves it.
+type Transition struct {
+	From       string
+	To         string
+	ToCategory string
+	ActorID    string
+	Assignees  []string
+	HasEndDate bool
+}
+
+// Violation is a rule the transition does not meet.
+type Violation struct {
+	RuleID      string `json:"rule_id"`
+	Requirement string `json:"requirement"`
+	Message     string `json:"message"`
+}
+
+// Applies reports whether the rule governs moves from one status to another.
+func Applies(r models.TransitionRule, from, to, toCategory string) bool {
+	if r.FromStatus != "" && r.FromStatus != from {
+		return false
+	}
+	if r.ToStatus != "" {
+		return r.ToStatus == to
+	}
+	return r.ToCategory == toCategory
+}
+
+// Check returns the rules the transition breaks, in the order of rules. A move
+// that does not change the status is always allowed.
+func Check(rules []models.TransitionRule, t Transition) []Violation {
+	violations := []Violation{}
+	if t.From == t.To {
+		return violations
+	}
+	for _, r := range rules {
+		if !Applies(r, t.From, t.To, t.ToCategory) {
+			continue
+		}
+		var msg string
+		switch r.Requirement {
+		case RequireAssignee:
+			if len(t.Assignees) == 0 {
+				msg = fmt.Sprintf("an issue needs an assignee to move to %s", t.To)
+			}
+		case RequireEndDate:
+			if !t.HasEndDate {
+				msg = fmt.Sprintf("an issue needs an end_date to move to %s", t.To)
+			}
+		case RequireActorAssignee:
+			if !contains(t.Assignees, t.ActorID) {
+				msg = fmt.Sprintf("only an assignee can move the issue to %s", t.To)
+			}
+		case Forbidden:
+			msg = fmt.Sprintf("issues cannot move from %s to %s", t.From, t.To)
+		}
+		if msg != "" {
+			violations = append(violations, Violation{RuleID: r.ID, Requirement: r.Requirement, Message: msg})
+		}
+	}
+	return violations
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
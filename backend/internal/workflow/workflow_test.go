@@ -0,0 +1,89 @@
+package workflow_test
+
+import (
+	"testing"
+
+	models "github.com/nack098/nakumanager/internal/models"
+	"github.com/nack098/nakumanager/internal/workflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func requirements(violations []workflow.Violation) []string {
+	out := []string{}
+	for _, v := range violations {
+		out = append(out, v.Requirement)
+	}
+	return out
+}
+
+func TestCheck(t *testing.T) {
+	rules := []models.TransitionRule{
+		{ID: "r1", ToStatus: "doing", Requirement: workflow.RequireAssignee},
+		{ID: "r2", ToCategory: "completed", Requirement: workflow.RequireEndDate},
+		{ID: "r3", ToStatus: "done", Requirement: workflow.RequireActorAssignee},
+		{ID: "r4", FromStatus: "done", ToStatus: "todo", Requirement: workflow.Forbidden},
+	}
+
+	tests := []struct {
+		name string
+		t    workflow.Transition
+		want []string
+	}{
+		{
+			name: "doing without assignee",
+			t:    workflow.Transition{From: "todo", To: "doing", ToCategory: "started"},
+			want: []string{"assignee"},
+		},
+		{
+			name: "doing with assignee",
+			t:    workflow.Transition{From: "todo", To: "doing", ToCategory: "started", Assignees: []string{"u2"}},
+			want: []string{},
+		},
+		{
+			name: "done by someone else without end date",
+			t:    workflow.Transition{From: "doing", To: "done", ToCategory: "completed", ActorID: "u1", Assignees: []string{"u2"}},
+			want: []string{"end_date", "actor_is_assignee"},
+		},
+		{
+			name: "done by the assignee",
+			t:    workflow.Transition{From: "doing", To: "done", ToCategory: "completed", ActorID: "u2", Assignees: []string{"u2"}, HasEndDate: true},
+			want: []string{},
+		},
+		{
+			name: "category rule covers custom statuses",
+			t:    workflow.Transition{From: "doing", To: "shipped", ToCategory: "completed"},
+			want: []string{"end_date"},
+		},
+		{
+			name: "forbidden only from its status",
+			t:    workflow.Transition{From: "done", To: "todo", ToCategory: "unstarted"},
+			want: []string{"forbidden"},
+		},
+		{
+			name: "other moves back are free",
+			t:    workflow.Transition{From: "doing", To: "todo", ToCategory: "unstarted"},
+			want: []string{},
+		},
+		{
+			name: "no change",
+			t:    workflow.Transition{From: "doing", To: "doing", ToCategory: "started"},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, requirements(workflow.Check(rules, tt.t)))
+		})
+	}
+}
+
+func TestCheckReportsRule(t *testing.T) {
+	rules := []models.TransitionRule{{ID: "r1", ToStatus: "done", Requirement: workflow.RequireEndDate}}
+	violations := workflow.Check(rules, workflow.Transition{From: "todo", To: "done", ToCategory: "completed"})
+	assert.Equal(t, []workflow.Violation{{
+		RuleID:      "r1",
+		Requirement: "end_date",
+		Message:     "an issue needs an end_date to move to done",
+	}}, violations)
+}
@@ -1,15 +1,28 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 
 	wsfiber "github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/repositories"
 )
 
 
-type WebSocketHandler struct{}
+type WebSocketHandler struct {
+	IssueRepo repositories.IssueRepository
+	Authz     *authz.Authorizer
+}
+
+func NewWebSocketHandler(issueRepo repositories.IssueRepository, roleRepo repositories.RoleRepository) *WebSocketHandler {
+	return &WebSocketHandler{
+		IssueRepo: issueRepo,
+		Authz:     authz.NewAuthorizer(roleRepo),
+	}
+}
 
 type ClientMessage struct {
 	Type  string              `json:"type"`  
@@ -49,6 +62,9 @@ func (h *WebSocketHandler) Handle(c *fiber.Ctx) error {
 			case "subscribe":
 				for roomType, ids := range clientMsg.Rooms {
 					for _, id := range ids {
+						if !h.CanJoin(context.Background(), userID, roomType, id) {
+							continue
+						}
 						RegisterToRoom(userID, conn, roomType, id)
 
 						
@@ -85,3 +101,37 @@ func (h *WebSocketHandler) Handle(c *fiber.Ctx) error {
 		}
 	})(c)
 }
+
+// CanJoin reports whether the user may receive the events broadcast to the
+// room. Events carry the same data the REST endpoints return, so a room is
+// guarded by the permission needed to read it there.
+func (h *WebSocketHandler) CanJoin(ctx context.Context, userID, roomType, id string) bool {
+	var (
+		action   authz.Action
+		resource authz.Resource
+	)
+	switch roomType {
+	// ห้อง user รับการแจ้งเตือนส่วนตัว เข้าได้แค่ห้องของตัวเอง
+	case "user":
+		return id == userID
+	case "workspace":
+		action, resource = authz.WorkspaceView, authz.ForWorkspace(id)
+	case "team":
+		action, resource = authz.TeamView, authz.ForTeam(id)
+	case "issue":
+		issue, err := h.IssueRepo.GetIssueByID(ctx, id)
+		if err != nil {
+			return false
+		}
+		action, resource = authz.IssueView, authz.ForIssue(issue)
+	default:
+		return true
+	}
+
+	allowed, err := h.Authz.Can(ctx, userID, action, resource)
+	if err != nil {
+		log.Printf("failed to authorize %s room %s for %s: %v", roomType, id, userID, err)
+		return false
+	}
+	return allowed
+}
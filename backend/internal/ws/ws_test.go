@@ -1,11 +1,17 @@
 package ws_test
 
 import (
+	"context"
+	"database/sql"
 	"sync"
 	"testing"
 
+	"github.com/nack098/nakumanager/internal/authz"
+	"github.com/nack098/nakumanager/internal/db"
+	mocks "github.com/nack098/nakumanager/internal/routes/mock_repo"
 	"github.com/nack098/nakumanager/internal/ws"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var mu sync.Mutex
@@ -69,3 +75,124 @@ func TestBroadcastToRoom(t *testing.T) {
 	assert.Equal(t, event, msg2["type"])
 	assert.Equal(t, payload, msg2["data"])
 }
+
+func TestCanJoin(t *testing.T) {
+	mockIssueRepo := new(mocks.MockIssueRepo)
+	mockRoleRepo := new(mocks.MockRoleRepo)
+	handler := ws.WebSocketHandler{
+		IssueRepo: mockIssueRepo,
+		Authz:     authz.NewAuthorizer(mockRoleRepo),
+	}
+
+	member := db.GetTeamMemberRolesRow{WorkspaceID: "ws-1", WorkspaceRole: "member", TeamRole: "member"}
+	outsider := db.GetTeamMemberRolesRow{WorkspaceID: "ws-1", WorkspaceRole: "member"}
+	issue := db.Issue{ID: "issue-1", TeamID: "team-1", OwnerID: "user-2"}
+
+	tests := []struct {
+		name     string
+		roomType string
+		id       string
+		setup    func()
+		want     bool
+	}{
+		{
+			name:     "own user room",
+			roomType: "user",
+			id:       "user-1",
+			setup:    func() {},
+			want:     true,
+		},
+		{
+			name:     "another user's room",
+			roomType: "user",
+			id:       "user-2",
+			setup:    func() {},
+			want:     false,
+		},
+		{
+			name:     "issue of own team",
+			roomType: "issue",
+			id:       "issue-1",
+			setup: func() {
+				mockIssueRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(issue, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-1").Return(member, nil)
+			},
+			want: true,
+		},
+		{
+			name:     "issue of another team",
+			roomType: "issue",
+			id:       "issue-1",
+			setup: func() {
+				mockIssueRepo.On("GetIssueByID", mock.Anything, "issue-1").Return(issue, nil)
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-1").Return(outsider, nil)
+			},
+			want: false,
+		},
+		{
+			name:     "issue not found",
+			roomType: "issue",
+			id:       "missing",
+			setup: func() {
+				mockIssueRepo.On("GetIssueByID", mock.Anything, "missing").Return(db.Issue{}, sql.ErrNoRows)
+			},
+			want: false,
+		},
+		{
+			name:     "own team",
+			roomType: "team",
+			id:       "team-1",
+			setup: func() {
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-1").Return(member, nil)
+			},
+			want: true,
+		},
+		{
+			name:     "another team",
+			roomType: "team",
+			id:       "team-1",
+			setup: func() {
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-1").Return(db.GetTeamMemberRolesRow{}, sql.ErrNoRows)
+			},
+			want: false,
+		},
+		{
+			name:     "role lookup fails",
+			roomType: "team",
+			id:       "team-1",
+			setup: func() {
+				mockRoleRepo.On("GetTeamRoles", mock.Anything, "team-1", "user-1").Return(db.GetTeamMemberRolesRow{}, assert.AnError)
+			},
+			want: false,
+		},
+		{
+			name:     "workspace member",
+			roomType: "workspace",
+			id:       "ws-1",
+			setup: func() {
+				mockRoleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-1").Return("guest", nil)
+			},
+			want: true,
+		},
+		{
+			name:     "not in workspace",
+			roomType: "workspace",
+			id:       "ws-1",
+			setup: func() {
+				mockRoleRepo.On("GetWorkspaceRole", mock.Anything, "ws-1", "user-1").Return("", sql.ErrNoRows)
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			assert.Equal(t, tt.want, handler.CanJoin(context.Background(), "user-1", tt.roomType, tt.id))
+
+			mockIssueRepo.ExpectedCalls = nil
+			mockRoleRepo.ExpectedCalls = nil
+		})
+	}
+}